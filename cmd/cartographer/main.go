@@ -34,6 +34,7 @@ var pProfPort int
 var maxConcurrentDeliveries int
 var maxConcurrentWorkloads int
 var maxConcurrentRunnables int
+var templateRevisionHistoryLimit int
 
 func init() {
 	flag.IntVar(&port, "Port", 9443, "Webhook server Port")
@@ -45,6 +46,7 @@ func init() {
 	flag.IntVar(&maxConcurrentDeliveries, "max-concurrent-deliveries", 2, "Maximum Concurrent Deliveries")
 	flag.IntVar(&maxConcurrentWorkloads, "max-concurrent-workloads", 2, "Maximum Concurrent Workloads")
 	flag.IntVar(&maxConcurrentRunnables, "max-concurrent-runnables", 2, "Maximum Concurrent Runnables")
+	flag.IntVar(&templateRevisionHistoryLimit, "template-revision-history-limit", 10, "Revisions kept for each template, besides those pinned by blueprints")
 	flag.Parse()
 }
 
@@ -55,14 +57,15 @@ func main() {
 	}
 
 	c := cmd.Command{
-		Port:                    port,
-		CertDir:                 certDir,
-		Logger:                  zap.New(loggerOpt, zap.UseDevMode(devMode)),
-		MetricsPort:             metricsPort,
-		PprofPort:               pProfPort,
-		MaxConcurrentDeliveries: maxConcurrentDeliveries,
-		MaxConcurrentWorkloads:  maxConcurrentWorkloads,
-		MaxConcurrentRunnables:  maxConcurrentRunnables,
+		Port:                         port,
+		CertDir:                      certDir,
+		Logger:                       zap.New(loggerOpt, zap.UseDevMode(devMode)),
+		MetricsPort:                  metricsPort,
+		PprofPort:                    pProfPort,
+		MaxConcurrentDeliveries:      maxConcurrentDeliveries,
+		MaxConcurrentWorkloads:       maxConcurrentWorkloads,
+		MaxConcurrentRunnables:       maxConcurrentRunnables,
+		TemplateRevisionHistoryLimit: templateRevisionHistoryLimit,
 	}
 
 	if err = c.Execute(ctrl.SetupSignalHandler()); err != nil {
@@ -41,8 +41,11 @@ type ConfigTemplateSpec struct {
 	// data that contains valid yaml. This
 	// is typically the information that will configure the
 	// components of the deployable image.
+	// Required unless the lifecycle is tekton, in which case the config
+	// result of the run is read by default.
 	// ConfigPath is specified in jsonpath format, eg: .data
-	ConfigPath string `json:"configPath"`
+	// +optional
+	ConfigPath string `json:"configPath,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -25,11 +25,11 @@ import (
 var _ webhook.Validator = &ClusterConfigTemplate{}
 
 func (c *ClusterConfigTemplate) ValidateCreate() error {
-	return c.Spec.TemplateSpec.validate()
+	return c.Spec.validate()
 }
 
 func (c *ClusterConfigTemplate) ValidateUpdate(_ runtime.Object) error {
-	return c.Spec.TemplateSpec.validate()
+	return c.Spec.validate()
 }
 
 func (c *ClusterConfigTemplate) ValidateDelete() error {
@@ -39,5 +39,6 @@ func (c *ClusterConfigTemplate) ValidateDelete() error {
 func (c *ClusterConfigTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
+		WithValidator(NewTemplateExtensionValidator(mgr.GetAPIReader())).
 		Complete()
 }
@@ -53,6 +53,17 @@ type ClusterDelivery struct {
 type DeliverySpec struct {
 	LegacySelector `json:",inline"`
 
+	// Priority breaks ties when more than one delivery selects a
+	// deliverable with equally specific selectors. The delivery with the
+	// highest priority is chosen. Defaults to 0.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// NamespaceSelector restricts the namespaces whose deliverables may select this
+	// delivery. If not set, deliverables in any namespace may select it.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
 	// Resources that are responsible for deploying and validating
 	// the deliverable
 	Resources []DeliveryResource `json:"resources"`
@@ -71,6 +82,11 @@ type DeliverySpec struct {
 	// workload's namespace.
 	// +optional
 	ServiceAccountRef ServiceAccountRef `json:"serviceAccountRef,omitempty"`
+
+	// MetadataPolicy specifies the labels and annotations of the owner that are
+	// propagated onto every stamped object.
+	// +optional
+	MetadataPolicy *MetadataPolicy `json:"metadataPolicy,omitempty"`
 }
 
 type DeliveryStatus struct {
@@ -136,6 +152,22 @@ type DeliveryTemplateReference struct {
 	// Only one of Name and Options can be specified.
 	// +kubebuilder:validation:MinItems=2
 	Options []TemplateOption `json:"options,omitempty"`
+
+	// Revision pins the template to a ClusterTemplateRevision, identified by the
+	// generation of the template when the revision was created. If unset, the
+	// latest spec of the template is used.
+	// Revisions are only taken of the generations Cartographer observes, so a
+	// generation replaced before it was reconciled cannot be pinned.
+	// Can only be specified with Name.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Revision int64 `json:"revision,omitempty"`
+
+	// Rollout moves a portion of the deliverables to another revision of the template,
+	// so that a template change can be rolled out in batches.
+	// Requires Revision.
+	// +optional
+	Rollout *TemplateRollout `json:"rollout,omitempty"`
 }
 
 type DeploymentReference struct {
@@ -154,6 +186,14 @@ func (c *ClusterDelivery) GetSelectors() LegacySelector {
 	return c.Spec.LegacySelector
 }
 
+func (c *ClusterDelivery) GetPriority() int32 {
+	return c.Spec.Priority
+}
+
+func (c *ClusterDelivery) GetNamespaceSelector() *metav1.LabelSelector {
+	return c.Spec.NamespaceSelector
+}
+
 func init() {
 	SchemeBuilder.Register(
 		&ClusterDelivery{},
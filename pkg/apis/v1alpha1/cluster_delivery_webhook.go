@@ -24,6 +24,9 @@ import (
 
 // +kubebuilder:webhook:path=/validate-carto-run-v1alpha1-clusterdelivery,mutating=false,failurePolicy=fail,sideEffects=none,admissionReviewVersions=v1beta1;v1,groups=carto.run,resources=clusterdeliveries,verbs=create;update,versions=v1alpha1,name=delivery-validator.cartographer.com
 
+// The selector overlap warning is served by webhooks.SelectorOverlapWarner.
+// +kubebuilder:webhook:path=/warn-carto-run-v1alpha1-clusterdelivery,mutating=false,failurePolicy=ignore,sideEffects=none,admissionReviewVersions=v1beta1;v1,groups=carto.run,resources=clusterdeliveries,verbs=create;update,versions=v1alpha1,name=delivery-selector-overlap-warner.cartographer.com
+
 var _ webhook.Validator = &ClusterDelivery{}
 
 func (c *ClusterDelivery) ValidateCreate() error {
@@ -59,6 +62,14 @@ func (c *ClusterDelivery) validateNewState() error {
 		return err
 	}
 
+	if err := validateNamespaceSelector(c.Spec.NamespaceSelector); err != nil {
+		return err
+	}
+
+	if err := c.Spec.MetadataPolicy.validate(); err != nil {
+		return err
+	}
+
 	if err := c.validateResourceNamesUnique(); err != nil {
 		return err
 	}
@@ -178,6 +189,10 @@ func validateDeliveryTemplateRef(ref DeliveryTemplateReference) error {
 	if err := validateResourceOptions(ref.Options, ValidDeliverablePaths, ValidDeliverablePrefixes); err != nil {
 		return err
 	}
+
+	if err := validateTemplateRevision(ref.Name, ref.Revision, ref.Rollout); err != nil {
+		return err
+	}
 	return nil
 }
 
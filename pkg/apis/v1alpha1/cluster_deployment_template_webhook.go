@@ -62,5 +62,6 @@ func (c *ClusterDeploymentTemplate) neitherConditionSet() bool {
 func (c *ClusterDeploymentTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
+		WithValidator(NewTemplateExtensionValidator(mgr.GetAPIReader())).
 		Complete()
 }
@@ -41,8 +41,11 @@ type ImageTemplateSpec struct {
 	// might be a URL or in some cases just a repository path and digest.
 	// The final spec for this field may change as we implement
 	// RFC-0016 https://github.com/vmware-tanzu/cartographer/blob/main/rfc/rfc-0016-validate-template-outputs.md
+	// Required unless the lifecycle is tekton, in which case the image
+	// result of the run is read by default.
 	// ImagePath is specified in jsonpath format, eg: .status.artifact.image_digest
-	ImagePath string `json:"imagePath"`
+	// +optional
+	ImagePath string `json:"imagePath,omitempty"`
 }
 
 // +kubebuilder:object:root=true
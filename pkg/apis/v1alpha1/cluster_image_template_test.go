@@ -37,6 +37,9 @@ var _ = Describe("ClusterImageTemplate", func() {
 					Name:      "some-template",
 					Namespace: "default",
 				},
+				Spec: v1alpha1.ImageTemplateSpec{
+					ImagePath: ".status.latestImage",
+				},
 			}
 		})
 
@@ -64,6 +67,27 @@ var _ = Describe("ClusterImageTemplate", func() {
 				})
 			})
 
+			Context("template does not set the imagePath", func() {
+				BeforeEach(func() {
+					template.Spec.Template = &runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap"}`)}
+					template.Spec.ImagePath = ""
+				})
+
+				It("returns a helpful error", func() {
+					Expect(template.ValidateCreate()).To(MatchError("invalid template: imagePath must be set unless lifecycle is tekton"))
+				})
+
+				Context("and the lifecycle is tekton", func() {
+					BeforeEach(func() {
+						template.Spec.Lifecycle = "tekton"
+					})
+
+					It("succeeds", func() {
+						Expect(template.ValidateCreate()).To(Succeed())
+					})
+				})
+			})
+
 			Context("template sets object namespace", func() {
 				BeforeEach(func() {
 					raw, err := json.Marshal(&ArbitraryObject{
@@ -25,11 +25,11 @@ import (
 var _ webhook.Validator = &ClusterImageTemplate{}
 
 func (c *ClusterImageTemplate) ValidateCreate() error {
-	return c.Spec.TemplateSpec.validate()
+	return c.Spec.validate()
 }
 
 func (c *ClusterImageTemplate) ValidateUpdate(_ runtime.Object) error {
-	return c.Spec.TemplateSpec.validate()
+	return c.Spec.validate()
 }
 
 func (c *ClusterImageTemplate) ValidateDelete() error {
@@ -39,5 +39,6 @@ func (c *ClusterImageTemplate) ValidateDelete() error {
 func (c *ClusterImageTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
+		WithValidator(NewTemplateExtensionValidator(mgr.GetAPIReader())).
 		Complete()
 }
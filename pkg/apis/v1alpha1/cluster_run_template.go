@@ -57,6 +57,34 @@ type RunTemplateSpec struct {
 	// will never display an output
 	// +optional
 	Outputs map[string]string `json:"outputs,omitempty"`
+
+	// HealthRule specifies rubric for determining whether an object stamped
+	// by this template has succeeded (healthy), failed (unhealthy) or is still
+	// running. It is used for outputs, retention and the runnable's
+	// StampedObjectCondition. Probe and children rules are not supported.
+	// Defaults to the object's Succeeded condition.
+	// See: https://cartographer.sh/docs/latest/health-rules/
+	// +optional
+	HealthRule *HealthRule `json:"healthRule,omitempty"`
+
+	// CancelPatch is a JSON merge patch applied to an in-flight object to
+	// cancel it when a Runnable with concurrencyPolicy Replace creates a
+	// newer object, e.g. for a Tekton PipelineRun:
+	//   spec:
+	//     status: Cancelled
+	// If not set, in-flight objects are deleted instead.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	CancelPatch *runtime.RawExtension `json:"cancelPatch,omitempty"`
+
+	// IgnoreObservedGeneration disables stale status detection for objects
+	// stamped by this template. By default, while an object's
+	// `status.observedGeneration` (or that of a condition used by the health
+	// rule) is behind its `metadata.generation`, its run is considered in
+	// flight and its outputs are not read. Set this for resources whose
+	// observedGeneration does not track their generation.
+	// +optional
+	IgnoreObservedGeneration bool `json:"ignoreObservedGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
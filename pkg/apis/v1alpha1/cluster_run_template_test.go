@@ -63,6 +63,44 @@ var _ = Describe("ClusterRunTemplate", func() {
 				It("succeeds", func() {
 					Expect(template.ValidateCreate()).To(Succeed())
 				})
+
+				Context("and a health rule", func() {
+					It("succeeds", func() {
+						template.Spec.HealthRule = &v1alpha1.HealthRule{SingleConditionType: "Complete"}
+						Expect(template.ValidateCreate()).To(Succeed())
+					})
+				})
+
+				Context("and an invalid health rule", func() {
+					It("returns an error", func() {
+						template.Spec.HealthRule = &v1alpha1.HealthRule{}
+						Expect(template.ValidateCreate()).
+							To(MatchError(ContainSubstring("invalid health rule: must specify one of")))
+					})
+				})
+
+				Context("and a probe health rule", func() {
+					It("returns an error", func() {
+						template.Spec.HealthRule = &v1alpha1.HealthRule{Probe: &v1alpha1.ProbeHealthRule{}}
+						Expect(template.ValidateCreate()).
+							To(MatchError("invalid health rule: probe and children rules are not supported on run templates"))
+					})
+				})
+
+				Context("and a cancelPatch object", func() {
+					It("succeeds", func() {
+						template.Spec.CancelPatch = &runtime.RawExtension{Raw: []byte(`{"spec":{"status":"Cancelled"}}`)}
+						Expect(template.ValidateCreate()).To(Succeed())
+					})
+				})
+
+				Context("and a cancelPatch that is not an object", func() {
+					It("returns an error", func() {
+						template.Spec.CancelPatch = &runtime.RawExtension{Raw: []byte(`["Cancelled"]`)}
+						Expect(template.ValidateCreate()).
+							To(MatchError(ContainSubstring("invalid cancelPatch: must be an object")))
+					})
+				})
 			})
 
 			Context("template sets object namespace", func() {
@@ -66,6 +66,22 @@ func (t *RunTemplateSpec) validate() error {
 		return fmt.Errorf("invalid template: object must have a spec; templated object: %+v", resourceTemplate)
 	}
 
+	if t.HealthRule != nil {
+		if t.HealthRule.Probe != nil || t.HealthRule.Children != nil {
+			return fmt.Errorf("invalid health rule: probe and children rules are not supported on run templates")
+		}
+		if err := t.HealthRule.validate(); err != nil {
+			return err
+		}
+	}
+
+	if t.CancelPatch != nil {
+		var cancelPatch map[string]interface{}
+		if err := json.Unmarshal(t.CancelPatch.Raw, &cancelPatch); err != nil {
+			return fmt.Errorf("invalid cancelPatch: must be an object: %w", err)
+		}
+	}
+
 	return nil
 }
 
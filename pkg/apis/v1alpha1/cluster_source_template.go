@@ -40,14 +40,20 @@ type SourceTemplateSpec struct {
 	// URLPath is a path into the templated object's
 	// data that contains a URL. The URL, along with the revision,
 	// represents the output of the Template.
+	// Required unless the lifecycle is tekton, in which case the url
+	// result of the run is read by default.
 	// URLPath is specified in jsonpath format, eg: .status.artifact.url
-	URLPath string `json:"urlPath"`
+	// +optional
+	URLPath string `json:"urlPath,omitempty"`
 
 	// RevisionPath is a path into the templated object's
 	// data that contains a revision. The revision, along with the URL,
 	// represents the output of the Template.
+	// Required unless the lifecycle is tekton, in which case the revision
+	// result of the run is read by default.
 	// RevisionPath is specified in jsonpath format, eg: .status.artifact.revision
-	RevisionPath string `json:"revisionPath"`
+	// +optional
+	RevisionPath string `json:"revisionPath,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -37,6 +37,10 @@ var _ = Describe("ClusterSourceTemplate", func() {
 					Name:      "some-template",
 					Namespace: "default",
 				},
+				Spec: v1alpha1.SourceTemplateSpec{
+					URLPath:      ".status.artifact.url",
+					RevisionPath: ".status.artifact.revision",
+				},
 			}
 		})
 
@@ -64,6 +68,27 @@ var _ = Describe("ClusterSourceTemplate", func() {
 				})
 			})
 
+			Context("template does not set the urlPath", func() {
+				BeforeEach(func() {
+					template.Spec.Template = &runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap"}`)}
+					template.Spec.URLPath = ""
+				})
+
+				It("returns a helpful error", func() {
+					Expect(template.ValidateCreate()).To(MatchError("invalid template: urlPath must be set unless lifecycle is tekton"))
+				})
+
+				Context("and the lifecycle is tekton", func() {
+					BeforeEach(func() {
+						template.Spec.Lifecycle = "tekton"
+					})
+
+					It("succeeds", func() {
+						Expect(template.ValidateCreate()).To(Succeed())
+					})
+				})
+			})
+
 			Context("template sets object namespace", func() {
 				BeforeEach(func() {
 					raw, err := json.Marshal(&ArbitraryObject{
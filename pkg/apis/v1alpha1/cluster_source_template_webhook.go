@@ -25,11 +25,11 @@ import (
 var _ webhook.Validator = &ClusterSourceTemplate{}
 
 func (c *ClusterSourceTemplate) ValidateCreate() error {
-	return c.Spec.TemplateSpec.validate()
+	return c.Spec.validate()
 }
 
 func (c *ClusterSourceTemplate) ValidateUpdate(_ runtime.Object) error {
-	return c.Spec.TemplateSpec.validate()
+	return c.Spec.validate()
 }
 
 func (c *ClusterSourceTemplate) ValidateDelete() error {
@@ -39,5 +39,6 @@ func (c *ClusterSourceTemplate) ValidateDelete() error {
 func (c *ClusterSourceTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
+		WithValidator(NewTemplateExtensionValidator(mgr.GetAPIReader())).
 		Complete()
 }
@@ -53,6 +53,17 @@ type ClusterSupplyChain struct {
 type SupplyChainSpec struct {
 	LegacySelector `json:",inline"`
 
+	// Priority breaks ties when more than one supply chain selects a
+	// workload with equally specific selectors. The supply chain with the
+	// highest priority is chosen. Defaults to 0.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// NamespaceSelector restricts the namespaces whose workloads may select this
+	// supply chain. If not set, workloads in any namespace may select it.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
 	// Resources that are responsible for bringing the application to a
 	// deliverable state.
 	Resources []SupplyChainResource `json:"resources"`
@@ -71,11 +82,21 @@ type SupplyChainSpec struct {
 	// workload's namespace.
 	// +optional
 	ServiceAccountRef ServiceAccountRef `json:"serviceAccountRef,omitempty"`
+
+	// MetadataPolicy specifies the labels and annotations of the owner that are
+	// propagated onto every stamped object.
+	// +optional
+	MetadataPolicy *MetadataPolicy `json:"metadataPolicy,omitempty"`
 }
 
 type SupplyChainStatus struct {
 	Conditions         []metav1.Condition `json:"conditions,omitempty"`
 	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+
+	// Params are the parameters a workload may specify, aggregated from the
+	// templates referenced by the supply chain's resources.
+	// See: https://cartographer.sh/docs/latest/architecture/#parameter-hierarchy
+	Params []AcceptedParam `json:"params,omitempty"`
 }
 
 type SupplyChainResource struct {
@@ -142,6 +163,22 @@ type SupplyChainTemplateReference struct {
 	// Minimum number of items in list is two.
 	// +kubebuilder:validation:MinItems=2
 	Options []TemplateOption `json:"options,omitempty"`
+
+	// Revision pins the template to a ClusterTemplateRevision, identified by the
+	// generation of the template when the revision was created. If unset, the
+	// latest spec of the template is used.
+	// Revisions are only taken of the generations Cartographer observes, so a
+	// generation replaced before it was reconciled cannot be pinned.
+	// Can only be specified with Name.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Revision int64 `json:"revision,omitempty"`
+
+	// Rollout moves a portion of the workloads to another revision of the template,
+	// so that a template change can be rolled out in batches.
+	// Requires Revision.
+	// +optional
+	Rollout *TemplateRollout `json:"rollout,omitempty"`
 }
 
 type FieldSelectorOperator string
@@ -158,6 +195,14 @@ func (c *ClusterSupplyChain) GetSelectors() LegacySelector {
 	return c.Spec.LegacySelector
 }
 
+func (c *ClusterSupplyChain) GetPriority() int32 {
+	return c.Spec.Priority
+}
+
+func (c *ClusterSupplyChain) GetNamespaceSelector() *metav1.LabelSelector {
+	return c.Spec.NamespaceSelector
+}
+
 func init() {
 	SchemeBuilder.Register(
 		&ClusterSupplyChain{},
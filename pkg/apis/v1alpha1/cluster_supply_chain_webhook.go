@@ -24,6 +24,9 @@ import (
 
 // +kubebuilder:webhook:path=/validate-carto-run-v1alpha1-clustersupplychain,mutating=false,failurePolicy=fail,sideEffects=none,admissionReviewVersions=v1beta1;v1,groups=carto.run,resources=clustersupplychains,verbs=create;update,versions=v1alpha1,name=supply-chain-validator.cartographer.com
 
+// The selector overlap warning is served by webhooks.SelectorOverlapWarner.
+// +kubebuilder:webhook:path=/warn-carto-run-v1alpha1-clustersupplychain,mutating=false,failurePolicy=ignore,sideEffects=none,admissionReviewVersions=v1beta1;v1,groups=carto.run,resources=clustersupplychains,verbs=create;update,versions=v1alpha1,name=supply-chain-selector-overlap-warner.cartographer.com
+
 var _ webhook.Validator = &ClusterSupplyChain{}
 
 func (c *ClusterSupplyChain) ValidateCreate() error {
@@ -61,6 +64,14 @@ func (c *ClusterSupplyChain) validateNewState() error {
 		return err
 	}
 
+	if err := validateNamespaceSelector(c.Spec.NamespaceSelector); err != nil {
+		return err
+	}
+
+	if err := c.Spec.MetadataPolicy.validate(); err != nil {
+		return err
+	}
+
 	for _, resource := range c.Spec.Resources {
 		if _, ok := names[resource.Name]; ok {
 			return fmt.Errorf("duplicate resource name [%s] found", resource.Name)
@@ -204,6 +215,10 @@ func validateSupplyChainTemplateRef(ref SupplyChainTemplateReference) error {
 	if err := validateResourceOptions(ref.Options, ValidWorkloadPaths, ValidWorkloadPrefixes); err != nil {
 		return err
 	}
+
+	if err := validateTemplateRevision(ref.Name, ref.Revision, ref.Rollout); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -21,6 +21,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -134,6 +135,133 @@ var _ = Describe("Webhook Validation", func() {
 			})
 		})
 
+		Context("Resource pins a template revision", func() {
+			BeforeEach(func() {
+				supplyChain.Spec.Resources[0].TemplateRef.Revision = 2
+			})
+
+			It("creates without error", func() {
+				Expect(supplyChain.ValidateCreate()).NotTo(HaveOccurred())
+			})
+
+			Context("with a rollout to a newer revision", func() {
+				BeforeEach(func() {
+					supplyChain.Spec.Resources[0].TemplateRef.Rollout = &v1alpha1.TemplateRollout{Revision: 3, Percent: 20}
+				})
+
+				It("creates without error", func() {
+					Expect(supplyChain.ValidateCreate()).NotTo(HaveOccurred())
+				})
+
+				Context("and a percent above 100", func() {
+					BeforeEach(func() {
+						supplyChain.Spec.Resources[0].TemplateRef.Rollout.Percent = 120
+					})
+
+					It("returns an error", func() {
+						Expect(supplyChain.ValidateCreate()).To(MatchError(ContainSubstring(
+							"error validating resource [source-provider]: templateRef.Rollout.Percent must be between 0 and 100",
+						)))
+					})
+				})
+			})
+
+			Context("without a template name", func() {
+				BeforeEach(func() {
+					supplyChain.Spec.Resources[0].TemplateRef.Name = ""
+					supplyChain.Spec.Resources[0].TemplateRef.Options = []v1alpha1.TemplateOption{
+						{Name: "some-template", Selector: v1alpha1.Selector{LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"a": "b"}}}},
+						{Name: "other-template", Selector: v1alpha1.Selector{LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"c": "d"}}}},
+					}
+				})
+
+				It("returns an error", func() {
+					Expect(supplyChain.ValidateCreate()).To(MatchError(ContainSubstring(
+						"error validating resource [source-provider]: templateRef.Revision and templateRef.Rollout may only be specified with templateRef.Name",
+					)))
+				})
+			})
+		})
+
+		Context("Resource rolls out a template revision without pinning one", func() {
+			BeforeEach(func() {
+				supplyChain.Spec.Resources[0].TemplateRef.Rollout = &v1alpha1.TemplateRollout{Revision: 3, Percent: 20}
+			})
+
+			It("returns an error", func() {
+				Expect(supplyChain.ValidateCreate()).To(MatchError(ContainSubstring(
+					"error validating resource [source-provider]: templateRef.Rollout requires templateRef.Revision",
+				)))
+			})
+		})
+
+		Context("Supply chain with a metadata policy", func() {
+			BeforeEach(func() {
+				supplyChain.Spec.MetadataPolicy = &v1alpha1.MetadataPolicy{
+					Labels: v1alpha1.MetadataKeySelector{
+						Keys: []string{"app.kubernetes.io/part-of"},
+					},
+					Annotations: v1alpha1.MetadataKeySelector{
+						Prefixes: []string{"team.example.com/"},
+					},
+				}
+			})
+
+			It("creates without error", func() {
+				Expect(supplyChain.ValidateCreate()).To(Succeed())
+			})
+
+			Context("selecting an invalid key", func() {
+				BeforeEach(func() {
+					supplyChain.Spec.MetadataPolicy.Labels.Keys = []string{"not a key"}
+				})
+
+				It("returns an error", func() {
+					Expect(supplyChain.ValidateCreate()).To(MatchError(ContainSubstring(
+						"invalid metadataPolicy: labels: invalid key [not a key]",
+					)))
+				})
+			})
+
+			Context("selecting an empty prefix", func() {
+				BeforeEach(func() {
+					supplyChain.Spec.MetadataPolicy.Annotations.Prefixes = []string{""}
+				})
+
+				It("returns an error", func() {
+					Expect(supplyChain.ValidateCreate()).To(MatchError(ContainSubstring(
+						"invalid metadataPolicy: annotations: prefixes must not be empty",
+					)))
+				})
+			})
+		})
+
+		Context("Supply chain with a namespace selector", func() {
+			BeforeEach(func() {
+				supplyChain.Spec.NamespaceSelector = &metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "prod"},
+				}
+			})
+
+			It("creates without error", func() {
+				Expect(supplyChain.ValidateCreate()).To(Succeed())
+			})
+
+			Context("that is malformed", func() {
+				BeforeEach(func() {
+					supplyChain.Spec.NamespaceSelector.MatchExpressions = []metav1.LabelSelectorRequirement{
+						{Key: "env", Operator: "Sometimes"},
+					}
+				})
+
+				It("returns an error", func() {
+					Expect(supplyChain.ValidateCreate()).To(MatchError(ContainSubstring(
+						"namespaceSelector is not valid",
+					)))
+				})
+			})
+		})
+
 		Context("SupplyChain with malformed params", func() {
 			Context("Top level params are malformed", func() {
 				Context("param does not specify a value or default", func() {
@@ -188,6 +316,70 @@ var _ = Describe("Webhook Validation", func() {
 						Expect(supplyChain.ValidateDelete()).NotTo(HaveOccurred())
 					})
 				})
+				Context("param specifies valueFrom", func() {
+					var valueFrom *v1alpha1.ParamValueSource
+
+					BeforeEach(func() {
+						valueFrom = &v1alpha1.ParamValueSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "some-secret"},
+								Key:                  "some-key",
+							},
+						}
+					})
+
+					JustBeforeEach(func() {
+						supplyChain.Spec.Params = []v1alpha1.BlueprintParam{
+							{
+								Name:      "some-param",
+								ValueFrom: valueFrom,
+							},
+						}
+					})
+
+					It("accepts the param", func() {
+						Expect(supplyChain.ValidateCreate()).To(Succeed())
+					})
+
+					Context("and a value", func() {
+						JustBeforeEach(func() {
+							supplyChain.Spec.Params[0].Value = &apiextensionsv1.JSON{Raw: []byte(`"some value"`)}
+						})
+
+						It("returns an error", func() {
+							Expect(supplyChain.ValidateCreate()).To(MatchError(
+								"error validating clustersupplychain [responsible-ops---default-params]: param [some-param] is invalid: must not set value or default when valueFrom is set",
+							))
+						})
+					})
+
+					Context("with both a configMapKeyRef and a secretKeyRef", func() {
+						BeforeEach(func() {
+							valueFrom.ConfigMapKeyRef = &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "some-config-map"},
+								Key:                  "some-key",
+							}
+						})
+
+						It("returns an error", func() {
+							Expect(supplyChain.ValidateCreate()).To(MatchError(
+								"error validating clustersupplychain [responsible-ops---default-params]: param [some-param] is invalid: valueFrom must set exactly one of configMapKeyRef and secretKeyRef",
+							))
+						})
+					})
+
+					Context("without a key", func() {
+						BeforeEach(func() {
+							valueFrom.SecretKeyRef.Key = ""
+						})
+
+						It("returns an error", func() {
+							Expect(supplyChain.ValidateCreate()).To(MatchError(
+								"error validating clustersupplychain [responsible-ops---default-params]: param [some-param] is invalid: valueFrom secretKeyRef must specify name and key",
+							))
+						})
+					})
+				})
 			})
 
 			Context("Params of an individual resource are malformed", func() {
@@ -819,6 +1011,64 @@ var _ = Describe("Webhook Validation", func() {
 					Expect(supplyChain.ValidateDelete()).NotTo(HaveOccurred())
 				})
 			})
+			Context("operator is Gt and has a value that is not a number", func() {
+				BeforeEach(func() {
+					supplyChain.Spec.Resources[0].TemplateRef.Options[0].Selector.MatchFields[0] = v1alpha1.FieldSelectorRequirement{
+						Key:      "something",
+						Operator: v1alpha1.FieldSelectorOpGt,
+						Values:   []string{"many"},
+					}
+				})
+
+				It("on create, it rejects the Resource", func() {
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"error validating clustersupplychain [responsible-ops---default-params]: error validating resource [source-provider]: error validating option [source-1] selector: value [many] for operator [Gt] is not a number",
+					))
+				})
+			})
+			Context("operator is Lt and has more than one value", func() {
+				BeforeEach(func() {
+					supplyChain.Spec.Resources[0].TemplateRef.Options[0].Selector.MatchFields[0] = v1alpha1.FieldSelectorRequirement{
+						Key:      "something",
+						Operator: v1alpha1.FieldSelectorOpLt,
+						Values:   []string{"1", "2"},
+					}
+				})
+
+				It("on create, it rejects the Resource", func() {
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"error validating clustersupplychain [responsible-ops---default-params]: error validating resource [source-provider]: error validating option [source-1] selector: must specify exactly one value with operator [Lt]",
+					))
+				})
+			})
+			Context("operator is Matches and has an invalid regular expression", func() {
+				BeforeEach(func() {
+					supplyChain.Spec.Resources[0].TemplateRef.Options[0].Selector.MatchFields[0] = v1alpha1.FieldSelectorRequirement{
+						Key:      "something",
+						Operator: v1alpha1.FieldSelectorOpMatches,
+						Values:   []string{"("},
+					}
+				})
+
+				It("on create, it rejects the Resource", func() {
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"error validating clustersupplychain [responsible-ops---default-params]: error validating resource [source-provider]: error validating option [source-1] selector: value [(] for operator [Matches] is not a valid regular expression: error parsing regexp: missing closing ): `(`",
+					))
+				})
+			})
+			Context("operator is NotMatches and has a valid regular expression", func() {
+				BeforeEach(func() {
+					supplyChain.Spec.Resources[0].TemplateRef.Options[0].Selector.MatchFields[0] = v1alpha1.FieldSelectorRequirement{
+						Key:      "spec.source.git.url",
+						Operator: v1alpha1.FieldSelectorOpNotMatches,
+						Values:   []string{"^https://github.com/acme/"},
+					}
+				})
+
+				It("on create, it does not return an error", func() {
+					Expect(supplyChain.ValidateCreate()).To(Succeed())
+				})
+			})
 			Context("operator is NotIn and does NOT have values", func() {
 				BeforeEach(func() {
 					supplyChain.Spec.Resources[0].TemplateRef.Options[0].Selector.MatchFields[0] = v1alpha1.FieldSelectorRequirement{
@@ -19,8 +19,10 @@
 package v1alpha1
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +kubebuilder:object:root=true
@@ -59,6 +61,19 @@ type TemplateSpec struct {
 	// the owner namespace, the resource will fail to be created.
 	Ytt string `json:"ytt,omitempty"`
 
+	// Extends names a template whose spec this template builds on. The
+	// template or ytt of the extended template is used, modified by Overlay.
+	// Params and HealthRule set on this template replace those of the
+	// extended template. Lifecycle and RetentionPolicy are not inherited.
+	// Template and Ytt must not be set when Extends is set.
+	// +optional
+	Extends *TemplateReference `json:"extends,omitempty"`
+
+	// Overlay modifies the template of the extended template.
+	// Requires Extends, and the extended template to define Template.
+	// +optional
+	Overlay *TemplateOverlay `json:"overlay,omitempty"`
+
 	// Additional parameters.
 	// See: https://cartographer.sh/docs/latest/architecture/#parameter-hierarchy
 	// +optional
@@ -70,20 +85,79 @@ type TemplateSpec struct {
 	// +optional
 	HealthRule *HealthRule `json:"healthRule,omitempty"`
 
+	// ObjectMetadata specifies labels and annotations set on every object
+	// stamped by this template. Labels and annotations present in the template
+	// itself take precedence.
+	// +optional
+	ObjectMetadata *ObjectMetadata `json:"objectMetadata,omitempty"`
+
+	// IgnoreObservedGeneration disables stale status detection for objects
+	// stamped by this template. By default, while an object's
+	// `status.observedGeneration` (or that of a condition used by the health
+	// rule) is behind its `metadata.generation`, its health is Unknown and its
+	// outputs are not read. Set this for resources whose observedGeneration
+	// does not track their generation.
+	// +optional
+	IgnoreObservedGeneration bool `json:"ignoreObservedGeneration,omitempty"`
+
 	// Lifecycle specifies whether template modifications should result in originally
 	// created objects being updated (`mutable`) or in new objects created alongside
 	// original objects (`immutable` or `tekton`).
+	// With `tekton`, the objects are expected to be Tekton PipelineRuns or
+	// TaskRuns: outputs are read from the results of the run unless their
+	// paths are set, in-flight runs are cancelled when a run for newer inputs
+	// is created, and the reason a run failed is reported in the Healthy
+	// condition of the resource.
 	// See: https://cartographer.sh/docs/latest/lifecycle/
 	// +kubebuilder:validation:Enum=mutable;immutable;tekton
 	// +kubebuilder:default="mutable"
 	Lifecycle string `json:"lifecycle,omitempty"`
 
-	// RetentionPolicy specifies how many successful and failed runs should be retained
-	// if the template lifecycle is immutable/tekton.
+	// RetentionPolicy specifies how many successful and failed runs should be retained,
+	// and for how long, if the template lifecycle is immutable/tekton.
 	// Runs older than this (ordered by creation time) will be deleted. Setting higher
 	// values will increase memory footprint.
 	// If unspecified on immutable/tekton, default behavior will == {maxFailedRuns: 10, maxSuccessfulRuns: 10}
 	RetentionPolicy *RetentionPolicy `json:"retentionPolicy,omitempty"`
+
+	// Debounce is how long the inputs of an immutable/tekton template must be
+	// unchanged before a new object is created, e.g. "1m". Without it, every
+	// change of the inputs creates a new object.
+	// +optional
+	Debounce *metav1.Duration `json:"debounce,omitempty"`
+}
+
+// TemplateOverlay modifies the template of an extended template. The
+// StrategicMergePatch is applied first, followed by the JSONPatch.
+type TemplateOverlay struct {
+	// StrategicMergePatch is merged into the template. Objects of kinds
+	// known to Kubernetes are merged using their patch strategies, other
+	// objects are merged as a JSON merge patch (RFC 7386).
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	StrategicMergePatch *runtime.RawExtension `json:"strategicMergePatch,omitempty"`
+
+	// JSONPatch is a list of JSON patch (RFC 6902) operations applied to the template.
+	// +optional
+	JSONPatch []JSONPatchOperation `json:"jsonPatch,omitempty"`
+}
+
+// JSONPatchOperation is a single JSON patch (RFC 6902) operation
+type JSONPatchOperation struct {
+	// Op is the operation to perform.
+	// +kubebuilder:validation:Enum=add;remove;replace;move;copy;test
+	Op string `json:"op"`
+
+	// Path is a JSON pointer to the location in the template to operate on.
+	Path string `json:"path"`
+
+	// From is a JSON pointer to the source location of move and copy operations.
+	// +optional
+	From string `json:"from,omitempty"`
+
+	// Value is the value of add, replace and test operations.
+	// +optional
+	Value *apiextensionsv1.JSON `json:"value,omitempty"`
 }
 
 // HealthRule specifies rubric for determining the health of a resource.
@@ -103,6 +177,141 @@ type HealthRule struct {
 	// to determine healthiness.
 	// +optional
 	MultiMatch *MultiMatchHealthRule `json:"multiMatch,omitempty"`
+
+	// CEL specifies Common Expression Language expressions, evaluated with the
+	// resource bound to `self`, which determine healthiness.
+	// +optional
+	CEL *CELHealthRule `json:"cel,omitempty"`
+
+	// Probe specifies a smoke test, an HTTP request or TCP connection to an address
+	// read from the resource, whose success determines healthiness. Probes run
+	// in the background and are repeated every 30s; until the first completes
+	// health is Unknown. Loopback, link-local, unspecified and multicast
+	// addresses may not be probed.
+	// +optional
+	Probe *ProbeHealthRule `json:"probe,omitempty"`
+
+	// Children specifies child objects of the resource, eg: the pods of a
+	// Deployment, whose conditions are aggregated into the health of the resource.
+	// It may be specified alone or alongside one of the other rules, in which
+	// case the resource is only healthy when both the rule and its children are.
+	// +optional
+	Children *ChildrenHealthRule `json:"children,omitempty"`
+}
+
+const (
+	ChildrenHealthPolicyAll       ChildrenHealthPolicy = "All"
+	ChildrenHealthPolicyAny       ChildrenHealthPolicy = "Any"
+	ChildrenHealthPolicyThreshold ChildrenHealthPolicy = "Threshold"
+)
+
+// +kubebuilder:validation:Enum=All;Any;Threshold
+type ChildrenHealthPolicy string
+
+// ChildrenHealthRule selects the child objects of a resource and how their
+// conditions are aggregated. Child objects are listed in the namespace of the
+// resource with the owner's service account.
+type ChildrenHealthRule struct {
+	// APIVersion of the child objects, eg: `v1`
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the child objects, eg: `Pod`
+	Kind string `json:"kind"`
+
+	// Selector selects the child objects by their labels
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// SelectorPath is a path in the resource to a label selector, or a map of
+	// labels, selecting the child objects, eg: `spec.selector`
+	// +optional
+	SelectorPath string `json:"selectorPath,omitempty"`
+
+	// Owned selects the child objects with an owner reference to the resource
+	// +optional
+	Owned bool `json:"owned,omitempty"`
+
+	// ConditionType names the condition of the child objects which, when True,
+	// indicates a child is healthy. When False it is unhealthy.
+	// +kubebuilder:default="Ready"
+	ConditionType string `json:"conditionType,omitempty"`
+
+	// Policy specifies how many child objects must be healthy for the resource to
+	// be healthy: `All` of them, `Any` of them or `Threshold` (at least MinHealthy).
+	// +kubebuilder:default="All"
+	// +optional
+	Policy ChildrenHealthPolicy `json:"policy,omitempty"`
+
+	// MinHealthy is the number, or percentage (eg: `50%`), of child objects which
+	// must be healthy when Policy is Threshold.
+	// +optional
+	MinHealthy *intstr.IntOrString `json:"minHealthy,omitempty"`
+}
+
+const (
+	ProbeTypeHTTP ProbeType = "HTTP"
+	ProbeTypeTCP  ProbeType = "TCP"
+)
+
+// +kubebuilder:validation:Enum=HTTP;TCP
+type ProbeType string
+
+// ProbeHealthRule probes an address of a delivered application, eg: the URL of
+// a Knative Service. The resource is unhealthy when every attempt fails.
+type ProbeHealthRule struct {
+	// URLPath is a path in the resource to the address to probe, eg: `status.url`.
+	// HTTP probes require a URL; TCP probes accept a URL or `host:port`.
+	URLPath string `json:"urlPath"`
+
+	// Type of the probe. `HTTP` probes GET the URL; `TCP` probes open a
+	// connection to its host and port.
+	// +kubebuilder:default="HTTP"
+	// +optional
+	Type ProbeType `json:"type,omitempty"`
+
+	// ExpectedStatus is the status code of a successful HTTP response.
+	// Defaults to 200.
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	ExpectedStatus int `json:"expectedStatus,omitempty"`
+
+	// BodyRegex is a regular expression which the body of a successful HTTP
+	// response must match.
+	// +optional
+	BodyRegex string `json:"bodyRegex,omitempty"`
+
+	// Retries is the number of times a failed probe is attempted again before
+	// the resource is considered unhealthy.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	Retries int `json:"retries,omitempty"`
+
+	// TimeoutSeconds is the time after which a single attempt fails.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=30
+	// +optional
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+// CELHealthRule is a pair of CEL expressions defining when a resource should be considered healthy or unhealthy
+type CELHealthRule struct {
+	// Healthy is an expression which, when true, indicates that the resource is healthy,
+	// eg: `self.status.readyReplicas == self.spec.replicas`
+	Healthy string `json:"healthy"`
+
+	// Unhealthy is an expression which, when true, indicates that the resource is unhealthy.
+	// It is evaluated before Healthy. When neither is true, healthiness is Unknown.
+	// +optional
+	Unhealthy string `json:"unhealthy,omitempty"`
+
+	// Message is an expression evaluating to a string, used as the message of the
+	// owner's resource condition,
+	// eg: `'ready replicas: ' + string(self.status.readyReplicas)`
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // MultiMatchHealthRule is a pair of HealthMatchRule defining when a resource should be considered healthy or unhealthy
@@ -0,0 +1,188 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +versionName=v1alpha1
+// +groupName=carto.run
+// +kubebuilder:object:generate=true
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	TemplateRevisionKindLabel = "carto.run/template-kind"
+	TemplateRevisionNameLabel = "carto.run/template-name"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=clustertemplaterevisions,scope=Cluster,shortName=ctr
+// +kubebuilder:printcolumn:name="Kind",type="string",JSONPath=".spec.templateRef.kind"
+// +kubebuilder:printcolumn:name="Template",type="string",JSONPath=".spec.templateRef.name"
+// +kubebuilder:printcolumn:name="Revision",type="integer",JSONPath=".spec.revision"
+
+// ClusterTemplateRevision is an immutable snapshot of a template, created by
+// Cartographer each time the spec of the template changes. Blueprint resources
+// may pin a revision with templateRef.revision. Only a limited number of the most
+// recent revisions are kept for each template, along with any revision pinned
+// by a blueprint.
+type ClusterTemplateRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec describes the template revision.
+	Spec TemplateRevisionSpec `json:"spec"`
+}
+
+type TemplateRevisionSpec struct {
+	// TemplateRef identifies the template this is a revision of.
+	TemplateRef TemplateReference `json:"templateRef"`
+
+	// Revision is the generation of the template when the snapshot was taken.
+	// +kubebuilder:validation:Minimum=1
+	Revision int64 `json:"revision"`
+
+	// Template is the effective spec of the template at this revision, with any
+	// extended templates and overlay already applied, so that the revision does
+	// not change when the templates it extended do.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Template runtime.RawExtension `json:"template"`
+}
+
+// TemplateRevisionName returns the name of the ClusterTemplateRevision holding
+// the given revision of a template.
+func TemplateRevisionName(kind, name string, revision int64) string {
+	return fmt.Sprintf("%s-%s-%d", strings.ToLower(kind), name, revision)
+}
+
+// NewTemplateRevision returns a revision snapshotting the current spec of template.
+// The template must be the effective template, with its extension resolved.
+func NewTemplateRevision(template client.Object) (*ClusterTemplateRevision, error) {
+	kind, err := getTemplateKind(template)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateResolved(template); err != nil {
+		return nil, err
+	}
+
+	templateObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert template: %w", err)
+	}
+
+	spec, err := json.Marshal(templateObj["spec"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal template spec: %w", err)
+	}
+
+	return &ClusterTemplateRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: TemplateRevisionName(kind, template.GetName(), template.GetGeneration()),
+			Labels: map[string]string{
+				TemplateRevisionKindLabel: kind,
+				TemplateRevisionNameLabel: template.GetName(),
+			},
+		},
+		Spec: TemplateRevisionSpec{
+			TemplateRef: TemplateReference{
+				Kind: kind,
+				Name: template.GetName(),
+			},
+			Revision: template.GetGeneration(),
+			Template: runtime.RawExtension{Raw: spec},
+		},
+	}, nil
+}
+
+// GetTemplate returns the template as it was at this revision. The generation of
+// the returned template is the revision.
+func (c *ClusterTemplateRevision) GetTemplate() (client.Object, error) {
+	template, err := GetAPITemplate(c.Spec.TemplateRef.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := c.Spec.Template.Raw
+	if len(spec) == 0 {
+		spec = []byte("{}")
+	}
+
+	if err := json.Unmarshal([]byte(fmt.Sprintf(`{"spec":%s}`, spec)), template); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal revision [%d] of template [%s/%s]: %w",
+			c.Spec.Revision, c.Spec.TemplateRef.Kind, c.Spec.TemplateRef.Name, err)
+	}
+
+	if err := validateResolved(template); err != nil {
+		return nil, fmt.Errorf("invalid revision [%d] of template [%s/%s]: %w",
+			c.Spec.Revision, c.Spec.TemplateRef.Kind, c.Spec.TemplateRef.Name, err)
+	}
+
+	template.SetName(c.Spec.TemplateRef.Name)
+	template.SetGeneration(c.Spec.Revision)
+
+	return template, nil
+}
+
+// validateResolved returns an error when the template still extends another
+// template, and so is not the effective template
+func validateResolved(template client.Object) error {
+	spec, err := GetTemplateSpec(template)
+	if err != nil {
+		return err
+	}
+	if spec.Extends != nil || spec.Overlay != nil {
+		return fmt.Errorf("template must be resolved and must not set extends or overlay")
+	}
+	return nil
+}
+
+func getTemplateKind(template client.Object) (string, error) {
+	switch template.(type) {
+	case *ClusterSourceTemplate:
+		return "ClusterSourceTemplate", nil
+	case *ClusterImageTemplate:
+		return "ClusterImageTemplate", nil
+	case *ClusterConfigTemplate:
+		return "ClusterConfigTemplate", nil
+	case *ClusterTemplate:
+		return "ClusterTemplate", nil
+	case *ClusterDeploymentTemplate:
+		return "ClusterDeploymentTemplate", nil
+	}
+	return "", fmt.Errorf("resource is not a known template: %T", template)
+}
+
+// +kubebuilder:object:root=true
+
+type ClusterTemplateRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterTemplateRevision `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&ClusterTemplateRevision{},
+		&ClusterTemplateRevisionList{},
+	)
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+var _ = Describe("ClusterTemplateRevision", func() {
+	var template *v1alpha1.ClusterConfigTemplate
+
+	BeforeEach(func() {
+		template = &v1alpha1.ClusterConfigTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "some-template",
+				Generation: 4,
+			},
+			Spec: v1alpha1.ConfigTemplateSpec{
+				TemplateSpec: v1alpha1.TemplateSpec{
+					Template: &runtime.RawExtension{Raw: []byte(`{"kind":"ConfigMap"}`)},
+				},
+				ConfigPath: ".data",
+			},
+		}
+	})
+
+	Describe("NewTemplateRevision", func() {
+		It("snapshots the template at its current generation", func() {
+			revision, err := v1alpha1.NewTemplateRevision(template)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(revision.Name).To(Equal("clusterconfigtemplate-some-template-4"))
+			Expect(revision.Labels).To(Equal(map[string]string{
+				"carto.run/template-kind": "ClusterConfigTemplate",
+				"carto.run/template-name": "some-template",
+			}))
+			Expect(revision.Spec.TemplateRef).To(Equal(v1alpha1.TemplateReference{Kind: "ClusterConfigTemplate", Name: "some-template"}))
+			Expect(revision.Spec.Revision).To(Equal(int64(4)))
+		})
+
+		It("returns an error for objects that are not templates", func() {
+			_, err := v1alpha1.NewTemplateRevision(&v1alpha1.Workload{})
+			Expect(err).To(MatchError(ContainSubstring("resource is not a known template")))
+		})
+
+		It("returns an error for templates whose extension is not resolved", func() {
+			template.Spec.Extends = &v1alpha1.TemplateReference{Kind: "ClusterConfigTemplate", Name: "some-base"}
+			_, err := v1alpha1.NewTemplateRevision(template)
+			Expect(err).To(MatchError(ContainSubstring("template must be resolved and must not set extends or overlay")))
+		})
+	})
+
+	Describe("GetTemplate", func() {
+		It("returns the template as it was at the revision", func() {
+			revision, err := v1alpha1.NewTemplateRevision(template)
+			Expect(err).NotTo(HaveOccurred())
+
+			template.Spec.ConfigPath = ".spec"
+
+			restored, err := revision.GetTemplate()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored).To(BeAssignableToTypeOf(&v1alpha1.ClusterConfigTemplate{}))
+
+			restoredTemplate := restored.(*v1alpha1.ClusterConfigTemplate)
+			Expect(restoredTemplate.Name).To(Equal("some-template"))
+			Expect(restoredTemplate.Generation).To(Equal(int64(4)))
+			Expect(restoredTemplate.Spec.ConfigPath).To(Equal(".data"))
+			Expect(string(restoredTemplate.Spec.Template.Raw)).To(MatchJSON(`{"kind":"ConfigMap"}`))
+		})
+	})
+
+	Describe("Webhook Validation", func() {
+		var revision *v1alpha1.ClusterTemplateRevision
+
+		BeforeEach(func() {
+			var err error
+			revision, err = v1alpha1.NewTemplateRevision(template)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("creates without error", func() {
+			Expect(revision.ValidateCreate()).To(Succeed())
+		})
+
+		It("rejects a name that does not match the template and revision", func() {
+			revision.Name = "some-other-name"
+			Expect(revision.ValidateCreate()).To(MatchError(
+				"error validating clustertemplaterevision [some-other-name]: name must be [clusterconfigtemplate-some-template-4]",
+			))
+		})
+
+		It("rejects an unknown template kind", func() {
+			revision.Spec.TemplateRef.Kind = "ClusterUnknownTemplate"
+			revision.Name = "clusterunknowntemplate-some-template-4"
+			Expect(revision.ValidateCreate()).To(HaveOccurred())
+		})
+
+		It("rejects a template that extends another", func() {
+			revision.Spec.Template = runtime.RawExtension{Raw: []byte(`{"extends":{"kind":"ClusterConfigTemplate","name":"some-base"},"configPath":".data"}`)}
+			Expect(revision.ValidateCreate()).To(MatchError(ContainSubstring("template must be resolved and must not set extends or overlay")))
+		})
+
+		It("allows metadata updates", func() {
+			updated := revision.DeepCopy()
+			updated.Labels["some"] = "label"
+			Expect(updated.ValidateUpdate(revision)).To(Succeed())
+		})
+
+		It("rejects spec updates", func() {
+			updated := revision.DeepCopy()
+			updated.Spec.Template = runtime.RawExtension{Raw: []byte(`{}`)}
+			Expect(updated.ValidateUpdate(revision)).To(MatchError(
+				"error validating clustertemplaterevision [clusterconfigtemplate-some-template-4]: spec is immutable",
+			))
+		})
+	})
+})
@@ -0,0 +1,66 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// +kubebuilder:webhook:path=/validate-carto-run-v1alpha1-clustertemplaterevision,mutating=false,failurePolicy=fail,sideEffects=none,admissionReviewVersions=v1beta1;v1,groups=carto.run,resources=clustertemplaterevisions,verbs=create;update,versions=v1alpha1,name=template-revision-validator.cartographer.com
+
+var _ webhook.Validator = &ClusterTemplateRevision{}
+
+func (c *ClusterTemplateRevision) ValidateCreate() error {
+	return c.validate()
+}
+
+func (c *ClusterTemplateRevision) ValidateUpdate(old runtime.Object) error {
+	oldRevision, ok := old.(*ClusterTemplateRevision)
+	if !ok {
+		return fmt.Errorf("failed to cast previous object to ClusterTemplateRevision")
+	}
+
+	if !reflect.DeepEqual(c.Spec, oldRevision.Spec) {
+		return fmt.Errorf("error validating clustertemplaterevision [%s]: spec is immutable", c.Name)
+	}
+	return nil
+}
+
+func (c *ClusterTemplateRevision) ValidateDelete() error {
+	return nil
+}
+
+func (c *ClusterTemplateRevision) validate() error {
+	expectedName := TemplateRevisionName(c.Spec.TemplateRef.Kind, c.Spec.TemplateRef.Name, c.Spec.Revision)
+	if c.Name != expectedName {
+		return fmt.Errorf("error validating clustertemplaterevision [%s]: name must be [%s]", c.Name, expectedName)
+	}
+
+	if _, err := c.GetTemplate(); err != nil {
+		return fmt.Errorf("error validating clustertemplaterevision [%s]: %w", c.Name, err)
+	}
+	return nil
+}
+
+func (c *ClusterTemplateRevision) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(c).
+		Complete()
+}
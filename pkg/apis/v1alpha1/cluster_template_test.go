@@ -16,12 +16,15 @@ package v1alpha1_test
 
 import (
 	"encoding/json"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
@@ -87,7 +90,7 @@ var _ = Describe("ClusterTemplate", func() {
 
 				It("returns an error if no types are specified", func() {
 					Expect(template.ValidateCreate()).
-						To(MatchError("invalid health rule: must specify one of alwaysHealthy, singleConditionType or multiMatch, found neither"))
+						To(MatchError("invalid health rule: must specify one of alwaysHealthy, singleConditionType, multiMatch, cel or probe, found neither"))
 				})
 
 				DescribeTable("returns an error if multiple types are specified",
@@ -128,7 +131,7 @@ var _ = Describe("ClusterTemplate", func() {
 							template.Spec.HealthRule.MultiMatch = nil
 						}
 						Expect(template.ValidateCreate()).
-							To(MatchError("invalid health rule: must specify one of alwaysHealthy, singleConditionType or multiMatch, found multiple"))
+							To(MatchError("invalid health rule: must specify one of alwaysHealthy, singleConditionType, multiMatch, cel or probe, found multiple"))
 
 					},
 					Entry("All types", true, true, true),
@@ -222,6 +225,205 @@ var _ = Describe("ClusterTemplate", func() {
 						Expect(template.ValidateCreate()).
 							To(MatchError("invalid multi match health rule: healthy rule has no matchFields or matchConditions"))
 					})
+
+					It("accepts numeric and regular expression operators in matchFields", func() {
+						template.Spec.HealthRule.MultiMatch.Unhealthy.MatchFields[0].FieldSelectorRequirement = v1alpha1.FieldSelectorRequirement{
+							Key:      ".status.failedReplicas",
+							Operator: v1alpha1.FieldSelectorOpGt,
+							Values:   []string{"0"},
+						}
+						Expect(template.ValidateCreate()).To(Succeed())
+					})
+
+					It("returns an error if a matchFields operator has invalid values", func() {
+						template.Spec.HealthRule.MultiMatch.Unhealthy.MatchFields[0].FieldSelectorRequirement = v1alpha1.FieldSelectorRequirement{
+							Key:      ".status.phase",
+							Operator: v1alpha1.FieldSelectorOpMatches,
+							Values:   []string{"(Failed"},
+						}
+						Expect(template.ValidateCreate()).
+							To(MatchError(ContainSubstring("invalid multi match health rule: matchFields key [.status.phase]: value [(Failed] for operator [Matches] is not a valid regular expression")))
+					})
+				})
+
+				It("returns an error if CEL is set alongside another type", func() {
+					template.Spec.HealthRule = &v1alpha1.HealthRule{
+						SingleConditionType: "Ready",
+						CEL:                 &v1alpha1.CELHealthRule{Healthy: "true"},
+					}
+					Expect(template.ValidateCreate()).
+						To(MatchError("invalid health rule: must specify one of alwaysHealthy, singleConditionType, multiMatch, cel or probe, found multiple"))
+				})
+
+				It("succeeds when CEL is set", func() {
+					template.Spec.HealthRule = &v1alpha1.HealthRule{
+						CEL: &v1alpha1.CELHealthRule{
+							Healthy:   "self.status.readyReplicas == self.spec.replicas",
+							Unhealthy: "has(self.status.failed) && self.status.failed > 0",
+							Message:   "'ready replicas: ' + string(self.status.readyReplicas)",
+						},
+					}
+					Expect(template.ValidateCreate()).To(Succeed())
+				})
+
+				Context("Invalid CEL rules", func() {
+					BeforeEach(func() {
+						template.Spec.HealthRule = &v1alpha1.HealthRule{
+							CEL: &v1alpha1.CELHealthRule{
+								Healthy: "self.status.ready",
+							},
+						}
+					})
+
+					It("returns an error if the healthy expression is missing", func() {
+						template.Spec.HealthRule.CEL.Healthy = ""
+						Expect(template.ValidateCreate()).
+							To(MatchError("invalid cel health rule: healthy expression must be specified"))
+					})
+
+					It("returns an error if an expression does not compile", func() {
+						template.Spec.HealthRule.CEL.Unhealthy = "self.status.phase =="
+						Expect(template.ValidateCreate()).
+							To(MatchError(HavePrefix("invalid cel health rule: unhealthy: failed to compile expression [self.status.phase ==]")))
+					})
+
+					It("returns an error if an expression evaluates to the wrong type", func() {
+						template.Spec.HealthRule.CEL.Message = "1 + 2"
+						Expect(template.ValidateCreate()).
+							To(MatchError("invalid cel health rule: message: expression [1 + 2] must evaluate to string, found int"))
+					})
+				})
+
+				It("succeeds when probe is set", func() {
+					template.Spec.HealthRule = &v1alpha1.HealthRule{
+						Probe: &v1alpha1.ProbeHealthRule{
+							URLPath:        "status.url",
+							ExpectedStatus: 204,
+							BodyRegex:      "ok|healthy",
+							Retries:        3,
+							TimeoutSeconds: 5,
+						},
+					}
+					Expect(template.ValidateCreate()).To(Succeed())
+				})
+
+				Context("Invalid probe rules", func() {
+					BeforeEach(func() {
+						template.Spec.HealthRule = &v1alpha1.HealthRule{
+							Probe: &v1alpha1.ProbeHealthRule{URLPath: "status.url"},
+						}
+					})
+
+					It("returns an error if probe is set alongside another type", func() {
+						template.Spec.HealthRule.SingleConditionType = "Ready"
+						Expect(template.ValidateCreate()).
+							To(MatchError("invalid health rule: must specify one of alwaysHealthy, singleConditionType, multiMatch, cel or probe, found multiple"))
+					})
+
+					It("returns an error if the url path is missing", func() {
+						template.Spec.HealthRule.Probe.URLPath = ""
+						Expect(template.ValidateCreate()).
+							To(MatchError("invalid probe health rule: urlPath must be specified"))
+					})
+
+					It("returns an error if the url path is not valid jsonpath", func() {
+						template.Spec.HealthRule.Probe.URLPath = "status.{"
+						Expect(template.ValidateCreate()).
+							To(MatchError(HavePrefix("invalid probe health rule: invalid jsonpath for urlPath [status.{]")))
+					})
+
+					It("returns an error if a TCP probe sets HTTP fields", func() {
+						template.Spec.HealthRule.Probe.Type = v1alpha1.ProbeTypeTCP
+						template.Spec.HealthRule.Probe.BodyRegex = "ok"
+						Expect(template.ValidateCreate()).
+							To(MatchError("invalid probe health rule: expectedStatus and bodyRegex may only be specified for HTTP probes"))
+					})
+
+					It("returns an error if the expected status is not a status code", func() {
+						template.Spec.HealthRule.Probe.ExpectedStatus = 42
+						Expect(template.ValidateCreate()).
+							To(MatchError("invalid probe health rule: expectedStatus [42] is not a valid HTTP status code"))
+					})
+
+					It("returns an error if the body regex does not compile", func() {
+						template.Spec.HealthRule.Probe.BodyRegex = "ok("
+						Expect(template.ValidateCreate()).
+							To(MatchError(HavePrefix("invalid probe health rule: bodyRegex:")))
+					})
+
+					It("returns an error if there are too many retries", func() {
+						template.Spec.HealthRule.Probe.Retries = 11
+						Expect(template.ValidateCreate()).
+							To(MatchError("invalid probe health rule: retries must be between 0 and 10"))
+					})
+				})
+
+				It("succeeds when children is the only rule", func() {
+					template.Spec.HealthRule = &v1alpha1.HealthRule{
+						Children: &v1alpha1.ChildrenHealthRule{APIVersion: "v1", Kind: "Pod", Owned: true},
+					}
+					Expect(template.ValidateCreate()).To(Succeed())
+				})
+
+				It("succeeds when children is set alongside another type", func() {
+					template.Spec.HealthRule = &v1alpha1.HealthRule{
+						SingleConditionType: "Ready",
+						Children:            &v1alpha1.ChildrenHealthRule{APIVersion: "v1", Kind: "Pod", SelectorPath: "spec.selector"},
+					}
+					Expect(template.ValidateCreate()).To(Succeed())
+				})
+
+				Context("Invalid children rules", func() {
+					BeforeEach(func() {
+						template.Spec.HealthRule = &v1alpha1.HealthRule{
+							Children: &v1alpha1.ChildrenHealthRule{APIVersion: "v1", Kind: "Pod", Owned: true},
+						}
+					})
+
+					It("returns an error if the kind is missing", func() {
+						template.Spec.HealthRule.Children.Kind = ""
+						Expect(template.ValidateCreate()).
+							To(MatchError("invalid children health rule: apiVersion and kind must be specified"))
+					})
+
+					It("returns an error if the children are not constrained", func() {
+						template.Spec.HealthRule.Children.Owned = false
+						Expect(template.ValidateCreate()).
+							To(MatchError("invalid children health rule: must specify at least one of selector, selectorPath or owned"))
+					})
+
+					It("returns an error if the selector is invalid", func() {
+						template.Spec.HealthRule.Children.Selector = &metav1.LabelSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Bogus"}},
+						}
+						Expect(template.ValidateCreate()).
+							To(MatchError(HavePrefix("invalid children health rule: selector:")))
+					})
+
+					It("returns an error if the selector path is not valid jsonpath", func() {
+						template.Spec.HealthRule.Children.SelectorPath = "spec.{"
+						Expect(template.ValidateCreate()).
+							To(MatchError(HavePrefix("invalid children health rule: invalid jsonpath for selectorPath [spec.{]")))
+					})
+
+					It("returns an error if a threshold is missing minHealthy", func() {
+						template.Spec.HealthRule.Children.Policy = v1alpha1.ChildrenHealthPolicyThreshold
+						Expect(template.ValidateCreate()).
+							To(MatchError("invalid children health rule: minHealthy must be specified when policy is Threshold"))
+					})
+
+					It("returns an error if minHealthy is set without a threshold", func() {
+						template.Spec.HealthRule.Children.MinHealthy = &intstr.IntOrString{Type: intstr.Int, IntVal: 1}
+						Expect(template.ValidateCreate()).
+							To(MatchError("invalid children health rule: minHealthy may only be specified when policy is Threshold"))
+					})
+
+					It("returns an error if minHealthy is not a percentage", func() {
+						template.Spec.HealthRule.Children.Policy = v1alpha1.ChildrenHealthPolicyThreshold
+						template.Spec.HealthRule.Children.MinHealthy = &intstr.IntOrString{Type: intstr.String, StrVal: "half"}
+						Expect(template.ValidateCreate()).
+							To(MatchError(HavePrefix("invalid children health rule: minHealthy:")))
+					})
 				})
 			})
 
@@ -313,6 +515,29 @@ var _ = Describe("ClusterTemplate", func() {
 						It("does not return an error", func() {
 							Expect(template.ValidateCreate()).To(Succeed())
 						})
+
+						Context("with TTLs", func() {
+							BeforeEach(func() {
+								template.Spec.RetentionPolicy.SuccessfulRunTTL = &metav1.Duration{Duration: 24 * time.Hour}
+								template.Spec.RetentionPolicy.UnresolvedRunTTL = &metav1.Duration{Duration: time.Hour}
+							})
+
+							It("does not return an error", func() {
+								Expect(template.ValidateCreate()).To(Succeed())
+							})
+						})
+
+						Context("with a TTL that is not positive", func() {
+							BeforeEach(func() {
+								template.Spec.RetentionPolicy.FailedRunTTL = &metav1.Duration{Duration: -time.Hour}
+							})
+
+							It("returns a helpful error", func() {
+								err := template.ValidateCreate()
+								Expect(err).To(HaveOccurred())
+								Expect(err).To(MatchError("invalid template: retentionPolicy.failedRunTTL must be positive"))
+							})
+						})
 					})
 
 					Context("a retention policy is not set", func() {
@@ -320,6 +545,27 @@ var _ = Describe("ClusterTemplate", func() {
 							Expect(template.ValidateCreate()).To(Succeed())
 						})
 					})
+
+					Context("a debounce window is set", func() {
+						BeforeEach(func() {
+							template.Spec.Debounce = &metav1.Duration{Duration: time.Minute}
+						})
+
+						It("does not return an error", func() {
+							Expect(template.ValidateCreate()).To(Succeed())
+						})
+					})
+
+					Context("a debounce window that is not positive is set", func() {
+						BeforeEach(func() {
+							template.Spec.Debounce = &metav1.Duration{}
+						})
+
+						It("returns a helpful error", func() {
+							err := template.ValidateCreate()
+							Expect(err).To(MatchError("invalid template: debounce must be positive"))
+						})
+					})
 				})
 
 				Context("is tekton", func() {
@@ -360,6 +606,16 @@ var _ = Describe("ClusterTemplate", func() {
 						})
 					})
 
+					Context("a debounce window is set", func() {
+						BeforeEach(func() {
+							template.Spec.Debounce = &metav1.Duration{Duration: time.Minute}
+						})
+						It("returns a helpful error", func() {
+							err := template.ValidateCreate()
+							Expect(err).To(MatchError("invalid template: if lifecycle is mutable, no debounce may be set"))
+						})
+					})
+
 					Context("a retention policy is not set", func() {
 						It("does not return an error", func() {
 							Expect(template.ValidateCreate()).To(Succeed())
@@ -367,6 +623,148 @@ var _ = Describe("ClusterTemplate", func() {
 					})
 				})
 			})
+			Context("objectMetadata", func() {
+				BeforeEach(func() {
+					template.Spec.Template = &runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap"}`)}
+				})
+
+				Context("labels and annotations are well formed", func() {
+					BeforeEach(func() {
+						template.Spec.ObjectMetadata = &v1alpha1.ObjectMetadata{
+							Labels:      map[string]string{"app.kubernetes.io/part-of": "my-app"},
+							Annotations: map[string]string{"example.com/docs": "https://example.com/docs"},
+						}
+					})
+
+					It("succeeds", func() {
+						Expect(template.ValidateCreate()).To(Succeed())
+					})
+				})
+
+				Context("a label value is invalid", func() {
+					BeforeEach(func() {
+						template.Spec.ObjectMetadata = &v1alpha1.ObjectMetadata{
+							Labels: map[string]string{"docs": "https://example.com/docs"},
+						}
+					})
+
+					It("returns a helpful error", func() {
+						Expect(template.ValidateCreate()).To(MatchError(ContainSubstring(
+							"invalid template: objectMetadata.labels: invalid value for key [docs]",
+						)))
+					})
+				})
+
+				Context("an annotation key is invalid", func() {
+					BeforeEach(func() {
+						template.Spec.ObjectMetadata = &v1alpha1.ObjectMetadata{
+							Annotations: map[string]string{"not a key": "value"},
+						}
+					})
+
+					It("returns a helpful error", func() {
+						Expect(template.ValidateCreate()).To(MatchError(ContainSubstring(
+							"invalid template: objectMetadata.annotations: invalid key [not a key]",
+						)))
+					})
+				})
+			})
+
+			Context("params", func() {
+				BeforeEach(func() {
+					raw, err := json.Marshal(&ArbitraryObject{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "some-kind",
+							APIVersion: "v1",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "some-name",
+						},
+						Spec: ArbitrarySpec{
+							SomeKey: "some-val",
+						},
+					})
+					Expect(err).NotTo(HaveOccurred())
+					template.Spec.Template = &runtime.RawExtension{Raw: raw}
+				})
+
+				It("succeeds when params are well formed", func() {
+					template.Spec.Params = v1alpha1.TemplateParams{
+						{
+							Name:        "replicas",
+							Description: "number of replicas",
+							Required:    true,
+							Schema:      &v1alpha1.ParamSchema{Type: "integer"},
+						},
+						{
+							Name:         "tier",
+							DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"dev"`)},
+							Schema: &v1alpha1.ParamSchema{
+								Type:    "string",
+								Pattern: "^[a-z]+$",
+								Enum:    []apiextensionsv1.JSON{{Raw: []byte(`"dev"`)}, {Raw: []byte(`"prod"`)}},
+							},
+						},
+					}
+					Expect(template.ValidateCreate()).To(Succeed())
+				})
+
+				DescribeTable("returns an error naming the offending param",
+					func(param v1alpha1.TemplateParam, expectedError string) {
+						template.Spec.Params = v1alpha1.TemplateParams{param}
+						Expect(template.ValidateCreate()).To(MatchError(expectedError))
+					},
+					Entry("required with a default",
+						v1alpha1.TemplateParam{
+							Name:         "some-param",
+							Required:     true,
+							DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"value"`)},
+						},
+						"invalid template: param [some-param] is invalid: must not set default when required"),
+					Entry("unknown schema type",
+						v1alpha1.TemplateParam{
+							Name:   "some-param",
+							Schema: &v1alpha1.ParamSchema{Type: "float"},
+						},
+						"invalid template: param [some-param] is invalid: schema type [float] is invalid"),
+					Entry("pattern on a non-string type",
+						v1alpha1.TemplateParam{
+							Name:   "some-param",
+							Schema: &v1alpha1.ParamSchema{Type: "integer", Pattern: "^[0-9]+$"},
+						},
+						"invalid template: param [some-param] is invalid: schema pattern may only be specified for type [string], found [integer]"),
+					Entry("pattern does not compile",
+						v1alpha1.TemplateParam{
+							Name:   "some-param",
+							Schema: &v1alpha1.ParamSchema{Pattern: "(unclosed"},
+						},
+						"invalid template: param [some-param] is invalid: schema pattern [(unclosed] is invalid: error parsing regexp: missing closing ): `(unclosed`"),
+					Entry("enum value of the wrong type",
+						v1alpha1.TemplateParam{
+							Name: "some-param",
+							Schema: &v1alpha1.ParamSchema{
+								Type: "boolean",
+								Enum: []apiextensionsv1.JSON{{Raw: []byte(`"yes"`)}},
+							},
+						},
+						`invalid template: param [some-param] is invalid: schema enum value ["yes"] is invalid: value must be of type [boolean]`),
+					Entry("default does not satisfy the schema",
+						v1alpha1.TemplateParam{
+							Name:         "some-param",
+							DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"three"`)},
+							Schema:       &v1alpha1.ParamSchema{Type: "integer"},
+						},
+						"invalid template: param [some-param] is invalid: default does not satisfy schema: value must be of type [integer]"),
+				)
+
+				It("returns an error when param names are duplicated", func() {
+					template.Spec.Params = v1alpha1.TemplateParams{
+						{Name: "some-param", DefaultValue: apiextensionsv1.JSON{Raw: []byte(`1`)}},
+						{Name: "some-param", DefaultValue: apiextensionsv1.JSON{Raw: []byte(`2`)}},
+					}
+					Expect(template.ValidateCreate()).To(MatchError("invalid template: duplicate param name [some-param] found"))
+				})
+			})
 		})
 
 		Describe("#Update", func() {
@@ -414,7 +812,7 @@ var _ = Describe("ClusterTemplate", func() {
 
 				It("returns an error if no types are specified", func() {
 					Expect(template.ValidateUpdate(nil)).
-						To(MatchError("invalid health rule: must specify one of alwaysHealthy, singleConditionType or multiMatch, found neither"))
+						To(MatchError("invalid health rule: must specify one of alwaysHealthy, singleConditionType, multiMatch, cel or probe, found neither"))
 				})
 
 				DescribeTable("returns an error if multiple types are specified",
@@ -455,7 +853,7 @@ var _ = Describe("ClusterTemplate", func() {
 							template.Spec.HealthRule.MultiMatch = nil
 						}
 						Expect(template.ValidateUpdate(nil)).
-							To(MatchError("invalid health rule: must specify one of alwaysHealthy, singleConditionType or multiMatch, found multiple"))
+							To(MatchError("invalid health rule: must specify one of alwaysHealthy, singleConditionType, multiMatch, cel or probe, found multiple"))
 
 					},
 					Entry("All types", true, true, true),
@@ -39,5 +39,6 @@ func (c *ClusterTemplate) ValidateDelete() error {
 func (c *ClusterTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
+		WithValidator(NewTemplateExtensionValidator(mgr.GetAPIReader())).
 		Complete()
 }
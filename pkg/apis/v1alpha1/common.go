@@ -16,6 +16,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -28,8 +29,18 @@ const (
 	FieldSelectorOpNotIn        FieldSelectorOperator = "NotIn"
 	FieldSelectorOpExists       FieldSelectorOperator = "Exists"
 	FieldSelectorOpDoesNotExist FieldSelectorOperator = "DoesNotExist"
+	FieldSelectorOpGt           FieldSelectorOperator = "Gt"
+	FieldSelectorOpLt           FieldSelectorOperator = "Lt"
+	FieldSelectorOpMatches      FieldSelectorOperator = "Matches"
+	FieldSelectorOpNotMatches   FieldSelectorOperator = "NotMatches"
 )
 
+// RunRequestAnnotation may be set on a Runnable, Workload or Deliverable to
+// request a new run. Objects stamped from immutable templates carry the value
+// of the annotation, so setting it to a new value (e.g. a timestamp) stamps
+// fresh objects even when their content is otherwise unchanged.
+const RunRequestAnnotation = "carto.run/run-request"
+
 type OwnerStatus struct {
 	// ObservedGeneration refers to the metadata.Generation of the spec that resulted in
 	// the current `status`.
@@ -51,7 +62,66 @@ type TemplateParam struct {
 	// DefaultValue of the parameter.
 	// Causes the parameter to be optional; If the Owner or Template
 	// does not specify this parameter, this value is used.
-	DefaultValue apiextensionsv1.JSON `json:"default"`
+	// Must not be set if Required is true.
+	// +optional
+	DefaultValue apiextensionsv1.JSON `json:"default,omitempty"`
+
+	// Description of the parameter. Surfaced in the status of
+	// blueprints using the template.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Required causes stamping to fail unless the Blueprint or Owner
+	// specifies a value for this parameter.
+	// +optional
+	Required bool `json:"required,omitempty"`
+
+	// Schema the value of the parameter must satisfy.
+	// +optional
+	Schema *ParamSchema `json:"schema,omitempty"`
+}
+
+// ParamSchema is the subset of an OpenAPI v3 schema used to validate
+// the value of a template parameter.
+type ParamSchema struct {
+	// Type of the value.
+	// +kubebuilder:validation:Enum=string;number;integer;boolean;object;array
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Enum lists the values the parameter may take.
+	// +optional
+	Enum []apiextensionsv1.JSON `json:"enum,omitempty"`
+
+	// Pattern is a regular expression (RE2 syntax) string values must match.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// AcceptedParam describes a parameter an Owner may specify for a blueprint.
+type AcceptedParam struct {
+	// Name of the parameter.
+	Name string `json:"name"`
+
+	// Description of the parameter, as declared by the template.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Required indicates the Owner must specify the parameter.
+	// +optional
+	Required bool `json:"required,omitempty"`
+
+	// Schema the value of the parameter must satisfy.
+	// +optional
+	Schema *ParamSchema `json:"schema,omitempty"`
+
+	// DefaultValue used when the Owner does not specify the parameter.
+	// +optional
+	DefaultValue *apiextensionsv1.JSON `json:"default,omitempty"`
+
+	// Resources are the names of the blueprint resources whose templates
+	// consume the parameter.
+	Resources []string `json:"resources"`
 }
 
 type OwnerParam struct {
@@ -60,7 +130,13 @@ type OwnerParam struct {
 	Name string `json:"name"`
 
 	// Value of the parameter.
-	Value apiextensionsv1.JSON `json:"value"`
+	// +optional
+	Value apiextensionsv1.JSON `json:"value,omitempty"`
+
+	// ValueFrom sources the value of the parameter from a ConfigMap
+	// or Secret in the owner's namespace. Cannot be used if Value is set.
+	// +optional
+	ValueFrom *ParamValueSource `json:"valueFrom,omitempty"`
 }
 
 type BlueprintParam struct {
@@ -72,6 +148,12 @@ type BlueprintParam struct {
 	// If specified, owner properties are ignored.
 	Value *apiextensionsv1.JSON `json:"value,omitempty"`
 
+	// ValueFrom sources the value of the parameter from a ConfigMap
+	// or Secret in the owner's namespace.
+	// If specified, owner properties are ignored.
+	// +optional
+	ValueFrom *ParamValueSource `json:"valueFrom,omitempty"`
+
 	// DefaultValue of the parameter.
 	// Causes the parameter to be optional; If the Owner does not specify
 	// this parameter, this value is used.
@@ -79,6 +161,16 @@ type BlueprintParam struct {
 }
 
 func (p *BlueprintParam) validate() error {
+	if p.ValueFrom != nil {
+		if p.Value != nil || p.DefaultValue != nil {
+			return fmt.Errorf("param [%s] is invalid: must not set value or default when valueFrom is set", p.Name)
+		}
+		if err := p.ValueFrom.validate(); err != nil {
+			return fmt.Errorf("param [%s] is invalid: %w", p.Name, err)
+		}
+		return nil
+	}
+
 	if p.bothValuesSet() || p.neitherValueSet() {
 		return fmt.Errorf("param [%s] is invalid: must set exactly one of value and default", p.Name)
 	}
@@ -93,6 +185,20 @@ func (p *BlueprintParam) neitherValueSet() bool {
 	return p.DefaultValue == nil && p.Value == nil
 }
 
+// ParamValueSource selects the value of a parameter from a key of a
+// ConfigMap or Secret. Exactly one of ConfigMapKeyRef and SecretKeyRef
+// must be specified. The value is used as a string.
+type ParamValueSource struct {
+	// ConfigMapKeyRef selects a key of a ConfigMap in the owner's namespace.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// SecretKeyRef selects a key of a Secret in the owner's namespace.
+	// Values sourced from Secrets are redacted from output previews and logs.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
 type ResourceReference struct {
 	Name     string `json:"name"`
 	Resource string `json:"resource"`
@@ -140,6 +246,55 @@ type ServiceAccountRef struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// MetadataPolicy selects the labels and annotations of the owner that are
+// propagated onto every object stamped for it.
+type MetadataPolicy struct {
+	// Labels of the owner to propagate onto stamped objects
+	// +optional
+	Labels MetadataKeySelector `json:"labels,omitempty"`
+
+	// Annotations of the owner to propagate onto stamped objects
+	// +optional
+	Annotations MetadataKeySelector `json:"annotations,omitempty"`
+}
+
+type MetadataKeySelector struct {
+	// Keys to propagate, eg: `example.com/cost-center`
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+
+	// Prefixes of the keys to propagate, eg: `team.example.com/`
+	// +optional
+	Prefixes []string `json:"prefixes,omitempty"`
+}
+
+// Matches returns whether key is selected by one of the keys or prefixes
+func (s MetadataKeySelector) Matches(key string) bool {
+	for _, selectedKey := range s.Keys {
+		if key == selectedKey {
+			return true
+		}
+	}
+	for _, prefix := range s.Prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ObjectMetadata holds labels and annotations set on every object stamped
+// from a template.
+type ObjectMetadata struct {
+	// Labels to set on stamped objects
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations to set on stamped objects
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
 func GetAPITemplate(templateKind string) (client.Object, error) {
 	var template client.Object
 
@@ -160,6 +315,21 @@ func GetAPITemplate(templateKind string) (client.Object, error) {
 	return template, nil
 }
 
+// TemplateRollout rolls a revision of a template out to a percentage of the
+// owners. Owners are assigned to the rollout by a stable hash of their namespace
+// and name, so raising Percent only ever adds owners to the rollout.
+type TemplateRollout struct {
+	// Revision of the template to roll out.
+	// +kubebuilder:validation:Minimum=1
+	Revision int64 `json:"revision"`
+
+	// Percent of owners which use Revision. The remaining owners use
+	// the revision specified by the templateRef.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+}
+
 type TemplateOption struct {
 	// Name of the template to apply
 	// Name or PassThrough must be specified
@@ -194,13 +364,19 @@ type FieldSelectorRequirement struct {
 	Key string `json:"key"`
 
 	// Operator represents a key's relationship to a set of values.
-	// Valid operators are In, NotIn, Exists and DoesNotExist.
-	// +kubebuilder:validation:Enum=In;NotIn;Exists;DoesNotExist
+	// Valid operators are In, NotIn, Exists, DoesNotExist, Gt, Lt, Matches
+	// and NotMatches.
+	// Gt and Lt compare the key's value numerically.
+	// Matches and NotMatches compare the key's value with an RE2 regular expression.
+	// +kubebuilder:validation:Enum=In;NotIn;Exists;DoesNotExist;Gt;Lt;Matches;NotMatches
 	Operator FieldSelectorOperator `json:"operator"`
 
 	// Values is an array of string values. If the operator is In or NotIn,
 	// the values array must be non-empty. If the operator is Exists or DoesNotExist,
-	// the values array must be empty.
+	// the values array must be empty. If the operator is Gt or Lt, the values
+	// array must have a single element that is a number. If the operator is
+	// Matches or NotMatches, the values array must have a single element that
+	// is a regular expression.
 	Values []string `json:"values,omitempty"`
 }
 
@@ -222,11 +398,43 @@ type RealizedResource struct {
 	// TemplateRef is a reference to the template used to create the object in StampedRef
 	TemplateRef *corev1.ObjectReference `json:"templateRef,omitempty"`
 
+	// TemplateRevision is the revision of the template in TemplateRef that stamped
+	// the object in StampedRef.
+	// +optional
+	TemplateRevision int64 `json:"templateRevision,omitempty"`
+
+	// BaseTemplateRefs are references to the templates extended, directly or
+	// transitively, by the template in TemplateRef
+	// +optional
+	BaseTemplateRefs []corev1.ObjectReference `json:"baseTemplateRefs,omitempty"`
+
 	// Inputs are references to resources that were used to template the object in StampedRef
 	Inputs []Input `json:"inputs,omitempty"`
 
 	// Outputs are values from the object in StampedRef that can be consumed by other resources
 	Outputs []Output `json:"outputs,omitempty"`
+
+	// RunRequest is the value of the owner's carto.run/run-request annotation
+	// that the object in StampedRef was stamped for.
+	// +optional
+	RunRequest string `json:"runRequest,omitempty"`
+
+	// PendingRun is the run waiting for the inputs of the resource to be
+	// stable for the template's debounce window.
+	// +optional
+	PendingRun *PendingRun `json:"pendingRun,omitempty"`
+}
+
+// PendingRun describes an object that will be stamped from an immutable
+// template once its inputs have stopped changing for the debounce window
+type PendingRun struct {
+	// InputsDigest is a digest of the content of the object that will be
+	// stamped
+	InputsDigest string `json:"inputsDigest"`
+
+	// RunAfter is when the object will be created if its inputs do not
+	// change again
+	RunAfter metav1.Time `json:"runAfter"`
 }
 
 type ResourceStatus struct {
@@ -236,6 +444,30 @@ type ResourceStatus struct {
 	// of type `Ready`, and follows these Kubernetes conventions:
 	// https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#typical-status-properties
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// HealthHistory records the 10 most recent changes to the status of the
+	// resource's Healthy condition, oldest first.
+	// +optional
+	HealthHistory []HealthTransition `json:"healthHistory,omitempty"`
+
+	// Flapping is true when the status of the resource's Healthy condition has
+	// changed between True and False more than 3 times within the last 30
+	// minutes. See the owner's Degraded condition.
+	// +optional
+	Flapping bool `json:"flapping,omitempty"`
+}
+
+// HealthTransition is a change in the status of a resource's Healthy condition
+type HealthTransition struct {
+	// Status of the Healthy condition after the change
+	Status metav1.ConditionStatus `json:"status"`
+
+	// Reason of the Healthy condition after the change
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Time of the change
+	Time metav1.Time `json:"time"`
 }
 
 type Input struct {
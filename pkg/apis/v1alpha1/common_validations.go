@@ -17,12 +17,20 @@ package v1alpha1
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/vmware-tanzu/cartographer/pkg/cel"
 )
 
 func validateResourceOptions(options []TemplateOption, validPaths map[string]bool, validPrefixes []string) error {
@@ -111,17 +119,8 @@ func validateSelector(selector Selector, validPaths map[string]bool, validPrefix
 
 func validateFieldSelectorRequirements(reqs []FieldSelectorRequirement, validPaths map[string]bool, validPrefixes []string) error {
 	for _, req := range reqs {
-		switch req.Operator {
-		case FieldSelectorOpExists, FieldSelectorOpDoesNotExist:
-			if len(req.Values) != 0 {
-				return fmt.Errorf("cannot specify values with operator [%s]", req.Operator)
-			}
-		case FieldSelectorOpIn, FieldSelectorOpNotIn:
-			if len(req.Values) == 0 {
-				return fmt.Errorf("must specify values with operator [%s]", req.Operator)
-			}
-		default:
-			return fmt.Errorf("operator [%s] is invalid", req.Operator)
+		if err := req.validateOperator(); err != nil {
+			return err
 		}
 
 		if !validPath(req.Key, validPaths, validPrefixes) {
@@ -135,6 +134,36 @@ func validateFieldSelectorRequirements(reqs []FieldSelectorRequirement, validPat
 	return nil
 }
 
+func (r *FieldSelectorRequirement) validateOperator() error {
+	switch r.Operator {
+	case FieldSelectorOpExists, FieldSelectorOpDoesNotExist:
+		if len(r.Values) != 0 {
+			return fmt.Errorf("cannot specify values with operator [%s]", r.Operator)
+		}
+	case FieldSelectorOpIn, FieldSelectorOpNotIn:
+		if len(r.Values) == 0 {
+			return fmt.Errorf("must specify values with operator [%s]", r.Operator)
+		}
+	case FieldSelectorOpGt, FieldSelectorOpLt:
+		if len(r.Values) != 1 {
+			return fmt.Errorf("must specify exactly one value with operator [%s]", r.Operator)
+		}
+		if _, err := strconv.ParseFloat(r.Values[0], 64); err != nil {
+			return fmt.Errorf("value [%s] for operator [%s] is not a number", r.Values[0], r.Operator)
+		}
+	case FieldSelectorOpMatches, FieldSelectorOpNotMatches:
+		if len(r.Values) != 1 {
+			return fmt.Errorf("must specify exactly one value with operator [%s]", r.Operator)
+		}
+		if _, err := regexp.Compile(r.Values[0]); err != nil {
+			return fmt.Errorf("value [%s] for operator [%s] is not a valid regular expression: %w", r.Values[0], r.Operator, err)
+		}
+	default:
+		return fmt.Errorf("operator [%s] is invalid", r.Operator)
+	}
+	return nil
+}
+
 func validJsonpath(path string) error {
 	parser := jsonpath.New("")
 
@@ -186,8 +215,26 @@ func validateLegacySelector(selectors LegacySelector, validPaths map[string]bool
 	return nil
 }
 
+func validateNamespaceSelector(namespaceSelector *metav1.LabelSelector) error {
+	if namespaceSelector == nil {
+		return nil
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(namespaceSelector); err != nil {
+		return fmt.Errorf("namespaceSelector is not valid: %w", err)
+	}
+
+	return nil
+}
+
 func (t *TemplateSpec) validate() error {
-	if t.Template == nil && t.Ytt == "" {
+	if t.Extends != nil {
+		if err := t.validateExtends(); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	} else if t.Overlay != nil {
+		return fmt.Errorf("invalid template: overlay may only be specified with extends")
+	} else if t.Template == nil && t.Ytt == "" {
 		return fmt.Errorf("invalid template: must specify one of template or ytt, found neither")
 	}
 	if t.Template != nil && t.Ytt != "" {
@@ -202,6 +249,21 @@ func (t *TemplateSpec) validate() error {
 			return fmt.Errorf("invalid template: template should not set metadata.namespace on the child object")
 		}
 	}
+	if err := t.Params.validate(); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	if err := t.ObjectMetadata.validate(); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	if err := t.RetentionPolicy.validate(); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	if t.Debounce != nil && t.Lifecycle == "mutable" {
+		return fmt.Errorf("invalid template: if lifecycle is mutable, no debounce may be set")
+	}
+	if t.Debounce != nil && t.Debounce.Duration <= 0 {
+		return fmt.Errorf("invalid template: debounce must be positive")
+	}
 	if t.HealthRule != nil {
 		return t.HealthRule.validate()
 	}
@@ -213,6 +275,147 @@ func (t *TemplateSpec) validate() error {
 	return nil
 }
 
+func (p *RetentionPolicy) validate() error {
+	if p == nil {
+		return nil
+	}
+
+	ttls := []struct {
+		name string
+		ttl  *metav1.Duration
+	}{
+		{"successfulRunTTL", p.SuccessfulRunTTL},
+		{"failedRunTTL", p.FailedRunTTL},
+		{"unresolvedRunTTL", p.UnresolvedRunTTL},
+	}
+	for _, ttl := range ttls {
+		if ttl.ttl != nil && ttl.ttl.Duration <= 0 {
+			return fmt.Errorf("retentionPolicy.%s must be positive", ttl.name)
+		}
+	}
+	return nil
+}
+
+func (m *ObjectMetadata) validate() error {
+	if m == nil {
+		return nil
+	}
+
+	for key, value := range m.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("objectMetadata.labels: invalid key [%s]: %s", key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("objectMetadata.labels: invalid value for key [%s]: %s", key, strings.Join(errs, ", "))
+		}
+	}
+
+	for key := range m.Annotations {
+		if errs := validation.IsQualifiedName(strings.ToLower(key)); len(errs) > 0 {
+			return fmt.Errorf("objectMetadata.annotations: invalid key [%s]: %s", key, strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
+
+func (p *MetadataPolicy) validate() error {
+	if p == nil {
+		return nil
+	}
+
+	if err := p.Labels.validate(); err != nil {
+		return fmt.Errorf("invalid metadataPolicy: labels: %w", err)
+	}
+	if err := p.Annotations.validate(); err != nil {
+		return fmt.Errorf("invalid metadataPolicy: annotations: %w", err)
+	}
+
+	return nil
+}
+
+func (s MetadataKeySelector) validate() error {
+	for _, key := range s.Keys {
+		if errs := validation.IsQualifiedName(strings.ToLower(key)); len(errs) > 0 {
+			return fmt.Errorf("invalid key [%s]: %s", key, strings.Join(errs, ", "))
+		}
+	}
+
+	for _, prefix := range s.Prefixes {
+		if prefix == "" {
+			return fmt.Errorf("prefixes must not be empty")
+		}
+	}
+
+	return nil
+}
+
+func (s *SourceTemplateSpec) validate() error {
+	if err := s.TemplateSpec.validate(); err != nil {
+		return err
+	}
+	if err := s.TemplateSpec.validateOutputPath("urlPath", s.URLPath); err != nil {
+		return err
+	}
+	return s.TemplateSpec.validateOutputPath("revisionPath", s.RevisionPath)
+}
+
+func (s *ImageTemplateSpec) validate() error {
+	if err := s.TemplateSpec.validate(); err != nil {
+		return err
+	}
+	return s.TemplateSpec.validateOutputPath("imagePath", s.ImagePath)
+}
+
+func (s *ConfigTemplateSpec) validate() error {
+	if err := s.TemplateSpec.validate(); err != nil {
+		return err
+	}
+	return s.TemplateSpec.validateOutputPath("configPath", s.ConfigPath)
+}
+
+// validateOutputPath requires the path of an output of the template, unless
+// it extends a template that may set it or reads its outputs from the
+// results of Tekton runs
+func (t *TemplateSpec) validateOutputPath(field string, path string) error {
+	if path == "" && t.Extends == nil && t.Lifecycle != "tekton" {
+		return fmt.Errorf("invalid template: %s must be set unless lifecycle is tekton", field)
+	}
+	return nil
+}
+
+func (t *TemplateSpec) validateExtends() error {
+	if t.Template != nil || t.Ytt != "" {
+		return fmt.Errorf("must not specify template or ytt when extends is set")
+	}
+
+	if _, err := GetAPITemplate(t.Extends.Kind); err != nil {
+		return fmt.Errorf("extends: %w", err)
+	}
+
+	if t.Overlay == nil {
+		return nil
+	}
+
+	if t.Overlay.StrategicMergePatch != nil {
+		patch := map[string]interface{}{}
+		if err := json.Unmarshal(t.Overlay.StrategicMergePatch.Raw, &patch); err != nil {
+			return fmt.Errorf("overlay.strategicMergePatch must be an object: %w", err)
+		}
+	}
+
+	for i, operation := range t.Overlay.JSONPatch {
+		if operation.Path == "" {
+			return fmt.Errorf("overlay.jsonPatch[%d]: path must be specified", i)
+		}
+		if (operation.Op == "move" || operation.Op == "copy") && operation.From == "" {
+			return fmt.Errorf("overlay.jsonPatch[%d]: from must be specified for %s", i, operation.Op)
+		}
+	}
+
+	return nil
+}
+
 func (r *HealthRule) validate() error {
 	nRules := 0
 	if r.AlwaysHealthy != nil {
@@ -224,15 +427,35 @@ func (r *HealthRule) validate() error {
 	if r.MultiMatch != nil {
 		nRules++
 	}
+	if r.CEL != nil {
+		nRules++
+	}
+	if r.Probe != nil {
+		nRules++
+	}
+	if r.Children != nil {
+		if err := r.Children.validate(); err != nil {
+			return err
+		}
+		if nRules == 0 {
+			return nil
+		}
+	}
 	if nRules == 0 {
-		return fmt.Errorf("invalid health rule: must specify one of alwaysHealthy, singleConditionType or multiMatch, found neither")
+		return fmt.Errorf("invalid health rule: must specify one of alwaysHealthy, singleConditionType, multiMatch, cel or probe, found neither")
 	}
 	if nRules > 1 {
-		return fmt.Errorf("invalid health rule: must specify one of alwaysHealthy, singleConditionType or multiMatch, found multiple")
+		return fmt.Errorf("invalid health rule: must specify one of alwaysHealthy, singleConditionType, multiMatch, cel or probe, found multiple")
 	}
 	if r.MultiMatch != nil {
 		return r.MultiMatch.validate()
 	}
+	if r.CEL != nil {
+		return r.CEL.validate()
+	}
+	if r.Probe != nil {
+		return r.Probe.validate()
+	}
 	return nil
 }
 
@@ -243,5 +466,264 @@ func (m *MultiMatchHealthRule) validate() error {
 	if len(m.Healthy.MatchConditions) == 0 && len(m.Healthy.MatchFields) == 0 {
 		return fmt.Errorf("invalid multi match health rule: healthy rule has no matchFields or matchConditions")
 	}
+	for _, matchFields := range [][]HealthMatchFieldSelectorRequirement{m.Healthy.MatchFields, m.Unhealthy.MatchFields} {
+		for _, matchField := range matchFields {
+			if err := matchField.validateOperator(); err != nil {
+				return fmt.Errorf("invalid multi match health rule: matchFields key [%s]: %w", matchField.Key, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *ChildrenHealthRule) validate() error {
+	if c.APIVersion == "" || c.Kind == "" {
+		return fmt.Errorf("invalid children health rule: apiVersion and kind must be specified")
+	}
+	if c.Selector == nil && c.SelectorPath == "" && !c.Owned {
+		return fmt.Errorf("invalid children health rule: must specify at least one of selector, selectorPath or owned")
+	}
+	if c.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(c.Selector); err != nil {
+			return fmt.Errorf("invalid children health rule: selector: %w", err)
+		}
+	}
+	if c.SelectorPath != "" {
+		if err := validJsonpath(c.SelectorPath); err != nil {
+			return fmt.Errorf("invalid children health rule: invalid jsonpath for selectorPath [%s]: %w", c.SelectorPath, err)
+		}
+	}
+	if c.Policy == ChildrenHealthPolicyThreshold {
+		if c.MinHealthy == nil {
+			return fmt.Errorf("invalid children health rule: minHealthy must be specified when policy is Threshold")
+		}
+		if _, err := intstr.GetScaledValueFromIntOrPercent(c.MinHealthy, 100, true); err != nil {
+			return fmt.Errorf("invalid children health rule: minHealthy: %w", err)
+		}
+	} else if c.MinHealthy != nil {
+		return fmt.Errorf("invalid children health rule: minHealthy may only be specified when policy is Threshold")
+	}
+	return nil
+}
+
+func (p *ProbeHealthRule) validate() error {
+	if p.URLPath == "" {
+		return fmt.Errorf("invalid probe health rule: urlPath must be specified")
+	}
+	if err := validJsonpath(p.URLPath); err != nil {
+		return fmt.Errorf("invalid probe health rule: invalid jsonpath for urlPath [%s]: %w", p.URLPath, err)
+	}
+	if p.Type == ProbeTypeTCP && (p.ExpectedStatus != 0 || p.BodyRegex != "") {
+		return fmt.Errorf("invalid probe health rule: expectedStatus and bodyRegex may only be specified for HTTP probes")
+	}
+	if p.ExpectedStatus != 0 && (p.ExpectedStatus < 100 || p.ExpectedStatus > 599) {
+		return fmt.Errorf("invalid probe health rule: expectedStatus [%d] is not a valid HTTP status code", p.ExpectedStatus)
+	}
+	if p.BodyRegex != "" {
+		if _, err := regexp.Compile(p.BodyRegex); err != nil {
+			return fmt.Errorf("invalid probe health rule: bodyRegex: %w", err)
+		}
+	}
+	if p.Retries < 0 || p.Retries > 10 {
+		return fmt.Errorf("invalid probe health rule: retries must be between 0 and 10")
+	}
+	if p.TimeoutSeconds < 0 || p.TimeoutSeconds > 30 {
+		return fmt.Errorf("invalid probe health rule: timeoutSeconds must be between 1 and 30")
+	}
+	return nil
+}
+
+func (c *CELHealthRule) validate() error {
+	if c.Healthy == "" {
+		return fmt.Errorf("invalid cel health rule: healthy expression must be specified")
+	}
+	if err := cel.CheckBool(c.Healthy); err != nil {
+		return fmt.Errorf("invalid cel health rule: healthy: %w", err)
+	}
+	if c.Unhealthy != "" {
+		if err := cel.CheckBool(c.Unhealthy); err != nil {
+			return fmt.Errorf("invalid cel health rule: unhealthy: %w", err)
+		}
+	}
+	if c.Message != "" {
+		if err := cel.CheckString(c.Message); err != nil {
+			return fmt.Errorf("invalid cel health rule: message: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *ParamValueSource) validate() error {
+	if (s.ConfigMapKeyRef == nil) == (s.SecretKeyRef == nil) {
+		return fmt.Errorf("valueFrom must set exactly one of configMapKeyRef and secretKeyRef")
+	}
+	if s.ConfigMapKeyRef != nil && (s.ConfigMapKeyRef.Name == "" || s.ConfigMapKeyRef.Key == "") {
+		return fmt.Errorf("valueFrom configMapKeyRef must specify name and key")
+	}
+	if s.SecretKeyRef != nil && (s.SecretKeyRef.Name == "" || s.SecretKeyRef.Key == "") {
+		return fmt.Errorf("valueFrom secretKeyRef must specify name and key")
+	}
+	return nil
+}
+
+func (p TemplateParams) validate() error {
+	names := make(map[string]bool)
+	for _, param := range p {
+		if names[param.Name] {
+			return fmt.Errorf("duplicate param name [%s] found", param.Name)
+		}
+		names[param.Name] = true
+
+		if err := param.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *TemplateParam) validate() error {
+	if p.Required && len(p.DefaultValue.Raw) != 0 {
+		return fmt.Errorf("param [%s] is invalid: must not set default when required", p.Name)
+	}
+	if p.Schema == nil {
+		return nil
+	}
+	if err := p.Schema.validate(); err != nil {
+		return fmt.Errorf("param [%s] is invalid: %w", p.Name, err)
+	}
+	if len(p.DefaultValue.Raw) != 0 {
+		if err := p.Schema.Validate(p.DefaultValue); err != nil {
+			return fmt.Errorf("param [%s] is invalid: default does not satisfy schema: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+func (s *ParamSchema) validate() error {
+	switch s.Type {
+	case "", "string", "number", "integer", "boolean", "object", "array":
+	default:
+		return fmt.Errorf("schema type [%s] is invalid", s.Type)
+	}
+
+	if s.Pattern != "" {
+		if s.Type != "" && s.Type != "string" {
+			return fmt.Errorf("schema pattern may only be specified for type [string], found [%s]", s.Type)
+		}
+		if _, err := regexp.Compile(s.Pattern); err != nil {
+			return fmt.Errorf("schema pattern [%s] is invalid: %w", s.Pattern, err)
+		}
+	}
+
+	for _, enumValue := range s.Enum {
+		var value interface{}
+		if err := json.Unmarshal(enumValue.Raw, &value); err != nil {
+			return fmt.Errorf("schema enum value [%s] is not valid json: %w", string(enumValue.Raw), err)
+		}
+		if err := s.validateType(value); err != nil {
+			return fmt.Errorf("schema enum value [%s] is invalid: %w", string(enumValue.Raw), err)
+		}
+	}
+
+	return nil
+}
+
+// Validate returns an error describing the first way in which value fails to
+// satisfy the schema, or nil if it satisfies the schema.
+func (s *ParamSchema) Validate(value apiextensionsv1.JSON) error {
+	var decoded interface{}
+	if err := json.Unmarshal(value.Raw, &decoded); err != nil {
+		return fmt.Errorf("value is not valid json: %w", err)
+	}
+
+	if err := s.validateType(decoded); err != nil {
+		return err
+	}
+
+	if len(s.Enum) != 0 {
+		var allowed []string
+		for _, enumValue := range s.Enum {
+			var decodedEnumValue interface{}
+			if err := json.Unmarshal(enumValue.Raw, &decodedEnumValue); err == nil && reflect.DeepEqual(decoded, decodedEnumValue) {
+				return nil
+			}
+			allowed = append(allowed, string(enumValue.Raw))
+		}
+		return fmt.Errorf("value %s is not one of [%s]", string(value.Raw), strings.Join(allowed, ", "))
+	}
+
+	if s.Pattern != "" {
+		str, ok := decoded.(string)
+		if !ok {
+			return fmt.Errorf("value %s must be a string to match pattern [%s]", string(value.Raw), s.Pattern)
+		}
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("schema pattern [%s] is invalid: %w", s.Pattern, err)
+		}
+		if !pattern.MatchString(str) {
+			return fmt.Errorf("value %s does not match pattern [%s]", string(value.Raw), s.Pattern)
+		}
+	}
+
+	return nil
+}
+
+func (s *ParamSchema) validateType(value interface{}) error {
+	var ok bool
+	switch s.Type {
+	case "":
+		return nil
+	case "string":
+		_, ok = value.(string)
+	case "number":
+		_, ok = value.(float64)
+	case "integer":
+		var number float64
+		number, ok = value.(float64)
+		ok = ok && number == math.Trunc(number)
+	case "boolean":
+		_, ok = value.(bool)
+	case "object":
+		_, ok = value.(map[string]interface{})
+	case "array":
+		_, ok = value.([]interface{})
+	}
+	if !ok {
+		return fmt.Errorf("value must be of type [%s]", s.Type)
+	}
+	return nil
+}
+
+// validateTemplateRevision validates the revision and rollout of a templateRef
+func validateTemplateRevision(name string, revision int64, rollout *TemplateRollout) error {
+	if revision == 0 && rollout == nil {
+		return nil
+	}
+
+	if name == "" {
+		return fmt.Errorf("templateRef.Revision and templateRef.Rollout may only be specified with templateRef.Name")
+	}
+
+	if revision < 0 {
+		return fmt.Errorf("templateRef.Revision must be greater than zero")
+	}
+
+	if rollout == nil {
+		return nil
+	}
+
+	if revision == 0 {
+		return fmt.Errorf("templateRef.Rollout requires templateRef.Revision")
+	}
+
+	if rollout.Revision < 1 {
+		return fmt.Errorf("templateRef.Rollout.Revision must be greater than zero")
+	}
+
+	if rollout.Percent < 0 || rollout.Percent > 100 {
+		return fmt.Errorf("templateRef.Rollout.Percent must be between 0 and 100")
+	}
+
 	return nil
 }
@@ -30,6 +30,14 @@ const (
 	WorkloadSupplyChainReady = "SupplyChainReady"
 	DeliverableDeliveryReady = "DeliveryReady"
 	OwnerResourcesSubmitted  = "ResourcesSubmitted"
+	OwnerDegraded            = "Degraded"
+)
+
+// -- OWNER ConditionType - Degraded ConditionReasons
+
+const (
+	ResourcesFlappingDegradedReason = "ResourcesFlapping"
+	ResourcesStableDegradedReason   = "ResourcesStable"
 )
 
 // -- OWNER ConditionType - SupplyChainReady ConditionReasons
@@ -39,6 +47,7 @@ const (
 	WorkloadLabelsMissingSupplyChainReason = "WorkloadLabelsMissing"
 	NotFoundSupplyChainReadyReason         = "SupplyChainNotFound"
 	MultipleMatchesSupplyChainReadyReason  = "MultipleSupplyChainMatches"
+	NamespaceNotAllowedSupplyChainReason   = "NamespaceNotAllowed"
 )
 
 // -- OWNER ConditionType - DeliveryReady ConditionReasons
@@ -48,6 +57,7 @@ const (
 	DeliverableLabelsMissingDeliveryReason = "DeliverableLabelsMissing"
 	NotFoundDeliveryReadyReason            = "DeliveryNotFound"
 	MultipleMatchesDeliveryReadyReason     = "MultipleDeliveryMatches"
+	NamespaceNotAllowedDeliveryReason      = "NamespaceNotAllowed"
 )
 
 // -- RESOURCE ConditionType - ResourceSubmitted ConditionReasons &&
@@ -59,9 +69,12 @@ const (
 	MissingValueAtPathResourcesSubmittedReason             = "MissingValueAtPath"
 	TemplateStampFailureResourcesSubmittedReason           = "TemplateStampFailure"
 	TemplateRejectedByAPIServerResourcesSubmittedReason    = "TemplateRejectedByAPIServer"
+	TemplateRejectedBySchemaResourcesSubmittedReason       = "TemplateRejectedBySchema"
 	UnknownErrorResourcesSubmittedReason                   = "UnknownError"
 	ResolveTemplateOptionsErrorResourcesSubmittedReason    = "ResolveTemplateOptionsError"
 	TemplateOptionsMatchErrorResourcesSubmittedReason      = "TemplateOptionsMatchError"
+	InvalidParamResourcesSubmittedReason                   = "InvalidParam"
+	StaleStatusResourcesSubmittedReason                    = "StaleStatus"
 	PassThroughReason                                      = "PassThrough"
 )
 
@@ -133,6 +146,7 @@ const (
 	OutputNotAvailableResourcesHealthyReason = "OutputNotAvailable"
 	NoStampedObjectHealthyReason             = "NoStampedObject"
 	NoMatchesFulfilledReason                 = "NoMatchesFulfilled"
+	StaleStatusHealthyReason                 = "StaleStatus"
 )
 
 // -- BLUEPRINT ConditionType - ResourcesHealthy MultiMatch ConditionReasons
@@ -142,6 +156,32 @@ const (
 	MultiMatchFieldHealthyReason     = "MatchedField"
 )
 
+// -- BLUEPRINT ConditionType - ResourcesHealthy CEL ConditionReasons
+
+const (
+	CELHealthyExpressionHealthyReason   = "MatchedHealthyExpression"
+	CELUnhealthyExpressionHealthyReason = "MatchedUnhealthyExpression"
+	CELEvaluationErrorHealthyReason     = "ExpressionEvaluationError"
+)
+
+// -- BLUEPRINT ConditionType - ResourcesHealthy Probe ConditionReasons
+
+const (
+	ProbeSucceededHealthyReason   = "ProbeSucceeded"
+	ProbeFailedHealthyReason      = "ProbeFailed"
+	ProbeURLNotFoundHealthyReason = "ProbeURLNotFound"
+	ProbePendingHealthyReason     = "ProbePending"
+)
+
+// -- BLUEPRINT ConditionType - ResourcesHealthy Children ConditionReasons
+
+const (
+	ChildrenHealthyReason    = "ChildrenHealthy"
+	ChildrenUnhealthyReason  = "ChildrenUnhealthy"
+	NoChildrenReason         = "NoChildren"
+	ChildrenListFailedReason = "ChildrenListFailed"
+)
+
 // -----------------------------------------
 // -- RUNNABLE.STATUS.CONDITIONS --
 // ConditionTypes
@@ -168,6 +208,8 @@ const (
 	SetOfImmutableStampedObjectsIncludesNoHealthyObjectReason = "SetOfImmutableStampedObjectsIncludesNoHealthyObject"
 	UnknownErrorReason                                        = "UnknownError"
 	ClientBuilderErrorResourcesSubmittedReason                = "ClientBuilderError"
+	InvalidScheduleRunTemplateReason                          = "InvalidSchedule"
 	SucceededStampedObjectConditionReason                     = "SucceededCondition"
+	HealthRuleStampedObjectConditionReason                    = "HealthRule"
 	UnknownStampedObjectConditionReason                       = "Unknown"
 )
@@ -53,6 +53,63 @@ type RunnableStatus struct {
 	// will never display an output
 	// +optional
 	Outputs map[string]apiextensionsv1.JSON `json:"outputs,omitempty"`
+
+	// LastScheduleTime is the most recent schedule tick for which a run
+	// was created. Only set when spec.schedule is specified.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextScheduleTime is the next schedule tick at which a run will be
+	// created. Only set when spec.schedule is specified.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// RunRequest is the value of the runnable's carto.run/run-request
+	// annotation that the most recently stamped object was created for.
+	// +optional
+	RunRequest string `json:"runRequest,omitempty"`
+
+	// PendingRun is the run waiting for the runnable's inputs to be stable
+	// for the debounce window.
+	// +optional
+	PendingRun *PendingRun `json:"pendingRun,omitempty"`
+
+	// RunHistory describes the objects stamped by the runnable that have not
+	// been garbage collected, most recent first. It holds at most
+	// maxSuccessfulRuns + maxFailedRuns records.
+	// +optional
+	RunHistory []RunRecord `json:"runHistory,omitempty"`
+}
+
+// RunRecord describes an object stamped by a runnable
+type RunRecord struct {
+	// StampedRef is a reference to the stamped object
+	StampedRef *StampedRef `json:"stampedRef"`
+
+	// InputsDigest is a sha256 of the stamped content of the object, which
+	// covers the runnable's inputs, selected objects, schedule tick and run
+	// request. Empty if the object was not stamped by this version of
+	// Cartographer.
+	// +optional
+	InputsDigest string `json:"inputsDigest,omitempty"`
+
+	// StartTime is the creation time of the stamped object
+	StartTime metav1.Time `json:"startTime"`
+
+	// FinishTime is the last transition time of the conditions read by the
+	// health rule once the health of the object is True or False, or when
+	// that health was first observed if the object has no such condition
+	// +optional
+	FinishTime *metav1.Time `json:"finishTime,omitempty"`
+
+	// Health of the object according to the health rule of the run template:
+	// True when it succeeded, False when it failed and Unknown while it runs
+	Health metav1.ConditionStatus `json:"health"`
+
+	// OutputsDigest is a sha256 of the outputs read from the object, once it
+	// has succeeded
+	// +optional
+	OutputsDigest string `json:"outputsDigest,omitempty"`
 }
 
 type RunnableSpec struct {
@@ -78,11 +135,67 @@ type RunnableSpec struct {
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 
-	// RetentionPolicy specifies how many successful and failed runs should be retained.
+	// RetentionPolicy specifies how many successful and failed runs should be retained,
+	// and for how long.
 	// Runs older than this (ordered by creation time) will be deleted. Setting higher
 	// values will increase memory footprint.
 	// +kubebuilder:default={maxFailedRuns: 10, maxSuccessfulRuns: 10}
 	RetentionPolicy RetentionPolicy `json:"retentionPolicy,omitempty"`
+
+	// ConcurrencyPolicy specifies how a new run is treated while an earlier
+	// run is still in flight, i.e. its health is neither True nor False.
+	// Valid values are:
+	// - "Allow" (default): runs are created regardless of in-flight runs;
+	// - "Forbid": no run is created while another is in flight. The run
+	//   for the latest inputs is created once the in-flight run completes;
+	// - "Replace": in-flight runs are cancelled when a new run is created,
+	//   using the cancelPatch of the ClusterRunTemplate, or by deleting them
+	//   if the template has no cancelPatch. The service account must be
+	//   permitted to patch (or delete) the objects.
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// +kubebuilder:default=Allow
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Debounce is how long the runnable's inputs must be unchanged before a
+	// new run is created, e.g. "1m". Without it, every change of the inputs
+	// creates a new run.
+	// +optional
+	Debounce *metav1.Duration `json:"debounce,omitempty"`
+
+	// Schedule, when specified, creates a new run at each tick of the
+	// schedule, even if the stamped object has not changed.
+	// +optional
+	Schedule *RunnableSchedule `json:"schedule,omitempty"`
+}
+
+type ConcurrencyPolicy string
+
+const (
+	AllowConcurrent   ConcurrencyPolicy = "Allow"
+	ForbidConcurrent  ConcurrencyPolicy = "Forbid"
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+type RunnableSchedule struct {
+	// Cron is the schedule in Cron format, e.g. "0 2 * * *".
+	// See https://en.wikipedia.org/wiki/Cron.
+	// +kubebuilder:validation:MinLength=1
+	Cron string `json:"cron"`
+
+	// TimeZone is the name of the time zone the schedule is evaluated in,
+	// e.g. "Europe/London". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// StartingDeadlineSeconds is how late a run may be created after its
+	// scheduled tick, e.g. because the controller was unavailable. Ticks
+	// missed by more than this are skipped. When several ticks have been
+	// missed, only a single run is created for the most recent one.
+	// If not set, missed ticks have no deadline.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
 }
 
 type RetentionPolicy struct {
@@ -92,6 +205,29 @@ type RetentionPolicy struct {
 	// MaxSuccessfulRuns is the number of successful runs to retain.
 	// +kubebuilder:validation:Minimum:=1
 	MaxSuccessfulRuns int64 `json:"maxSuccessfulRuns"`
+
+	// SuccessfulRunTTL is how long successful runs are retained after they
+	// complete, e.g. "24h". The most recent successful run is always retained.
+	// A run completes when its conditions last transitioned.
+	// +optional
+	SuccessfulRunTTL *metav1.Duration `json:"successfulRunTTL,omitempty"`
+
+	// FailedRunTTL is how long failed runs are retained after they complete.
+	// A failed run of the current inputs is retained until the inputs change,
+	// rather than being run again.
+	// +optional
+	FailedRunTTL *metav1.Duration `json:"failedRunTTL,omitempty"`
+
+	// UnresolvedRunTTL is how long runs whose health has not resolved to
+	// succeeded or failed are retained after they are created. The run of the
+	// current inputs is retained until they change.
+	// +optional
+	UnresolvedRunTTL *metav1.Duration `json:"unresolvedRunTTL,omitempty"`
+
+	// DryRun logs the runs that the policy would delete instead of deleting
+	// them.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 type ResourceSelector struct {
@@ -101,8 +237,40 @@ type ResourceSelector struct {
 	// MatchingLabels must match on a single target object, making the object
 	// available in the template as $(selected)$
 	MatchingLabels map[string]string `json:"matchingLabels"`
+
+	// MatchFields further restricts the objects matched by MatchingLabels.
+	// Keys are JSON paths in the candidate object, e.g. "data.suite"
+	// +optional
+	MatchFields []FieldSelectorRequirement `json:"matchFields,omitempty"`
+
+	// Mode determines how matched objects are made available in the
+	// template. Single requires exactly one object to match and makes it
+	// available as $(selected)$. List makes all matched objects available as
+	// a list sorted by namespace and name, e.g. $(selected[0].data)$. Map
+	// makes all matched objects available keyed by name, e.g.
+	// $(selected.my-config.data)$.
+	// With List and Map, a change to any matched object, other than to its
+	// status, results in a new run.
+	// +kubebuilder:validation:Enum=Single;List;Map
+	// +kubebuilder:default=Single
+	// +optional
+	Mode SelectionMode `json:"mode,omitempty"`
+
+	// MaxSelected is the maximum number of objects that may be selected in
+	// List or Map mode. Selection fails if more objects match.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxSelected *int64 `json:"maxSelected,omitempty"`
 }
 
+type SelectionMode string
+
+const (
+	SingleSelection SelectionMode = "Single"
+	ListSelection   SelectionMode = "List"
+	MapSelection    SelectionMode = "Map"
+)
+
 type ResourceType struct {
 	APIVersion string `json:"apiVersion,omitempty"`
 	Kind       string `json:"kind,omitempty"`
@@ -0,0 +1,191 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// TemplateBasesAnnotation is set on templates resolved from an extends
+	// chain. It lists the extended templates, nearest first.
+	TemplateBasesAnnotation = "carto.run/template-bases"
+
+	// MaxTemplateExtensionDepth is the longest chain of extended templates allowed
+	MaxTemplateExtensionDepth = 10
+)
+
+// GetTemplateSpec returns the TemplateSpec of a ClusterSourceTemplate,
+// ClusterImageTemplate, ClusterConfigTemplate, ClusterTemplate or
+// ClusterDeploymentTemplate
+func GetTemplateSpec(template client.Object) (*TemplateSpec, error) {
+	switch v := template.(type) {
+	case *ClusterSourceTemplate:
+		return &v.Spec.TemplateSpec, nil
+	case *ClusterImageTemplate:
+		return &v.Spec.TemplateSpec, nil
+	case *ClusterConfigTemplate:
+		return &v.Spec.TemplateSpec, nil
+	case *ClusterTemplate:
+		return &v.Spec, nil
+	case *ClusterDeploymentTemplate:
+		return &v.Spec.TemplateSpec, nil
+	}
+	return nil, fmt.Errorf("resource is not a known template: %T", template)
+}
+
+// GetTemplateBases returns the templates extended by template, as recorded
+// in the TemplateBasesAnnotation
+func GetTemplateBases(template client.Object) ([]TemplateReference, error) {
+	value, ok := template.GetAnnotations()[TemplateBasesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var bases []TemplateReference
+	if err := json.Unmarshal([]byte(value), &bases); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal annotation [%s]: %w", TemplateBasesAnnotation, err)
+	}
+	return bases, nil
+}
+
+// SetTemplateBases records the templates extended by template in the
+// TemplateBasesAnnotation
+func SetTemplateBases(template client.Object, bases []TemplateReference) error {
+	value, err := json.Marshal(bases)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template bases: %w", err)
+	}
+
+	annotations := template.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[TemplateBasesAnnotation] = string(value)
+	template.SetAnnotations(annotations)
+	return nil
+}
+
+// templateExtensionValidator runs the validations of a template and ensures
+// that its extends chain does not form a cycle
+type templateExtensionValidator struct {
+	reader client.Reader
+}
+
+var _ admission.CustomValidator = &templateExtensionValidator{}
+
+// NewTemplateExtensionValidator returns a validator for templates which reads
+// extended templates through reader
+func NewTemplateExtensionValidator(reader client.Reader) admission.CustomValidator {
+	return &templateExtensionValidator{reader: reader}
+}
+
+func (v *templateExtensionValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	validator, ok := obj.(webhook.Validator)
+	if !ok {
+		return fmt.Errorf("failed to cast object to validator")
+	}
+
+	if err := validator.ValidateCreate(); err != nil {
+		return err
+	}
+	return v.validateExtendsChain(ctx, obj)
+}
+
+func (v *templateExtensionValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	validator, ok := newObj.(webhook.Validator)
+	if !ok {
+		return fmt.Errorf("failed to cast object to validator")
+	}
+
+	if err := validator.ValidateUpdate(oldObj); err != nil {
+		return err
+	}
+	return v.validateExtendsChain(ctx, newObj)
+}
+
+func (v *templateExtensionValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *templateExtensionValidator) validateExtendsChain(ctx context.Context, obj runtime.Object) error {
+	template, ok := obj.(client.Object)
+	if !ok {
+		return fmt.Errorf("failed to cast object to client object")
+	}
+
+	kind, err := getTemplateKind(template)
+	if err != nil {
+		return err
+	}
+
+	spec, err := GetTemplateSpec(template)
+	if err != nil {
+		return err
+	}
+
+	chain := []TemplateReference{{Kind: kind, Name: template.GetName()}}
+	for ref := spec.Extends; ref != nil; ref = spec.Extends {
+		chain = append(chain, *ref)
+
+		for _, previous := range chain[:len(chain)-1] {
+			if previous == *ref {
+				return fmt.Errorf("invalid template: extends forms a cycle: %s", formatExtendsChain(chain))
+			}
+		}
+
+		if len(chain)-1 > MaxTemplateExtensionDepth {
+			return fmt.Errorf("invalid template: extends chain is longer than %d templates: %s", MaxTemplateExtensionDepth, formatExtendsChain(chain))
+		}
+
+		base, err := GetAPITemplate(ref.Kind)
+		if err != nil {
+			return fmt.Errorf("invalid template: extends: %w", err)
+		}
+
+		err = v.reader.Get(ctx, client.ObjectKey{Name: ref.Name}, base)
+		if kerrors.IsNotFound(err) {
+			// the chain cannot be completed until the extended template exists
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get extended template [%s/%s]: %w", ref.Kind, ref.Name, err)
+		}
+
+		spec, err = GetTemplateSpec(base)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatExtendsChain(chain []TemplateReference) string {
+	refs := make([]string, len(chain))
+	for i, ref := range chain {
+		refs[i] = fmt.Sprintf("%s/%s", ref.Kind, ref.Name)
+	}
+	return strings.Join(refs, " -> ")
+}
@@ -0,0 +1,163 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+var _ = Describe("Template extension", func() {
+	var template *v1alpha1.ClusterTemplate
+
+	BeforeEach(func() {
+		template = &v1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "a"},
+			Spec: v1alpha1.TemplateSpec{
+				Extends: &v1alpha1.TemplateReference{Kind: "ClusterTemplate", Name: "b"},
+				Overlay: &v1alpha1.TemplateOverlay{
+					StrategicMergePatch: &runtime.RawExtension{Raw: []byte(`{"spec": {"replicas": 2}}`)},
+					JSONPatch: []v1alpha1.JSONPatchOperation{
+						{Op: "remove", Path: "/metadata/labels"},
+					},
+				},
+			},
+		}
+	})
+
+	Describe("Webhook Validation", func() {
+		It("succeeds without template or ytt", func() {
+			Expect(template.ValidateCreate()).To(Succeed())
+		})
+
+		It("rejects a template", func() {
+			template.Spec.Template = &runtime.RawExtension{Raw: []byte(`{}`)}
+			Expect(template.ValidateCreate()).To(MatchError("invalid template: must not specify template or ytt when extends is set"))
+		})
+
+		It("rejects an unknown kind", func() {
+			template.Spec.Extends.Kind = "ClusterRunTemplate"
+			Expect(template.ValidateCreate()).To(MatchError("invalid template: extends: resource does not have valid kind: ClusterRunTemplate"))
+		})
+
+		It("rejects a strategic merge patch that is not an object", func() {
+			template.Spec.Overlay.StrategicMergePatch = &runtime.RawExtension{Raw: []byte(`[]`)}
+			Expect(template.ValidateCreate()).To(MatchError(ContainSubstring("invalid template: overlay.strategicMergePatch must be an object")))
+		})
+
+		It("rejects a copy operation without from", func() {
+			template.Spec.Overlay.JSONPatch = []v1alpha1.JSONPatchOperation{{Op: "copy", Path: "/spec/a"}}
+			Expect(template.ValidateCreate()).To(MatchError("invalid template: overlay.jsonPatch[0]: from must be specified for copy"))
+		})
+
+		It("rejects an overlay without extends", func() {
+			template.Spec.Extends = nil
+			template.Spec.Template = &runtime.RawExtension{Raw: []byte(`{}`)}
+			Expect(template.ValidateCreate()).To(MatchError("invalid template: overlay may only be specified with extends"))
+		})
+	})
+
+	Describe("TemplateExtensionValidator", func() {
+		var (
+			clientObjects []client.Object
+			validator     admission.CustomValidator
+		)
+
+		BeforeEach(func() {
+			clientObjects = nil
+		})
+
+		JustBeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+			validator = v1alpha1.NewTemplateExtensionValidator(
+				fake.NewClientBuilder().WithScheme(scheme).WithObjects(clientObjects...).Build(),
+			)
+		})
+
+		It("runs the validations of the template", func() {
+			template.Spec.Template = &runtime.RawExtension{Raw: []byte(`{}`)}
+			Expect(validator.ValidateCreate(context.Background(), template)).To(MatchError("invalid template: must not specify template or ytt when extends is set"))
+		})
+
+		It("succeeds when the extended template does not exist yet", func() {
+			Expect(validator.ValidateCreate(context.Background(), template)).To(Succeed())
+		})
+
+		It("rejects a template that extends itself", func() {
+			template.Spec.Extends.Name = "a"
+			Expect(validator.ValidateCreate(context.Background(), template)).To(MatchError(
+				"invalid template: extends forms a cycle: ClusterTemplate/a -> ClusterTemplate/a",
+			))
+		})
+
+		Context("the chain of extended templates leads back to the template", func() {
+			BeforeEach(func() {
+				clientObjects = []client.Object{
+					&v1alpha1.ClusterTemplate{
+						ObjectMeta: metav1.ObjectMeta{Name: "b"},
+						Spec: v1alpha1.TemplateSpec{
+							Extends: &v1alpha1.TemplateReference{Kind: "ClusterTemplate", Name: "c"},
+						},
+					},
+					&v1alpha1.ClusterTemplate{
+						ObjectMeta: metav1.ObjectMeta{Name: "c"},
+						Spec: v1alpha1.TemplateSpec{
+							Extends: &v1alpha1.TemplateReference{Kind: "ClusterTemplate", Name: "a"},
+						},
+					},
+				}
+			})
+
+			It("rejects the template on create", func() {
+				Expect(validator.ValidateCreate(context.Background(), template)).To(MatchError(
+					"invalid template: extends forms a cycle: ClusterTemplate/a -> ClusterTemplate/b -> ClusterTemplate/c -> ClusterTemplate/a",
+				))
+			})
+
+			It("rejects the template on update", func() {
+				Expect(validator.ValidateUpdate(context.Background(), template, template)).To(MatchError(
+					ContainSubstring("extends forms a cycle"),
+				))
+			})
+		})
+
+		Context("the chain of extended templates ends", func() {
+			BeforeEach(func() {
+				clientObjects = []client.Object{
+					&v1alpha1.ClusterTemplate{
+						ObjectMeta: metav1.ObjectMeta{Name: "b"},
+						Spec: v1alpha1.TemplateSpec{
+							Template: &runtime.RawExtension{Raw: []byte(`{}`)},
+						},
+					},
+				}
+			})
+
+			It("succeeds", func() {
+				Expect(validator.ValidateCreate(context.Background(), template)).To(Succeed())
+			})
+		})
+	})
+})
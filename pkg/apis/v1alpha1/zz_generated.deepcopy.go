@@ -10,8 +10,39 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AcceptedParam) DeepCopyInto(out *AcceptedParam) {
+	*out = *in
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(ParamSchema)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultValue != nil {
+		in, out := &in.DefaultValue, &out.DefaultValue
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AcceptedParam.
+func (in *AcceptedParam) DeepCopy() *AcceptedParam {
+	if in == nil {
+		return nil
+	}
+	out := new(AcceptedParam)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlueprintParam) DeepCopyInto(out *BlueprintParam) {
 	*out = *in
@@ -20,6 +51,11 @@ func (in *BlueprintParam) DeepCopyInto(out *BlueprintParam) {
 		*out = new(apiextensionsv1.JSON)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ParamValueSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DefaultValue != nil {
 		in, out := &in.DefaultValue, &out.DefaultValue
 		*out = new(apiextensionsv1.JSON)
@@ -37,6 +73,46 @@ func (in *BlueprintParam) DeepCopy() *BlueprintParam {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CELHealthRule) DeepCopyInto(out *CELHealthRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CELHealthRule.
+func (in *CELHealthRule) DeepCopy() *CELHealthRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CELHealthRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChildrenHealthRule) DeepCopyInto(out *ChildrenHealthRule) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MinHealthy != nil {
+		in, out := &in.MinHealthy, &out.MinHealthy
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChildrenHealthRule.
+func (in *ChildrenHealthRule) DeepCopy() *ChildrenHealthRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ChildrenHealthRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterConfigTemplate) DeepCopyInto(out *ClusterConfigTemplate) {
 	*out = *in
@@ -503,6 +579,64 @@ func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateRevision) DeepCopyInto(out *ClusterTemplateRevision) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevision.
+func (in *ClusterTemplateRevision) DeepCopy() *ClusterTemplateRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateRevision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateRevisionList) DeepCopyInto(out *ClusterTemplateRevisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplateRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateRevisionList.
+func (in *ClusterTemplateRevisionList) DeepCopy() *ClusterTemplateRevisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateRevisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateRevisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -701,6 +835,11 @@ func (in *DeliveryResource) DeepCopy() *DeliveryResource {
 func (in *DeliverySpec) DeepCopyInto(out *DeliverySpec) {
 	*out = *in
 	in.LegacySelector.DeepCopyInto(&out.LegacySelector)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = make([]DeliveryResource, len(*in))
@@ -716,6 +855,11 @@ func (in *DeliverySpec) DeepCopyInto(out *DeliverySpec) {
 		}
 	}
 	out.ServiceAccountRef = in.ServiceAccountRef
+	if in.MetadataPolicy != nil {
+		in, out := &in.MetadataPolicy, &out.MetadataPolicy
+		*out = new(MetadataPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeliverySpec.
@@ -760,6 +904,11 @@ func (in *DeliveryTemplateReference) DeepCopyInto(out *DeliveryTemplateReference
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(TemplateRollout)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeliveryTemplateReference.
@@ -944,6 +1093,21 @@ func (in *HealthRule) DeepCopyInto(out *HealthRule) {
 		*out = new(MultiMatchHealthRule)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CEL != nil {
+		in, out := &in.CEL, &out.CEL
+		*out = new(CELHealthRule)
+		**out = **in
+	}
+	if in.Probe != nil {
+		in, out := &in.Probe, &out.Probe
+		*out = new(ProbeHealthRule)
+		**out = **in
+	}
+	if in.Children != nil {
+		in, out := &in.Children, &out.Children
+		*out = new(ChildrenHealthRule)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthRule.
@@ -956,6 +1120,22 @@ func (in *HealthRule) DeepCopy() *HealthRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthTransition) DeepCopyInto(out *HealthTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthTransition.
+func (in *HealthTransition) DeepCopy() *HealthTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageTemplateSpec) DeepCopyInto(out *ImageTemplateSpec) {
 	*out = *in
@@ -987,6 +1167,26 @@ func (in *Input) DeepCopy() *Input {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONPatchOperation) DeepCopyInto(out *JSONPatchOperation) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONPatchOperation.
+func (in *JSONPatchOperation) DeepCopy() *JSONPatchOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONPatchOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LegacySelector) DeepCopyInto(out *LegacySelector) {
 	*out = *in
@@ -1023,6 +1223,48 @@ func (in *LegacySelector) DeepCopy() *LegacySelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataKeySelector) DeepCopyInto(out *MetadataKeySelector) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataKeySelector.
+func (in *MetadataKeySelector) DeepCopy() *MetadataKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataPolicy) DeepCopyInto(out *MetadataPolicy) {
+	*out = *in
+	in.Labels.DeepCopyInto(&out.Labels)
+	in.Annotations.DeepCopyInto(&out.Annotations)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataPolicy.
+func (in *MetadataPolicy) DeepCopy() *MetadataPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MultiMatchHealthRule) DeepCopyInto(out *MultiMatchHealthRule) {
 	*out = *in
@@ -1040,6 +1282,35 @@ func (in *MultiMatchHealthRule) DeepCopy() *MultiMatchHealthRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectMetadata) DeepCopyInto(out *ObjectMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectMetadata.
+func (in *ObjectMetadata) DeepCopy() *ObjectMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
 	*out = *in
@@ -1111,6 +1382,11 @@ func (in *Output) DeepCopy() *Output {
 func (in *OwnerParam) DeepCopyInto(out *OwnerParam) {
 	*out = *in
 	in.Value.DeepCopyInto(&out.Value)
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ParamValueSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OwnerParam.
@@ -1145,6 +1421,84 @@ func (in *OwnerStatus) DeepCopy() *OwnerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParamSchema) DeepCopyInto(out *ParamSchema) {
+	*out = *in
+	if in.Enum != nil {
+		in, out := &in.Enum, &out.Enum
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParamSchema.
+func (in *ParamSchema) DeepCopy() *ParamSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(ParamSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParamValueSource) DeepCopyInto(out *ParamValueSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParamValueSource.
+func (in *ParamValueSource) DeepCopy() *ParamValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ParamValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingRun) DeepCopyInto(out *PendingRun) {
+	*out = *in
+	in.RunAfter.DeepCopyInto(&out.RunAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingRun.
+func (in *PendingRun) DeepCopy() *PendingRun {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeHealthRule) DeepCopyInto(out *ProbeHealthRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeHealthRule.
+func (in *ProbeHealthRule) DeepCopy() *ProbeHealthRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeHealthRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RealizedResource) DeepCopyInto(out *RealizedResource) {
 	*out = *in
@@ -1158,6 +1512,11 @@ func (in *RealizedResource) DeepCopyInto(out *RealizedResource) {
 		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
+	if in.BaseTemplateRefs != nil {
+		in, out := &in.BaseTemplateRefs, &out.BaseTemplateRefs
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.Inputs != nil {
 		in, out := &in.Inputs, &out.Inputs
 		*out = make([]Input, len(*in))
@@ -1170,6 +1529,11 @@ func (in *RealizedResource) DeepCopyInto(out *RealizedResource) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PendingRun != nil {
+		in, out := &in.PendingRun, &out.PendingRun
+		*out = new(PendingRun)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RealizedResource.
@@ -1208,6 +1572,18 @@ func (in *ResourceSelector) DeepCopyInto(out *ResourceSelector) {
 			(*out)[key] = val
 		}
 	}
+	if in.MatchFields != nil {
+		in, out := &in.MatchFields, &out.MatchFields
+		*out = make([]FieldSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MaxSelected != nil {
+		in, out := &in.MaxSelected, &out.MaxSelected
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSelector.
@@ -1231,6 +1607,13 @@ func (in *ResourceStatus) DeepCopyInto(out *ResourceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HealthHistory != nil {
+		in, out := &in.HealthHistory, &out.HealthHistory
+		*out = make([]HealthTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceStatus.
@@ -1261,6 +1644,21 @@ func (in *ResourceType) DeepCopy() *ResourceType {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RetentionPolicy) DeepCopyInto(out *RetentionPolicy) {
 	*out = *in
+	if in.SuccessfulRunTTL != nil {
+		in, out := &in.SuccessfulRunTTL, &out.SuccessfulRunTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.FailedRunTTL != nil {
+		in, out := &in.FailedRunTTL, &out.FailedRunTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.UnresolvedRunTTL != nil {
+		in, out := &in.UnresolvedRunTTL, &out.UnresolvedRunTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionPolicy.
@@ -1273,6 +1671,31 @@ func (in *RetentionPolicy) DeepCopy() *RetentionPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunRecord) DeepCopyInto(out *RunRecord) {
+	*out = *in
+	if in.StampedRef != nil {
+		in, out := &in.StampedRef, &out.StampedRef
+		*out = new(StampedRef)
+		(*in).DeepCopyInto(*out)
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.FinishTime != nil {
+		in, out := &in.FinishTime, &out.FinishTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunRecord.
+func (in *RunRecord) DeepCopy() *RunRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(RunRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RunTemplateSpec) DeepCopyInto(out *RunTemplateSpec) {
 	*out = *in
@@ -1284,6 +1707,16 @@ func (in *RunTemplateSpec) DeepCopyInto(out *RunTemplateSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.HealthRule != nil {
+		in, out := &in.HealthRule, &out.HealthRule
+		*out = new(HealthRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CancelPatch != nil {
+		in, out := &in.CancelPatch, &out.CancelPatch
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplateSpec.
@@ -1355,6 +1788,26 @@ func (in *RunnableList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunnableSchedule) DeepCopyInto(out *RunnableSchedule) {
+	*out = *in
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunnableSchedule.
+func (in *RunnableSchedule) DeepCopy() *RunnableSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(RunnableSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RunnableSpec) DeepCopyInto(out *RunnableSpec) {
 	*out = *in
@@ -1371,7 +1824,17 @@ func (in *RunnableSpec) DeepCopyInto(out *RunnableSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
-	out.RetentionPolicy = in.RetentionPolicy
+	in.RetentionPolicy.DeepCopyInto(&out.RetentionPolicy)
+	if in.Debounce != nil {
+		in, out := &in.Debounce, &out.Debounce
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(RunnableSchedule)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunnableSpec.
@@ -1401,6 +1864,26 @@ func (in *RunnableStatus) DeepCopyInto(out *RunnableStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PendingRun != nil {
+		in, out := &in.PendingRun, &out.PendingRun
+		*out = new(PendingRun)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RunHistory != nil {
+		in, out := &in.RunHistory, &out.RunHistory
+		*out = make([]RunRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunnableStatus.
@@ -1559,6 +2042,11 @@ func (in *SupplyChainResource) DeepCopy() *SupplyChainResource {
 func (in *SupplyChainSpec) DeepCopyInto(out *SupplyChainSpec) {
 	*out = *in
 	in.LegacySelector.DeepCopyInto(&out.LegacySelector)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = make([]SupplyChainResource, len(*in))
@@ -1574,6 +2062,11 @@ func (in *SupplyChainSpec) DeepCopyInto(out *SupplyChainSpec) {
 		}
 	}
 	out.ServiceAccountRef = in.ServiceAccountRef
+	if in.MetadataPolicy != nil {
+		in, out := &in.MetadataPolicy, &out.MetadataPolicy
+		*out = new(MetadataPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainSpec.
@@ -1596,6 +2089,13 @@ func (in *SupplyChainStatus) DeepCopyInto(out *SupplyChainStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make([]AcceptedParam, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainStatus.
@@ -1618,6 +2118,11 @@ func (in *SupplyChainTemplateReference) DeepCopyInto(out *SupplyChainTemplateRef
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(TemplateRollout)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainTemplateReference.
@@ -1646,10 +2151,42 @@ func (in *TemplateOption) DeepCopy() *TemplateOption {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateOverlay) DeepCopyInto(out *TemplateOverlay) {
+	*out = *in
+	if in.StrategicMergePatch != nil {
+		in, out := &in.StrategicMergePatch, &out.StrategicMergePatch
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JSONPatch != nil {
+		in, out := &in.JSONPatch, &out.JSONPatch
+		*out = make([]JSONPatchOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateOverlay.
+func (in *TemplateOverlay) DeepCopy() *TemplateOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateParam) DeepCopyInto(out *TemplateParam) {
 	*out = *in
 	in.DefaultValue.DeepCopyInto(&out.DefaultValue)
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(ParamSchema)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateParam.
@@ -1698,6 +2235,38 @@ func (in *TemplateReference) DeepCopy() *TemplateReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateRevisionSpec) DeepCopyInto(out *TemplateRevisionSpec) {
+	*out = *in
+	out.TemplateRef = in.TemplateRef
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateRevisionSpec.
+func (in *TemplateRevisionSpec) DeepCopy() *TemplateRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateRollout) DeepCopyInto(out *TemplateRollout) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateRollout.
+func (in *TemplateRollout) DeepCopy() *TemplateRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateSpec) DeepCopyInto(out *TemplateSpec) {
 	*out = *in
@@ -1706,6 +2275,16 @@ func (in *TemplateSpec) DeepCopyInto(out *TemplateSpec) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Extends != nil {
+		in, out := &in.Extends, &out.Extends
+		*out = new(TemplateReference)
+		**out = **in
+	}
+	if in.Overlay != nil {
+		in, out := &in.Overlay, &out.Overlay
+		*out = new(TemplateOverlay)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Params != nil {
 		in, out := &in.Params, &out.Params
 		*out = make(TemplateParams, len(*in))
@@ -1718,9 +2297,19 @@ func (in *TemplateSpec) DeepCopyInto(out *TemplateSpec) {
 		*out = new(HealthRule)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ObjectMetadata != nil {
+		in, out := &in.ObjectMetadata, &out.ObjectMetadata
+		*out = new(ObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RetentionPolicy != nil {
 		in, out := &in.RetentionPolicy, &out.RetentionPolicy
 		*out = new(RetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Debounce != nil {
+		in, out := &in.Debounce, &out.Debounce
+		*out = new(v1.Duration)
 		**out = **in
 	}
 }
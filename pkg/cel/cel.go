@@ -0,0 +1,134 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cel evaluates Common Expression Language expressions against
+// unstructured objects, bound to the variable `self`.
+package cel
+
+import (
+	"fmt"
+	"sync"
+
+	celgo "github.com/google/cel-go/cel"
+)
+
+// costLimit bounds the work done evaluating a single expression, protecting
+// the controller from expressions iterating over very large objects
+const costLimit = 1000000
+
+var (
+	envOnce sync.Once
+	env     *celgo.Env
+	envErr  error
+
+	programs sync.Map
+)
+
+func getEnv() (*celgo.Env, error) {
+	envOnce.Do(func() {
+		env, envErr = celgo.NewEnv(celgo.Variable("self", celgo.DynType))
+	})
+	return env, envErr
+}
+
+type compiledProgram struct {
+	program    celgo.Program
+	outputType *celgo.Type
+}
+
+func compile(expression string) (*compiledProgram, error) {
+	if cached, ok := programs.Load(expression); ok {
+		return cached.(*compiledProgram), nil
+	}
+
+	celEnv, err := getEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cel environment: %w", err)
+	}
+
+	ast, issues := celEnv.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("failed to compile expression [%s]: %w", expression, issues.Err())
+	}
+
+	program, err := celEnv.Program(ast, celgo.CostLimit(costLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create program for expression [%s]: %w", expression, err)
+	}
+
+	compiled := &compiledProgram{program: program, outputType: ast.OutputType()}
+	programs.Store(expression, compiled)
+	return compiled, nil
+}
+
+// CheckBool returns an error if expression does not compile or cannot evaluate to a bool
+func CheckBool(expression string) error {
+	return check(expression, celgo.BoolType)
+}
+
+// CheckString returns an error if expression does not compile or cannot evaluate to a string
+func CheckString(expression string) error {
+	return check(expression, celgo.StringType)
+}
+
+func check(expression string, expectedType *celgo.Type) error {
+	compiled, err := compile(expression)
+	if err != nil {
+		return err
+	}
+	// expressions reading fields of self are dynamically typed, and checked when evaluated
+	if !expectedType.IsAssignableType(compiled.outputType) && !compiled.outputType.IsAssignableType(celgo.DynType) {
+		return fmt.Errorf("expression [%s] must evaluate to %s, found %s", expression, expectedType, compiled.outputType)
+	}
+	return nil
+}
+
+// EvaluateBool evaluates expression with self bound to obj
+func EvaluateBool(expression string, obj map[string]interface{}) (bool, error) {
+	result, err := evaluate(expression, obj)
+	if err != nil {
+		return false, err
+	}
+	value, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression [%s] evaluated to %T, expected bool", expression, result)
+	}
+	return value, nil
+}
+
+// EvaluateString evaluates expression with self bound to obj
+func EvaluateString(expression string, obj map[string]interface{}) (string, error) {
+	result, err := evaluate(expression, obj)
+	if err != nil {
+		return "", err
+	}
+	value, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("expression [%s] evaluated to %T, expected string", expression, result)
+	}
+	return value, nil
+}
+
+func evaluate(expression string, obj map[string]interface{}) (interface{}, error) {
+	compiled, err := compile(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	result, _, err := compiled.program.Eval(map[string]interface{}{"self": obj})
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate expression [%s]: %w", expression, err)
+	}
+	return result.Value(), nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/cartographer/pkg/cel"
+)
+
+var _ = Describe("CEL", func() {
+	var obj map[string]interface{}
+
+	BeforeEach(func() {
+		obj = map[string]interface{}{
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+			},
+			"status": map[string]interface{}{
+				"readyReplicas": int64(2),
+				"phase":         "Running",
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "True"},
+				},
+			},
+		}
+	})
+
+	DescribeTable("EvaluateBool",
+		func(expression string, expected bool, expectedErr string) {
+			result, err := cel.EvaluateBool(expression, obj)
+			if expectedErr != "" {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		},
+		Entry("field comparison", "self.status.readyReplicas == self.spec.replicas", false, ""),
+		Entry("numeric comparison", "self.status.readyReplicas >= 2", true, ""),
+		Entry("string comparison", "self.status.phase == 'Running'", true, ""),
+		Entry("macro over a list", "self.status.conditions.exists(c, c.type == 'Ready' && c.status == 'True')", true, ""),
+		Entry("field presence", "has(self.status.failed)", false, ""),
+		Entry("missing field", "self.status.failed > 0", false, "no such key: failed"),
+		Entry("non-bool result", "self.status.phase", false, "evaluated to string, expected bool"),
+		Entry("invalid expression", "self.status.phase ==", false, "failed to compile expression"),
+	)
+
+	DescribeTable("EvaluateString",
+		func(expression string, expected string, expectedErr string) {
+			result, err := cel.EvaluateString(expression, obj)
+			if expectedErr != "" {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(expected))
+		},
+		Entry("concatenation", "'phase: ' + self.status.phase", "phase: Running", ""),
+		Entry("conversion", "string(self.status.readyReplicas) + '/' + string(self.spec.replicas)", "2/3", ""),
+		Entry("non-string result", "self.spec.replicas", "", "evaluated to int64, expected string"),
+	)
+
+	DescribeTable("CheckBool",
+		func(expression string, expectedErr string) {
+			err := cel.CheckBool(expression)
+			if expectedErr != "" {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+		},
+		Entry("bool expression", "1 < 2", ""),
+		Entry("dynamically typed expression", "self.status.ready", ""),
+		Entry("non-bool expression", "'a' + 'b'", "must evaluate to bool, found string"),
+		Entry("undeclared variable", "other.status.ready", "undeclared reference to 'other'"),
+	)
+})
@@ -26,21 +26,25 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/cartographer/pkg/controllers"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
 	"github.com/vmware-tanzu/cartographer/pkg/utils"
+	"github.com/vmware-tanzu/cartographer/pkg/webhooks"
 )
 
 type Command struct {
-	Port                    int
-	CertDir                 string
-	MetricsPort             int
-	PprofPort               int
-	Logger                  logr.Logger
-	MaxConcurrentDeliveries int
-	MaxConcurrentWorkloads  int
-	MaxConcurrentRunnables  int
+	Port                         int
+	CertDir                      string
+	MetricsPort                  int
+	PprofPort                    int
+	Logger                       logr.Logger
+	MaxConcurrentDeliveries      int
+	MaxConcurrentWorkloads       int
+	MaxConcurrentRunnables       int
+	TemplateRevisionHistoryLimit int
 }
 
 func (cmd *Command) Execute(ctx context.Context) error {
@@ -78,7 +82,12 @@ func (cmd *Command) Execute(ctx context.Context) error {
 		return fmt.Errorf("failed to create new manager: %w", err)
 	}
 
-	if err := cmd.registerControllers(mgr); err != nil {
+	prober := healthcheck.NewProber(healthcheck.ProbeInterval, healthcheck.DefaultDeniedProbeNetworks)
+	if err := mgr.Add(prober); err != nil {
+		return fmt.Errorf("failed to add prober: %w", err)
+	}
+
+	if err := cmd.registerControllers(mgr, prober); err != nil {
 		return fmt.Errorf("failed to register controllers: %w", err)
 	}
 
@@ -97,8 +106,8 @@ func (cmd *Command) Execute(ctx context.Context) error {
 	return nil
 }
 
-func (cmd *Command) registerControllers(mgr manager.Manager) error {
-	if err := (&controllers.WorkloadReconciler{}).SetupWithManager(mgr, cmd.MaxConcurrentWorkloads); err != nil {
+func (cmd *Command) registerControllers(mgr manager.Manager, prober *healthcheck.Prober) error {
+	if err := (&controllers.WorkloadReconciler{Prober: prober}).SetupWithManager(mgr, cmd.MaxConcurrentWorkloads); err != nil {
 		return fmt.Errorf("failed to register workload controller: %w", err)
 	}
 
@@ -106,7 +115,7 @@ func (cmd *Command) registerControllers(mgr manager.Manager) error {
 		return fmt.Errorf("failed to register supply chain controller: %w", err)
 	}
 
-	if err := (&controllers.DeliverableReconciler{}).SetupWithManager(mgr, cmd.MaxConcurrentDeliveries); err != nil {
+	if err := (&controllers.DeliverableReconciler{Prober: prober}).SetupWithManager(mgr, cmd.MaxConcurrentDeliveries); err != nil {
 		return fmt.Errorf("failed to register deliverable controller: %w", err)
 	}
 
@@ -114,10 +123,16 @@ func (cmd *Command) registerControllers(mgr manager.Manager) error {
 		return fmt.Errorf("failed to register delivery controller: %w", err)
 	}
 
-	if err := (&controllers.RunnableReconciler{}).SetupWithManager(mgr, cmd.MaxConcurrentRunnables); err != nil {
+	if err := (&controllers.RunnableReconciler{Prober: prober}).SetupWithManager(mgr, cmd.MaxConcurrentRunnables); err != nil {
 		return fmt.Errorf("failed to register runnable controller: %w", err)
 	}
 
+	for _, kind := range controllers.TemplateRevisionKinds {
+		if err := (&controllers.TemplateRevisionReconciler{TemplateKind: kind, RevisionHistoryLimit: cmd.TemplateRevisionHistoryLimit}).SetupWithManager(mgr); err != nil {
+			return fmt.Errorf("failed to register template revision controller for [%s]: %w", kind, err)
+		}
+	}
+
 	return nil
 }
 
@@ -130,6 +145,10 @@ func registerWebhooks(mgr manager.Manager) error {
 		return fmt.Errorf("failed to setup cluster delivery webhook: %w", err)
 	}
 
+	selectorOverlapWarner := &webhook.Admission{Handler: &webhooks.SelectorOverlapWarner{Client: mgr.GetClient()}}
+	mgr.GetWebhookServer().Register("/warn-carto-run-v1alpha1-clustersupplychain", selectorOverlapWarner)
+	mgr.GetWebhookServer().Register("/warn-carto-run-v1alpha1-clusterdelivery", selectorOverlapWarner)
+
 	if err := (&v1alpha1.ClusterConfigTemplate{}).SetupWebhookWithManager(mgr); err != nil {
 		return fmt.Errorf("failed to setup cluster config template webhook: %w", err)
 	}
@@ -154,6 +173,10 @@ func registerWebhooks(mgr manager.Manager) error {
 		return fmt.Errorf("failed to setup cluster template webhook: %w", err)
 	}
 
+	if err := (&v1alpha1.ClusterTemplateRevision{}).SetupWebhookWithManager(mgr); err != nil {
+		return fmt.Errorf("failed to setup cluster template revision webhook: %w", err)
+	}
+
 	return nil
 }
 
@@ -34,6 +34,9 @@ const Positive Polarity = "Positive"
 // Negative Polarity means a "False" ConditionStatus is a success
 const Negative Polarity = "Negative"
 
+// Informational Polarity means the ConditionStatus does not affect the top level condition
+const Informational Polarity = "Informational"
+
 //counterfeiter:generate . ConditionManager
 
 // ConditionManager supports collecting condition statuses for your controller
@@ -89,16 +92,18 @@ func NewConditionManager(topLevelType string, previousConditions []metav1.Condit
 func (c *conditionManager) Add(condition metav1.Condition, polarity Polarity) {
 	condition.LastTransitionTime = metav1.Now()
 
-	if (condition.Status == metav1.ConditionFalse && polarity == Positive) ||
-		(condition.Status == metav1.ConditionTrue && polarity == Negative) {
-		c.status = metav1.ConditionFalse
-		c.reason = condition.Reason
-		c.message = condition.Message
-	} else if condition.Status == metav1.ConditionUnknown {
-		if c.status == metav1.ConditionTrue {
-			c.status = metav1.ConditionUnknown
+	if polarity != Informational {
+		if (condition.Status == metav1.ConditionFalse && polarity == Positive) ||
+			(condition.Status == metav1.ConditionTrue && polarity == Negative) {
+			c.status = metav1.ConditionFalse
 			c.reason = condition.Reason
 			c.message = condition.Message
+		} else if condition.Status == metav1.ConditionUnknown {
+			if c.status == metav1.ConditionTrue {
+				c.status = metav1.ConditionUnknown
+				c.reason = condition.Reason
+				c.message = condition.Message
+			}
 		}
 	}
 
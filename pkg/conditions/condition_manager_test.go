@@ -425,6 +425,46 @@ var _ = Describe("conditionManager", func() {
 
 	})
 
+	Context("with informational conditions", func() {
+		BeforeEach(func() {
+			manager = conditions.NewConditionManager("HappyParent", []metav1.Condition{})
+			manager.AddPositive(metav1.Condition{
+				Type:   "Goodness",
+				Status: metav1.ConditionTrue,
+			})
+			manager.Add(metav1.Condition{
+				Type:   "Degraded",
+				Status: metav1.ConditionTrue,
+			}, conditions.Informational)
+			manager.Add(metav1.Condition{
+				Type:   "Progressing",
+				Status: metav1.ConditionUnknown,
+			}, conditions.Informational)
+		})
+
+		It("returns the conditions without affecting the parent", func() {
+			result, changed := manager.Finalize()
+
+			Expect(manager.IsSuccessful()).To(BeTrue())
+			Expect(changed).To(BeTrue())
+			Expect(result).To(HaveLen(4))
+			Expect(result).To(ContainElements(
+				MatchFields(IgnoreExtras,
+					Fields{
+						"Type":   Equal("Degraded"),
+						"Status": Equal(metav1.ConditionTrue),
+					},
+				),
+				MatchFields(IgnoreExtras,
+					Fields{
+						"Type":   Equal("HappyParent"),
+						"Status": Equal(metav1.ConditionTrue),
+					},
+				),
+			))
+		})
+	})
+
 	Context("with previous conditions", func() {
 		var (
 			firstConditions   []metav1.Condition
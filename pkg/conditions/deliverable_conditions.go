@@ -16,6 +16,7 @@ package conditions
 
 import (
 	"fmt"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -26,11 +27,12 @@ import (
 
 // -- Deliverable.Status.Conditions - DeliveryReady
 
-func DeliveryReadyCondition() metav1.Condition {
+func DeliveryReadyCondition(selectionReason string) metav1.Condition {
 	return metav1.Condition{
-		Type:   v1alpha1.DeliverableDeliveryReady,
-		Status: metav1.ConditionTrue,
-		Reason: v1alpha1.ReadyDeliveryReason,
+		Type:    v1alpha1.DeliverableDeliveryReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1alpha1.ReadyDeliveryReason,
+		Message: selectionReason,
 	}
 }
 
@@ -43,21 +45,30 @@ func DeliverableMissingLabelsCondition() metav1.Condition {
 	}
 }
 
-func DeliveryNotFoundCondition(labels map[string]string) metav1.Condition {
+func DeliveryNotFoundCondition(labels map[string]string, explanation string) metav1.Condition {
 	return metav1.Condition{
 		Type:    v1alpha1.DeliverableDeliveryReady,
 		Status:  metav1.ConditionFalse,
 		Reason:  v1alpha1.NotFoundDeliveryReadyReason,
-		Message: fmt.Sprintf("no delivery found where full selector is satisfied by labels: %+v", labels),
+		Message: withExplanation(fmt.Sprintf("no delivery found where full selector is satisfied by labels: %+v", labels), explanation),
 	}
 }
 
-func TooManyDeliveryMatchesCondition() metav1.Condition {
+func TooManyDeliveryMatchesCondition(explanation string) metav1.Condition {
 	return metav1.Condition{
 		Type:    v1alpha1.DeliverableDeliveryReady,
 		Status:  metav1.ConditionFalse,
 		Reason:  v1alpha1.MultipleMatchesDeliveryReadyReason,
-		Message: "deliverable may only match a single delivery's selector",
+		Message: withExplanation("deliverable may only match a single delivery's selector", explanation),
+	}
+}
+
+func DeliveryNamespaceNotAllowedCondition(namespace string, names []string) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.DeliverableDeliveryReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.NamespaceNotAllowedDeliveryReason,
+		Message: fmt.Sprintf("deliveries [%s] match the deliverable's labels but do not allow namespace [%s]", strings.Join(names, ", "), namespace),
 	}
 }
 
@@ -108,10 +119,14 @@ func AddConditionForResourceSubmittedDeliverable(conditionManager *ConditionMana
 		(*conditionManager).AddPositive(TemplateStampFailureCondition(isOwner, typedErr))
 	case cerrors.ApplyStampedObjectError:
 		(*conditionManager).AddPositive(TemplateRejectedByAPIServerCondition(isOwner, typedErr))
+	case cerrors.StampedObjectRejectedBySchemaError:
+		(*conditionManager).AddPositive(TemplateRejectedBySchemaCondition(isOwner, typedErr))
 	case cerrors.RetrieveOutputError:
 		switch typedErr.Err.(type) {
 		case stamp.ObservedGenerationError:
 			(*conditionManager).AddPositive(TemplateStampFailureByObservedGenerationCondition(typedErr))
+		case stamp.StaleStatusError:
+			(*conditionManager).AddPositive(StaleStatusCondition(isOwner, typedErr.StampedObject, typedErr.Err, typedErr.GetQualifiedResource()))
 		case stamp.DeploymentFailedConditionMetError:
 			(*conditionManager).AddPositive(DeploymentFailedConditionMetCondition(typedErr))
 		case stamp.DeploymentConditionError:
@@ -129,6 +144,8 @@ func AddConditionForResourceSubmittedDeliverable(conditionManager *ConditionMana
 		(*conditionManager).AddPositive(ResolveTemplateOptionsErrorCondition(isOwner, typedErr))
 	case cerrors.TemplateOptionsMatchError:
 		(*conditionManager).AddPositive(TemplateOptionsMatchErrorCondition(isOwner, typedErr))
+	case cerrors.InvalidParamError:
+		(*conditionManager).AddPositive(InvalidParamCondition(isOwner, typedErr))
 	default:
 		(*conditionManager).AddPositive(UnknownResourceErrorCondition(isOwner, typedErr))
 	}
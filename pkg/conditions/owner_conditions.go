@@ -16,6 +16,7 @@ package conditions
 
 import (
 	"fmt"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -75,6 +76,20 @@ func MissingValueAtPathCondition(isOwner bool, obj *unstructured.Unstructured, e
 	}
 }
 
+func StaleStatusCondition(isOwner bool, obj *unstructured.Unstructured, err error, qualifiedResource string) metav1.Condition {
+	var namespaceMsg string
+	if obj.GetNamespace() != "" {
+		namespaceMsg = fmt.Sprintf(" in namespace [%s]", obj.GetNamespace())
+	}
+	return metav1.Condition{
+		Type:   getConditionType(isOwner),
+		Status: metav1.ConditionUnknown,
+		Reason: v1alpha1.StaleStatusResourcesSubmittedReason,
+		Message: fmt.Sprintf("waiting for resource [%s/%s]%s to observe its latest generation: %s",
+			qualifiedResource, obj.GetName(), namespaceMsg, err.Error()),
+	}
+}
+
 func TemplateStampFailureCondition(isOwner bool, err error) metav1.Condition {
 	return metav1.Condition{
 		Type:    getConditionType(isOwner),
@@ -93,6 +108,15 @@ func TemplateRejectedByAPIServerCondition(isOwner bool, err error) metav1.Condit
 	}
 }
 
+func TemplateRejectedBySchemaCondition(isOwner bool, err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    getConditionType(isOwner),
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.TemplateRejectedBySchemaResourcesSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
 func BlueprintsFailedToListCreatedObjectsCondition(isOwner bool, err error) metav1.Condition {
 	return metav1.Condition{
 		Type:    getConditionType(isOwner),
@@ -138,6 +162,15 @@ func TemplateOptionsMatchErrorCondition(isOwner bool, err error) metav1.Conditio
 	}
 }
 
+func InvalidParamCondition(isOwner bool, err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    getConditionType(isOwner),
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.InvalidParamResourcesSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
 func getConditionType(isOwner bool) string {
 	if isOwner {
 		return v1alpha1.OwnerResourcesSubmitted
@@ -174,3 +207,30 @@ func ResourceRealizerBuilderErrorCondition(err error) metav1.Condition {
 		Message: err.Error(),
 	}
 }
+
+// -- OWNER ConditionType - Degraded
+
+func ResourcesStableCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:   v1alpha1.OwnerDegraded,
+		Status: metav1.ConditionFalse,
+		Reason: v1alpha1.ResourcesStableDegradedReason,
+	}
+}
+
+func ResourcesFlappingCondition(resourceNames []string) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.OwnerDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1alpha1.ResourcesFlappingDegradedReason,
+		Message: fmt.Sprintf("health of resources [%s] is flapping", strings.Join(resourceNames, ", ")),
+	}
+}
+
+// withExplanation appends an explanation of blueprint selection to a condition message.
+func withExplanation(message, explanation string) string {
+	if explanation == "" {
+		return message
+	}
+	return fmt.Sprintf("%s: %s", message, explanation)
+}
@@ -17,10 +17,15 @@ package conditions_test
 import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/cartographer/pkg/conditions"
+	cerrors "github.com/vmware-tanzu/cartographer/pkg/errors"
+	"github.com/vmware-tanzu/cartographer/pkg/stamp"
 )
 
 var _ = Describe("Conditions", func() {
@@ -53,4 +58,48 @@ var _ = Describe("Conditions", func() {
 			})
 		})
 	})
+
+	Describe("StaleStatus", func() {
+		var (
+			obj *unstructured.Unstructured
+			err cerrors.RetrieveOutputError
+		)
+
+		BeforeEach(func() {
+			obj = &unstructured.Unstructured{}
+			obj.SetName("my-widget")
+			obj.SetNamespace("my-ns")
+			err = cerrors.RetrieveOutputError{
+				Err:               stamp.StaleStatusError{Generation: 3, ObservedGeneration: 2},
+				ResourceName:      "my-resource",
+				StampedObject:     obj,
+				QualifiedResource: "widget.thing.io",
+			}
+		})
+
+		It("is reported for a workload", func() {
+			conditionManager := conditions.NewConditionManager(v1alpha1.ResourceSubmitted, nil)
+			conditions.AddConditionForResourceSubmittedWorkload(&conditionManager, false, err)
+			resultConditions, _ := conditionManager.Finalize()
+
+			Expect(resultConditions).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Type":    Equal(v1alpha1.ResourceSubmitted),
+				"Status":  Equal(metav1.ConditionUnknown),
+				"Reason":  Equal("StaleStatus"),
+				"Message": Equal("waiting for resource [widget.thing.io/my-widget] in namespace [my-ns] to observe its latest generation: status.observedGeneration [2] is behind metadata.generation [3]"),
+			})))
+		})
+
+		It("is reported for a deliverable", func() {
+			conditionManager := conditions.NewConditionManager(v1alpha1.ResourceSubmitted, nil)
+			conditions.AddConditionForResourceSubmittedDeliverable(&conditionManager, false, err)
+			resultConditions, _ := conditionManager.Finalize()
+
+			Expect(resultConditions).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(v1alpha1.ResourceSubmitted),
+				"Status": Equal(metav1.ConditionUnknown),
+				"Reason": Equal("StaleStatus"),
+			})))
+		})
+	})
 })
@@ -83,6 +83,15 @@ func OutputNotAvailableResourcesHealthyCondition() metav1.Condition {
 	}
 }
 
+func StaleStatusResourcesHealthyCondition(message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.ResourceHealthy,
+		Status:  metav1.ConditionUnknown,
+		Reason:  v1alpha1.StaleStatusHealthyReason,
+		Message: message,
+	}
+}
+
 func MultiMatchNoMatchesCondition() metav1.Condition {
 	return metav1.Condition{
 		Type:   v1alpha1.ResourceHealthy,
@@ -101,3 +110,36 @@ func MultiMatchResourcesHealthyCondition(status metav1.ConditionStatus, reason,
 		Message: message,
 	}
 }
+
+// -- Resource.Conditions - ResourcesHealthy - CEL
+
+func CELResourcesHealthyCondition(status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.ResourceHealthy,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// -- Resource.Conditions - ResourcesHealthy - Probe
+
+func ProbeResourcesHealthyCondition(status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.ResourceHealthy,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// -- Resource.Conditions - ResourcesHealthy - Children
+
+func ChildrenResourcesHealthyCondition(status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.ResourceHealthy,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
@@ -125,6 +125,15 @@ func ClientBuilderErrorCondition(err error) metav1.Condition {
 	}
 }
 
+func InvalidScheduleCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.RunTemplateReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.InvalidScheduleRunTemplateReason,
+		Message: err.Error(),
+	}
+}
+
 // -- Runnable.Status.Conditions - StampedObjectCondition
 
 func StampedObjectConditionUnknown() metav1.Condition {
@@ -143,3 +152,12 @@ func StampedObjectConditionKnown(condition *metav1.Condition) metav1.Condition {
 		Message: condition.Message,
 	}
 }
+
+func StampedObjectHealthRuleCondition(healthCondition metav1.Condition) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.StampedObjectCondition,
+		Status:  healthCondition.Status,
+		Reason:  v1alpha1.HealthRuleStampedObjectConditionReason,
+		Message: healthCondition.Message,
+	}
+}
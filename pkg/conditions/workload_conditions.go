@@ -16,20 +16,23 @@ package conditions
 
 import (
 	"fmt"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	cerrors "github.com/vmware-tanzu/cartographer/pkg/errors"
+	"github.com/vmware-tanzu/cartographer/pkg/stamp"
 )
 
 // -- Workload.Status.Conditions - SupplyChainReady
 
-func SupplyChainReadyCondition() metav1.Condition {
+func SupplyChainReadyCondition(selectionReason string) metav1.Condition {
 	return metav1.Condition{
-		Type:   v1alpha1.WorkloadSupplyChainReady,
-		Status: metav1.ConditionTrue,
-		Reason: v1alpha1.ReadySupplyChainReason,
+		Type:    v1alpha1.WorkloadSupplyChainReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1alpha1.ReadySupplyChainReason,
+		Message: selectionReason,
 	}
 }
 
@@ -42,21 +45,30 @@ func WorkloadMissingLabelsCondition() metav1.Condition {
 	}
 }
 
-func SupplyChainNotFoundCondition(labels map[string]string) metav1.Condition {
+func SupplyChainNotFoundCondition(labels map[string]string, explanation string) metav1.Condition {
 	return metav1.Condition{
 		Type:    v1alpha1.WorkloadSupplyChainReady,
 		Status:  metav1.ConditionFalse,
 		Reason:  v1alpha1.NotFoundSupplyChainReadyReason,
-		Message: fmt.Sprintf("no supply chain found where full selector is satisfied by labels: %+v", labels),
+		Message: withExplanation(fmt.Sprintf("no supply chain found where full selector is satisfied by labels: %+v", labels), explanation),
 	}
 }
 
-func TooManySupplyChainMatchesCondition() metav1.Condition {
+func TooManySupplyChainMatchesCondition(explanation string) metav1.Condition {
 	return metav1.Condition{
 		Type:    v1alpha1.WorkloadSupplyChainReady,
 		Status:  metav1.ConditionFalse,
 		Reason:  v1alpha1.MultipleMatchesSupplyChainReadyReason,
-		Message: "workload may only match a single supply chain's selector",
+		Message: withExplanation("workload may only match a single supply chain's selector", explanation),
+	}
+}
+
+func SupplyChainNamespaceNotAllowedCondition(namespace string, names []string) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadSupplyChainReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.NamespaceNotAllowedSupplyChainReason,
+		Message: fmt.Sprintf("supply chains [%s] match the workload's labels but do not allow namespace [%s]", strings.Join(names, ", "), namespace),
 	}
 }
 
@@ -86,12 +98,16 @@ func AddConditionForResourceSubmittedWorkload(conditionManager *ConditionManager
 		(*conditionManager).AddPositive(TemplateStampFailureCondition(isOwner, typedErr))
 	case cerrors.ApplyStampedObjectError:
 		(*conditionManager).AddPositive(TemplateRejectedByAPIServerCondition(isOwner, typedErr))
+	case cerrors.StampedObjectRejectedBySchemaError:
+		(*conditionManager).AddPositive(TemplateRejectedBySchemaCondition(isOwner, typedErr))
 	case cerrors.ListCreatedObjectsError:
 		(*conditionManager).AddPositive(BlueprintsFailedToListCreatedObjectsCondition(isOwner, typedErr))
 	case cerrors.NoHealthyImmutableObjectsError:
 		(*conditionManager).AddPositive(NoHealthyImmutableObjectsCondition(isOwner, typedErr))
 	case cerrors.RetrieveOutputError:
-		if typedErr.StampedObject == nil {
+		if staleErr, ok := typedErr.Err.(stamp.StaleStatusError); ok {
+			(*conditionManager).AddPositive(StaleStatusCondition(isOwner, typedErr.StampedObject, staleErr, typedErr.GetQualifiedResource()))
+		} else if typedErr.StampedObject == nil {
 			(*conditionManager).AddPositive(MissingPassThroughInputCondition(typedErr.PassThroughInput, typedErr.GetQualifiedResource()))
 		} else {
 			(*conditionManager).AddPositive(MissingValueAtPathCondition(isOwner, typedErr.StampedObject, typedErr.JsonPathExpression(), typedErr.GetQualifiedResource()))
@@ -100,6 +116,8 @@ func AddConditionForResourceSubmittedWorkload(conditionManager *ConditionManager
 		(*conditionManager).AddPositive(ResolveTemplateOptionsErrorCondition(isOwner, typedErr))
 	case cerrors.TemplateOptionsMatchError:
 		(*conditionManager).AddPositive(TemplateOptionsMatchErrorCondition(isOwner, typedErr))
+	case cerrors.InvalidParamError:
+		(*conditionManager).AddPositive(InvalidParamCondition(isOwner, typedErr))
 	default:
 		(*conditionManager).AddPositive(UnknownResourceErrorCondition(isOwner, typedErr))
 	}
@@ -17,15 +17,22 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/statuses"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
+	"github.com/vmware-tanzu/cartographer/pkg/tracker/dependency"
 )
 
 //go:generate go run -modfile ../../hack/tools/go.mod github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -91,3 +98,189 @@ func getEquivalenceTest(ctx context.Context, repo repository.Repository, prevRes
 		return mutableEquivalenceTest, nil
 	}
 }
+
+// paramValueSourceKeys returns the keys of the ConfigMaps and Secrets in namespace
+// that the owner and blueprint params are sourced from
+func paramValueSourceKeys(namespace string, ownerParams []v1alpha1.OwnerParam, blueprintParams ...[]v1alpha1.BlueprintParam) []dependency.Key {
+	var sources []*v1alpha1.ParamValueSource
+	for _, param := range ownerParams {
+		sources = append(sources, param.ValueFrom)
+	}
+	for _, params := range blueprintParams {
+		for _, param := range params {
+			sources = append(sources, param.ValueFrom)
+		}
+	}
+
+	var keys []dependency.Key
+	for _, source := range sources {
+		if source == nil {
+			continue
+		}
+
+		var kind, name string
+		if source.ConfigMapKeyRef != nil {
+			kind, name = "ConfigMap", source.ConfigMapKeyRef.Name
+		} else if source.SecretKeyRef != nil {
+			kind, name = "Secret", source.SecretKeyRef.Name
+		} else {
+			continue
+		}
+
+		keys = append(keys, dependency.Key{
+			GroupKind: schema.GroupKind{
+				Group: corev1.SchemeGroupVersion.Group,
+				Kind:  kind,
+			},
+			NamespacedName: types.NamespacedName{
+				Namespace: namespace,
+				Name:      name,
+			},
+		})
+	}
+
+	return keys
+}
+
+// templateKeys returns the keys of the template that stamped a resource and of
+// the templates it extends
+func templateKeys(resource v1alpha1.ResourceStatus) []dependency.Key {
+	if resource.TemplateRef == nil {
+		return nil
+	}
+
+	refs := append([]corev1.ObjectReference{*resource.TemplateRef}, resource.BaseTemplateRefs...)
+
+	var keys []dependency.Key
+	for _, ref := range refs {
+		keys = append(keys, dependency.Key{
+			GroupKind: schema.GroupKind{
+				Group: v1alpha1.SchemeGroupVersion.Group,
+				Kind:  ref.Kind,
+			},
+			NamespacedName: types.NamespacedName{
+				Name: ref.Name,
+			},
+		})
+	}
+
+	return keys
+}
+
+// templateRevisionKeys returns the keys of the ClusterTemplateRevisions that the
+// resources pin, directly or through a rollout
+func templateRevisionKeys(resources []realizer.OwnerResource) []dependency.Key {
+	var keys []dependency.Key
+	for _, resource := range resources {
+		revisions := []int64{resource.TemplateRevision}
+		if resource.TemplateRollout != nil {
+			revisions = append(revisions, resource.TemplateRollout.Revision)
+		}
+
+		for _, revision := range revisions {
+			if revision == 0 {
+				continue
+			}
+			keys = append(keys, dependency.Key{
+				GroupKind: schema.GroupKind{
+					Group: v1alpha1.SchemeGroupVersion.Group,
+					Kind:  "ClusterTemplateRevision",
+				},
+				NamespacedName: types.NamespacedName{
+					Name: v1alpha1.TemplateRevisionName(resource.TemplateRef.Kind, resource.TemplateRef.Name, revision),
+				},
+			})
+		}
+	}
+	return keys
+}
+
+// earliestRequeue returns the earlier of two requeue delays, where zero means
+// no requeue. A delay that has already passed is rounded up to a second.
+func earliestRequeue(requeueAfter time.Duration, other time.Duration) time.Duration {
+	if other < time.Second {
+		other = time.Second
+	}
+	if requeueAfter == 0 || other < requeueAfter {
+		return other
+	}
+	return requeueAfter
+}
+
+// ChildrenHealthRecheckInterval is how often the health of a resource whose
+// health rule evaluates its children is evaluated again. Changes to children
+// are not watched, so they are only seen when the owner is reconciled.
+const ChildrenHealthRecheckInterval = 30 * time.Second
+
+// ProbePendingRecheckInterval is how soon the health of a resource whose probe
+// has not completed is evaluated again
+const ProbePendingRecheckInterval = 2 * time.Second
+
+// requeueResult requeues the owner when a pending run is due, when the health
+// of a resource depends on its children or on a probe, or when a resource
+// stops flapping. Probe results are served for probeInterval.
+func requeueResult(resourceStatuses statuses.ResourceStatuses, probeInterval time.Duration, now time.Time) ctrl.Result {
+	result := pendingRunResult(resourceStatuses, now)
+	if resourceStatuses == nil {
+		return result
+	}
+
+	for _, resourceStatus := range resourceStatuses.GetCurrent() {
+		if flappingEndsAfter := statuses.FlappingEndsAfter(resourceStatus.HealthHistory, now); flappingEndsAfter > 0 {
+			result.RequeueAfter = earliestRequeue(result.RequeueAfter, flappingEndsAfter)
+		}
+		healthy := meta.FindStatusCondition(resourceStatus.Conditions, v1alpha1.ResourceHealthy)
+		if healthy == nil {
+			continue
+		}
+		if interval := healthRecheckInterval(healthy.Reason, probeInterval); interval > 0 {
+			result.RequeueAfter = earliestRequeue(result.RequeueAfter, interval)
+		}
+	}
+	return result
+}
+
+// healthRecheckInterval is when a health condition with the reason may change
+// without the owner being reconciled, or 0 when it may not
+func healthRecheckInterval(reason string, probeInterval time.Duration) time.Duration {
+	switch reason {
+	case v1alpha1.ChildrenHealthyReason, v1alpha1.ChildrenUnhealthyReason, v1alpha1.NoChildrenReason, v1alpha1.ChildrenListFailedReason:
+		return ChildrenHealthRecheckInterval
+	case v1alpha1.ProbePendingHealthyReason:
+		return ProbePendingRecheckInterval
+	case v1alpha1.ProbeSucceededHealthyReason, v1alpha1.ProbeFailedHealthyReason:
+		return probeInterval
+	}
+	return 0
+}
+
+// pendingRunResult requeues the owner when the earliest run pending in its
+// resources' debounce windows is due
+func pendingRunResult(resourceStatuses statuses.ResourceStatuses, now time.Time) ctrl.Result {
+	if resourceStatuses == nil {
+		return ctrl.Result{}
+	}
+
+	var requeueAfter time.Duration
+	for _, resourceStatus := range resourceStatuses.GetCurrent() {
+		if resourceStatus.PendingRun != nil {
+			requeueAfter = earliestRequeue(requeueAfter, resourceStatus.PendingRun.RunAfter.Sub(now))
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}
+}
+
+// cleanupResult requeues the owner when a retained run of a resource expires
+// by a TTL of its retention policy, so that it is cleaned up
+func cleanupResult(result ctrl.Result, err error, resourceRealizer realizer.ResourceRealizer, resourceStatuses statuses.ResourceStatuses) (ctrl.Result, error) {
+	if err != nil || resourceStatuses == nil {
+		return result, err
+	}
+
+	for _, resourceStatus := range resourceStatuses.GetCurrent() {
+		if cleanupAfter := resourceRealizer.CleanupAfter(resourceStatus.Name); cleanupAfter > 0 {
+			result.RequeueAfter = earliestRequeue(result.RequeueAfter, cleanupAfter)
+		}
+	}
+	return result, nil
+}
@@ -30,15 +30,27 @@ func (l *lifecycleReader) GetLifecycle() *templates.Lifecycle {
 func (l *lifecycleReader) GetDefaultParams() v1alpha1.TemplateParams {
 	panic("not implemented")
 }
+
 func (l *lifecycleReader) GetResourceTemplate() v1alpha1.TemplateSpec {
 	panic("not implemented")
 }
+
 func (l *lifecycleReader) GetHealthRule() *v1alpha1.HealthRule {
 	panic("not implemented")
 }
+
 func (l *lifecycleReader) IsYTTTemplate() bool {
 	panic("not implemented")
 }
+
 func (l *lifecycleReader) GetRetentionPolicy() v1alpha1.RetentionPolicy {
 	panic("not implemented")
 }
+
+func (l *lifecycleReader) GetRevision() int64 {
+	panic("not implemented")
+}
+
+func (l *lifecycleReader) GetBaseTemplates() []v1alpha1.TemplateReference {
+	panic("not implemented")
+}
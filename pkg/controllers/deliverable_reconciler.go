@@ -16,7 +16,9 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -31,6 +33,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	crtcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -43,6 +46,7 @@ import (
 	"github.com/vmware-tanzu/cartographer/pkg/events"
 	"github.com/vmware-tanzu/cartographer/pkg/logger"
 	"github.com/vmware-tanzu/cartographer/pkg/mapper"
+	"github.com/vmware-tanzu/cartographer/pkg/openapi"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer"
 	realizerclient "github.com/vmware-tanzu/cartographer/pkg/realizer/client"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
@@ -65,6 +69,7 @@ type DeliverableReconciler struct {
 	DependencyTracker       dependency.DependencyTracker
 	EventRecorder           record.EventRecorder
 	RESTMapper              meta.RESTMapper
+	Prober                  *healthcheck.Prober
 }
 
 func (r *DeliverableReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -94,7 +99,7 @@ func (r *DeliverableReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	conditionManager := r.ConditionManagerBuilder(v1alpha1.OwnerReady, deliverable.Status.Conditions)
 
-	delivery, err := r.getDeliveriesForDeliverable(ctx, deliverable, conditionManager)
+	delivery, selectionReason, err := r.getDeliveriesForDeliverable(ctx, deliverable, conditionManager)
 	if err != nil {
 		return r.completeReconciliation(ctx, deliverable, nil, conditionManager, err)
 	}
@@ -117,7 +122,7 @@ func (r *DeliverableReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		log.Info("delivery is not in ready state")
 		return r.completeReconciliation(ctx, deliverable, nil, conditionManager, fmt.Errorf("delivery [%s] is not in ready state", delivery.Name))
 	}
-	conditionManager.AddPositive(conditions.DeliveryReadyCondition())
+	conditionManager.AddPositive(conditions.DeliveryReadyCondition(selectionReason))
 
 	serviceAccountName, serviceAccountNS := getServiceAccountNameAndNamespaceForDeliverable(deliverable, delivery)
 
@@ -160,8 +165,9 @@ func (r *DeliverableReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	conditionManager.AddPositive(healthcheck.OwnerHealthCondition(resourceStatuses.GetCurrent(), deliverable.Status.Conditions))
+	conditionManager.Add(healthcheck.OwnerDegradedCondition(resourceStatuses.GetCurrent()), conditions.Informational)
 
-	r.trackDependencies(deliverable, resourceStatuses.GetCurrent(), serviceAccountName, serviceAccountNS)
+	r.trackDependencies(deliverable, delivery, resourceStatuses.GetCurrent(), serviceAccountName, serviceAccountNS)
 
 	cleanupErr := r.cleanupOrphanedObjects(ctx, deliverable.Status.Resources, resourceStatuses.GetCurrent())
 	if cleanupErr != nil {
@@ -187,7 +193,8 @@ func (r *DeliverableReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}
 
-	return r.completeReconciliation(ctx, deliverable, resourceStatuses, conditionManager, reconcileErr)
+	result, err := r.completeReconciliation(ctx, deliverable, resourceStatuses, conditionManager, reconcileErr)
+	return cleanupResult(result, err, resourceRealizer, resourceStatuses)
 }
 
 func (r *DeliverableReconciler) completeReconciliation(ctx context.Context, deliverable *v1alpha1.Deliverable, resourceStatuses statuses.ResourceStatuses, conditionManager conditions.ConditionManager, err error) (ctrl.Result, error) {
@@ -217,7 +224,7 @@ func (r *DeliverableReconciler) completeReconciliation(ctx context.Context, deli
 		log.Info("handled error reconciling deliverable", "handled error", err)
 	}
 
-	return ctrl.Result{}, nil
+	return requeueResult(resourceStatuses, r.Prober.Interval(), time.Now()), nil
 }
 
 func (r *DeliverableReconciler) isDeliveryReady(delivery *v1alpha1.ClusterDelivery) bool {
@@ -239,7 +246,7 @@ func buildDeliverableResourceLabeler(owner, blueprint client.Object) realizer.Re
 	}
 }
 
-func (r *DeliverableReconciler) trackDependencies(deliverable *v1alpha1.Deliverable, realizedResources []v1alpha1.ResourceStatus, serviceAccountName, serviceAccountNS string) {
+func (r *DeliverableReconciler) trackDependencies(deliverable *v1alpha1.Deliverable, delivery *v1alpha1.ClusterDelivery, realizedResources []v1alpha1.ResourceStatus, serviceAccountName, serviceAccountNS string) {
 	r.DependencyTracker.ClearTracked(types.NamespacedName{
 		Namespace: deliverable.Namespace,
 		Name:      deliverable.Name,
@@ -259,24 +266,32 @@ func (r *DeliverableReconciler) trackDependencies(deliverable *v1alpha1.Delivera
 		Name:      deliverable.Name,
 	})
 
-	for _, resource := range realizedResources {
-		if resource.TemplateRef == nil {
-			continue
-		}
-		r.DependencyTracker.Track(dependency.Key{
-			GroupKind: schema.GroupKind{
-				Group: v1alpha1.SchemeGroupVersion.Group,
-				Kind:  resource.TemplateRef.Kind,
-			},
-			NamespacedName: types.NamespacedName{
-				Namespace: "",
-				Name:      resource.TemplateRef.Name,
-			},
-		}, types.NamespacedName{
+	blueprintParams := [][]v1alpha1.BlueprintParam{delivery.Spec.Params}
+	for _, resource := range delivery.Spec.Resources {
+		blueprintParams = append(blueprintParams, resource.Params)
+	}
+	for _, key := range paramValueSourceKeys(deliverable.Namespace, deliverable.Spec.Params, blueprintParams...) {
+		r.DependencyTracker.Track(key, types.NamespacedName{
+			Namespace: deliverable.Namespace,
+			Name:      deliverable.Name,
+		})
+	}
+
+	for _, key := range templateRevisionKeys(realizer.MakeDeliveryOwnerResources(delivery)) {
+		r.DependencyTracker.Track(key, types.NamespacedName{
 			Namespace: deliverable.Namespace,
 			Name:      deliverable.Name,
 		})
 	}
+
+	for _, resource := range realizedResources {
+		for _, key := range templateKeys(resource) {
+			r.DependencyTracker.Track(key, types.NamespacedName{
+				Namespace: deliverable.Namespace,
+				Name:      deliverable.Name,
+			})
+		}
+	}
 }
 
 func (r *DeliverableReconciler) cleanupOrphanedObjects(ctx context.Context, previousResources, realizedResources []v1alpha1.ResourceStatus) error {
@@ -347,41 +362,47 @@ func getDeliveryReadyCondition(delivery *v1alpha1.ClusterDelivery) metav1.Condit
 	return metav1.Condition{}
 }
 
-func (r *DeliverableReconciler) getDeliveriesForDeliverable(ctx context.Context, deliverable *v1alpha1.Deliverable, conditionManager conditions.ConditionManager) (*v1alpha1.ClusterDelivery, error) {
+func (r *DeliverableReconciler) getDeliveriesForDeliverable(ctx context.Context, deliverable *v1alpha1.Deliverable, conditionManager conditions.ConditionManager) (*v1alpha1.ClusterDelivery, string, error) {
 	log := logr.FromContextOrDiscard(ctx)
 	if len(deliverable.Labels) == 0 {
 		conditionManager.AddPositive(conditions.DeliverableMissingLabelsCondition())
 		log.Info("deliverable is missing required labels")
-		return nil, fmt.Errorf("deliverable [%s/%s] is missing required labels",
+		return nil, "", fmt.Errorf("deliverable [%s/%s] is missing required labels",
 			deliverable.Namespace, deliverable.Name)
 	}
 
-	deliveries, err := r.Repo.GetDeliveriesForDeliverable(ctx, deliverable)
+	deliveries, selectionReason, err := r.Repo.GetDeliveriesForDeliverable(ctx, deliverable)
 	if err != nil {
+		var namespaceNotAllowedErr repository.NamespaceNotAllowedError
+		if errors.As(err, &namespaceNotAllowedErr) {
+			conditionManager.AddPositive(conditions.DeliveryNamespaceNotAllowedCondition(namespaceNotAllowedErr.Namespace, namespaceNotAllowedErr.Blueprints))
+			log.Info("deliveries matching labels do not allow the namespace", "deliveries", namespaceNotAllowedErr.Blueprints)
+			return nil, "", fmt.Errorf("no eligible deliveries for deliverable [%s/%s]: %w", deliverable.Namespace, deliverable.Name, err)
+		}
 		log.Error(err, "failed to get deliveries for deliverable")
-		return nil, cerrors.NewUnhandledError(fmt.Errorf("failed to get deliveries for deliverable [%s/%s]: %w",
+		return nil, "", cerrors.NewUnhandledError(fmt.Errorf("failed to get deliveries for deliverable [%s/%s]: %w",
 			deliverable.Namespace, deliverable.Name, err))
 	}
 
 	if len(deliveries) == 0 {
-		conditionManager.AddPositive(conditions.DeliveryNotFoundCondition(deliverable.Labels))
+		conditionManager.AddPositive(conditions.DeliveryNotFoundCondition(deliverable.Labels, selectionReason))
 		log.Info("no delivery found where full selector is satisfied by label",
 			"labels", deliverable.Labels)
-		return nil, fmt.Errorf("no delivery [%s/%s] found where full selector is satisfied by labels: %v",
+		return nil, "", fmt.Errorf("no delivery [%s/%s] found where full selector is satisfied by labels: %v",
 			deliverable.Namespace, deliverable.Name, deliverable.Labels)
 	}
 
 	if len(deliveries) > 1 {
-		conditionManager.AddPositive(conditions.TooManyDeliveryMatchesCondition())
+		conditionManager.AddPositive(conditions.TooManyDeliveryMatchesCondition(selectionReason))
 		log.Info("more than one delivery selected for deliverable",
 			"deliveries", getDeliveryNames(deliveries))
-		return nil, fmt.Errorf("more than one delivery selected for deliverable [%s/%s]: %+v",
+		return nil, "", fmt.Errorf("more than one delivery selected for deliverable [%s/%s]: %+v",
 			deliverable.Namespace, deliverable.Name, getDeliveryNames(deliveries))
 	}
 
 	delivery := deliveries[0]
 	log.V(logger.DEBUG).Info("delivery matched for deliverable", "delivery", delivery.Name)
-	return delivery, nil
+	return delivery, selectionReason, nil
 }
 
 func getDeliveryNames(objs []*v1alpha1.ClusterDelivery) []string {
@@ -428,8 +449,11 @@ func (r *DeliverableReconciler) SetupWithManager(mgr ctrl.Manager, concurrency i
 		repository.NewRepository,
 		realizerclient.NewClientBuilder(mgr.GetConfig()),
 		repository.NewCache(mgr.GetLogger().WithName("deliverable-stamping-repo-cache")),
+		templates.NewStampCache("deliverable", templates.DefaultStampCacheSize),
+		openapi.NewValidator(openapi.NewFetcher(clientSet.Discovery().RESTClient()), openapi.DefaultSchemaTTL),
+		r.Prober,
 	)
-	r.Realizer = realizer.NewRealizer(nil, r.RESTMapper)
+	r.Realizer = realizer.NewRealizer(nil, r.Prober, r.RESTMapper)
 	r.DependencyTracker = dependency.NewDependencyTracker(
 		2*utils.DefaultResyncTime,
 		mgr.GetLogger().WithName("tracker-deliverable"),
@@ -448,6 +472,7 @@ func (r *DeliverableReconciler) SetupWithManager(mgr ctrl.Manager, concurrency i
 	watches := map[client.Object]handler.MapFunc{
 		&v1alpha1.ClusterDelivery{}:  m.ClusterDeliveryToDeliverableRequests,
 		&corev1.ServiceAccount{}:     m.ServiceAccountToDeliverableRequests,
+		&corev1.Namespace{}:          m.NamespaceToDeliverableRequests,
 		&rbacv1.Role{}:               m.RoleToDeliverableRequests,
 		&rbacv1.RoleBinding{}:        m.RoleBindingToDeliverableRequests,
 		&rbacv1.ClusterRole{}:        m.ClusterRoleToDeliverableRequests,
@@ -461,6 +486,21 @@ func (r *DeliverableReconciler) SetupWithManager(mgr ctrl.Manager, concurrency i
 		)
 	}
 
+	// params are only sourced from tracked ConfigMaps and Secrets, so only their
+	// metadata is cached rather than the content of every Secret in the cluster
+	for _, paramSource := range []client.Object{&corev1.ConfigMap{}, &corev1.Secret{}} {
+		builder = builder.Watches(
+			&source.Kind{Type: paramSource},
+			enqueuer.EnqueueTracked(paramSource, r.DependencyTracker, mgr.GetScheme()),
+			ctrlbuilder.OnlyMetadata,
+		)
+	}
+
+	builder = builder.Watches(
+		&source.Kind{Type: &v1alpha1.ClusterTemplateRevision{}},
+		enqueuer.EnqueueTracked(&v1alpha1.ClusterTemplateRevision{}, r.DependencyTracker, mgr.GetScheme()),
+	)
+
 	for _, template := range v1alpha1.ValidDeliveryTemplates {
 		builder = builder.Watches(
 			&source.Kind{Type: template},
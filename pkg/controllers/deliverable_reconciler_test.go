@@ -44,6 +44,7 @@ import (
 	"github.com/vmware-tanzu/cartographer/pkg/controllers/controllersfakes"
 	cerrors "github.com/vmware-tanzu/cartographer/pkg/errors"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/realizerfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/statuses"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
@@ -120,6 +121,7 @@ var _ = Describe("DeliverableReconciler", func() {
 				return nil, resourceRealizerBuilderError
 			}
 			resourceRealizerAuthToken = authToken
+			builtResourceRealizer = &realizerfakes.FakeResourceRealizer{}
 			return builtResourceRealizer, nil
 		}
 
@@ -131,6 +133,7 @@ var _ = Describe("DeliverableReconciler", func() {
 			Realizer:                rlzr,
 			StampedTracker:          stampedTracker,
 			DependencyTracker:       dependencyTracker,
+			Prober:                  healthcheck.NewProber(healthcheck.ProbeInterval, nil),
 		}
 
 		req = ctrl.Request{
@@ -249,7 +252,7 @@ var _ = Describe("DeliverableReconciler", func() {
 					},
 				},
 			}
-			repo.GetDeliveriesForDeliverableReturns([]*v1alpha1.ClusterDelivery{&delivery}, nil)
+			repo.GetDeliveriesForDeliverableReturns([]*v1alpha1.ClusterDelivery{&delivery}, "selected as the most specific match", nil)
 
 			resourceStatuses = statuses.NewResourceStatuses(nil, conditions.AddConditionForResourceSubmittedDeliverable)
 			resourceStatuses.Add(
@@ -460,7 +463,7 @@ var _ = Describe("DeliverableReconciler", func() {
 
 		It("calls the condition manager to specify the delivery is ready", func() {
 			_, _ = reconciler.Reconcile(ctx, req)
-			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.DeliveryReadyCondition()))
+			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.DeliveryReadyCondition("selected as the most specific match")))
 		})
 
 		It("calls the condition manager to report the resources have been submitted", func() {
@@ -468,6 +471,14 @@ var _ = Describe("DeliverableReconciler", func() {
 			Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(conditions.ResourcesSubmittedCondition(true)))
 		})
 
+		It("calls the condition manager to report whether the resources are degraded", func() {
+			_, _ = reconciler.Reconcile(ctx, req)
+			Expect(conditionManager.AddCallCount()).To(Equal(1))
+			condition, polarity := conditionManager.AddArgsForCall(0)
+			Expect(condition).To(Equal(conditions.ResourcesStableCondition()))
+			Expect(polarity).To(Equal(conditions.Informational))
+		})
+
 		It("watches the stampedObjects kinds", func() {
 			_, _ = reconciler.Reconcile(ctx, req)
 			Expect(stampedTracker.WatchCallCount()).To(Equal(2))
@@ -545,7 +556,7 @@ var _ = Describe("DeliverableReconciler", func() {
 						Message: "some informative message",
 					},
 				}
-				repo.GetDeliveriesForDeliverableReturns([]*v1alpha1.ClusterDelivery{&delivery}, nil)
+				repo.GetDeliveriesForDeliverableReturns([]*v1alpha1.ClusterDelivery{&delivery}, "selected as the most specific match", nil)
 			})
 
 			It("does not return an error", func() {
@@ -1408,6 +1419,17 @@ var _ = Describe("DeliverableReconciler", func() {
 	})
 
 	Context("and repo returns an empty list of deliveries", func() {
+		Context("and the repo explains why no deliveries matched", func() {
+			BeforeEach(func() {
+				repo.GetDeliveriesForDeliverableReturns(nil, "[web]: did not match: unsatisfied label [type=web]", nil)
+			})
+
+			It("includes the explanation in the condition", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+				Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.DeliveryNotFoundCondition(deliverableLabels, "[web]: did not match: unsatisfied label [type=web]")))
+			})
+		})
+
 		It("does not return an error", func() {
 			_, err := reconciler.Reconcile(ctx, req)
 			Expect(err).NotTo(HaveOccurred())
@@ -1415,7 +1437,7 @@ var _ = Describe("DeliverableReconciler", func() {
 
 		It("calls the condition manager to add a delivery not found condition", func() {
 			_, _ = reconciler.Reconcile(ctx, req)
-			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.DeliveryNotFoundCondition(deliverableLabels)))
+			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.DeliveryNotFoundCondition(deliverableLabels, "")))
 		})
 
 		It("logs the handled error message", func() {
@@ -1430,7 +1452,7 @@ var _ = Describe("DeliverableReconciler", func() {
 
 	Context("and repo returns an an error when requesting deliveries", func() {
 		BeforeEach(func() {
-			repo.GetDeliveriesForDeliverableReturns(nil, errors.New("some error"))
+			repo.GetDeliveriesForDeliverableReturns(nil, "", errors.New("some error"))
 		})
 
 		It("returns an unhandled error and requeues", func() {
@@ -1439,6 +1461,31 @@ var _ = Describe("DeliverableReconciler", func() {
 		})
 	})
 
+	Context("and the repo reports that matching deliveries do not allow the namespace", func() {
+		BeforeEach(func() {
+			repo.GetDeliveriesForDeliverableReturns(nil, "", fmt.Errorf("evaluating selectors: %w", repository.NamespaceNotAllowedError{
+				Namespace:  "my-namespace",
+				Blueprints: []string{"production"},
+			}))
+		})
+
+		It("calls the condition manager to report the namespace is not allowed", func() {
+			_, _ = reconciler.Reconcile(ctx, req)
+			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.DeliveryNamespaceNotAllowedCondition("my-namespace", []string{"production"})))
+		})
+
+		It("does not return an error", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("logs the handled error message", func() {
+			_, _ = reconciler.Reconcile(ctx, req)
+
+			Expect(out).To(Say(`"handled error":"no eligible deliveries for deliverable \[my-namespace/my-deliverable\]: evaluating selectors: namespace \[my-namespace\] is not allowed by the namespaceSelector of \[production\]"`))
+		})
+	})
+
 	Context("and the repo returns multiple deliveries", func() {
 		BeforeEach(func() {
 			delivery := v1alpha1.ClusterDelivery{
@@ -1452,7 +1499,7 @@ var _ = Describe("DeliverableReconciler", func() {
 				Version: "alphabeta1",
 				Kind:    "MyThing",
 			})
-			repo.GetDeliveriesForDeliverableReturns([]*v1alpha1.ClusterDelivery{&delivery, &delivery}, nil)
+			repo.GetDeliveriesForDeliverableReturns([]*v1alpha1.ClusterDelivery{&delivery, &delivery}, "", nil)
 		})
 
 		It("does not return an error", func() {
@@ -1462,7 +1509,7 @@ var _ = Describe("DeliverableReconciler", func() {
 
 		It("calls the condition manager to report too mane deliveries matched", func() {
 			_, _ = reconciler.Reconcile(ctx, req)
-			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.TooManyDeliveryMatchesCondition()))
+			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.TooManyDeliveryMatchesCondition("")))
 		})
 
 		It("logs the handled error message", func() {
@@ -1537,7 +1584,7 @@ var _ = Describe("DeliverableReconciler", func() {
 					},
 				},
 			}
-			repo.GetDeliveriesForDeliverableReturns([]*v1alpha1.ClusterDelivery{&delivery}, nil)
+			repo.GetDeliveriesForDeliverableReturns([]*v1alpha1.ClusterDelivery{&delivery}, "selected as the most specific match", nil)
 
 			rlzr.RealizeReturns(nil)
 
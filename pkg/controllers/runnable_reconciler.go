@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -25,10 +26,13 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,9 +48,11 @@ import (
 	"github.com/vmware-tanzu/cartographer/pkg/logger"
 	"github.com/vmware-tanzu/cartographer/pkg/mapper"
 	realizerclient "github.com/vmware-tanzu/cartographer/pkg/realizer/client"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
 	realizer "github.com/vmware-tanzu/cartographer/pkg/realizer/runnable"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
 	"github.com/vmware-tanzu/cartographer/pkg/satoken"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
 	"github.com/vmware-tanzu/cartographer/pkg/tracker/dependency"
 	"github.com/vmware-tanzu/cartographer/pkg/tracker/stamped"
 	"github.com/vmware-tanzu/cartographer/pkg/utils"
@@ -61,9 +67,17 @@ type RunnableReconciler struct {
 	ClientBuilder           realizerclient.ClientBuilder
 	RunnableCache           repository.RepoCache
 	StampedTracker          stamped.StampedTracker
+	SelectedTracker         stamped.StampedTracker
 	DependencyTracker       dependency.DependencyTracker
 	EventRecorder           record.EventRecorder
 	RESTMapper              meta.RESTMapper
+	Clock                   clock.PassiveClock
+	Prober                  *healthcheck.Prober
+}
+
+type reconcileOutcome struct {
+	statusChanged bool
+	requeueAfter  time.Duration
 }
 
 func (r *RunnableReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -93,6 +107,12 @@ func (r *RunnableReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	conditionManager := r.ConditionManagerBuilder(v1alpha1.RunnableReady, runnable.Status.Conditions)
 
+	outcome, err := r.evaluateSchedule(ctx, runnable)
+	if err != nil {
+		conditionManager.AddPositive(conditions.InvalidScheduleCondition(err))
+		return r.completeReconciliation(ctx, runnable, nil, conditionManager, outcome, fmt.Errorf("failed to evaluate schedule: %w", err))
+	}
+
 	serviceAccountName := "default"
 	if runnable.Spec.ServiceAccountName != "" {
 		serviceAccountName = runnable.Spec.ServiceAccountName
@@ -103,23 +123,24 @@ func (r *RunnableReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	serviceAccount, err := r.Repo.GetServiceAccount(ctx, serviceAccountName, req.Namespace)
 	if err != nil {
 		conditionManager.AddPositive(conditions.RunnableServiceAccountNotFoundCondition(err))
-		return r.completeReconciliation(ctx, runnable, nil, conditionManager, fmt.Errorf("failed to get service account [%s]: %w", fmt.Sprintf("%s/%s", req.Namespace, serviceAccountName), err))
+		return r.completeReconciliation(ctx, runnable, nil, conditionManager, outcome, fmt.Errorf("failed to get service account [%s]: %w", fmt.Sprintf("%s/%s", req.Namespace, serviceAccountName), err))
 	}
 
 	saToken, err := r.TokenManager.GetServiceAccountToken(serviceAccount)
 	if err != nil {
 		conditionManager.AddPositive(conditions.RunnableServiceAccountTokenErrorCondition(err))
 		log.Info("failed to get token for service account", "service account", fmt.Sprintf("%s/%s", req.Namespace, serviceAccountName))
-		return r.completeReconciliation(ctx, runnable, nil, conditionManager, fmt.Errorf("failed to get token for service account [%s]: %w", fmt.Sprintf("%s/%s", req.Namespace, serviceAccountName), err))
+		return r.completeReconciliation(ctx, runnable, nil, conditionManager, outcome, fmt.Errorf("failed to get token for service account [%s]: %w", fmt.Sprintf("%s/%s", req.Namespace, serviceAccountName), err))
 	}
 
 	runnableClient, discoveryClient, err := r.ClientBuilder(saToken, true)
 	if err != nil {
 		conditionManager.AddPositive(conditions.ClientBuilderErrorCondition(err))
-		return r.completeReconciliation(ctx, runnable, nil, conditionManager, cerrors.NewUnhandledError(fmt.Errorf("failed to build resource realizer: %w", err)))
+		return r.completeReconciliation(ctx, runnable, nil, conditionManager, outcome, cerrors.NewUnhandledError(fmt.Errorf("failed to build resource realizer: %w", err)))
 	}
 
-	stampedObject, outputs, err := r.Realizer.Realize(ctx, runnable, r.Repo, r.RepositoryBuilder(runnableClient, r.RunnableCache), discoveryClient)
+	previousStatus := runnable.Status.DeepCopy()
+	stampedObject, outputs, stampedCondition, cleanupAfter, err := r.Realizer.Realize(ctx, runnable, r.Repo, r.RepositoryBuilder(runnableClient, r.RunnableCache), discoveryClient)
 	if err != nil {
 		log.V(logger.DEBUG).Info("failed to realize")
 		switch typedErr := err.(type) {
@@ -149,13 +170,26 @@ func (r *RunnableReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		conditionManager.AddPositive(conditions.RunTemplateReadyCondition())
 	}
 
+	if !reflect.DeepEqual(previousStatus.RunHistory, runnable.Status.RunHistory) ||
+		previousStatus.RunRequest != runnable.Status.RunRequest ||
+		!reflect.DeepEqual(previousStatus.PendingRun, runnable.Status.PendingRun) {
+		outcome.statusChanged = true
+	}
+
+	if runnable.Status.PendingRun != nil {
+		outcome.requeueAfter = earliestRequeue(outcome.requeueAfter, runnable.Status.PendingRun.RunAfter.Sub(r.Clock.Now()))
+	}
+
+	if cleanupAfter > 0 {
+		outcome.requeueAfter = earliestRequeue(outcome.requeueAfter, cleanupAfter)
+	}
+
 	var stampedObjectStatusPresent = false
 	var trackingError error
 
 	if stampedObject != nil {
-		stampedCondition := utils.ExtractConditions(stampedObject).ConditionWithType("Succeeded")
 		if stampedCondition != nil {
-			conditionManager.AddPositive(conditions.StampedObjectConditionKnown(stampedCondition))
+			conditionManager.AddPositive(*stampedCondition)
 			stampedObjectStatusPresent = true
 		}
 		trackingError = r.StampedTracker.Watch(log, stampedObject, &handler.EnqueueRequestForOwner{OwnerType: &v1alpha1.Runnable{}})
@@ -166,19 +200,29 @@ func (r *RunnableReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			log.V(logger.DEBUG).Info("added informer for object", "object", stampedObject)
 		}
 	}
+	if runnable.Spec.Selector != nil {
+		selectedKind := &unstructured.Unstructured{}
+		selectedKind.SetAPIVersion(runnable.Spec.Selector.Resource.APIVersion)
+		selectedKind.SetKind(runnable.Spec.Selector.Resource.Kind)
+		if watchErr := r.SelectedTracker.Watch(log, selectedKind, enqueuer.EnqueueTrackedByLabels(r.DependencyTracker)); watchErr != nil {
+			log.Error(watchErr, "failed to add informer for selected objects", "selector", runnable.Spec.Selector)
+			err = cerrors.NewUnhandledError(watchErr)
+		}
+	}
+
 	if !stampedObjectStatusPresent {
 		conditionManager.AddPositive(conditions.StampedObjectConditionUnknown())
 	}
 
-	return r.completeReconciliation(ctx, runnable, outputs, conditionManager, err)
+	return r.completeReconciliation(ctx, runnable, outputs, conditionManager, outcome, err)
 }
 
-func (r *RunnableReconciler) completeReconciliation(ctx context.Context, runnable *v1alpha1.Runnable, outputs map[string]apiextensionsv1.JSON, conditionManager conditions.ConditionManager, err error) (ctrl.Result, error) {
+func (r *RunnableReconciler) completeReconciliation(ctx context.Context, runnable *v1alpha1.Runnable, outputs map[string]apiextensionsv1.JSON, conditionManager conditions.ConditionManager, outcome reconcileOutcome, err error) (ctrl.Result, error) {
 	log := logr.FromContextOrDiscard(ctx)
 	var changed bool
 	runnable.Status.Conditions, changed = conditionManager.Finalize()
 
-	if changed || (runnable.Status.ObservedGeneration != runnable.Generation) || !reflect.DeepEqual(runnable.Status.Outputs, outputs) {
+	if changed || outcome.statusChanged || (runnable.Status.ObservedGeneration != runnable.Generation) || !reflect.DeepEqual(runnable.Status.Outputs, outputs) {
 		runnable.Status.Outputs = outputs
 		runnable.Status.ObservedGeneration = runnable.Generation
 		statusUpdateError := r.Repo.StatusUpdate(ctx, runnable)
@@ -195,7 +239,61 @@ func (r *RunnableReconciler) completeReconciliation(ctx context.Context, runnabl
 		log.Info("handled error reconciling runnable", "handled error", err)
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: outcome.requeueAfter}, nil
+}
+
+// evaluateSchedule records the most recent due tick of the runnable's schedule
+// in its status, so that the realizer stamps a new run for it, and computes
+// when the runnable should next be reconciled.
+func (r *RunnableReconciler) evaluateSchedule(ctx context.Context, runnable *v1alpha1.Runnable) (reconcileOutcome, error) {
+	if runnable.Spec.Schedule == nil {
+		if runnable.Status.LastScheduleTime == nil && runnable.Status.NextScheduleTime == nil {
+			return reconcileOutcome{}, nil
+		}
+		runnable.Status.LastScheduleTime = nil
+		runnable.Status.NextScheduleTime = nil
+		return reconcileOutcome{statusChanged: true}, nil
+	}
+
+	log := logr.FromContextOrDiscard(ctx)
+	now := r.Clock.Now()
+
+	last := runnable.CreationTimestamp.Time
+	if runnable.Status.LastScheduleTime != nil {
+		last = runnable.Status.LastScheduleTime.Time
+	}
+
+	run, err := realizer.EvaluateSchedule(runnable.Spec.Schedule, last, now)
+	if err != nil {
+		outcome := reconcileOutcome{statusChanged: runnable.Status.NextScheduleTime != nil}
+		runnable.Status.NextScheduleTime = nil
+		return outcome, err
+	}
+
+	outcome := reconcileOutcome{requeueAfter: run.Next.Sub(now)}
+
+	if run.Tick != nil {
+		log.Info("scheduling a new run", "tick", run.Tick, "missed", run.Missed)
+		runnable.Status.LastScheduleTime = &metav1.Time{Time: *run.Tick}
+		outcome.statusChanged = true
+
+		rec := events.FromContextOrDie(ctx)
+		if run.TooManyMissed {
+			rec.Eventf(events.NormalType, events.ScheduledRunReason, "Scheduled a run for [%s], skipping more than [%d] missed ticks", run.Tick.UTC().Format(time.RFC3339), run.Missed)
+		} else if run.Missed > 0 {
+			rec.Eventf(events.NormalType, events.ScheduledRunReason, "Scheduled a run for [%s], skipping [%d] missed ticks", run.Tick.UTC().Format(time.RFC3339), run.Missed)
+		} else {
+			rec.Eventf(events.NormalType, events.ScheduledRunReason, "Scheduled a run for [%s]", run.Tick.UTC().Format(time.RFC3339))
+		}
+	}
+
+	next := metav1.NewTime(run.Next)
+	if runnable.Status.NextScheduleTime == nil || !runnable.Status.NextScheduleTime.Equal(&next) {
+		runnable.Status.NextScheduleTime = &next
+		outcome.statusChanged = true
+	}
+
+	return outcome, nil
 }
 
 func (r *RunnableReconciler) trackDependencies(runnable *v1alpha1.Runnable, serviceAccountName string) {
@@ -230,6 +328,21 @@ func (r *RunnableReconciler) trackDependencies(runnable *v1alpha1.Runnable, serv
 		Namespace: runnable.Namespace,
 		Name:      runnable.Name,
 	})
+
+	if runnable.Spec.Selector != nil {
+		selectedGroupKind := schema.FromAPIVersionAndKind(runnable.Spec.Selector.Resource.APIVersion, runnable.Spec.Selector.Resource.Kind).GroupKind()
+		// the scope of the selected kind is not known here, so objects are
+		// tracked both in the runnable's namespace and at cluster scope
+		for _, namespace := range []string{runnable.Namespace, ""} {
+			r.DependencyTracker.Track(
+				dependency.NewLabelSelectorKey(selectedGroupKind, namespace, runnable.Spec.Selector.MatchingLabels),
+				types.NamespacedName{
+					Namespace: runnable.Namespace,
+					Name:      runnable.Name,
+				},
+			)
+		}
+	}
 }
 
 func (r *RunnableReconciler) SetupWithManager(mgr ctrl.Manager, concurrency int) error {
@@ -248,11 +361,12 @@ func (r *RunnableReconciler) SetupWithManager(mgr ctrl.Manager, concurrency int)
 		repository.NewCache(mgr.GetLogger().WithName("runnable-repo-cache")),
 	)
 
-	r.Realizer = realizer.NewRealizer(mgr.GetRESTMapper())
 	r.RunnableCache = repository.NewCache(mgr.GetLogger().WithName("runnable-stamping-repo-cache"))
 	r.RepositoryBuilder = repository.NewRepository
 	r.ClientBuilder = realizerclient.NewClientBuilder(mgr.GetConfig())
 	r.ConditionManagerBuilder = conditions.NewConditionManager
+	r.Clock = clock.RealClock{}
+	r.Realizer = realizer.NewRealizer(mgr.GetRESTMapper(), templates.NewStampCache("runnable", templates.DefaultStampCacheSize), r.Prober, r.Clock)
 	r.DependencyTracker = dependency.NewDependencyTracker(
 		2*utils.DefaultResyncTime,
 		mgr.GetLogger().WithName("tracker-runnable"),
@@ -291,6 +405,7 @@ func (r *RunnableReconciler) SetupWithManager(mgr ctrl.Manager, concurrency int)
 		return fmt.Errorf("failed to build controller for runnable: %w", err)
 	}
 	r.StampedTracker = &external.ObjectTracker{Controller: controller}
+	r.SelectedTracker = &external.ObjectTracker{Controller: controller}
 
 	return nil
 }
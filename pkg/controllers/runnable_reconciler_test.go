@@ -17,6 +17,7 @@ package controllers_test
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo"
@@ -31,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
+	clocktesting "k8s.io/utils/clock/testing"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -41,6 +43,7 @@ import (
 	"github.com/vmware-tanzu/cartographer/pkg/conditions/conditionsfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/controllers"
 	cerrors "github.com/vmware-tanzu/cartographer/pkg/errors"
+	"github.com/vmware-tanzu/cartographer/pkg/events"
 	"github.com/vmware-tanzu/cartographer/pkg/events/eventsfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/runnable/runnablefakes"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
@@ -61,6 +64,7 @@ var _ = Describe("Reconcile", func() {
 		tokenManager             *satokenfakes.FakeTokenManager
 		rlzr                     *runnablefakes.FakeRealizer
 		stampedTracker           *stampedfakes.FakeStampedTracker
+		selectedTracker          *stampedfakes.FakeStampedTracker
 		dependencyTracker        *dependencyfakes.FakeDependencyTracker
 		conditionManager         *conditionsfakes.FakeConditionManager
 		builtClient              *repositoryfakes.FakeClient
@@ -84,6 +88,7 @@ var _ = Describe("Reconcile", func() {
 		tokenManager = &satokenfakes.FakeTokenManager{}
 		rlzr = &runnablefakes.FakeRealizer{}
 		stampedTracker = &stampedfakes.FakeStampedTracker{}
+		selectedTracker = &stampedfakes.FakeStampedTracker{}
 		dependencyTracker = &dependencyfakes.FakeDependencyTracker{}
 		conditionManager = &conditionsfakes.FakeConditionManager{}
 		fakeCache = &repositoryfakes.FakeRepoCache{}
@@ -117,6 +122,7 @@ var _ = Describe("Reconcile", func() {
 			TokenManager:            tokenManager,
 			Realizer:                rlzr,
 			StampedTracker:          stampedTracker,
+			SelectedTracker:         selectedTracker,
 			ConditionManagerBuilder: fakeConditionManagerBuilder,
 			RunnableCache:           fakeCache,
 			ClientBuilder:           clientBuilder,
@@ -241,6 +247,58 @@ var _ = Describe("Reconcile", func() {
 			Expect(runTemplateKey.String()).To(Equal("ClusterRunTemplate.carto.run//my-run-template"))
 		})
 
+		Context("with a selector", func() {
+			BeforeEach(func() {
+				rb.Spec.Selector = &v1alpha1.ResourceSelector{
+					Resource: v1alpha1.ResourceType{
+						APIVersion: "thing.io/alphabeta1",
+						Kind:       "MyThing",
+					},
+					MatchingLabels: map[string]string{"suite": "smoke", "app": "web"},
+				}
+			})
+
+			It("tracks the selected kind by the selector's labels, in the namespace and at cluster scope", func() {
+				_, _ = reconciler.Reconcile(ctx, request)
+
+				Expect(dependencyTracker.TrackCallCount()).To(Equal(4))
+				namespacedKey, obj := dependencyTracker.TrackArgsForCall(2)
+				Expect(namespacedKey.String()).To(Equal("MyThing.thing.io/my-namespace/app=web"))
+				Expect(obj).To(Equal(types.NamespacedName{Namespace: "my-namespace", Name: "my-runnable"}))
+
+				clusterKey, _ := dependencyTracker.TrackArgsForCall(3)
+				Expect(clusterKey.String()).To(Equal("MyThing.thing.io//app=web"))
+			})
+
+			It("watches the selected kind", func() {
+				_, _ = reconciler.Reconcile(ctx, request)
+
+				Expect(selectedTracker.WatchCallCount()).To(Equal(1))
+				_, obj, _, _ := selectedTracker.WatchArgsForCall(0)
+				Expect(obj.GetObjectKind().GroupVersionKind()).To(Equal(schema.GroupVersionKind{
+					Group:   "thing.io",
+					Version: "alphabeta1",
+					Kind:    "MyThing",
+				}))
+			})
+
+			Context("and watching the selected kind fails", func() {
+				It("returns an unhandled error", func() {
+					selectedTracker.WatchReturns(errors.New("could not watch"))
+
+					_, err := reconciler.Reconcile(ctx, request)
+					Expect(err).To(MatchError(ContainSubstring("could not watch")))
+				})
+			})
+		})
+
+		Context("without a selector", func() {
+			It("does not watch any selected kind", func() {
+				_, _ = reconciler.Reconcile(ctx, request)
+				Expect(selectedTracker.WatchCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("watching does not cause an error", func() {
 			It("watches the stampedObject's kind", func() {
 				stampedObject := &unstructured.Unstructured{}
@@ -249,7 +307,7 @@ var _ = Describe("Reconcile", func() {
 					Version: "alphabeta1",
 					Kind:    "MyThing",
 				})
-				rlzr.RealizeReturns(stampedObject, nil, nil)
+				rlzr.RealizeReturns(stampedObject, nil, nil, 0, nil)
 
 				_, _ = reconciler.Reconcile(ctx, request)
 				Expect(stampedTracker.WatchCallCount()).To(Equal(1))
@@ -260,10 +318,43 @@ var _ = Describe("Reconcile", func() {
 			})
 		})
 
+		Context("the realizer reports the stamped object condition", func() {
+			It("adds the condition", func() {
+				stampedCondition := conditions.StampedObjectHealthRuleCondition(metav1.Condition{
+					Status:  metav1.ConditionFalse,
+					Message: "job failed",
+				})
+				rlzr.RealizeReturns(&unstructured.Unstructured{}, nil, &stampedCondition, 0, nil)
+
+				_, _ = reconciler.Reconcile(ctx, request)
+
+				var added []metav1.Condition
+				for i := 0; i < conditionManager.AddPositiveCallCount(); i++ {
+					added = append(added, conditionManager.AddPositiveArgsForCall(i))
+				}
+				Expect(added).To(ContainElement(stampedCondition))
+				Expect(added).NotTo(ContainElement(conditions.StampedObjectConditionUnknown()))
+			})
+		})
+
+		Context("the realizer does not report the stamped object condition", func() {
+			It("adds an unknown condition", func() {
+				rlzr.RealizeReturns(&unstructured.Unstructured{}, nil, nil, 0, nil)
+
+				_, _ = reconciler.Reconcile(ctx, request)
+
+				var added []metav1.Condition
+				for i := 0; i < conditionManager.AddPositiveCallCount(); i++ {
+					added = append(added, conditionManager.AddPositiveArgsForCall(i))
+				}
+				Expect(added).To(ContainElement(conditions.StampedObjectConditionUnknown()))
+			})
+		})
+
 		Context("watching causes an error", func() {
 			BeforeEach(func() {
 				stampedObject := &unstructured.Unstructured{}
-				rlzr.RealizeReturns(stampedObject, nil, nil)
+				rlzr.RealizeReturns(stampedObject, nil, nil, 0, nil)
 
 				stampedTracker.WatchReturns(errors.New("could not watch"))
 			})
@@ -328,7 +419,7 @@ var _ = Describe("Reconcile", func() {
 
 		Context("no outputs were returned from the realizer", func() {
 			BeforeEach(func() {
-				rlzr.RealizeReturns(nil, nil, nil)
+				rlzr.RealizeReturns(nil, nil, nil, 0, nil)
 			})
 
 			It("fetches the runnable", func() {
@@ -361,11 +452,248 @@ var _ = Describe("Reconcile", func() {
 			})
 		})
 
+		Context("the realizer updates the run history", func() {
+			var history []v1alpha1.RunRecord
+
+			BeforeEach(func() {
+				rb.Status.ObservedGeneration = rb.Generation
+				history = []v1alpha1.RunRecord{{
+					StampedRef:   &v1alpha1.StampedRef{ObjectReference: &corev1.ObjectReference{Name: "my-stamped-resource-abcde"}},
+					InputsDigest: "sha256:abc123",
+					Health:       metav1.ConditionUnknown,
+				}}
+				rlzr.RealizeStub = func(_ context.Context, runnable *v1alpha1.Runnable, _ repository.Repository, _ repository.Repository, _ discovery.DiscoveryInterface) (*unstructured.Unstructured, templates.Outputs, *metav1.Condition, time.Duration, error) {
+					runnable.Status.RunHistory = history
+					return nil, nil, nil, 0, nil
+				}
+			})
+
+			It("updates the status with the run history", func() {
+				_, err := reconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+				_, obj := repo.StatusUpdateArgsForCall(0)
+				statusObject, ok := obj.(*v1alpha1.Runnable)
+				Expect(ok).To(BeTrue())
+
+				Expect(statusObject.Status.RunHistory).To(Equal(history))
+			})
+
+			Context("and the run history has not changed", func() {
+				BeforeEach(func() {
+					rb.Status.RunHistory = history
+				})
+
+				It("does not update the status", func() {
+					_, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(repo.StatusUpdateCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("the realizer records a pending run", func() {
+			var now time.Time
+			var pendingRun *v1alpha1.PendingRun
+
+			BeforeEach(func() {
+				rb.Status.ObservedGeneration = rb.Generation
+				now = time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+				reconciler.Clock = clocktesting.NewFakePassiveClock(now)
+				pendingRun = &v1alpha1.PendingRun{
+					InputsDigest: "sha256:abc123",
+					RunAfter:     metav1.NewTime(now.Add(45 * time.Second)),
+				}
+				rlzr.RealizeStub = func(_ context.Context, runnable *v1alpha1.Runnable, _ repository.Repository, _ repository.Repository, _ discovery.DiscoveryInterface) (*unstructured.Unstructured, templates.Outputs, *metav1.Condition, time.Duration, error) {
+					runnable.Status.PendingRun = pendingRun
+					return nil, nil, nil, 0, nil
+				}
+			})
+
+			It("updates the status with the pending run", func() {
+				_, err := reconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+				_, obj := repo.StatusUpdateArgsForCall(0)
+				statusObject, ok := obj.(*v1alpha1.Runnable)
+				Expect(ok).To(BeTrue())
+
+				Expect(statusObject.Status.PendingRun).To(Equal(pendingRun))
+			})
+
+			It("requeues when the pending run is due", func() {
+				result, err := reconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(45 * time.Second))
+			})
+
+			Context("and the pending run is already due", func() {
+				BeforeEach(func() {
+					pendingRun.RunAfter = metav1.NewTime(now.Add(-time.Second))
+				})
+
+				It("requeues promptly", func() {
+					result, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.RequeueAfter).To(Equal(time.Second))
+				})
+			})
+		})
+
+		Context("the realizer reports that a retained run expires", func() {
+			BeforeEach(func() {
+				rlzr.RealizeReturns(nil, nil, nil, 10*time.Minute, nil)
+			})
+
+			It("requeues to clean it up", func() {
+				result, err := reconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(10 * time.Minute))
+			})
+		})
+
+		Context("the runnable has a schedule", func() {
+			var now time.Time
+
+			BeforeEach(func() {
+				now = time.Date(2022, 3, 5, 2, 0, 30, 0, time.UTC)
+				reconciler.Clock = clocktesting.NewFakePassiveClock(now)
+
+				rb.CreationTimestamp = metav1.NewTime(time.Date(2022, 3, 4, 12, 0, 0, 0, time.UTC))
+				rb.Spec.Schedule = &v1alpha1.RunnableSchedule{Cron: "0 2 * * *"}
+				rlzr.RealizeReturns(nil, nil, nil, 0, nil)
+			})
+
+			Context("and a tick is due", func() {
+				It("records the tick before realizing so that a new run is stamped", func() {
+					_, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(rlzr.RealizeCallCount()).To(Equal(1))
+					_, realizedRunnable, _, _, _ := rlzr.RealizeArgsForCall(0)
+					Expect(realizedRunnable.Status.LastScheduleTime.Time).To(Equal(time.Date(2022, 3, 5, 2, 0, 0, 0, time.UTC)))
+				})
+
+				It("updates the status with the last and next schedule times", func() {
+					_, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+					_, obj := repo.StatusUpdateArgsForCall(0)
+					status := obj.(*v1alpha1.Runnable).Status
+					Expect(status.LastScheduleTime.Time).To(Equal(time.Date(2022, 3, 5, 2, 0, 0, 0, time.UTC)))
+					Expect(status.NextScheduleTime.Time).To(Equal(time.Date(2022, 3, 6, 2, 0, 0, 0, time.UTC)))
+				})
+
+				It("emits a ScheduledRun event", func() {
+					_, _ = reconciler.Reconcile(ctx, request)
+
+					Expect(fakeEventRecorder.EventfCallCount()).To(Equal(1))
+					_, evType, reason, messageFmt, fmtArgs := fakeEventRecorder.EventfArgsForCall(0)
+					Expect(evType).To(Equal(events.NormalType))
+					Expect(reason).To(Equal(events.ScheduledRunReason))
+					Expect(messageFmt).To(Equal("Scheduled a run for [%s]"))
+					Expect(fmtArgs).To(Equal([]interface{}{"2022-03-05T02:00:00Z"}))
+				})
+
+				It("requeues at the next tick", func() {
+					result, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.RequeueAfter).To(Equal(24*time.Hour - 30*time.Second))
+				})
+			})
+
+			Context("and several ticks were missed", func() {
+				BeforeEach(func() {
+					reconciler.Clock = clocktesting.NewFakePassiveClock(now.Add(48 * time.Hour))
+				})
+
+				It("schedules a single run for the most recent tick and reports the missed ticks", func() {
+					_, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, realizedRunnable, _, _, _ := rlzr.RealizeArgsForCall(0)
+					Expect(realizedRunnable.Status.LastScheduleTime.Time).To(Equal(time.Date(2022, 3, 7, 2, 0, 0, 0, time.UTC)))
+
+					Expect(fakeEventRecorder.EventfCallCount()).To(Equal(1))
+					_, _, _, messageFmt, fmtArgs := fakeEventRecorder.EventfArgsForCall(0)
+					Expect(messageFmt).To(Equal("Scheduled a run for [%s], skipping [%d] missed ticks"))
+					Expect(fmtArgs).To(Equal([]interface{}{"2022-03-07T02:00:00Z", 2}))
+				})
+			})
+
+			Context("and more ticks were missed than are counted", func() {
+				BeforeEach(func() {
+					reconciler.Clock = clocktesting.NewFakePassiveClock(now.Add(365 * 24 * time.Hour))
+				})
+
+				It("schedules a single run for the most recent tick", func() {
+					_, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(rlzr.RealizeCallCount()).To(Equal(1))
+					_, realizedRunnable, _, _, _ := rlzr.RealizeArgsForCall(0)
+					Expect(realizedRunnable.Status.LastScheduleTime.Time).To(Equal(time.Date(2023, 3, 5, 2, 0, 0, 0, time.UTC)))
+
+					_, _, _, messageFmt, fmtArgs := fakeEventRecorder.EventfArgsForCall(0)
+					Expect(messageFmt).To(Equal("Scheduled a run for [%s], skipping more than [%d] missed ticks"))
+					Expect(fmtArgs).To(Equal([]interface{}{"2023-03-05T02:00:00Z", 100}))
+				})
+			})
+
+			Context("and no tick is due", func() {
+				BeforeEach(func() {
+					rb.Status.LastScheduleTime = &metav1.Time{Time: time.Date(2022, 3, 5, 2, 0, 0, 0, time.UTC)}
+					rb.Status.NextScheduleTime = &metav1.Time{Time: time.Date(2022, 3, 6, 2, 0, 0, 0, time.UTC)}
+					rb.Status.ObservedGeneration = rb.Generation
+				})
+
+				It("does not change the schedule status", func() {
+					_, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(repo.StatusUpdateCallCount()).To(Equal(0))
+					Expect(fakeEventRecorder.EventfCallCount()).To(Equal(0))
+				})
+
+				It("still requeues at the next tick", func() {
+					result, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.RequeueAfter).To(Equal(24*time.Hour - 30*time.Second))
+				})
+			})
+
+			Context("and the schedule is invalid", func() {
+				BeforeEach(func() {
+					rb.Spec.Schedule.Cron = "not a cron"
+				})
+
+				It("calls the condition manager to report", func() {
+					_, _ = reconciler.Reconcile(ctx, request)
+					Expect(conditionManager.AddPositiveArgsForCall(0)).To(
+						Equal(conditions.InvalidScheduleCondition(errors.New(`invalid cron [not a cron]: expected exactly 5 fields, found 3: [not a cron]`))))
+				})
+
+				It("does not realize", func() {
+					_, _ = reconciler.Reconcile(ctx, request)
+					Expect(rlzr.RealizeCallCount()).To(Equal(0))
+				})
+
+				It("does not return an error", func() {
+					_, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+		})
+
 		Context("outputs are returned from the realizer", func() {
 			BeforeEach(func() {
 				rlzr.RealizeReturns(nil, templates.Outputs{
 					"an-output": apiextensionsv1.JSON{Raw: []byte(`"the value"`)},
-				}, nil)
+				}, nil, 0, nil)
 			})
 
 			It("Updates the status with the outputs", func() {
@@ -407,7 +735,7 @@ var _ = Describe("Reconcile", func() {
 
 		Context("updating the status fails", func() {
 			BeforeEach(func() {
-				rlzr.RealizeReturns(nil, nil, nil)
+				rlzr.RealizeReturns(nil, nil, nil, 0, nil)
 				repo.StatusUpdateReturns(errors.New("bad status update error"))
 			})
 
@@ -427,7 +755,7 @@ var _ = Describe("Reconcile", func() {
 
 		Context("the realizer returns an error", func() {
 			BeforeEach(func() {
-				rlzr.RealizeReturns(nil, nil, nil)
+				rlzr.RealizeReturns(nil, nil, nil, 0, nil)
 			})
 
 			It("Starts and Finishes cleanly", func() {
@@ -452,7 +780,7 @@ var _ = Describe("Reconcile", func() {
 						Err:         errors.New("some error"),
 						TemplateRef: &v1alpha1.TemplateReference{Kind: "ClusterRunTemplate", Name: "my-run-template"},
 					}
-					rlzr.RealizeReturns(nil, nil, err)
+					rlzr.RealizeReturns(nil, nil, nil, 0, err)
 				})
 
 				It("calls the condition manager to report", func() {
@@ -480,7 +808,7 @@ var _ = Describe("Reconcile", func() {
 							MatchingLabels: map[string]string{"foo": "bar", "moo": "cow"},
 						},
 					}
-					rlzr.RealizeReturns(nil, nil, err)
+					rlzr.RealizeReturns(nil, nil, nil, 0, err)
 				})
 
 				It("calls the condition manager to report", func() {
@@ -509,7 +837,7 @@ var _ = Describe("Reconcile", func() {
 						Err:         errors.New("some error"),
 						TemplateRef: &v1alpha1.TemplateReference{Kind: "ClusterRunTemplate", Name: "my-run-template"},
 					}
-					rlzr.RealizeReturns(nil, nil, err)
+					rlzr.RealizeReturns(nil, nil, nil, 0, err)
 				})
 
 				It("does not try to watch the stampedObjects", func() {
@@ -546,7 +874,7 @@ var _ = Describe("Reconcile", func() {
 						StampedObject: &unstructured.Unstructured{},
 						TemplateRef:   &v1alpha1.TemplateReference{Kind: "ClusterRunTemplate", Name: "my-run-template"},
 					}
-					rlzr.RealizeReturns(nil, nil, err)
+					rlzr.RealizeReturns(nil, nil, nil, 0, err)
 				})
 
 				It("calls the condition manager to report", func() {
@@ -584,7 +912,7 @@ var _ = Describe("Reconcile", func() {
 						TemplateRef:   &v1alpha1.TemplateReference{Kind: "ClusterRunTemplate", Name: "my-run-template"},
 					}
 
-					rlzr.RealizeReturns(nil, nil, stampedObjectError)
+					rlzr.RealizeReturns(nil, nil, nil, 0, stampedObjectError)
 				})
 
 				It("calls the condition manager to report", func() {
@@ -609,7 +937,7 @@ var _ = Describe("Reconcile", func() {
 						Namespace: "some-ns",
 						Labels:    map[string]string{"hi": "bye"},
 					}
-					rlzr.RealizeReturns(nil, nil, err)
+					rlzr.RealizeReturns(nil, nil, nil, 0, err)
 				})
 
 				It("calls the condition manager to report", func() {
@@ -644,7 +972,7 @@ var _ = Describe("Reconcile", func() {
 						StampedObject:     stampedObject,
 						QualifiedResource: "mything.thing.io",
 					}
-					rlzr.RealizeReturns(nil, nil, err)
+					rlzr.RealizeReturns(nil, nil, nil, 0, err)
 				})
 
 				It("calls the condition manager to report", func() {
@@ -670,7 +998,7 @@ var _ = Describe("Reconcile", func() {
 				var err error
 				BeforeEach(func() {
 					err = errors.New("some error")
-					rlzr.RealizeReturns(nil, nil, err)
+					rlzr.RealizeReturns(nil, nil, nil, 0, err)
 				})
 
 				It("calls the condition manager to report", func() {
@@ -17,19 +17,23 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/cartographer/pkg/conditions"
 	"github.com/vmware-tanzu/cartographer/pkg/enqueuer"
 	cerrors "github.com/vmware-tanzu/cartographer/pkg/errors"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
 	"github.com/vmware-tanzu/cartographer/pkg/tracker/dependency"
 	"github.com/vmware-tanzu/cartographer/pkg/utils"
 )
@@ -64,20 +68,21 @@ func (r *SupplyChainReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	conditionManager := r.ConditionManagerBuilder(v1alpha1.BlueprintReady, supplyChain.Status.Conditions)
+	previousParams := supplyChain.Status.Params
 
 	err = r.reconcileSupplyChain(ctx, supplyChain, conditionManager)
 
-	return r.completeReconciliation(ctx, supplyChain, conditionManager, err)
+	return r.completeReconciliation(ctx, supplyChain, previousParams, conditionManager, err)
 }
 
-func (r *SupplyChainReconciler) completeReconciliation(ctx context.Context, supplyChain *v1alpha1.ClusterSupplyChain, conditionManager conditions.ConditionManager, err error) (ctrl.Result, error) {
+func (r *SupplyChainReconciler) completeReconciliation(ctx context.Context, supplyChain *v1alpha1.ClusterSupplyChain, previousParams []v1alpha1.AcceptedParam, conditionManager conditions.ConditionManager, err error) (ctrl.Result, error) {
 	log := logr.FromContextOrDiscard(ctx)
 
 	var changed bool
 	supplyChain.Status.Conditions, changed = conditionManager.Finalize()
 
 	var updateErr error
-	if changed || (supplyChain.Status.ObservedGeneration != supplyChain.Generation) {
+	if changed || (supplyChain.Status.ObservedGeneration != supplyChain.Generation) || !reflect.DeepEqual(previousParams, supplyChain.Status.Params) {
 		supplyChain.Status.ObservedGeneration = supplyChain.Generation
 		updateErr = r.Repo.StatusUpdate(ctx, supplyChain)
 		if updateErr != nil {
@@ -100,37 +105,50 @@ func (r *SupplyChainReconciler) completeReconciliation(ctx context.Context, supp
 func (r *SupplyChainReconciler) reconcileSupplyChain(ctx context.Context, chain *v1alpha1.ClusterSupplyChain, conditionManager conditions.ConditionManager) error {
 	log := logr.FromContextOrDiscard(ctx)
 	var resourcesNotFound []string
+	var resourceTemplateParams []realizer.ResourceTemplateParams
 
 	for _, resource := range chain.Spec.Resources {
+		var templateNames []string
 		if resource.TemplateRef.Name != "" {
-			found, err := r.validateResource(ctx, chain, resource.TemplateRef.Name, resource.TemplateRef.Kind)
+			templateNames = append(templateNames, resource.TemplateRef.Name)
+		} else {
+			for _, option := range resource.TemplateRef.Options {
+				if option.Name != "" {
+					templateNames = append(templateNames, option.Name)
+				}
+			}
+		}
+
+		for _, templateName := range templateNames {
+			template, err := r.validateResource(ctx, chain, templateName, resource.TemplateRef.Kind)
 			if err != nil {
 				log.Error(err, "failed to get cluster template", "template",
-					fmt.Sprintf("%s/%s", resource.TemplateRef.Kind, resource.TemplateRef.Name))
+					fmt.Sprintf("%s/%s", resource.TemplateRef.Kind, templateName))
 				return cerrors.NewUnhandledError(fmt.Errorf("failed to get cluster template: %w", err))
 			}
 
-			if !found {
+			if template == nil {
 				resourcesNotFound = append(resourcesNotFound, resource.Name)
+				continue
 			}
-		} else {
-			for _, option := range resource.TemplateRef.Options {
-				if option.Name != "" {
-					found, err := r.validateResource(ctx, chain, option.Name, resource.TemplateRef.Kind)
-					if err != nil {
-						log.Error(err, "failed to get cluster template", "template",
-							fmt.Sprintf("%s/%s", resource.TemplateRef.Kind, resource.TemplateRef.Name))
-						return cerrors.NewUnhandledError(fmt.Errorf("failed to get cluster template: %w", err))
-					}
-
-					if !found {
-						resourcesNotFound = append(resourcesNotFound, resource.Name)
-					}
-				}
+
+			reader, err := templates.NewReaderFromAPI(template)
+			if err != nil {
+				log.Error(err, "failed to get reader for cluster template", "template",
+					fmt.Sprintf("%s/%s", resource.TemplateRef.Kind, templateName))
+				return cerrors.NewUnhandledError(fmt.Errorf("failed to get reader for cluster template: %w", err))
 			}
+
+			resourceTemplateParams = append(resourceTemplateParams, realizer.ResourceTemplateParams{
+				ResourceName:   resource.Name,
+				ResourceParams: resource.Params,
+				TemplateParams: reader.GetDefaultParams(),
+			})
 		}
 	}
 
+	chain.Status.Params = realizer.AcceptedParams(chain.Spec.Params, resourceTemplateParams)
+
 	if len(resourcesNotFound) > 0 {
 		conditionManager.AddPositive(conditions.TemplatesNotFoundCondition(resourcesNotFound))
 	} else {
@@ -140,10 +158,10 @@ func (r *SupplyChainReconciler) reconcileSupplyChain(ctx context.Context, chain
 	return nil
 }
 
-func (r *SupplyChainReconciler) validateResource(ctx context.Context, supplyChain *v1alpha1.ClusterSupplyChain, templateName, templateKind string) (bool, error) {
+func (r *SupplyChainReconciler) validateResource(ctx context.Context, supplyChain *v1alpha1.ClusterSupplyChain, templateName, templateKind string) (client.Object, error) {
 	template, err := r.Repo.GetTemplate(ctx, templateName, templateKind)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	r.DependencyTracker.Track(dependency.Key{
@@ -159,7 +177,7 @@ func (r *SupplyChainReconciler) validateResource(ctx context.Context, supplyChai
 		Name:      supplyChain.Name,
 	})
 
-	return template != nil, nil
+	return template, nil
 }
 
 func (r *SupplyChainReconciler) SetupWithManager(mgr ctrl.Manager) error {
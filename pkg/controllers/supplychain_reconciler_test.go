@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
 	. "github.com/onsi/gomega/gstruct"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -241,6 +242,81 @@ var _ = Describe("SupplyChainReconciler", func() {
 			thirdTemplateKey, _ := dependencyTracker.TrackArgsForCall(2)
 			Expect(thirdTemplateKey.String()).To(Equal("ClusterTemplate.carto.run//my-final-template-option2"))
 		})
+
+		Context("the templates declare params", func() {
+			BeforeEach(func() {
+				firstTemplate.Spec.Params = v1alpha1.TemplateParams{
+					{
+						Name:        "git-ssh-secret",
+						Description: "secret used to fetch source",
+						Required:    true,
+					},
+				}
+				secondTemplate.Spec.Params = v1alpha1.TemplateParams{
+					{
+						Name:         "replicas",
+						DefaultValue: apiextensionsv1.JSON{Raw: []byte(`1`)},
+						Schema:       &v1alpha1.ParamSchema{Type: "integer"},
+					},
+				}
+				thirdTemplate.Spec.Params = v1alpha1.TemplateParams{
+					{
+						Name:     "replicas",
+						Required: true,
+					},
+				}
+			})
+
+			It("aggregates the params into the supply chain status", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+
+				_, updatedSupplyChain := repo.StatusUpdateArgsForCall(0)
+
+				Expect(updatedSupplyChain.(*v1alpha1.ClusterSupplyChain).Status.Params).To(Equal([]v1alpha1.AcceptedParam{
+					{
+						Name:        "git-ssh-secret",
+						Description: "secret used to fetch source",
+						Required:    true,
+						Resources:   []string{"first-resource"},
+					},
+					{
+						Name:         "replicas",
+						Required:     true,
+						DefaultValue: &apiextensionsv1.JSON{Raw: []byte(`1`)},
+						Schema:       &v1alpha1.ParamSchema{Type: "integer"},
+						Resources:    []string{"second-resource"},
+					},
+				}))
+			})
+
+			Context("the status already reflects the params", func() {
+				BeforeEach(func() {
+					conditionManager.FinalizeReturns(expectedConditions, false)
+					sc.Status.ObservedGeneration = 1
+					sc.Status.Params = []v1alpha1.AcceptedParam{
+						{
+							Name:        "git-ssh-secret",
+							Description: "secret used to fetch source",
+							Required:    true,
+							Resources:   []string{"first-resource"},
+						},
+						{
+							Name:         "replicas",
+							Required:     true,
+							DefaultValue: &apiextensionsv1.JSON{Raw: []byte(`1`)},
+							Schema:       &v1alpha1.ParamSchema{Type: "integer"},
+							Resources:    []string{"second-resource"},
+						},
+					}
+				})
+
+				It("does not update the status", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(repo.StatusUpdateCallCount()).To(Equal(0))
+				})
+			})
+		})
 	})
 
 	Context("get cluster template fails", func() {
@@ -0,0 +1,208 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/logger"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+)
+
+// TemplateRevisionKinds are the kinds of template for which revisions are kept
+var TemplateRevisionKinds = []string{
+	"ClusterSourceTemplate",
+	"ClusterImageTemplate",
+	"ClusterConfigTemplate",
+	"ClusterDeploymentTemplate",
+	"ClusterTemplate",
+}
+
+// DefaultRevisionHistoryLimit is the number of revisions kept for each template
+// when TemplateRevisionReconciler.RevisionHistoryLimit is not set.
+const DefaultRevisionHistoryLimit = 10
+
+// TemplateRevisionReconciler snapshots each generation of the templates of
+// TemplateKind into a ClusterTemplateRevision.
+//
+// Revisions are taken when the template is reconciled, so a generation that is
+// superseded before it is reconciled has no revision, and a template that existed
+// before revisions were introduced has revisions only from its current generation.
+type TemplateRevisionReconciler struct {
+	Repo         repository.Repository
+	TemplateKind string
+
+	// RevisionHistoryLimit is the number of most recent revisions kept for each
+	// template. Older revisions are deleted unless a supply chain or delivery pins
+	// them. Defaults to DefaultRevisionHistoryLimit.
+	RevisionHistoryLimit int
+}
+
+func (r *TemplateRevisionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logr.FromContextOrDiscard(ctx)
+	log.Info("started")
+	defer log.Info("finished")
+
+	log = log.WithValues("template", fmt.Sprintf("%s/%s", r.TemplateKind, req.Name))
+	ctx = logr.NewContext(ctx, log)
+
+	// the effective template is snapshotted, so that a revision does not change
+	// when the templates it extends do
+	template, err := r.Repo.GetTemplate(ctx, req.Name, r.TemplateKind)
+	if err != nil {
+		log.Error(err, "failed to get template")
+		return ctrl.Result{}, fmt.Errorf("failed to get template [%s/%s]: %w", r.TemplateKind, req.Name, err)
+	}
+
+	if template == nil {
+		log.Info("template no longer exists")
+		return ctrl.Result{}, nil
+	}
+
+	revision, err := v1alpha1.NewTemplateRevision(template)
+	if err != nil {
+		log.Error(err, "failed to build template revision")
+		return ctrl.Result{}, fmt.Errorf("failed to build revision of template [%s/%s]: %w", r.TemplateKind, req.Name, err)
+	}
+
+	// revisions are removed along with their template
+	if err = controllerutil.SetOwnerReference(template, revision, r.Repo.GetScheme()); err != nil {
+		log.Error(err, "failed to set owner of template revision")
+		return ctrl.Result{}, fmt.Errorf("failed to set owner of template revision [%s]: %w", revision.Name, err)
+	}
+
+	log.V(logger.DEBUG).Info("ensuring template revision exists", "revision", revision.Spec.Revision)
+	if err = r.Repo.EnsureTemplateRevisionExistsOnCluster(ctx, revision); err != nil {
+		log.Error(err, "failed to ensure template revision exists")
+		return ctrl.Result{}, fmt.Errorf("failed to ensure template revision [%s] exists: %w", revision.Name, err)
+	}
+
+	if err = r.pruneRevisions(ctx, req.Name); err != nil {
+		log.Error(err, "failed to prune template revisions")
+		return ctrl.Result{}, fmt.Errorf("failed to prune revisions of template [%s/%s]: %w", r.TemplateKind, req.Name, err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// pruneRevisions deletes the revisions of the template beyond the history limit
+// that are not pinned by a blueprint
+func (r *TemplateRevisionReconciler) pruneRevisions(ctx context.Context, name string) error {
+	limit := r.RevisionHistoryLimit
+	if limit <= 0 {
+		limit = DefaultRevisionHistoryLimit
+	}
+
+	revisions, err := r.Repo.ListUnstructured(ctx, v1alpha1.SchemeGroupVersion.WithKind("ClusterTemplateRevision"), "", map[string]string{
+		v1alpha1.TemplateRevisionKindLabel: r.TemplateKind,
+		v1alpha1.TemplateRevisionNameLabel: name,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	if len(revisions) <= limit {
+		return nil
+	}
+
+	pinned, err := r.pinnedRevisions(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisionNumber(revisions[i]) > revisionNumber(revisions[j])
+	})
+
+	for _, revision := range revisions[limit:] {
+		if pinned[revisionNumber(revision)] {
+			continue
+		}
+
+		logr.FromContextOrDiscard(ctx).V(logger.DEBUG).Info("deleting template revision", "revision", revision.GetName())
+		if err = r.Repo.DeleteTemplateRevision(ctx, revision.GetName()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pinnedRevisions returns the revisions of the template that the resources of
+// supply chains and deliveries pin, directly or through a rollout
+func (r *TemplateRevisionReconciler) pinnedRevisions(ctx context.Context, name string) (map[int64]bool, error) {
+	pinned := map[int64]bool{}
+
+	for _, blueprintKind := range []string{"ClusterSupplyChain", "ClusterDelivery"} {
+		blueprints, err := r.Repo.ListUnstructured(ctx, v1alpha1.SchemeGroupVersion.WithKind(blueprintKind), "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", blueprintKind, err)
+		}
+
+		for _, blueprint := range blueprints {
+			resources, _, _ := unstructured.NestedSlice(blueprint.Object, "spec", "resources")
+			for _, resource := range resources {
+				resourceObj, ok := resource.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				kind, _, _ := unstructured.NestedString(resourceObj, "templateRef", "kind")
+				templateName, _, _ := unstructured.NestedString(resourceObj, "templateRef", "name")
+				if kind != r.TemplateKind || templateName != name {
+					continue
+				}
+				if revision, ok, _ := unstructured.NestedInt64(resourceObj, "templateRef", "revision"); ok {
+					pinned[revision] = true
+				}
+				if revision, ok, _ := unstructured.NestedInt64(resourceObj, "templateRef", "rollout", "revision"); ok {
+					pinned[revision] = true
+				}
+			}
+		}
+	}
+
+	return pinned, nil
+}
+
+func revisionNumber(revision *unstructured.Unstructured) int64 {
+	number, _, _ := unstructured.NestedInt64(revision.Object, "spec", "revision")
+	return number
+}
+
+func (r *TemplateRevisionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	template, err := v1alpha1.GetAPITemplate(r.TemplateKind)
+	if err != nil {
+		return fmt.Errorf("failed to get api template: %w", err)
+	}
+
+	r.Repo = repository.NewRepository(
+		mgr.GetClient(),
+		repository.NewCache(mgr.GetLogger().WithName("template-revision-repo-cache")),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(fmt.Sprintf("%s-revision", strings.ToLower(r.TemplateKind))).
+		For(template).
+		Complete(r)
+}
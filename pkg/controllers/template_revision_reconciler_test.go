@@ -0,0 +1,218 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/controllers"
+	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/utils"
+)
+
+var _ = Describe("TemplateRevisionReconciler", func() {
+	var (
+		out        *Buffer
+		reconciler controllers.TemplateRevisionReconciler
+		ctx        context.Context
+		req        reconcile.Request
+		repo       *repositoryfakes.FakeRepository
+		template   *v1alpha1.ClusterSourceTemplate
+	)
+
+	BeforeEach(func() {
+		out = NewBuffer()
+		logger := zap.New(zap.WriteTo(out))
+		ctx = logr.NewContext(context.Background(), logger)
+
+		repo = &repositoryfakes.FakeRepository{}
+
+		scheme := runtime.NewScheme()
+		Expect(utils.AddToScheme(scheme)).To(Succeed())
+		repo.GetSchemeReturns(scheme)
+
+		template = &v1alpha1.ClusterSourceTemplate{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ClusterSourceTemplate",
+				APIVersion: "carto.run/v1alpha1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "my-template",
+				UID:        "some-uid",
+				Generation: 3,
+			},
+			Spec: v1alpha1.SourceTemplateSpec{
+				URLPath: ".spec.url",
+			},
+		}
+		repo.GetTemplateReturns(template, nil)
+
+		reconciler = controllers.TemplateRevisionReconciler{
+			Repo:         repo,
+			TemplateKind: "ClusterSourceTemplate",
+		}
+
+		req = reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: "my-template"},
+		}
+	})
+
+	It("snapshots the current generation of the template", func() {
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, name, kind := repo.GetTemplateArgsForCall(0)
+		Expect(name).To(Equal("my-template"))
+		Expect(kind).To(Equal("ClusterSourceTemplate"))
+
+		Expect(repo.EnsureTemplateRevisionExistsOnClusterCallCount()).To(Equal(1))
+		_, revision := repo.EnsureTemplateRevisionExistsOnClusterArgsForCall(0)
+		Expect(revision.Name).To(Equal("clustersourcetemplate-my-template-3"))
+		Expect(revision.Spec.Revision).To(Equal(int64(3)))
+		Expect(revision.Spec.TemplateRef).To(Equal(v1alpha1.TemplateReference{Kind: "ClusterSourceTemplate", Name: "my-template"}))
+
+		snapshot, err := revision.GetTemplate()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshot.(*v1alpha1.ClusterSourceTemplate).Spec).To(Equal(template.Spec))
+	})
+
+	It("makes the template the owner of the revision", func() {
+		_, _ = reconciler.Reconcile(ctx, req)
+
+		_, revision := repo.EnsureTemplateRevisionExistsOnClusterArgsForCall(0)
+		Expect(revision.OwnerReferences).To(HaveLen(1))
+		Expect(revision.OwnerReferences[0].Kind).To(Equal("ClusterSourceTemplate"))
+		Expect(revision.OwnerReferences[0].Name).To(Equal("my-template"))
+	})
+
+	Context("the template no longer exists", func() {
+		BeforeEach(func() {
+			repo.GetTemplateReturns(nil, nil)
+		})
+
+		It("does not create a revision", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.EnsureTemplateRevisionExistsOnClusterCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the template has more revisions than the history limit", func() {
+		var revisions []*unstructured.Unstructured
+
+		BeforeEach(func() {
+			reconciler.RevisionHistoryLimit = 2
+
+			revisions = nil
+			for _, number := range []int64{1, 3, 2, 4} {
+				revision := &unstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{"revision": number},
+				}}
+				revision.SetName(v1alpha1.TemplateRevisionName("ClusterSourceTemplate", "my-template", number))
+				revisions = append(revisions, revision)
+			}
+
+			repo.ListUnstructuredStub = func(_ context.Context, gvk schema.GroupVersionKind, _ string, labels map[string]string) ([]*unstructured.Unstructured, error) {
+				switch gvk.Kind {
+				case "ClusterTemplateRevision":
+					Expect(labels).To(Equal(map[string]string{
+						"carto.run/template-kind": "ClusterSourceTemplate",
+						"carto.run/template-name": "my-template",
+					}))
+					return revisions, nil
+				case "ClusterSupplyChain":
+					return []*unstructured.Unstructured{{Object: map[string]interface{}{
+						"spec": map[string]interface{}{
+							"resources": []interface{}{
+								map[string]interface{}{
+									"templateRef": map[string]interface{}{
+										"kind":     "ClusterSourceTemplate",
+										"name":     "my-template",
+										"revision": int64(1),
+									},
+								},
+								map[string]interface{}{
+									"templateRef": map[string]interface{}{
+										"kind":     "ClusterSourceTemplate",
+										"name":     "other-template",
+										"revision": int64(2),
+									},
+								},
+							},
+						},
+					}}}, nil
+				}
+				return nil, nil
+			}
+		})
+
+		It("deletes the oldest revisions that are not pinned by a blueprint", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(repo.DeleteTemplateRevisionCallCount()).To(Equal(1))
+			_, name := repo.DeleteTemplateRevisionArgsForCall(0)
+			Expect(name).To(Equal("clustersourcetemplate-my-template-2"))
+		})
+
+		Context("and deleting a revision fails", func() {
+			BeforeEach(func() {
+				repo.DeleteTemplateRevisionReturns(errors.New("some error"))
+			})
+
+			It("returns an error to requeue", func() {
+				_, err := reconciler.Reconcile(ctx, req)
+				Expect(err).To(MatchError(ContainSubstring("failed to prune revisions of template [ClusterSourceTemplate/my-template]: some error")))
+			})
+		})
+	})
+
+	Context("the template has no more revisions than the history limit", func() {
+		BeforeEach(func() {
+			repo.ListUnstructuredReturns([]*unstructured.Unstructured{{}}, nil)
+		})
+
+		It("deletes no revisions", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.DeleteTemplateRevisionCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the revision cannot be created", func() {
+		BeforeEach(func() {
+			repo.EnsureTemplateRevisionExistsOnClusterReturns(errors.New("some error"))
+		})
+
+		It("returns an error to requeue", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).To(MatchError(ContainSubstring("failed to ensure template revision [clustersourcetemplate-my-template-3] exists: some error")))
+		})
+	})
+})
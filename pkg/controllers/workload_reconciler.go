@@ -16,7 +16,9 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -31,6 +33,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	crtcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -43,6 +46,7 @@ import (
 	"github.com/vmware-tanzu/cartographer/pkg/events"
 	"github.com/vmware-tanzu/cartographer/pkg/logger"
 	"github.com/vmware-tanzu/cartographer/pkg/mapper"
+	"github.com/vmware-tanzu/cartographer/pkg/openapi"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer"
 	realizerclient "github.com/vmware-tanzu/cartographer/pkg/realizer/client"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
@@ -65,6 +69,7 @@ type WorkloadReconciler struct {
 	DependencyTracker       dependency.DependencyTracker
 	EventRecorder           record.EventRecorder
 	RESTMapper              meta.RESTMapper
+	Prober                  *healthcheck.Prober
 }
 
 func (r *WorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -94,7 +99,7 @@ func (r *WorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	conditionManager := r.ConditionManagerBuilder(v1alpha1.OwnerReady, workload.Status.Conditions)
 
-	supplyChain, err := r.getSupplyChainsForWorkload(ctx, workload, conditionManager)
+	supplyChain, selectionReason, err := r.getSupplyChainsForWorkload(ctx, workload, conditionManager)
 	if err != nil {
 		return r.completeReconciliation(ctx, workload, nil, conditionManager, err)
 	}
@@ -118,7 +123,7 @@ func (r *WorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		log.Info("supply chain is not in ready state")
 		return r.completeReconciliation(ctx, workload, nil, conditionManager, fmt.Errorf("supply chain [%s] is not in ready state", supplyChain.Name))
 	}
-	conditionManager.AddPositive(conditions.SupplyChainReadyCondition())
+	conditionManager.AddPositive(conditions.SupplyChainReadyCondition(selectionReason))
 
 	serviceAccountName, serviceAccountNS := getServiceAccountNameAndNamespaceForWorkload(workload, supplyChain)
 
@@ -163,8 +168,9 @@ func (r *WorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	}
 
 	conditionManager.AddPositive(healthcheck.OwnerHealthCondition(resourceStatuses.GetCurrent(), workload.Status.Conditions))
+	conditionManager.Add(healthcheck.OwnerDegradedCondition(resourceStatuses.GetCurrent()), conditions.Informational)
 
-	r.trackDependencies(workload, resourceStatuses.GetCurrent(), serviceAccountName, serviceAccountNS)
+	r.trackDependencies(workload, supplyChain, resourceStatuses.GetCurrent(), serviceAccountName, serviceAccountNS)
 
 	cleanupErr := r.cleanupOrphanedObjects(ctx, workload.Status.Resources, resourceStatuses.GetCurrent())
 	if cleanupErr != nil {
@@ -190,7 +196,8 @@ func (r *WorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		}
 	}
 
-	return r.completeReconciliation(ctx, workload, resourceStatuses, conditionManager, reconcileErr)
+	result, err := r.completeReconciliation(ctx, workload, resourceStatuses, conditionManager, reconcileErr)
+	return cleanupResult(result, err, resourceRealizer, resourceStatuses)
 }
 
 func (r *WorkloadReconciler) completeReconciliation(ctx context.Context, workload *v1alpha1.Workload, resourceStatuses statuses.ResourceStatuses, conditionManager conditions.ConditionManager, err error) (ctrl.Result, error) {
@@ -220,7 +227,7 @@ func (r *WorkloadReconciler) completeReconciliation(ctx context.Context, workloa
 		log.Info("handled error reconciling workload", "handled error", err)
 	}
 
-	return ctrl.Result{}, nil
+	return requeueResult(resourceStatuses, r.Prober.Interval(), time.Now()), nil
 }
 
 func (r *WorkloadReconciler) isSupplyChainReady(supplyChain *v1alpha1.ClusterSupplyChain) bool {
@@ -251,43 +258,49 @@ func getSupplyChainReadyCondition(supplyChain *v1alpha1.ClusterSupplyChain) meta
 	return metav1.Condition{}
 }
 
-func (r *WorkloadReconciler) getSupplyChainsForWorkload(ctx context.Context, workload *v1alpha1.Workload, conditionManager conditions.ConditionManager) (*v1alpha1.ClusterSupplyChain, error) {
+func (r *WorkloadReconciler) getSupplyChainsForWorkload(ctx context.Context, workload *v1alpha1.Workload, conditionManager conditions.ConditionManager) (*v1alpha1.ClusterSupplyChain, string, error) {
 	log := logr.FromContextOrDiscard(ctx)
 	if len(workload.Labels) == 0 {
 		conditionManager.AddPositive(conditions.WorkloadMissingLabelsCondition())
 		log.Info("workload is missing required labels")
-		return nil, fmt.Errorf("workload [%s/%s] is missing required labels",
+		return nil, "", fmt.Errorf("workload [%s/%s] is missing required labels",
 			workload.Namespace, workload.Name)
 	}
 
-	supplyChains, err := r.Repo.GetSupplyChainsForWorkload(ctx, workload)
+	supplyChains, selectionReason, err := r.Repo.GetSupplyChainsForWorkload(ctx, workload)
 	if err != nil {
+		var namespaceNotAllowedErr repository.NamespaceNotAllowedError
+		if errors.As(err, &namespaceNotAllowedErr) {
+			conditionManager.AddPositive(conditions.SupplyChainNamespaceNotAllowedCondition(namespaceNotAllowedErr.Namespace, namespaceNotAllowedErr.Blueprints))
+			log.Info("supply chains matching labels do not allow the namespace", "supply chains", namespaceNotAllowedErr.Blueprints)
+			return nil, "", fmt.Errorf("no eligible supply chains for workload [%s/%s]: %w", workload.Namespace, workload.Name, err)
+		}
 		log.Error(err, "failed to get supply chains for workload")
-		return nil, cerrors.NewUnhandledError(fmt.Errorf("failed to get supply chains for workload [%s/%s]: %w",
+		return nil, "", cerrors.NewUnhandledError(fmt.Errorf("failed to get supply chains for workload [%s/%s]: %w",
 			workload.Namespace, workload.Name, err))
 	}
 
 	if len(supplyChains) == 0 {
-		conditionManager.AddPositive(conditions.SupplyChainNotFoundCondition(workload.Labels))
+		conditionManager.AddPositive(conditions.SupplyChainNotFoundCondition(workload.Labels, selectionReason))
 		log.Info("no supply chain found where full selector is satisfied by label",
 			"labels", workload.Labels)
-		return nil, fmt.Errorf("no supply chain [%s/%s] found where full selector is satisfied by labels: %v",
+		return nil, "", fmt.Errorf("no supply chain [%s/%s] found where full selector is satisfied by labels: %v",
 			workload.Namespace, workload.Name, workload.Labels)
 	}
 
 	if len(supplyChains) > 1 {
-		conditionManager.AddPositive(conditions.TooManySupplyChainMatchesCondition())
+		conditionManager.AddPositive(conditions.TooManySupplyChainMatchesCondition(selectionReason))
 		log.Info("more than one supply chain selected for workload",
 			"supply chains", GetSupplyChainNames(supplyChains))
-		return nil, fmt.Errorf("more than one supply chain selected for workload [%s/%s]: %+v",
+		return nil, "", fmt.Errorf("more than one supply chain selected for workload [%s/%s]: %+v",
 			workload.Namespace, workload.Name, GetSupplyChainNames(supplyChains))
 	}
 
 	log.V(logger.DEBUG).Info("supply chain matched for workload", "supply chain", supplyChains[0].Name)
-	return supplyChains[0], nil
+	return supplyChains[0], selectionReason, nil
 }
 
-func (r *WorkloadReconciler) trackDependencies(workload *v1alpha1.Workload, realizedResources []v1alpha1.ResourceStatus, serviceAccountName, serviceAccountNS string) {
+func (r *WorkloadReconciler) trackDependencies(workload *v1alpha1.Workload, supplyChain *v1alpha1.ClusterSupplyChain, realizedResources []v1alpha1.ResourceStatus, serviceAccountName, serviceAccountNS string) {
 	r.DependencyTracker.ClearTracked(types.NamespacedName{
 		Namespace: workload.Namespace,
 		Name:      workload.Name,
@@ -307,25 +320,31 @@ func (r *WorkloadReconciler) trackDependencies(workload *v1alpha1.Workload, real
 		Name:      workload.Name,
 	})
 
+	blueprintParams := [][]v1alpha1.BlueprintParam{supplyChain.Spec.Params}
+	for _, resource := range supplyChain.Spec.Resources {
+		blueprintParams = append(blueprintParams, resource.Params)
+	}
+	for _, key := range paramValueSourceKeys(workload.Namespace, workload.Spec.Params, blueprintParams...) {
+		r.DependencyTracker.Track(key, types.NamespacedName{
+			Namespace: workload.Namespace,
+			Name:      workload.Name,
+		})
+	}
+
+	for _, key := range templateRevisionKeys(realizer.MakeSupplychainOwnerResources(supplyChain)) {
+		r.DependencyTracker.Track(key, types.NamespacedName{
+			Namespace: workload.Namespace,
+			Name:      workload.Name,
+		})
+	}
+
 	for _, resource := range realizedResources {
-		if resource.TemplateRef == nil {
-			continue
-		}
-		r.DependencyTracker.Track(
-			dependency.Key{
-				GroupKind: schema.GroupKind{
-					Group: v1alpha1.SchemeGroupVersion.Group,
-					Kind:  resource.TemplateRef.Kind,
-				},
-				NamespacedName: types.NamespacedName{
-					Name: resource.TemplateRef.Name,
-				},
-			},
-			types.NamespacedName{
+		for _, key := range templateKeys(resource) {
+			r.DependencyTracker.Track(key, types.NamespacedName{
 				Namespace: workload.Namespace,
 				Name:      workload.Name,
-			},
-		)
+			})
+		}
 	}
 }
 
@@ -432,9 +451,12 @@ func (r *WorkloadReconciler) SetupWithManager(mgr ctrl.Manager, concurrency int)
 		repository.NewRepository,
 		realizerclient.NewClientBuilder(mgr.GetConfig()),
 		repository.NewCache(mgr.GetLogger().WithName("workload-stamping-repo-cache")),
+		templates.NewStampCache("workload", templates.DefaultStampCacheSize),
+		openapi.NewValidator(openapi.NewFetcher(clientSet.Discovery().RESTClient()), openapi.DefaultSchemaTTL),
+		r.Prober,
 	)
 
-	r.Realizer = realizer.NewRealizer(nil, r.RESTMapper)
+	r.Realizer = realizer.NewRealizer(nil, r.Prober, r.RESTMapper)
 	r.DependencyTracker = dependency.NewDependencyTracker(
 		2*utils.DefaultResyncTime,
 		mgr.GetLogger().WithName("tracker-workload"),
@@ -453,6 +475,7 @@ func (r *WorkloadReconciler) SetupWithManager(mgr ctrl.Manager, concurrency int)
 	watches := map[client.Object]handler.MapFunc{
 		&v1alpha1.ClusterSupplyChain{}: m.ClusterSupplyChainToWorkloadRequests,
 		&corev1.ServiceAccount{}:       m.ServiceAccountToWorkloadRequests,
+		&corev1.Namespace{}:            m.NamespaceToWorkloadRequests,
 		&rbacv1.Role{}:                 m.RoleToWorkloadRequests,
 		&rbacv1.RoleBinding{}:          m.RoleBindingToWorkloadRequests,
 		&rbacv1.ClusterRole{}:          m.ClusterRoleToWorkloadRequests,
@@ -466,6 +489,21 @@ func (r *WorkloadReconciler) SetupWithManager(mgr ctrl.Manager, concurrency int)
 		)
 	}
 
+	// params are only sourced from tracked ConfigMaps and Secrets, so only their
+	// metadata is cached rather than the content of every Secret in the cluster
+	for _, paramSource := range []client.Object{&corev1.ConfigMap{}, &corev1.Secret{}} {
+		builder = builder.Watches(
+			&source.Kind{Type: paramSource},
+			enqueuer.EnqueueTracked(paramSource, r.DependencyTracker, mgr.GetScheme()),
+			ctrlbuilder.OnlyMetadata,
+		)
+	}
+
+	builder = builder.Watches(
+		&source.Kind{Type: &v1alpha1.ClusterTemplateRevision{}},
+		enqueuer.EnqueueTracked(&v1alpha1.ClusterTemplateRevision{}, r.DependencyTracker, mgr.GetScheme()),
+	)
+
 	for _, template := range v1alpha1.ValidSupplyChainTemplates {
 		builder = builder.Watches(
 			&source.Kind{Type: template},
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo"
@@ -44,6 +45,7 @@ import (
 	"github.com/vmware-tanzu/cartographer/pkg/controllers/controllersfakes"
 	cerrors "github.com/vmware-tanzu/cartographer/pkg/errors"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/realizerfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/statuses"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
@@ -133,6 +135,7 @@ var _ = Describe("WorkloadReconciler", func() {
 			Realizer:                rlzr,
 			StampedTracker:          stampedTracker,
 			DependencyTracker:       dependencyTracker,
+			Prober:                  healthcheck.NewProber(time.Minute, nil),
 		}
 
 		req = ctrl.Request{
@@ -249,7 +252,7 @@ var _ = Describe("WorkloadReconciler", func() {
 					},
 				},
 			}
-			repo.GetSupplyChainsForWorkloadReturns([]*v1alpha1.ClusterSupplyChain{&supplyChain}, nil)
+			repo.GetSupplyChainsForWorkloadReturns([]*v1alpha1.ClusterSupplyChain{&supplyChain}, "selected as the most specific match", nil)
 
 			resourceStatuses = statuses.NewResourceStatuses(nil, conditions.AddConditionForResourceSubmittedWorkload)
 			resourceStatuses.Add(
@@ -322,6 +325,141 @@ var _ = Describe("WorkloadReconciler", func() {
 			}))
 		})
 
+		It("does not requeue", func() {
+			result, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+		})
+
+		Context("when a resource has a pending run", func() {
+			BeforeEach(func() {
+				resourceStatuses.Add(
+					&v1alpha1.RealizedResource{
+						Name: "resource3",
+						PendingRun: &v1alpha1.PendingRun{
+							InputsDigest: "sha256:abc123",
+							RunAfter:     metav1.NewTime(time.Now().Add(time.Hour)),
+						},
+					}, nil, false,
+				)
+			})
+
+			It("requeues when the pending run is due", func() {
+				result, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+			})
+		})
+
+		Context("when a retained run of a resource expires", func() {
+			BeforeEach(func() {
+				realizeStub := rlzr.RealizeStub
+				rlzr.RealizeStub = func(ctx context.Context, resourceRealizer realizer.ResourceRealizer, deliveryName string, resources []realizer.OwnerResource, statuses statuses.ResourceStatuses) error {
+					resourceRealizer.(*realizerfakes.FakeResourceRealizer).CleanupAfterReturns(10 * time.Minute)
+					return realizeStub(ctx, resourceRealizer, deliveryName, resources, statuses)
+				}
+			})
+
+			It("requeues to clean it up", func() {
+				result, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(10 * time.Minute))
+			})
+		})
+
+		Context("when the health of a resource depends on its children", func() {
+			BeforeEach(func() {
+				resourceStatuses.Add(
+					&v1alpha1.RealizedResource{Name: "resource3"}, nil, false,
+					metav1.Condition{
+						Type:   v1alpha1.ResourceHealthy,
+						Status: metav1.ConditionFalse,
+						Reason: v1alpha1.ChildrenUnhealthyReason,
+					},
+				)
+			})
+
+			It("requeues to evaluate the health of the children again", func() {
+				result, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(controllers.ChildrenHealthRecheckInterval))
+			})
+		})
+
+		Context("when the probe of a resource has not completed", func() {
+			BeforeEach(func() {
+				resourceStatuses.Add(
+					&v1alpha1.RealizedResource{Name: "resource3"}, nil, false,
+					metav1.Condition{
+						Type:   v1alpha1.ResourceHealthy,
+						Status: metav1.ConditionUnknown,
+						Reason: v1alpha1.ProbePendingHealthyReason,
+					},
+				)
+			})
+
+			It("requeues to read the result of the probe", func() {
+				result, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(controllers.ProbePendingRecheckInterval))
+			})
+		})
+
+		Context("when the probe of a resource has completed", func() {
+			BeforeEach(func() {
+				resourceStatuses.Add(
+					&v1alpha1.RealizedResource{Name: "resource3"}, nil, false,
+					metav1.Condition{
+						Type:   v1alpha1.ResourceHealthy,
+						Status: metav1.ConditionTrue,
+						Reason: v1alpha1.ProbeSucceededHealthyReason,
+					},
+				)
+			})
+
+			It("requeues once the prober probes the resource again", func() {
+				result, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(time.Minute))
+			})
+		})
+
+		Context("when the health of a resource is flapping", func() {
+			BeforeEach(func() {
+				transition := func(status metav1.ConditionStatus, ago time.Duration) v1alpha1.HealthTransition {
+					return v1alpha1.HealthTransition{Status: status, Time: metav1.NewTime(time.Now().Add(-ago))}
+				}
+				resourceStatuses = statuses.NewResourceStatuses([]v1alpha1.ResourceStatus{
+					{
+						RealizedResource: v1alpha1.RealizedResource{Name: "resource3"},
+						HealthHistory: []v1alpha1.HealthTransition{
+							transition(metav1.ConditionTrue, 25*time.Minute),
+							transition(metav1.ConditionFalse, 20*time.Minute),
+							transition(metav1.ConditionTrue, 15*time.Minute),
+							transition(metav1.ConditionFalse, 10*time.Minute),
+							transition(metav1.ConditionTrue, 5*time.Minute),
+							transition(metav1.ConditionFalse, 2*time.Minute),
+						},
+					},
+				}, conditions.AddConditionForResourceSubmittedWorkload)
+				resourceStatuses.Add(
+					&v1alpha1.RealizedResource{Name: "resource3"}, nil, false,
+					metav1.Condition{
+						Type:               v1alpha1.ResourceHealthy,
+						Status:             metav1.ConditionTrue,
+						Reason:             "Ready",
+						LastTransitionTime: metav1.Now(),
+					},
+				)
+			})
+
+			It("requeues for when the resource stops flapping", func() {
+				result, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically("~", 20*time.Minute, 5*time.Second))
+			})
+		})
+
 		It("updates the status of the workload with the realizedResources", func() {
 			_, _ = reconciler.Reconcile(ctx, req)
 
@@ -462,7 +600,7 @@ var _ = Describe("WorkloadReconciler", func() {
 
 		It("calls the condition manager to specify the supply chain is ready", func() {
 			_, _ = reconciler.Reconcile(ctx, req)
-			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.SupplyChainReadyCondition()))
+			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.SupplyChainReadyCondition("selected as the most specific match")))
 		})
 
 		It("calls the condition manager to report the resources have been submitted", func() {
@@ -470,6 +608,14 @@ var _ = Describe("WorkloadReconciler", func() {
 			Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(conditions.ResourcesSubmittedCondition(true)))
 		})
 
+		It("calls the condition manager to report whether the resources are degraded", func() {
+			_, _ = reconciler.Reconcile(ctx, req)
+			Expect(conditionManager.AddCallCount()).To(Equal(1))
+			condition, polarity := conditionManager.AddArgsForCall(0)
+			Expect(condition).To(Equal(conditions.ResourcesStableCondition()))
+			Expect(polarity).To(Equal(conditions.Informational))
+		})
+
 		It("watches the stampedObjects kinds", func() {
 			_, _ = reconciler.Reconcile(ctx, req)
 			Expect(stampedTracker.WatchCallCount()).To(Equal(2))
@@ -525,6 +671,126 @@ var _ = Describe("WorkloadReconciler", func() {
 			Expect(secondTemplateKey.String()).To(Equal("my-config-kind.carto.run//my-config-template"))
 		})
 
+		Context("params are sourced from config maps and secrets", func() {
+			BeforeEach(func() {
+				wl.Spec.Params = []v1alpha1.OwnerParam{
+					{
+						Name: "registry",
+						ValueFrom: &v1alpha1.ParamValueSource{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "registry-config"},
+								Key:                  "server",
+							},
+						},
+					},
+				}
+				supplyChain.Spec.Params = []v1alpha1.BlueprintParam{
+					{
+						Name: "token",
+						ValueFrom: &v1alpha1.ParamValueSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "registry-credentials"},
+								Key:                  "token",
+							},
+						},
+					},
+				}
+			})
+
+			It("watches the config maps and secrets in the workload namespace", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+
+				Expect(dependencyTracker.TrackCallCount()).To(Equal(5))
+
+				configMapKey, obj := dependencyTracker.TrackArgsForCall(1)
+				Expect(configMapKey.String()).To(Equal("ConfigMap/my-namespace/registry-config"))
+				Expect(obj.Name).To(Equal("my-workload-name"))
+
+				secretKey, _ := dependencyTracker.TrackArgsForCall(2)
+				Expect(secretKey.String()).To(Equal("Secret/my-namespace/registry-credentials"))
+			})
+		})
+
+		Context("resources pin template revisions", func() {
+			BeforeEach(func() {
+				supplyChain.Spec.Resources = []v1alpha1.SupplyChainResource{
+					{
+						Name: "resource1",
+						TemplateRef: v1alpha1.SupplyChainTemplateReference{
+							Kind:     "ClusterImageTemplate",
+							Name:     "my-image-template",
+							Revision: 2,
+							Rollout:  &v1alpha1.TemplateRollout{Revision: 3, Percent: 50},
+						},
+					},
+				}
+			})
+
+			It("watches the pinned revisions", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+
+				Expect(dependencyTracker.TrackCallCount()).To(Equal(5))
+
+				revisionKey, obj := dependencyTracker.TrackArgsForCall(1)
+				Expect(revisionKey.String()).To(Equal("ClusterTemplateRevision.carto.run//clusterimagetemplate-my-image-template-2"))
+				Expect(obj.Name).To(Equal("my-workload-name"))
+
+				rolloutKey, _ := dependencyTracker.TrackArgsForCall(2)
+				Expect(rolloutKey.String()).To(Equal("ClusterTemplateRevision.carto.run//clusterimagetemplate-my-image-template-3"))
+			})
+		})
+
+		Context("a template extends other templates", func() {
+			BeforeEach(func() {
+				resourceStatuses = statuses.NewResourceStatuses(nil, conditions.AddConditionForResourceSubmittedWorkload)
+				resourceStatuses.Add(
+					&v1alpha1.RealizedResource{
+						Name: "resource1",
+						StampedRef: &v1alpha1.StampedRef{
+							ObjectReference: &corev1.ObjectReference{
+								Kind:       "MyThing",
+								APIVersion: "thing.io/alphabeta1",
+							},
+							Resource: "mything",
+						},
+						TemplateRef: &corev1.ObjectReference{
+							Kind:       "my-image-kind",
+							Name:       "my-image-template",
+							APIVersion: "carto.run/v1alpha1",
+						},
+						BaseTemplateRefs: []corev1.ObjectReference{
+							{
+								Kind:       "my-image-kind",
+								Name:       "my-base-image-template",
+								APIVersion: "carto.run/v1alpha1",
+							},
+							{
+								Kind:       "my-image-kind",
+								Name:       "my-root-image-template",
+								APIVersion: "carto.run/v1alpha1",
+							},
+						},
+					}, nil, false,
+				)
+			})
+
+			It("watches the extended templates", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+
+				Expect(dependencyTracker.TrackCallCount()).To(Equal(4))
+
+				templateKey, _ := dependencyTracker.TrackArgsForCall(1)
+				Expect(templateKey.String()).To(Equal("my-image-kind.carto.run//my-image-template"))
+
+				baseTemplateKey, obj := dependencyTracker.TrackArgsForCall(2)
+				Expect(baseTemplateKey.String()).To(Equal("my-image-kind.carto.run//my-base-image-template"))
+				Expect(obj.Name).To(Equal("my-workload-name"))
+
+				rootTemplateKey, _ := dependencyTracker.TrackArgsForCall(3)
+				Expect(rootTemplateKey.String()).To(Equal("my-image-kind.carto.run//my-root-image-template"))
+			})
+		})
+
 		Context("but getting the object GVK fails", func() {
 			BeforeEach(func() {
 				repo.GetSchemeReturns(runtime.NewScheme())
@@ -546,7 +812,7 @@ var _ = Describe("WorkloadReconciler", func() {
 						Message: "some informative message",
 					},
 				}
-				repo.GetSupplyChainsForWorkloadReturns([]*v1alpha1.ClusterSupplyChain{&supplyChain}, nil)
+				repo.GetSupplyChainsForWorkloadReturns([]*v1alpha1.ClusterSupplyChain{&supplyChain}, "selected as the most specific match", nil)
 			})
 
 			It("does not return an error", func() {
@@ -1239,9 +1505,20 @@ var _ = Describe("WorkloadReconciler", func() {
 	})
 
 	Context("and repo returns an empty list of supply chains", func() {
+		Context("and the repo explains why no supply chains matched", func() {
+			BeforeEach(func() {
+				repo.GetSupplyChainsForWorkloadReturns(nil, "[web]: did not match: unsatisfied label [type=web]", nil)
+			})
+
+			It("includes the explanation in the condition", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+				Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.SupplyChainNotFoundCondition(workloadLabels, "[web]: did not match: unsatisfied label [type=web]")))
+			})
+		})
+
 		It("calls the condition manager to add a supply chain not found condition", func() {
 			_, _ = reconciler.Reconcile(ctx, req)
-			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.SupplyChainNotFoundCondition(workloadLabels)))
+			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.SupplyChainNotFoundCondition(workloadLabels, "")))
 		})
 
 		It("does not return an error", func() {
@@ -1260,7 +1537,7 @@ var _ = Describe("WorkloadReconciler", func() {
 
 	Context("and repo returns an an error when requesting supply chains", func() {
 		BeforeEach(func() {
-			repo.GetSupplyChainsForWorkloadReturns(nil, errors.New("some error"))
+			repo.GetSupplyChainsForWorkloadReturns(nil, "", errors.New("some error"))
 		})
 
 		It("returns an unhandled error and requeues", func() {
@@ -1269,17 +1546,53 @@ var _ = Describe("WorkloadReconciler", func() {
 		})
 	})
 
+	Context("and the repo reports that matching supply chains do not allow the namespace", func() {
+		BeforeEach(func() {
+			repo.GetSupplyChainsForWorkloadReturns(nil, "", fmt.Errorf("evaluating selectors: %w", repository.NamespaceNotAllowedError{
+				Namespace:  "my-namespace",
+				Blueprints: []string{"production"},
+			}))
+		})
+
+		It("calls the condition manager to report the namespace is not allowed", func() {
+			_, _ = reconciler.Reconcile(ctx, req)
+			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.SupplyChainNamespaceNotAllowedCondition("my-namespace", []string{"production"})))
+		})
+
+		It("does not return an error", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("logs the handled error message", func() {
+			_, _ = reconciler.Reconcile(ctx, req)
+
+			Expect(out).To(Say(`"handled error":"no eligible supply chains for workload \[my-namespace/my-workload-name\]: evaluating selectors: namespace \[my-namespace\] is not allowed by the namespaceSelector of \[production\]"`))
+		})
+	})
+
 	Context("and the repo returns multiple supply chains", func() {
 		BeforeEach(func() {
 			supplyChain := v1alpha1.ClusterSupplyChain{
 				ObjectMeta: metav1.ObjectMeta{Name: "my-supply-chain"},
 			}
-			repo.GetSupplyChainsForWorkloadReturns([]*v1alpha1.ClusterSupplyChain{&supplyChain, &supplyChain}, nil)
+			repo.GetSupplyChainsForWorkloadReturns([]*v1alpha1.ClusterSupplyChain{&supplyChain, &supplyChain}, "", nil)
 		})
 
 		It("calls the condition manager to report too mane supply chains matched", func() {
 			_, _ = reconciler.Reconcile(ctx, req)
-			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.TooManySupplyChainMatchesCondition()))
+			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.TooManySupplyChainMatchesCondition("")))
+		})
+
+		It("includes the explanation of the selected supply chains in the condition", func() {
+			first := v1alpha1.ClusterSupplyChain{ObjectMeta: metav1.ObjectMeta{Name: "first"}}
+			second := v1alpha1.ClusterSupplyChain{ObjectMeta: metav1.ObjectMeta{Name: "second"}}
+			repo.GetSupplyChainsForWorkloadReturns([]*v1alpha1.ClusterSupplyChain{&first, &second}, "[first]: matched with score [1]; [second]: matched with score [1]", nil)
+
+			_, _ = reconciler.Reconcile(ctx, req)
+			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(conditions.TooManySupplyChainMatchesCondition(
+				"[first]: matched with score [1]; [second]: matched with score [1]",
+			)))
 		})
 
 		It("does not return an error", func() {
@@ -1356,7 +1669,7 @@ var _ = Describe("WorkloadReconciler", func() {
 					},
 				},
 			}
-			repo.GetSupplyChainsForWorkloadReturns([]*v1alpha1.ClusterSupplyChain{&supplyChain}, nil)
+			repo.GetSupplyChainsForWorkloadReturns([]*v1alpha1.ClusterSupplyChain{&supplyChain}, "selected as the most specific match", nil)
 
 			rlzr.RealizeReturns(nil)
 
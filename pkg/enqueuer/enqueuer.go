@@ -50,3 +50,22 @@ func EnqueueTracked(by client.Object, t tracker.DependencyTracker, s *runtime.Sc
 		},
 	)
 }
+
+// EnqueueTrackedByLabels enqueues the objects tracking a label selector which
+// may select the object, by way of dependency.NewLabelSelectorKey.
+func EnqueueTrackedByLabels(t tracker.DependencyTracker) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(
+		func(a client.Object) []reconcile.Request {
+			var requests []reconcile.Request
+
+			gk := a.GetObjectKind().GroupVersionKind().GroupKind()
+			for _, key := range tracker.LabelKeys(gk, a.GetNamespace(), a.GetLabels()) {
+				for _, item := range t.Lookup(key) {
+					requests = append(requests, reconcile.Request{NamespacedName: item})
+				}
+			}
+
+			return requests
+		},
+	)
+}
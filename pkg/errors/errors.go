@@ -68,6 +68,7 @@ type TemplateOptionsMatchError struct {
 	OptionNames   []string
 	BlueprintName string
 	BlueprintType string
+	Explanation   string
 }
 
 func (e TemplateOptionsMatchError) Error() string {
@@ -75,12 +76,17 @@ func (e TemplateOptionsMatchError) Error() string {
 	if len(e.OptionNames) != 0 {
 		optionNamesList = "[" + strings.Join(e.OptionNames, ", ") + "] "
 	}
-	return fmt.Errorf("expected exactly 1 option to match, found [%d] matching options %sfor resource [%s] in %s [%s]",
+	var explanation string
+	if e.Explanation != "" {
+		explanation = ": " + e.Explanation
+	}
+	return fmt.Errorf("expected exactly 1 option to match, found [%d] matching options %sfor resource [%s] in %s [%s]%s",
 		len(e.OptionNames),
 		optionNamesList,
 		e.ResourceName,
 		e.BlueprintType,
 		e.BlueprintName,
+		explanation,
 	).Error()
 }
 
@@ -103,6 +109,29 @@ func (e ApplyStampedObjectError) Error() string {
 	).Error()
 }
 
+type StampedObjectRejectedBySchemaError struct {
+	Err           error
+	StampedObject *unstructured.Unstructured
+	ResourceName  string
+	TemplateName  string
+	TemplateKind  string
+	BlueprintName string
+	BlueprintType string
+}
+
+func (e StampedObjectRejectedBySchemaError) Error() string {
+	return fmt.Errorf("stamped object [%s/%s] of template [%s/%s] for resource [%s] in %s [%s] is invalid: %w",
+		e.StampedObject.GetNamespace(),
+		e.StampedObject.GetName(),
+		e.TemplateKind,
+		e.TemplateName,
+		e.ResourceName,
+		e.BlueprintType,
+		e.BlueprintName,
+		e.Err,
+	).Error()
+}
+
 type StampError struct {
 	Err           error
 	ResourceName  string
@@ -123,6 +152,31 @@ func (e StampError) Error() string {
 	).Error()
 }
 
+type InvalidParamError struct {
+	Err           error
+	ParamName     string
+	ResourceName  string
+	TemplateName  string
+	TemplateKind  string
+	BlueprintName string
+	BlueprintType string
+}
+
+func (e InvalidParamError) Error() string {
+	if e.ResourceName == "" {
+		return fmt.Errorf("invalid value for param [%s]: %w", e.ParamName, e.Err).Error()
+	}
+	return fmt.Errorf("invalid value for param [%s] of template [%s/%s] for resource [%s] in %s [%s]: %w",
+		e.ParamName,
+		e.TemplateKind,
+		e.TemplateName,
+		e.ResourceName,
+		e.BlueprintType,
+		e.BlueprintName,
+		e.Err,
+	).Error()
+}
+
 type RetrieveOutputError struct {
 	Err               error
 	ResourceName      string
@@ -219,7 +273,7 @@ func IsUnhandledErrorType(err error) bool {
 		} else {
 			return false
 		}
-	case StampError, RetrieveOutputError, ResolveTemplateOptionError, TemplateOptionsMatchError:
+	case StampError, RetrieveOutputError, ResolveTemplateOptionError, TemplateOptionsMatchError, InvalidParamError, StampedObjectRejectedBySchemaError:
 		return false
 	default:
 		return true
@@ -20,3 +20,5 @@ const StampedObjectAppliedReason = "StampedObjectApplied"
 const StampedObjectRemovedReason = "StampedObjectRemoved"
 const ResourceOutputChangedReason = "ResourceOutputChanged"
 const ResourceHealthyStatusChangedReason = "ResourceHealthyStatusChanged"
+const ScheduledRunReason = "ScheduledRun"
+const RunCancelledReason = "RunCancelled"
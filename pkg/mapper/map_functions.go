@@ -67,6 +67,27 @@ func (mapper *Mapper) ClusterSupplyChainToWorkloadRequests(_ client.Object) []re
 	return requests
 }
 
+func (mapper *Mapper) NamespaceToWorkloadRequests(namespaceObject client.Object) []reconcile.Request {
+	list := &v1alpha1.WorkloadList{}
+	err := mapper.Client.List(context.TODO(), list, client.InNamespace(namespaceObject.GetName()))
+	if err != nil {
+		mapper.Logger.Error(err, "namespace to workload requests: client list workloads")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, item := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.Name,
+				Namespace: item.Namespace,
+			},
+		})
+	}
+
+	return requests
+}
+
 func (mapper *Mapper) ServiceAccountToWorkloadRequests(serviceAccountObject client.Object) []reconcile.Request {
 	err := mapper.addGVK(serviceAccountObject)
 	if err != nil {
@@ -212,6 +233,27 @@ func (mapper *Mapper) ClusterDeliveryToDeliverableRequests(_ client.Object) []re
 	return requests
 }
 
+func (mapper *Mapper) NamespaceToDeliverableRequests(namespaceObject client.Object) []reconcile.Request {
+	list := &v1alpha1.DeliverableList{}
+	err := mapper.Client.List(context.TODO(), list, client.InNamespace(namespaceObject.GetName()))
+	if err != nil {
+		mapper.Logger.Error(err, "namespace to deliverable requests: client list deliverables")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, item := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.Name,
+				Namespace: item.Namespace,
+			},
+		})
+	}
+
+	return requests
+}
+
 func (mapper *Mapper) ServiceAccountToDeliverableRequests(serviceAccountObject client.Object) []reconcile.Request {
 	err := mapper.addGVK(serviceAccountObject)
 	if err != nil {
@@ -135,6 +135,68 @@ var _ = Describe("MapFunctions", func() {
 		})
 	})
 
+	Describe("NamespaceToWorkloadRequests", func() {
+		var namespace *corev1.Namespace
+		BeforeEach(func() {
+			namespace = &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "first-namespace"},
+			}
+		})
+
+		Context("workloads in the namespace", func() {
+			BeforeEach(func() {
+				workload := v1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "first-workload",
+						Namespace: "first-namespace",
+					},
+				}
+
+				fakeClient.ListStub = func(ctx context.Context, list client.ObjectList, options ...client.ListOption) error {
+					listVal := reflect.Indirect(reflect.ValueOf(list))
+
+					existingVal := reflect.Indirect(reflect.ValueOf(v1alpha1.WorkloadList{Items: []v1alpha1.Workload{workload}}))
+					listVal.Set(existingVal)
+					return nil
+				}
+			})
+
+			It("lists the workloads in the namespace", func() {
+				_ = m.NamespaceToWorkloadRequests(namespace)
+
+				Expect(fakeClient.ListCallCount()).To(Equal(1))
+				_, _, opts := fakeClient.ListArgsForCall(0)
+				Expect(opts).To(ConsistOf(client.InNamespace("first-namespace")))
+			})
+
+			It("returns a list of requests that includes the workload", func() {
+				result := m.NamespaceToWorkloadRequests(namespace)
+				Expect(result).To(Equal([]reconcile.Request{
+					{
+						NamespacedName: types.NamespacedName{
+							Namespace: "first-namespace",
+							Name:      "first-workload",
+						},
+					},
+				}))
+			})
+		})
+
+		Context("client returns an error", func() {
+			BeforeEach(func() {
+				fakeClient.ListReturns(fmt.Errorf("some-error"))
+			})
+			It("logs an error to the client", func() {
+				result := m.NamespaceToWorkloadRequests(namespace)
+				Expect(result).To(BeEmpty())
+
+				Expect(fakeLogger.ErrorCallCount()).To(Equal(1))
+				_, secondArg, _ := fakeLogger.ErrorArgsForCall(0)
+				Expect(secondArg).To(Equal("namespace to workload requests: client list workloads"))
+			})
+		})
+	})
+
 	Describe("ServiceAccountToWorkloadRequests", func() {
 		var serviceAccount *corev1.ServiceAccount
 		BeforeEach(func() {
@@ -584,6 +646,68 @@ var _ = Describe("MapFunctions", func() {
 		})
 	})
 
+	Describe("NamespaceToDeliverableRequests", func() {
+		var namespace *corev1.Namespace
+		BeforeEach(func() {
+			namespace = &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "first-namespace"},
+			}
+		})
+
+		Context("deliverables in the namespace", func() {
+			BeforeEach(func() {
+				deliverable := v1alpha1.Deliverable{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "first-deliverable",
+						Namespace: "first-namespace",
+					},
+				}
+
+				fakeClient.ListStub = func(ctx context.Context, list client.ObjectList, options ...client.ListOption) error {
+					listVal := reflect.Indirect(reflect.ValueOf(list))
+
+					existingVal := reflect.Indirect(reflect.ValueOf(v1alpha1.DeliverableList{Items: []v1alpha1.Deliverable{deliverable}}))
+					listVal.Set(existingVal)
+					return nil
+				}
+			})
+
+			It("lists the deliverables in the namespace", func() {
+				_ = m.NamespaceToDeliverableRequests(namespace)
+
+				Expect(fakeClient.ListCallCount()).To(Equal(1))
+				_, _, opts := fakeClient.ListArgsForCall(0)
+				Expect(opts).To(ConsistOf(client.InNamespace("first-namespace")))
+			})
+
+			It("returns a list of requests that includes the deliverable", func() {
+				result := m.NamespaceToDeliverableRequests(namespace)
+				Expect(result).To(Equal([]reconcile.Request{
+					{
+						NamespacedName: types.NamespacedName{
+							Namespace: "first-namespace",
+							Name:      "first-deliverable",
+						},
+					},
+				}))
+			})
+		})
+
+		Context("client returns an error", func() {
+			BeforeEach(func() {
+				fakeClient.ListReturns(fmt.Errorf("some-error"))
+			})
+			It("logs an error to the client", func() {
+				result := m.NamespaceToDeliverableRequests(namespace)
+				Expect(result).To(BeEmpty())
+
+				Expect(fakeLogger.ErrorCallCount()).To(Equal(1))
+				_, secondArg, _ := fakeLogger.ErrorArgsForCall(0)
+				Expect(secondArg).To(Equal("namespace to deliverable requests: client list deliverables"))
+			})
+		})
+	})
+
 	Describe("ServiceAccountToDeliverableRequests", func() {
 		var serviceAccount *corev1.ServiceAccount
 		BeforeEach(func() {
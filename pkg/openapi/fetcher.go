@@ -0,0 +1,59 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"context"
+	"fmt"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+//go:generate go run -modfile ../../hack/tools/go.mod github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+//counterfeiter:generate . Fetcher
+type Fetcher interface {
+	// Fetch returns the OpenAPI v3 document served for a group version, or nil
+	// if the API server does not publish one.
+	Fetch(ctx context.Context, gv schema.GroupVersion) ([]byte, error)
+}
+
+// NewFetcher returns a Fetcher reading OpenAPI v3 documents from the
+// /openapi/v3 endpoint of the API server
+func NewFetcher(client rest.Interface) Fetcher {
+	return &fetcher{client: client}
+}
+
+type fetcher struct {
+	client rest.Interface
+}
+
+func (f *fetcher) Fetch(ctx context.Context, gv schema.GroupVersion) ([]byte, error) {
+	path := "/openapi/v3/apis/" + gv.String()
+	if gv.Group == "" {
+		path = "/openapi/v3/api/" + gv.Version
+	}
+
+	document, err := f.client.Get().AbsPath(path).Do(ctx).Raw()
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch openapi document [%s]: %w", path, err)
+	}
+	return document, nil
+}
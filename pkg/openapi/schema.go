@@ -0,0 +1,271 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+type document struct {
+	Components struct {
+		Schemas map[string]*openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+type groupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// openAPISchema holds the parts of an OpenAPI v3 schema used for validation
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties json.RawMessage           `json:"additionalProperties,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Enum                 []interface{}             `json:"enum,omitempty"`
+	Default              json.RawMessage           `json:"default,omitempty"`
+	AllOf                []*openAPISchema          `json:"allOf,omitempty"`
+	AnyOf                []*openAPISchema          `json:"anyOf,omitempty"`
+	OneOf                []*openAPISchema          `json:"oneOf,omitempty"`
+
+	PreserveUnknownFields bool               `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	IntOrString           bool               `json:"x-kubernetes-int-or-string,omitempty"`
+	EmbeddedResource      bool               `json:"x-kubernetes-embedded-resource,omitempty"`
+	GroupVersionKinds     []groupVersionKind `json:"x-kubernetes-group-version-kind,omitempty"`
+}
+
+func (d *document) schemaFor(gvk schema.GroupVersionKind) *openAPISchema {
+	for _, s := range d.Components.Schemas {
+		for _, candidate := range s.GroupVersionKinds {
+			if candidate.Group == gvk.Group && candidate.Version == gvk.Version && candidate.Kind == gvk.Kind {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+func (d *document) resolve(s *openAPISchema) *openAPISchema {
+	for depth := 0; s != nil && s.Ref != ""; depth++ {
+		if depth > 10 || !strings.HasPrefix(s.Ref, schemaRefPrefix) {
+			return nil
+		}
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, schemaRefPrefix)]
+	}
+	return s
+}
+
+// validate appends an error for each field of value, at path, which does not match s
+func (d *document) validate(s *openAPISchema, value interface{}, path string, errs *[]FieldError) {
+	s = d.resolve(s)
+	if s == nil || value == nil {
+		return
+	}
+
+	for _, subSchema := range s.AllOf {
+		d.validate(subSchema, value, path, errs)
+	}
+
+	if s.IntOrString || s.Format == "int-or-string" {
+		if !isString(value) && !isInteger(value) {
+			*errs = append(*errs, FieldError{Path: path, Message: fmt.Sprintf("expected integer or string, found %s", typeName(value))})
+		}
+		return
+	}
+
+	if len(s.AnyOf) > 0 || len(s.OneOf) > 0 {
+		// alternatives are left to the API server
+		return
+	}
+
+	if !matchesType(s.Type, value) {
+		*errs = append(*errs, FieldError{Path: path, Message: fmt.Sprintf("expected %s, found %s", s.Type, typeName(value))})
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		*errs = append(*errs, FieldError{Path: path, Message: fmt.Sprintf("unsupported value %v, expected one of %v", value, s.Enum)})
+	}
+
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		d.validateObject(s, typedValue, path, errs)
+	case []interface{}:
+		for i, item := range typedValue {
+			d.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func (d *document) validateObject(s *openAPISchema, value map[string]interface{}, path string, errs *[]FieldError) {
+	additionalProperties, allowsAdditional := s.additionalProperties()
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := joinPath(path, key)
+
+		if property, ok := s.Properties[key]; ok {
+			d.validate(property, value[key], fieldPath, errs)
+			continue
+		}
+
+		if additionalProperties != nil {
+			d.validate(additionalProperties, value[key], fieldPath, errs)
+			continue
+		}
+
+		if allowsAdditional || s.PreserveUnknownFields || len(s.Properties) == 0 {
+			continue
+		}
+
+		if s.EmbeddedResource && (key == "apiVersion" || key == "kind" || key == "metadata") {
+			continue
+		}
+
+		*errs = append(*errs, FieldError{Path: fieldPath, Message: "unknown field"})
+	}
+
+	for _, required := range s.Required {
+		if _, ok := value[required]; ok {
+			continue
+		}
+		if property := d.resolve(s.Properties[required]); property != nil && property.Default != nil {
+			continue
+		}
+		*errs = append(*errs, FieldError{Path: joinPath(path, required), Message: "required field is missing"})
+	}
+}
+
+// additionalProperties returns the schema of additional properties, or
+// whether any additional property is allowed when no schema is given
+func (s *openAPISchema) additionalProperties() (*openAPISchema, bool) {
+	if len(s.AdditionalProperties) == 0 {
+		return nil, false
+	}
+
+	var allowed bool
+	if err := json.Unmarshal(s.AdditionalProperties, &allowed); err == nil {
+		return nil, allowed
+	}
+
+	additional := &openAPISchema{}
+	if err := json.Unmarshal(s.AdditionalProperties, additional); err != nil {
+		return nil, true
+	}
+	return additional, true
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func matchesType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		return isString(value)
+	case "integer":
+		return isInteger(value)
+	case "number":
+		return isNumber(value)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	default:
+		return true
+	}
+}
+
+func isString(value interface{}) bool {
+	_, ok := value.(string)
+	return ok
+}
+
+func isInteger(value interface{}) bool {
+	switch typedValue := value.(type) {
+	case int, int32, int64:
+		return true
+	case float64:
+		return typedValue == math.Trunc(typedValue)
+	}
+	return false
+}
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, int32, int64, float32, float64:
+		return true
+	}
+	return false
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if reflect.DeepEqual(allowed, value) {
+			return true
+		}
+		if isNumber(allowed) && isNumber(value) && fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+	if isInteger(value) {
+		return "integer"
+	}
+	if isNumber(value) {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,170 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/vmware-tanzu/cartographer/pkg/logger"
+)
+
+// DefaultSchemaTTL is how long the schemas of a group version are used before
+// being fetched again, so that CRD changes are eventually picked up. An object
+// which does not match a cached schema is checked again against a fresh one
+// before it is rejected.
+const DefaultSchemaTTL = 10 * time.Minute
+
+//counterfeiter:generate . Validator
+type Validator interface {
+	// Validate checks obj against the schema the API server publishes for its
+	// kind, returning a ValidationError listing every invalid field. Objects of
+	// kinds without a published schema are not validated.
+	Validate(ctx context.Context, obj *unstructured.Unstructured) error
+}
+
+// FieldError describes an invalid field of an object
+type FieldError struct {
+	// Path is the path to the field, eg: spec.template.spec.containers[0].image
+	Path    string
+	Message string
+}
+
+func (e FieldError) String() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationError is returned when an object does not match its schema
+type ValidationError struct {
+	GroupVersionKind schema.GroupVersionKind
+	Errors           []FieldError
+}
+
+func (e ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldError := range e.Errors {
+		messages[i] = fieldError.String()
+	}
+	return fmt.Sprintf("object does not match the schema of [%s]: %s", e.GroupVersionKind.String(), strings.Join(messages, ", "))
+}
+
+// NewValidator returns a Validator using schemas fetched with fetcher. The
+// schemas of a group version are fetched once every ttl.
+func NewValidator(fetcher Fetcher, ttl time.Duration) Validator {
+	return &validator{
+		fetcher:   fetcher,
+		ttl:       ttl,
+		documents: make(map[schema.GroupVersion]*cachedDocument),
+		now:       time.Now,
+	}
+}
+
+type validator struct {
+	mu        sync.Mutex
+	fetcher   Fetcher
+	ttl       time.Duration
+	documents map[schema.GroupVersion]*cachedDocument
+	now       func() time.Time
+}
+
+type cachedDocument struct {
+	document  *document
+	fetchedAt time.Time
+}
+
+func (v *validator) Validate(ctx context.Context, obj *unstructured.Unstructured) error {
+	log := logr.FromContextOrDiscard(ctx)
+	gvk := obj.GroupVersionKind()
+
+	doc, cached, err := v.getDocument(ctx, gvk.GroupVersion(), false)
+	if err != nil {
+		// validation is best effort, the API server remains the authority
+		log.V(logger.DEBUG).Info("unable to fetch schema, skipping validation", "gvk", gvk.String(), "error", err.Error())
+		return nil
+	}
+
+	fieldErrors := doc.validateKind(gvk, obj.Object)
+	if len(fieldErrors) > 0 && cached {
+		// the cached schema may predate a change to the CRD, so the object is only
+		// rejected once it fails to match the schema currently published
+		log.V(logger.DEBUG).Info("object does not match cached schema, fetching schema again", "gvk", gvk.String())
+		doc, _, err = v.getDocument(ctx, gvk.GroupVersion(), true)
+		if err != nil {
+			log.V(logger.DEBUG).Info("unable to fetch schema, skipping validation", "gvk", gvk.String(), "error", err.Error())
+			return nil
+		}
+		fieldErrors = doc.validateKind(gvk, obj.Object)
+	}
+
+	if len(fieldErrors) > 0 {
+		return ValidationError{GroupVersionKind: gvk, Errors: fieldErrors}
+	}
+	return nil
+}
+
+// getDocument returns the schemas of the group version, and whether they were
+// read from the cache rather than fetched. When refresh is set they are always
+// fetched.
+func (v *validator) getDocument(ctx context.Context, gv schema.GroupVersion, refresh bool) (*document, bool, error) {
+	v.mu.Lock()
+	cached, ok := v.documents[gv]
+	v.mu.Unlock()
+
+	if !refresh && ok && v.now().Sub(cached.fetchedAt) < v.ttl {
+		return cached.document, true, nil
+	}
+
+	raw, err := v.fetcher.Fetch(ctx, gv)
+	if err != nil {
+		return nil, false, err
+	}
+
+	doc := &document{}
+	if raw != nil {
+		if err := json.Unmarshal(raw, doc); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal openapi document for [%s]: %w", gv.String(), err)
+		}
+	}
+
+	v.mu.Lock()
+	v.documents[gv] = &cachedDocument{document: doc, fetchedAt: v.now()}
+	v.mu.Unlock()
+
+	return doc, false, nil
+}
+
+// validateKind returns the fields of obj that do not match the schema of gvk,
+// or nothing when no schema is published for gvk
+func (d *document) validateKind(gvk schema.GroupVersionKind, obj map[string]interface{}) []FieldError {
+	kindSchema := d.schemaFor(gvk)
+	if kindSchema == nil {
+		return nil
+	}
+
+	var fieldErrors []FieldError
+	d.validate(kindSchema, obj, "", &fieldErrors)
+	return fieldErrors
+}
@@ -0,0 +1,284 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/vmware-tanzu/cartographer/pkg/openapi"
+	"github.com/vmware-tanzu/cartographer/pkg/openapi/openapifakes"
+)
+
+const appsDocument = `{
+  "components": {
+    "schemas": {
+      "io.k8s.api.apps.v1.Deployment": {
+        "type": "object",
+        "properties": {
+          "apiVersion": {"type": "string"},
+          "kind": {"type": "string"},
+          "metadata": {"allOf": [{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"}]},
+          "spec": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.apps.v1.DeploymentSpec"}]}
+        },
+        "x-kubernetes-group-version-kind": [{"group": "apps", "version": "v1", "kind": "Deployment"}]
+      },
+      "io.k8s.api.apps.v1.DeploymentSpec": {
+        "type": "object",
+        "required": ["selector", "template"],
+        "properties": {
+          "replicas": {"type": "integer", "format": "int32"},
+          "selector": {"type": "object", "additionalProperties": {"type": "string"}},
+          "strategy": {"type": "object", "properties": {"type": {"type": "string", "enum": ["Recreate", "RollingUpdate"]}, "maxSurge": {"x-kubernetes-int-or-string": true}}},
+          "template": {
+            "type": "object",
+            "properties": {
+              "containers": {"type": "array", "items": {"$ref": "#/components/schemas/io.k8s.api.core.v1.Container"}}
+            }
+          }
+        }
+      },
+      "io.k8s.api.core.v1.Container": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string"},
+          "image": {"type": "string"},
+          "imagePullPolicy": {"type": "string", "default": "IfNotPresent"}
+        }
+      },
+      "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "labels": {"type": "object", "additionalProperties": {"type": "string"}}
+        }
+      }
+    }
+  }
+}`
+
+const crdDocument = `{
+  "components": {
+    "schemas": {
+      "run.example.v1.Pipeline": {
+        "type": "object",
+        "properties": {
+          "apiVersion": {"type": "string"},
+          "kind": {"type": "string"},
+          "metadata": {"type": "object"},
+          "spec": {"type": "object", "x-kubernetes-preserve-unknown-fields": true}
+        },
+        "x-kubernetes-group-version-kind": [{"group": "example.run", "version": "v1", "kind": "Pipeline"}]
+      }
+    }
+  }
+}`
+
+var _ = Describe("Validator", func() {
+	var (
+		ctx         context.Context
+		fakeFetcher *openapifakes.FakeFetcher
+		validator   openapi.Validator
+		deployment  *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeFetcher = &openapifakes.FakeFetcher{}
+		fakeFetcher.FetchStub = func(_ context.Context, gv schema.GroupVersion) ([]byte, error) {
+			switch gv.Group {
+			case "apps":
+				return []byte(appsDocument), nil
+			case "example.run":
+				return []byte(crdDocument), nil
+			}
+			return nil, nil
+		}
+		validator = openapi.NewValidator(fakeFetcher, time.Hour)
+
+		deployment = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":   "my-app",
+				"labels": map[string]interface{}{"app": "my-app"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(2),
+				"selector": map[string]interface{}{"app": "my-app"},
+				"strategy": map[string]interface{}{"type": "Recreate", "maxSurge": "25%"},
+				"template": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "my-image"},
+					},
+				},
+			},
+		}}
+	})
+
+	Context("the object matches its schema", func() {
+		It("does not return an error", func() {
+			Expect(validator.Validate(ctx, deployment)).To(Succeed())
+		})
+
+		It("fetches the schema of the object's group version", func() {
+			Expect(validator.Validate(ctx, deployment)).To(Succeed())
+
+			Expect(fakeFetcher.FetchCallCount()).To(Equal(1))
+			_, gv := fakeFetcher.FetchArgsForCall(0)
+			Expect(gv).To(Equal(schema.GroupVersion{Group: "apps", Version: "v1"}))
+		})
+
+		It("accepts integral floats for integer fields", func() {
+			Expect(unstructured.SetNestedField(deployment.Object, float64(3), "spec", "replicas")).To(Succeed())
+			Expect(validator.Validate(ctx, deployment)).To(Succeed())
+		})
+	})
+
+	Context("the object does not match its schema", func() {
+		BeforeEach(func() {
+			Expect(unstructured.SetNestedField(deployment.Object, "two", "spec", "replicas")).To(Succeed())
+			Expect(unstructured.SetNestedField(deployment.Object, "Sometimes", "spec", "strategy", "type")).To(Succeed())
+			Expect(unstructured.SetNestedField(deployment.Object, true, "spec", "strategy", "maxSurge")).To(Succeed())
+			Expect(unstructured.SetNestedSlice(deployment.Object, []interface{}{
+				map[string]interface{}{"imagee": "my-image"},
+			}, "spec", "template", "containers")).To(Succeed())
+			unstructured.RemoveNestedField(deployment.Object, "spec", "selector")
+		})
+
+		It("returns a ValidationError with the path to every invalid field", func() {
+			err := validator.Validate(ctx, deployment)
+			Expect(err).To(HaveOccurred())
+
+			var validationError openapi.ValidationError
+			Expect(errors.As(err, &validationError)).To(BeTrue())
+			Expect(validationError.GroupVersionKind).To(Equal(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}))
+			Expect(validationError.Errors).To(ConsistOf(
+				openapi.FieldError{Path: "spec.replicas", Message: "expected integer, found string"},
+				openapi.FieldError{Path: "spec.strategy.type", Message: "unsupported value Sometimes, expected one of [Recreate RollingUpdate]"},
+				openapi.FieldError{Path: "spec.strategy.maxSurge", Message: "expected integer or string, found boolean"},
+				openapi.FieldError{Path: "spec.template.containers[0].imagee", Message: "unknown field"},
+				openapi.FieldError{Path: "spec.template.containers[0].name", Message: "required field is missing"},
+				openapi.FieldError{Path: "spec.selector", Message: "required field is missing"},
+			))
+			Expect(err.Error()).To(ContainSubstring("object does not match the schema of [apps/v1, Kind=Deployment]: "))
+			Expect(err.Error()).To(ContainSubstring("spec.template.containers[0].imagee: unknown field"))
+		})
+	})
+
+	Context("the object does not match the cached schema", func() {
+		BeforeEach(func() {
+			Expect(validator.Validate(ctx, deployment)).To(Succeed())
+			Expect(unstructured.SetNestedField(deployment.Object, "two", "spec", "replicas")).To(Succeed())
+		})
+
+		It("fetches the schema again before rejecting the object", func() {
+			Expect(validator.Validate(ctx, deployment)).To(HaveOccurred())
+			Expect(fakeFetcher.FetchCallCount()).To(Equal(2))
+		})
+
+		Context("and the schema has since changed to allow the object", func() {
+			BeforeEach(func() {
+				fakeFetcher.FetchStub = func(_ context.Context, _ schema.GroupVersion) ([]byte, error) {
+					return []byte(strings.Replace(appsDocument, `"replicas": {"type": "integer", "format": "int32"}`, `"replicas": {"x-kubernetes-int-or-string": true}`, 1)), nil
+				}
+			})
+
+			It("accepts the object", func() {
+				Expect(validator.Validate(ctx, deployment)).To(Succeed())
+			})
+		})
+
+		Context("and fetching the schema again fails", func() {
+			BeforeEach(func() {
+				fakeFetcher.FetchStub = nil
+				fakeFetcher.FetchReturns(nil, errors.New("connection refused"))
+			})
+
+			It("does not validate the object", func() {
+				Expect(validator.Validate(ctx, deployment)).To(Succeed())
+			})
+		})
+	})
+
+	Context("the schema preserves unknown fields", func() {
+		It("does not report unknown fields", func() {
+			pipeline := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "example.run/v1",
+				"kind":       "Pipeline",
+				"metadata":   map[string]interface{}{"name": "my-pipeline"},
+				"spec":       map[string]interface{}{"anything": map[string]interface{}{"goes": true}},
+			}}
+			Expect(validator.Validate(ctx, pipeline)).To(Succeed())
+		})
+	})
+
+	Context("no schema is published for the kind", func() {
+		It("does not validate the object", func() {
+			configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"data":       "not-a-map",
+			}}
+			Expect(validator.Validate(ctx, configMap)).To(Succeed())
+		})
+	})
+
+	Context("fetching the schema fails", func() {
+		BeforeEach(func() {
+			fakeFetcher.FetchStub = nil
+			fakeFetcher.FetchReturns(nil, errors.New("connection refused"))
+		})
+
+		It("does not validate the object", func() {
+			Expect(unstructured.SetNestedField(deployment.Object, "two", "spec", "replicas")).To(Succeed())
+			Expect(validator.Validate(ctx, deployment)).To(Succeed())
+		})
+
+		It("tries again on the next validation", func() {
+			Expect(validator.Validate(ctx, deployment)).To(Succeed())
+			Expect(validator.Validate(ctx, deployment)).To(Succeed())
+			Expect(fakeFetcher.FetchCallCount()).To(Equal(2))
+		})
+	})
+
+	Context("validating objects of the same group version", func() {
+		It("fetches the schema once", func() {
+			Expect(validator.Validate(ctx, deployment)).To(Succeed())
+			Expect(validator.Validate(ctx, deployment)).To(Succeed())
+			Expect(fakeFetcher.FetchCallCount()).To(Equal(1))
+		})
+
+		Context("the schema has expired", func() {
+			BeforeEach(func() {
+				validator = openapi.NewValidator(fakeFetcher, 0)
+			})
+
+			It("fetches the schema again", func() {
+				Expect(validator.Validate(ctx, deployment)).To(Succeed())
+				Expect(validator.Validate(ctx, deployment)).To(Succeed())
+				Expect(fakeFetcher.FetchCallCount()).To(Equal(2))
+			})
+		})
+	})
+})
@@ -17,21 +17,27 @@ package realizer
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/cartographer/pkg/errors"
 	"github.com/vmware-tanzu/cartographer/pkg/logger"
+	"github.com/vmware-tanzu/cartographer/pkg/openapi"
 	realizerclient "github.com/vmware-tanzu/cartographer/pkg/realizer/client"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/runnable/gc"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
 	"github.com/vmware-tanzu/cartographer/pkg/selector"
 	"github.com/vmware-tanzu/cartographer/pkg/stamp"
+	"github.com/vmware-tanzu/cartographer/pkg/tekton"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
 	"github.com/vmware-tanzu/cartographer/pkg/utils"
 )
@@ -39,15 +45,21 @@ import (
 //go:generate go run -modfile ../../hack/tools/go.mod github.com/maxbrunsfeld/counterfeiter/v6 -generate
 
 type ContextGenerator interface {
-	Generate(templateParams TemplateParams, resource OwnerResource, outputs OutputsGetter, labels templates.Labels) map[string]interface{}
+	Generate(ctx context.Context, resolver ParamValueResolver, templateParams TemplateParams, resource OwnerResource, outputs OutputsGetter, labels templates.Labels) (map[string]interface{}, error)
 }
 
 type resourceRealizer struct {
-	owner             client.Object
-	systemRepo        repository.Repository
-	ownerRepo         repository.Repository
-	templatingContext ContextGenerator
-	resourceLabeler   ResourceLabeler
+	owner              client.Object
+	systemRepo         repository.Repository
+	ownerRepo          repository.Repository
+	paramValueResolver ParamValueResolver
+	templatingContext  ContextGenerator
+	resourceLabeler    ResourceLabeler
+	stampCache         templates.StampCache
+	schemaValidator    openapi.Validator
+	prober             *healthcheck.Prober
+	pendingRuns        map[string]*v1alpha1.PendingRun
+	cleanupAfters      map[string]time.Duration
 }
 
 type ResourceLabeler func(resource OwnerResource, reader templates.Reader) templates.Labels
@@ -55,7 +67,7 @@ type ResourceLabeler func(resource OwnerResource, reader templates.Reader) templ
 type ResourceRealizerBuilder func(authToken string, owner client.Object, templatingContext ContextGenerator, systemRepo repository.Repository, resourceLabeler ResourceLabeler) (ResourceRealizer, error)
 
 //counterfeiter:generate sigs.k8s.io/controller-runtime/pkg/client.Client
-func NewResourceRealizerBuilder(repositoryBuilder repository.RepositoryBuilder, clientBuilder realizerclient.ClientBuilder, cache repository.RepoCache) ResourceRealizerBuilder {
+func NewResourceRealizerBuilder(repositoryBuilder repository.RepositoryBuilder, clientBuilder realizerclient.ClientBuilder, cache repository.RepoCache, stampCache templates.StampCache, schemaValidator openapi.Validator, prober *healthcheck.Prober) ResourceRealizerBuilder {
 	return func(authToken string, owner client.Object, templatingContext ContextGenerator, systemRepo repository.Repository, resourceLabeler ResourceLabeler) (ResourceRealizer, error) {
 		ownerClient, _, err := clientBuilder(authToken, false)
 		if err != nil {
@@ -65,11 +77,17 @@ func NewResourceRealizerBuilder(repositoryBuilder repository.RepositoryBuilder,
 		ownerRepo := repositoryBuilder(ownerClient, cache)
 
 		return &resourceRealizer{
-			owner:             owner,
-			systemRepo:        systemRepo,
-			ownerRepo:         ownerRepo,
-			templatingContext: templatingContext,
-			resourceLabeler:   resourceLabeler,
+			owner:              owner,
+			systemRepo:         systemRepo,
+			ownerRepo:          ownerRepo,
+			paramValueResolver: NewParamValueResolver(ownerRepo, owner.GetNamespace()),
+			templatingContext:  templatingContext,
+			resourceLabeler:    resourceLabeler,
+			stampCache:         stampCache,
+			schemaValidator:    schemaValidator,
+			prober:             prober,
+			pendingRuns:        map[string]*v1alpha1.PendingRun{},
+			cleanupAfters:      map[string]time.Duration{},
 		}, nil
 	}
 }
@@ -112,7 +130,15 @@ func (r *resourceRealizer) Do(ctx context.Context, resource OwnerResource, bluep
 
 	log.V(logger.DEBUG).Info("realizing template", "template", fmt.Sprintf("[%s/%s]", resource.TemplateRef.Kind, templateName))
 
-	apiTemplate, err = r.systemRepo.GetTemplate(ctx, templateName, resource.TemplateRef.Kind)
+	revision := TemplateRevisionFor(resource, r.owner)
+	if revision != 0 {
+		apiTemplate, err = r.systemRepo.GetTemplateRevision(ctx, templateName, resource.TemplateRef.Kind, revision)
+		if err == nil && apiTemplate == nil {
+			err = fmt.Errorf("revision [%d] not found", revision)
+		}
+	} else {
+		apiTemplate, err = r.systemRepo.GetTemplate(ctx, templateName, resource.TemplateRef.Kind)
+	}
 	if err != nil {
 		log.Error(err, "failed to get cluster template")
 		return nil, nil, nil, passThrough, templateName, errors.GetTemplateError{
@@ -132,8 +158,27 @@ func (r *resourceRealizer) Do(ctx context.Context, resource OwnerResource, bluep
 
 	labels := r.resourceLabeler(resource, template)
 
-	stamper := templates.StamperBuilder(r.owner, r.templatingContext.Generate(template, resource, outputs, labels), labels)
-	stampedObject, err = stamper.Stamp(ctx, template.GetResourceTemplate())
+	templatingContext, err := r.templatingContext.Generate(ctx, r.paramValueResolver, template, resource, outputs, labels)
+	if err != nil {
+		log.Error(err, "failed to generate templating context")
+		paramErr, ok := err.(errors.InvalidParamError)
+		if !ok {
+			return template, nil, nil, passThrough, templateName, fmt.Errorf("failed to generate templating context: %w", err)
+		}
+		paramErr.ResourceName = resource.Name
+		paramErr.TemplateName = templateName
+		paramErr.TemplateKind = resource.TemplateRef.Kind
+		paramErr.BlueprintName = blueprintName
+		paramErr.BlueprintType = errors.SupplyChain
+		return template, nil, nil, passThrough, templateName, paramErr
+	}
+
+	stamper := templates.StamperBuilder(r.owner, templatingContext, labels)
+	stamper.Metadata = StampMetadata(resource.MetadataPolicy, r.owner, template.GetResourceTemplate())
+	if template.GetLifecycle().IsImmutable() {
+		stamper.Metadata = RunRequestMetadata(r.owner, stamper.Metadata)
+	}
+	stampedObject, err = stamper.StampCached(ctx, r.stampCache, apiTemplate, template.GetResourceTemplate())
 	if err != nil {
 		log.Error(err, "failed to stamp resource")
 		return template, nil, nil, passThrough, templateName, errors.StampError{
@@ -146,6 +191,21 @@ func (r *resourceRealizer) Do(ctx context.Context, resource OwnerResource, bluep
 		}
 	}
 
+	if r.schemaValidator != nil {
+		if err = r.schemaValidator.Validate(ctx, stampedObject); err != nil {
+			log.Error(err, "stamped object does not match its schema")
+			return template, nil, nil, passThrough, templateName, errors.StampedObjectRejectedBySchemaError{
+				Err:           err,
+				StampedObject: stampedObject,
+				TemplateName:  templateName,
+				TemplateKind:  resource.TemplateRef.Kind,
+				ResourceName:  resource.Name,
+				BlueprintName: blueprintName,
+				BlueprintType: errors.SupplyChain,
+			}
+		}
+	}
+
 	stampReader, err = stamp.NewReader(apiTemplate, inputGenerator)
 	if err != nil {
 		log.Error(err, "failed to create new stamp reader")
@@ -160,14 +220,99 @@ func (r *resourceRealizer) Do(ctx context.Context, resource OwnerResource, bluep
 	}
 }
 
+// ListChildren lists the child objects of parent selected by rule, with the owner's service account
+func (r *resourceRealizer) ListChildren(ctx context.Context, rule *v1alpha1.ChildrenHealthRule, parent *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	labelSelector, err := healthcheck.ChildrenLabelSelector(rule, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid children selector: %w", err)
+	}
+
+	gvk := schema.FromAPIVersionAndKind(rule.APIVersion, rule.Kind)
+	candidates, err := r.ownerRepo.ListUnstructured(ctx, gvk, parent.GetNamespace(), labelSelector.MatchLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list children of kind [%s]: %w", rule.Kind, err)
+	}
+
+	var children []*unstructured.Unstructured
+	for _, candidate := range candidates {
+		if healthcheck.IsChild(rule, selector, parent, candidate) {
+			children = append(children, candidate)
+		}
+	}
+	return children, nil
+}
+
+// PendingRun returns the run of the named resource that is waiting for its
+// inputs to be stable for the debounce window of its template, if any
+func (r *resourceRealizer) PendingRun(resourceName string) *v1alpha1.PendingRun {
+	return r.pendingRuns[resourceName]
+}
+
+// CleanupAfter returns how soon a retained run of the named resource expires
+// by a TTL of the retention policy of its template, or 0 if none will
+func (r *resourceRealizer) CleanupAfter(resourceName string) time.Duration {
+	return r.cleanupAfters[resourceName]
+}
+
+// stampedObjectHealth is the health of an immutable stamped object, including
+// that of its children when the health rule evaluates them
+func (r *resourceRealizer) stampedObjectHealth(ctx context.Context, healthRule *v1alpha1.HealthRule, stampedObject *unstructured.Unstructured, ignoreObservedGeneration bool) metav1.ConditionStatus {
+	health := healthcheck.DetermineStampedObjectHealth(r.prober, healthRule, stampedObject, ignoreObservedGeneration)
+	if healthRule == nil || healthRule.Children == nil {
+		return health
+	}
+	if !ignoreObservedGeneration && healthcheck.StaleStatusCondition(healthRule, stampedObject) != nil {
+		return metav1.ConditionUnknown
+	}
+
+	children, listErr := r.ListChildren(ctx, healthRule.Children, stampedObject)
+	if listErr != nil {
+		logr.FromContextOrDiscard(ctx).Error(listErr, "failed to list children", "object", stampedObject)
+	}
+	return healthcheck.ChildrenHealthCondition(healthRule, metav1.Condition{Status: health}, children, listErr).Status
+}
+
 func (r *resourceRealizer) doImmutable(ctx context.Context, resource OwnerResource, blueprintName string,
 	stampedObject *unstructured.Unstructured, labels templates.Labels, log logr.Logger, template templates.Reader,
 	passThrough bool, templateName string, stampReader stamp.Outputter, mapper meta.RESTMapper,
 	templateOption v1alpha1.TemplateOption) (templates.Reader, *unstructured.Unstructured, *templates.Output, bool, string, error) {
-	err := r.ownerRepo.EnsureImmutableObjectExistsOnCluster(ctx, stampedObject, labels)
+	delete(r.pendingRuns, resource.Name)
+	delete(r.cleanupAfters, resource.Name)
+
+	var debouncedObject *unstructured.Unstructured
+	if debounce := template.GetResourceTemplate().Debounce; debounce != nil {
+		existingObjects, err := r.ownerRepo.ListUnstructured(ctx, stampedObject.GroupVersionKind(), stampedObject.GetNamespace(), labels)
+		if err != nil {
+			log.Error(err, "failed to list objects")
+			return template, nil, nil, passThrough, templateName, errors.ListCreatedObjectsError{
+				Err:       err,
+				Namespace: stampedObject.GetNamespace(),
+				Labels:    labels,
+			}
+		}
+
+		var pendingRun *v1alpha1.PendingRun
+		pendingRun, debouncedObject = stamp.Debounce(debounce.Duration, stampedObject, existingObjects, resource.PendingRun, time.Now())
+		if pendingRun != nil {
+			r.pendingRuns[resource.Name] = pendingRun
+		}
+	}
+
+	var err error
+	if debouncedObject != nil {
+		log.V(logger.DEBUG).Info("not creating object until inputs are stable", "runAfter", r.pendingRuns[resource.Name].RunAfter)
+		stampedObject = debouncedObject
+	} else {
+		err = r.ownerRepo.EnsureImmutableObjectExistsOnCluster(ctx, stampedObject, labels)
+	}
 
 	if err != nil {
-		log.Error(err, "failed to ensure object exists on cluster", "object", stampedObject)
+		log.Error(err, "failed to ensure object exists on cluster", "object", RedactorFromContext(ctx).RedactObject(stampedObject))
 		return template, nil, nil, passThrough, templateName, errors.ApplyStampedObjectError{
 			Err:           err,
 			StampedObject: stampedObject,
@@ -192,7 +337,7 @@ func (r *resourceRealizer) doImmutable(ctx context.Context, resource OwnerResour
 	var examinedObjects []*stamp.ExaminedObject
 
 	for _, someStampedObject := range allRunnableStampedObjects {
-		health := healthcheck.DetermineStampedObjectHealth(healthRule, someStampedObject)
+		health := r.stampedObjectHealth(ctx, healthRule, someStampedObject, template.GetResourceTemplate().IgnoreObservedGeneration)
 
 		examinedObjects = append(examinedObjects, &stamp.ExaminedObject{
 			StampedObject: someStampedObject,
@@ -200,7 +345,14 @@ func (r *resourceRealizer) doImmutable(ctx context.Context, resource OwnerResour
 		})
 	}
 
-	gc.CleanupRunnableStampedObjects(ctx, examinedObjects, template.GetRetentionPolicy(), r.ownerRepo)
+	if *template.GetLifecycle() == templates.Tekton && debouncedObject == nil {
+		r.cancelSupersededRuns(ctx, stampedObject, examinedObjects)
+	}
+
+	_, cleanupAfter := gc.CleanupRunnableStampedObjects(ctx, examinedObjects, template.GetRetentionPolicy(), r.ownerRepo, stampedObject, time.Now())
+	if cleanupAfter > 0 {
+		r.cleanupAfters[resource.Name] = cleanupAfter
+	}
 
 	latestSuccessfulObject := stamp.GetLatestSuccessfulObjFromExaminedObject(examinedObjects)
 
@@ -208,7 +360,7 @@ func (r *resourceRealizer) doImmutable(ctx context.Context, resource OwnerResour
 
 	if latestSuccessfulObject == nil {
 		for _, obj := range allRunnableStampedObjects {
-			log.V(logger.DEBUG).Info("failed to retrieve output from any object", "considered", obj)
+			log.V(logger.DEBUG).Info("failed to retrieve output from any object", "considered", RedactorFromContext(ctx).RedactObject(obj))
 		}
 
 		return template, stampedObject, nil, passThrough, templateName, errors.NoHealthyImmutableObjectsError{
@@ -224,7 +376,7 @@ func (r *resourceRealizer) doImmutable(ctx context.Context, resource OwnerResour
 	if err != nil {
 		qualifiedResource, rErr := utils.GetQualifiedResource(mapper, latestSuccessfulObject)
 		if rErr != nil {
-			log.Error(err, "failed to retrieve qualified resource name", "object", latestSuccessfulObject)
+			log.Error(err, "failed to retrieve qualified resource name", "object", RedactorFromContext(ctx).RedactObject(latestSuccessfulObject))
 			qualifiedResource = "could not fetch - see the log line for 'failed to retrieve qualified resource name'"
 		}
 
@@ -242,6 +394,26 @@ func (r *resourceRealizer) doImmutable(ctx context.Context, resource OwnerResour
 	return template, stampedObject, output, passThrough, templateName, nil
 }
 
+// cancelSupersededRuns requests Tekton to cancel the runs still in flight
+// for earlier inputs of the resource. Failures are logged rather than
+// returned, as the run for the current inputs already exists.
+func (r *resourceRealizer) cancelSupersededRuns(ctx context.Context, stampedObject *unstructured.Unstructured, examinedObjects []*stamp.ExaminedObject) {
+	log := logr.FromContextOrDiscard(ctx)
+
+	for _, examinedObject := range examinedObjects {
+		obj := examinedObject.StampedObject
+		if examinedObject.Health != metav1.ConditionUnknown || obj.GetName() == stampedObject.GetName() || !tekton.IsRun(obj) || tekton.IsCancelled(obj) {
+			continue
+		}
+
+		if err := r.ownerRepo.MergePatch(ctx, obj, tekton.CancelPatch(obj)); err != nil {
+			log.Error(err, "failed to cancel superseded run", "object", RedactorFromContext(ctx).RedactObject(obj))
+			continue
+		}
+		log.Info("cancelled superseded run", "run", obj.GetName())
+	}
+}
+
 func (r *resourceRealizer) doMutable(ctx context.Context, resource OwnerResource, blueprintName string,
 	stampedObject *unstructured.Unstructured, log logr.Logger, template templates.Reader, passThrough bool,
 	templateName string, stampReader stamp.Outputter, mapper meta.RESTMapper,
@@ -249,7 +421,7 @@ func (r *resourceRealizer) doMutable(ctx context.Context, resource OwnerResource
 
 	err := r.ownerRepo.EnsureMutableObjectExistsOnCluster(ctx, stampedObject)
 	if err != nil {
-		log.Error(err, "failed to ensure object exists on cluster", "object", stampedObject)
+		log.Error(err, "failed to ensure object exists on cluster", "object", RedactorFromContext(ctx).RedactObject(stampedObject))
 		return template, nil, nil, passThrough, templateName, errors.ApplyStampedObjectError{
 			Err:           err,
 			StampedObject: stampedObject,
@@ -262,11 +434,11 @@ func (r *resourceRealizer) doMutable(ctx context.Context, resource OwnerResource
 	output, err := stampReader.Output(stampedObject)
 
 	if err != nil {
-		log.Error(err, "failed to retrieve output from object", "object", stampedObject)
+		log.Error(err, "failed to retrieve output from object", "object", RedactorFromContext(ctx).RedactObject(stampedObject))
 
 		qualifiedResource, rErr := utils.GetQualifiedResource(mapper, stampedObject)
 		if rErr != nil {
-			log.Error(err, "failed to retrieve qualified resource name", "object", stampedObject)
+			log.Error(err, "failed to retrieve qualified resource name", "object", RedactorFromContext(ctx).RedactObject(stampedObject))
 			qualifiedResource = "could not fetch - see the log line for 'failed to retrieve qualified resource name'"
 		}
 
@@ -341,8 +513,22 @@ func (r *resourceRealizer) findMatchingTemplateOption(resource OwnerResource, su
 			OptionNames:   optionNames,
 			BlueprintName: supplyChainName,
 			BlueprintType: errors.SupplyChain,
+			Explanation:   r.explainTemplateOptions(resource.TemplateOptions, len(optionNames) > 1),
 		}
 	}
 
 	return resource.TemplateOptions[bestMatchingTemplateOptionsIndices[0]], nil
 }
+
+// explainTemplateOptions describes how each option's selector was evaluated against the
+// owner. When bestOnly is set, only the most specific matching options are described.
+func (r *resourceRealizer) explainTemplateOptions(options []v1alpha1.TemplateOption, bestOnly bool) string {
+	var summaries []string
+	for idx, explanation := range selector.Explain(r.owner, v1alpha1.TemplateOptionSelectors(options)) {
+		if bestOnly && !explanation.Best {
+			continue
+		}
+		summaries = append(summaries, fmt.Sprintf("[%s]: %s", options[idx].Name, explanation.Summary()))
+	}
+	return strings.Join(summaries, "; ")
+}
@@ -36,7 +36,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/openapi"
+	"github.com/vmware-tanzu/cartographer/pkg/openapi/openapifakes"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer"
+	realizerclient "github.com/vmware-tanzu/cartographer/pkg/realizer/client"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/realizerfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
 	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
@@ -62,6 +65,8 @@ var _ = Describe("Resource", func() {
 		repoCache                repository.RepoCache
 		supplyChainParams        []v1alpha1.BlueprintParam
 		fakeMapper               *realizerfakes.FakeRESTMapper
+		repositoryBuilder        repository.RepositoryBuilder
+		clientBuilder            realizerclient.ClientBuilder
 	)
 
 	BeforeEach(func() {
@@ -85,14 +90,14 @@ var _ = Describe("Resource", func() {
 		fakeOwnerRepo = repositoryfakes.FakeRepository{}
 		workload = v1alpha1.Workload{}
 
-		repositoryBuilder := func(client client.Client, repoCache repository.RepoCache) repository.Repository {
+		repositoryBuilder = func(client client.Client, repoCache repository.RepoCache) repository.Repository {
 			clientForBuiltRepository = client
 			cacheForBuiltRepository = repoCache
 			return &fakeOwnerRepo
 		}
 
 		builtClient := &repositoryfakes.FakeClient{}
-		clientBuilder := func(authToken string, _ bool) (client.Client, discovery.DiscoveryInterface, error) {
+		clientBuilder = func(authToken string, _ bool) (client.Client, discovery.DiscoveryInterface, error) {
 			authTokenForBuiltClient = authToken
 			return builtClient, nil, nil
 		}
@@ -100,7 +105,7 @@ var _ = Describe("Resource", func() {
 		logger := zap.New(zap.WriteTo(out))
 
 		repoCache = repository.NewCache(logger)
-		resourceRealizerBuilder := realizer.NewResourceRealizerBuilder(repositoryBuilder, clientBuilder, repoCache)
+		resourceRealizerBuilder := realizer.NewResourceRealizerBuilder(repositoryBuilder, clientBuilder, repoCache, nil, nil, nil)
 
 		theAuthToken = "tis-but-a-flesh-wound"
 
@@ -210,6 +215,61 @@ var _ = Describe("Resource", func() {
 					fakeOwnerRepo.EnsureMutableObjectExistsOnClusterReturns(nil)
 				})
 
+				When("the workload requests a run", func() {
+					BeforeEach(func() {
+						workload.Annotations = map[string]string{v1alpha1.RunRequestAnnotation: "2022-03-05T02:00:00Z"}
+					})
+
+					It("does not annotate the stamped object with the run request", func() {
+						_, _, _, _, _, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+						Expect(err).ToNot(HaveOccurred())
+
+						_, stampedObject := fakeOwnerRepo.EnsureMutableObjectExistsOnClusterArgsForCall(0)
+						Expect(stampedObject.GetAnnotations()).NotTo(HaveKey(v1alpha1.RunRequestAnnotation))
+					})
+				})
+
+				When("the stamped object does not match its schema", func() {
+					var fakeValidator *openapifakes.FakeValidator
+
+					BeforeEach(func() {
+						fakeValidator = &openapifakes.FakeValidator{}
+						fakeValidator.ValidateReturns(openapi.ValidationError{
+							GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+							Errors:           []openapi.FieldError{{Path: "data.player_current_lives", Message: "expected string, found integer"}},
+						})
+
+						resourceRealizerBuilder := realizer.NewResourceRealizerBuilder(repositoryBuilder, clientBuilder, repoCache, nil, fakeValidator, nil)
+						var err error
+						r, err = resourceRealizerBuilder(theAuthToken, &workload, realizer.NewContextGenerator(&workload, []v1alpha1.OwnerParam{}, supplyChainParams), &fakeSystemRepo, func(realizer.OwnerResource, templates.Reader) templates.Labels {
+							return templates.Labels{"expected-labels-from-labeler-placeholder": "labeler"}
+						})
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("validates the stamped object", func() {
+						_, _, _, _, _, _ = r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+
+						Expect(fakeValidator.ValidateCallCount()).To(Equal(1))
+						_, validatedObject := fakeValidator.ValidateArgsForCall(0)
+						Expect(*validatedObject).To(Equal(expectedObject))
+					})
+
+					It("returns StampedObjectRejectedBySchemaError without applying the object", func() {
+						template, stampedObject, out, _, templateRefName, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+						Expect(template).ToNot(BeNil())
+						Expect(stampedObject).To(BeNil())
+						Expect(out).To(BeNil())
+						Expect(templateRefName).To(Equal("image-template-1"))
+
+						Expect(err).To(HaveOccurred())
+						Expect(reflect.TypeOf(err).String()).To(Equal("errors.StampedObjectRejectedBySchemaError"))
+						Expect(err.Error()).To(ContainSubstring("data.player_current_lives: expected string, found integer"))
+
+						Expect(fakeOwnerRepo.EnsureMutableObjectExistsOnClusterCallCount()).To(Equal(0))
+					})
+				})
+
 				It("creates a stamped object and returns the outputs and stampedObjects", func() {
 					template, returnedStampedObject, out, isPassThrough, templateRefName, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
 					Expect(err).ToNot(HaveOccurred())
@@ -246,6 +306,56 @@ var _ = Describe("Resource", func() {
 				})
 			})
 
+			When("the resource pins a template revision", func() {
+				BeforeEach(func() {
+					resource.TemplateRevision = 2
+					fakeOwnerRepo.EnsureMutableObjectExistsOnClusterReturns(nil)
+				})
+
+				It("stamps the template from the revision", func() {
+					fakeSystemRepo.GetTemplateRevisionReturns(templateAPI, nil)
+
+					template, _, out, _, _, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(template).ToNot(BeNil())
+					Expect(out.Source.URL).To(Equal("some-url"))
+
+					Expect(fakeSystemRepo.GetTemplateCallCount()).To(Equal(0))
+					Expect(fakeSystemRepo.GetTemplateRevisionCallCount()).To(Equal(1))
+					_, name, kind, revision := fakeSystemRepo.GetTemplateRevisionArgsForCall(0)
+					Expect(name).To(Equal("image-template-1"))
+					Expect(kind).To(Equal("ClusterImageTemplate"))
+					Expect(revision).To(Equal(int64(2)))
+				})
+
+				When("the revision does not exist", func() {
+					It("returns GetTemplateError", func() {
+						fakeSystemRepo.GetTemplateRevisionReturns(nil, nil)
+
+						_, _, _, _, _, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+						Expect(err).To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring("unable to get template [image-template-1]"))
+						Expect(err.Error()).To(ContainSubstring("revision [2] not found"))
+						Expect(reflect.TypeOf(err).String()).To(Equal("errors.GetTemplateError"))
+					})
+				})
+
+				When("the workload is part of a rollout", func() {
+					BeforeEach(func() {
+						resource.TemplateRollout = &v1alpha1.TemplateRollout{Revision: 3, Percent: 100}
+						fakeSystemRepo.GetTemplateRevisionReturns(templateAPI, nil)
+					})
+
+					It("stamps the template from the rollout revision", func() {
+						_, _, _, _, _, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+						Expect(err).ToNot(HaveOccurred())
+
+						_, _, _, revision := fakeSystemRepo.GetTemplateRevisionArgsForCall(0)
+						Expect(revision).To(Equal(int64(3)))
+					})
+				})
+			})
+
 			When("template is immutable", func() {
 				BeforeEach(func() {
 					templateAPI.Spec.TemplateSpec.Lifecycle = "immutable"
@@ -354,6 +464,125 @@ var _ = Describe("Resource", func() {
 								Expect(out.Source.Revision).To(Equal("some-revision"))
 								Expect(out.Source.URL).To(Equal("some-url"))
 							})
+
+							When("the workload requests a run", func() {
+								BeforeEach(func() {
+									workload.Annotations = map[string]string{v1alpha1.RunRequestAnnotation: "2022-03-05T02:00:00Z"}
+								})
+
+								It("annotates the stamped object with the run request", func() {
+									_, _, _, _, _, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+									Expect(err).ToNot(HaveOccurred())
+
+									_, stampedObject, _ := fakeOwnerRepo.EnsureImmutableObjectExistsOnClusterArgsForCall(0)
+									Expect(stampedObject.GetAnnotations()).To(Equal(map[string]string{
+										v1alpha1.RunRequestAnnotation: "2022-03-05T02:00:00Z",
+									}))
+								})
+							})
+
+							When("the template has a debounce window", func() {
+								BeforeEach(func() {
+									templateAPI.Spec.TemplateSpec.Debounce = &metav1.Duration{Duration: time.Minute}
+								})
+
+								It("does not create an object for the changed inputs and returns the earlier object", func() {
+									_, returnedStampedObject, out, _, _, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+									Expect(err).ToNot(HaveOccurred())
+
+									Expect(fakeOwnerRepo.EnsureImmutableObjectExistsOnClusterCallCount()).To(Equal(0))
+									Expect(returnedStampedObject.GetCreationTimestamp().Time).To(Equal(time.Unix(1, 0)))
+									Expect(out.Source.URL).To(Equal("some-url"))
+								})
+
+								It("records a pending run for the resource", func() {
+									before := time.Now()
+									_, _, _, _, _, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+									Expect(err).ToNot(HaveOccurred())
+
+									pendingRun := r.PendingRun(resource.Name)
+									Expect(pendingRun).NotTo(BeNil())
+									Expect(pendingRun.InputsDigest).To(HavePrefix("sha256:"))
+									Expect(pendingRun.RunAfter.Time).To(BeTemporally(">=", before.Add(time.Minute)))
+								})
+
+								When("the pending run is due", func() {
+									It("creates the object and clears the pending run", func() {
+										_, _, _, _, _, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+										Expect(err).ToNot(HaveOccurred())
+
+										resource.PendingRun = r.PendingRun(resource.Name)
+										resource.PendingRun.RunAfter = metav1.NewTime(time.Now().Add(-time.Second))
+
+										_, _, _, _, _, err = r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+										Expect(err).ToNot(HaveOccurred())
+
+										Expect(fakeOwnerRepo.EnsureImmutableObjectExistsOnClusterCallCount()).To(Equal(1))
+										Expect(r.PendingRun(resource.Name)).To(BeNil())
+									})
+								})
+							})
+						})
+
+						When("the healthRule only evaluates children", func() {
+							var child *unstructured.Unstructured
+
+							BeforeEach(func() {
+								templateAPI.Spec.TemplateSpec.HealthRule = &v1alpha1.HealthRule{
+									Children: &v1alpha1.ChildrenHealthRule{
+										APIVersion: "v1",
+										Kind:       "Pod",
+										Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}},
+									},
+								}
+
+								stampedObjectWithTime := expectedObject.DeepCopy()
+								stampedObjectWithTime.SetCreationTimestamp(metav1.NewTime(time.Unix(1, 0)))
+								fakeOwnerRepo.ListUnstructuredReturnsOnCall(0, []*unstructured.Unstructured{stampedObjectWithTime}, nil)
+
+								child = &unstructured.Unstructured{Object: map[string]interface{}{
+									"status": map[string]interface{}{
+										"conditions": []interface{}{
+											map[string]interface{}{"type": "Ready", "status": "False"},
+										},
+									},
+								}}
+								child.SetName("my-pod")
+								child.SetLabels(map[string]string{"app": "my-app"})
+								fakeOwnerRepo.ListUnstructuredReturnsOnCall(1, []*unstructured.Unstructured{child}, nil)
+							})
+
+							It("lists the children of the examined objects", func() {
+								_, _, _, _, _, _ = r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+
+								Expect(fakeOwnerRepo.ListUnstructuredCallCount()).To(Equal(2))
+								_, gvk, _, labels := fakeOwnerRepo.ListUnstructuredArgsForCall(1)
+								Expect(gvk).To(Equal(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}))
+								Expect(labels).To(Equal(map[string]string{"app": "my-app"}))
+							})
+
+							When("the children are unhealthy", func() {
+								It("returns a NoHealthyImmutableObjectsError", func() {
+									_, _, out, _, _, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+									Expect(out).To(BeNil())
+									Expect(err).To(HaveOccurred())
+									Expect(reflect.TypeOf(err).String()).To(Equal("errors.NoHealthyImmutableObjectsError"))
+								})
+							})
+
+							When("the children are healthy", func() {
+								BeforeEach(func() {
+									Expect(unstructured.SetNestedSlice(child.Object, []interface{}{
+										map[string]interface{}{"type": "Ready", "status": "True"},
+									}, "status", "conditions")).To(Succeed())
+								})
+
+								It("returns the outputs of the examined object", func() {
+									_, _, out, _, _, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+									Expect(err).ToNot(HaveOccurred())
+									Expect(out.Source.URL).To(Equal("some-url"))
+								})
+							})
 						})
 					})
 
@@ -391,6 +620,92 @@ var _ = Describe("Resource", func() {
 					})
 				})
 			})
+
+			When("template has the tekton lifecycle", func() {
+				makeRun := func(name string, succeeded string) *unstructured.Unstructured {
+					run := &unstructured.Unstructured{}
+					run.SetAPIVersion("tekton.dev/v1beta1")
+					run.SetKind("PipelineRun")
+					run.SetName(name)
+					run.SetCreationTimestamp(metav1.NewTime(time.Unix(1, 0)))
+					Expect(unstructured.SetNestedSlice(run.Object, []interface{}{
+						map[string]interface{}{"type": "Succeeded", "status": succeeded},
+					}, "status", "conditions")).To(Succeed())
+					return run
+				}
+
+				BeforeEach(func() {
+					templateAPI.Spec.TemplateSpec.Lifecycle = "tekton"
+					templateAPI.Spec.URLPath = ""
+					templateAPI.Spec.RevisionPath = ""
+					fakeSystemRepo.GetTemplateReturns(templateAPI, nil)
+
+					fakeMapper.RESTMappingReturns(&meta.RESTMapping{
+						Resource: schema.GroupVersionResource{Group: "tekton.dev", Version: "v1beta1", Resource: "pipelineruns"},
+					}, nil)
+
+					fakeOwnerRepo.EnsureImmutableObjectExistsOnClusterStub = func(_ context.Context, obj *unstructured.Unstructured, _ map[string]string) error {
+						obj.SetName("current-run")
+						return nil
+					}
+
+					finishedRun := makeRun("finished-run", "True")
+					Expect(unstructured.SetNestedSlice(finishedRun.Object, []interface{}{
+						map[string]interface{}{"name": "url", "value": "finished-url"},
+						map[string]interface{}{"name": "revision", "value": "finished-revision"},
+					}, "status", "pipelineResults")).To(Succeed())
+
+					cancelledRun := makeRun("cancelled-run", "Unknown")
+					Expect(unstructured.SetNestedField(cancelledRun.Object, "Cancelled", "spec", "status")).To(Succeed())
+
+					fakeOwnerRepo.ListUnstructuredReturns([]*unstructured.Unstructured{
+						makeRun("current-run", "Unknown"),
+						makeRun("superseded-run", "Unknown"),
+						finishedRun,
+						cancelledRun,
+					}, nil)
+				})
+
+				It("cancels the runs still in flight for earlier inputs", func() {
+					_, _, _, _, _, _ = r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+
+					Expect(fakeOwnerRepo.MergePatchCallCount()).To(Equal(1))
+					_, run, patch := fakeOwnerRepo.MergePatchArgsForCall(0)
+					Expect(run.GetName()).To(Equal("superseded-run"))
+					Expect(string(patch)).To(Equal(`{"spec":{"status":"Cancelled"}}`))
+				})
+
+				It("reads the outputs from the results of the latest successful run", func() {
+					_, _, out, _, _, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(out.Source.URL).To(Equal("finished-url"))
+					Expect(out.Source.Revision).To(Equal("finished-revision"))
+				})
+
+				When("cancelling a run fails", func() {
+					BeforeEach(func() {
+						fakeOwnerRepo.MergePatchReturns(errors.New("patch failed"))
+					})
+
+					It("still reads the outputs of the resource", func() {
+						_, returnedStampedObject, _, _, _, err := r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(fakeOwnerRepo.MergePatchCallCount()).To(Equal(1))
+						Expect(returnedStampedObject.GetName()).To(Equal("current-run"))
+					})
+				})
+
+				When("the template is immutable", func() {
+					BeforeEach(func() {
+						templateAPI.Spec.TemplateSpec.Lifecycle = "immutable"
+					})
+
+					It("does not cancel in-flight runs", func() {
+						_, _, _, _, _, _ = r.Do(ctx, resource, blueprintName, outputs, fakeMapper)
+						Expect(fakeOwnerRepo.MergePatchCallCount()).To(Equal(0))
+					})
+				})
+			})
 		})
 
 		When("unable to get the template ref from repo", func() {
@@ -865,6 +1180,7 @@ var _ = Describe("Resource", func() {
 
 						Expect(err).To(HaveOccurred())
 						Expect(err.Error()).To(ContainSubstring("expected exactly 1 option to match, found [2] matching options [template-not-chosen, template-chosen] for resource [resource-1] in supply chain [supply-chain-name]"))
+						Expect(err.Error()).To(ContainSubstring("[template-not-chosen]: matched with score [1]; [template-chosen]: matched with score [1]"))
 					})
 
 				})
@@ -881,6 +1197,7 @@ var _ = Describe("Resource", func() {
 
 						Expect(err).To(HaveOccurred())
 						Expect(err.Error()).To(ContainSubstring("expected exactly 1 option to match, found [0] matching options for resource [resource-1] in supply chain [supply-chain-name]"))
+						Expect(err.Error()).To(ContainSubstring("[template-not-chosen]: did not match: unsatisfied field [spec.source.image"))
 					})
 				})
 
@@ -936,4 +1253,51 @@ var _ = Describe("Resource", func() {
 			})
 		})
 	})
+
+	Describe("ListChildren", func() {
+		var (
+			rule   *v1alpha1.ChildrenHealthRule
+			parent *unstructured.Unstructured
+		)
+
+		BeforeEach(func() {
+			rule = &v1alpha1.ChildrenHealthRule{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}},
+				Owned:      true,
+			}
+			parent = &unstructured.Unstructured{}
+			parent.SetNamespace("my-ns")
+			parent.SetUID("parent-uid")
+		})
+
+		It("lists the owned objects matching the selector in the namespace of the parent", func() {
+			owned := &unstructured.Unstructured{}
+			owned.SetName("owned")
+			owned.SetLabels(map[string]string{"app": "my-app"})
+			owned.SetOwnerReferences([]metav1.OwnerReference{{UID: "parent-uid"}})
+			notOwned := &unstructured.Unstructured{}
+			notOwned.SetName("not-owned")
+			notOwned.SetLabels(map[string]string{"app": "my-app"})
+			fakeOwnerRepo.ListUnstructuredReturns([]*unstructured.Unstructured{owned, notOwned}, nil)
+
+			children, err := r.ListChildren(ctx, rule, parent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(children).To(Equal([]*unstructured.Unstructured{owned}))
+
+			Expect(fakeOwnerRepo.ListUnstructuredCallCount()).To(Equal(1))
+			_, gvk, namespace, labels := fakeOwnerRepo.ListUnstructuredArgsForCall(0)
+			Expect(gvk).To(Equal(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}))
+			Expect(namespace).To(Equal("my-ns"))
+			Expect(labels).To(Equal(map[string]string{"app": "my-app"}))
+		})
+
+		It("returns an error when listing fails", func() {
+			fakeOwnerRepo.ListUnstructuredReturns(nil, errors.New("forbidden"))
+
+			_, err := r.ListChildren(ctx, rule, parent)
+			Expect(err).To(MatchError("failed to list children of kind [ReplicaSet]: forbidden"))
+		})
+	})
 })
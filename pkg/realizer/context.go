@@ -15,9 +15,14 @@
 package realizer
 
 import (
+	"context"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/errors"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
 )
 
@@ -36,10 +41,32 @@ type contextGenerator struct {
 	owner           client.Object
 }
 
-// Generate builds a context based on the template, owner and resource
-func (c contextGenerator) Generate(templateParams TemplateParams, resource OwnerResource, outputs OutputsGetter, labels templates.Labels) map[string]interface{} {
+// Generate builds a context based on the template, owner and resource.
+// Params sourced from ConfigMaps or Secrets are resolved with the resolver.
+func (c contextGenerator) Generate(ctx context.Context, resolver ParamValueResolver, templateParams TemplateParams, resource OwnerResource, outputs OutputsGetter, labels templates.Labels) (map[string]interface{}, error) {
 	inputGenerator := NewInputGenerator(resource, outputs)
-	merger := NewParamMerger(resource.Params, c.blueprintParams, c.ownerParams)
+
+	ownerParams, err := resolveOwnerParams(ctx, resolver, c.ownerParams)
+	if err != nil {
+		return nil, err
+	}
+
+	blueprintParams, err := resolveBlueprintParams(ctx, resolver, c.blueprintParams)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceParams, err := resolveBlueprintParams(ctx, resolver, resource.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	merger := NewParamMerger(resourceParams, blueprintParams, ownerParams)
+
+	params, err := merger.Merge(templateParams)
+	if err != nil {
+		return nil, err
+	}
 
 	configs := inputGenerator.GetConfigs()
 	sources := inputGenerator.GetSources()
@@ -47,7 +74,7 @@ func (c contextGenerator) Generate(templateParams TemplateParams, resource Owner
 	result := map[string]interface{}{
 		"workload":    c.owner,
 		"deliverable": c.owner,
-		"params":      merger.Merge(templateParams),
+		"params":      params,
 		"sources":     sources,
 		"images":      images,
 		"configs":     configs,
@@ -73,5 +100,94 @@ func (c contextGenerator) Generate(templateParams TemplateParams, resource Owner
 		}
 	}
 
-	return result
+	return result, nil
+}
+
+// ResolveParams resolves the values of the owner and blueprint params that
+// are sourced from ConfigMaps or Secrets with the resolver.
+func ResolveParams(ctx context.Context, resolver ParamValueResolver, ownerParams []v1alpha1.OwnerParam, blueprintParams []v1alpha1.BlueprintParam) ([]v1alpha1.OwnerParam, []v1alpha1.BlueprintParam, error) {
+	resolvedOwnerParams, err := resolveOwnerParams(ctx, resolver, ownerParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolvedBlueprintParams, err := resolveBlueprintParams(ctx, resolver, blueprintParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resolvedOwnerParams, resolvedBlueprintParams, nil
+}
+
+func resolveOwnerParams(ctx context.Context, resolver ParamValueResolver, params []v1alpha1.OwnerParam) ([]v1alpha1.OwnerParam, error) {
+	var resolved []v1alpha1.OwnerParam
+	for _, param := range params {
+		if param.ValueFrom == nil && param.Value.Raw == nil {
+			return nil, errors.InvalidParamError{
+				Err:       fmt.Errorf("must set exactly one of value and valueFrom"),
+				ParamName: param.Name,
+			}
+		}
+		if param.ValueFrom != nil {
+			if param.Value.Raw != nil {
+				return nil, errors.InvalidParamError{
+					Err:       fmt.Errorf("must set exactly one of value and valueFrom"),
+					ParamName: param.Name,
+				}
+			}
+			value, err := resolveParamValue(ctx, resolver, param.Name, *param.ValueFrom)
+			if err != nil {
+				return nil, err
+			}
+			if value == nil {
+				continue
+			}
+			param.Value = *value
+		}
+		resolved = append(resolved, param)
+	}
+	return resolved, nil
+}
+
+func resolveBlueprintParams(ctx context.Context, resolver ParamValueResolver, params []v1alpha1.BlueprintParam) ([]v1alpha1.BlueprintParam, error) {
+	var resolved []v1alpha1.BlueprintParam
+	for _, param := range params {
+		if param.ValueFrom != nil {
+			if param.Value != nil || param.DefaultValue != nil {
+				return nil, errors.InvalidParamError{
+					Err:       fmt.Errorf("must not set value or default when valueFrom is set"),
+					ParamName: param.Name,
+				}
+			}
+			value, err := resolveParamValue(ctx, resolver, param.Name, *param.ValueFrom)
+			if err != nil {
+				return nil, err
+			}
+			if value == nil {
+				continue
+			}
+			param.Value = value
+		}
+		resolved = append(resolved, param)
+	}
+	return resolved, nil
+}
+
+func resolveParamValue(ctx context.Context, resolver ParamValueResolver, name string, source v1alpha1.ParamValueSource) (*apiextensionsv1.JSON, error) {
+	if resolver == nil {
+		return nil, errors.InvalidParamError{
+			Err:       fmt.Errorf("values from configmaps and secrets cannot be resolved"),
+			ParamName: name,
+		}
+	}
+
+	value, err := resolver.ResolveParamValue(ctx, source)
+	if err != nil {
+		if paramErr, ok := err.(errors.InvalidParamError); ok {
+			paramErr.ParamName = name
+			return nil, paramErr
+		}
+		return nil, fmt.Errorf("failed to resolve value of param [%s]: %w", name, err)
+	}
+	return value, nil
 }
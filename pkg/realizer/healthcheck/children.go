@@ -0,0 +1,193 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions"
+	"github.com/vmware-tanzu/cartographer/pkg/eval"
+	"github.com/vmware-tanzu/cartographer/pkg/utils"
+)
+
+// maxNamedChildren limits the number of failing children named in a message
+const maxNamedChildren = 5
+
+// ChildrenLabelSelector returns the label selector, from the rule's selector and
+// the selector found at its selectorPath in parent, selecting the children of parent.
+func ChildrenLabelSelector(rule *v1alpha1.ChildrenHealthRule, parent *unstructured.Unstructured) (*metav1.LabelSelector, error) {
+	selector := &metav1.LabelSelector{}
+	if rule.Selector != nil {
+		selector = rule.Selector.DeepCopy()
+	}
+
+	if rule.SelectorPath == "" {
+		return selector, nil
+	}
+
+	value, err := eval.EvaluatorBuilder().EvaluateJsonPath(rule.SelectorPath, parent.UnstructuredContent())
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate selectorPath [%s]: %w", rule.SelectorPath, err)
+	}
+
+	pathSelector := &metav1.LabelSelector{}
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		_, hasMatchLabels := typedValue["matchLabels"]
+		_, hasMatchExpressions := typedValue["matchExpressions"]
+		if hasMatchLabels || hasMatchExpressions {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(typedValue, pathSelector); err != nil {
+				return nil, fmt.Errorf("selectorPath [%s] is not a label selector: %w", rule.SelectorPath, err)
+			}
+		} else {
+			pathSelector.MatchLabels = map[string]string{}
+			for key, labelValue := range typedValue {
+				stringValue, ok := labelValue.(string)
+				if !ok {
+					return nil, fmt.Errorf("selectorPath [%s] is not a map of labels: value of [%s] is not a string", rule.SelectorPath, key)
+				}
+				pathSelector.MatchLabels[key] = stringValue
+			}
+		}
+	default:
+		return nil, fmt.Errorf("selectorPath [%s] is not a label selector, found %T", rule.SelectorPath, value)
+	}
+
+	for key, value := range pathSelector.MatchLabels {
+		if selector.MatchLabels == nil {
+			selector.MatchLabels = map[string]string{}
+		}
+		selector.MatchLabels[key] = value
+	}
+	selector.MatchExpressions = append(selector.MatchExpressions, pathSelector.MatchExpressions...)
+
+	return selector, nil
+}
+
+// IsChild returns whether obj is selected by selector and, when the rule
+// requires it, owned by parent
+func IsChild(rule *v1alpha1.ChildrenHealthRule, selector labels.Selector, parent, obj *unstructured.Unstructured) bool {
+	if !selector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+	if !rule.Owned {
+		return true
+	}
+	for _, ownerReference := range obj.GetOwnerReferences() {
+		if ownerReference.UID == parent.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
+// ChildrenHealthCondition combines condition, the health of a resource as
+// determined by the other rules of healthRule, with the health of its children.
+// A resource is only healthy when both it and its children are.
+func ChildrenHealthCondition(healthRule *v1alpha1.HealthRule, condition metav1.Condition, children []*unstructured.Unstructured, listErr error) metav1.Condition {
+	rule := healthRule.Children
+	onlyChildren := healthRule.AlwaysHealthy == nil && healthRule.SingleConditionType == "" && healthRule.MultiMatch == nil && healthRule.CEL == nil
+
+	if !onlyChildren && condition.Status != metav1.ConditionTrue {
+		return condition
+	}
+
+	if listErr != nil {
+		return conditions.ChildrenResourcesHealthyCondition(metav1.ConditionUnknown, v1alpha1.ChildrenListFailedReason, listErr.Error())
+	}
+
+	if len(children) == 0 {
+		return conditions.ChildrenResourcesHealthyCondition(metav1.ConditionUnknown, v1alpha1.NoChildrenReason,
+			fmt.Sprintf("no child objects of kind [%s] found", rule.Kind))
+	}
+
+	conditionType := rule.ConditionType
+	if conditionType == "" {
+		conditionType = "Ready"
+	}
+
+	var healthy int
+	var failing []string
+	anyUnhealthy := false
+	for _, child := range children {
+		childCondition := utils.ExtractConditions(child).ConditionWithType(conditionType)
+		if childCondition != nil && childCondition.Status == metav1.ConditionTrue {
+			healthy++
+			continue
+		}
+		if childCondition != nil && childCondition.Status == metav1.ConditionFalse {
+			anyUnhealthy = true
+		}
+		failing = append(failing, child.GetName())
+	}
+
+	required, err := requiredHealthyChildren(rule, len(children))
+	if err != nil {
+		return conditions.ChildrenResourcesHealthyCondition(metav1.ConditionUnknown, v1alpha1.ChildrenUnhealthyReason, err.Error())
+	}
+
+	message := fmt.Sprintf("%d/%d children healthy", healthy, len(children))
+
+	if healthy < required {
+		status := metav1.ConditionUnknown
+		if anyUnhealthy {
+			status = metav1.ConditionFalse
+		}
+		return conditions.ChildrenResourcesHealthyCondition(status, v1alpha1.ChildrenUnhealthyReason,
+			fmt.Sprintf("%s, %d required, failing: %s", message, required, namesMessage(failing)))
+	}
+
+	if onlyChildren {
+		return conditions.ChildrenResourcesHealthyCondition(metav1.ConditionTrue, v1alpha1.ChildrenHealthyReason, message)
+	}
+
+	if condition.Message != "" {
+		message = condition.Message + "; " + message
+	}
+	condition.Message = message
+	return condition
+}
+
+func requiredHealthyChildren(rule *v1alpha1.ChildrenHealthRule, total int) (int, error) {
+	switch rule.Policy {
+	case v1alpha1.ChildrenHealthPolicyAny:
+		return 1, nil
+	case v1alpha1.ChildrenHealthPolicyThreshold:
+		minHealthy := rule.MinHealthy
+		if minHealthy == nil {
+			minHealthy = &intstr.IntOrString{Type: intstr.String, StrVal: "100%"}
+		}
+		return intstr.GetScaledValueFromIntOrPercent(minHealthy, total, true)
+	default:
+		return total, nil
+	}
+}
+
+func namesMessage(names []string) string {
+	sort.Strings(names)
+	if len(names) > maxNamedChildren {
+		return fmt.Sprintf("[%s] and %d more", strings.Join(names[:maxNamedChildren], ", "), len(names)-maxNamedChildren)
+	}
+	return fmt.Sprintf("[%s]", strings.Join(names, ", "))
+}
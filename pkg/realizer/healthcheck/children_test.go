@@ -0,0 +1,263 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
+)
+
+func child(name, readyStatus string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	obj.SetName(name)
+	if readyStatus != "" {
+		AddConditionToUnstructured("Ready", readyStatus, obj)
+	}
+	return obj
+}
+
+var _ = Describe("ChildrenLabelSelector", func() {
+	var (
+		rule   *v1alpha1.ChildrenHealthRule
+		parent *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		rule = &v1alpha1.ChildrenHealthRule{APIVersion: "v1", Kind: "Pod"}
+		parent = &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"app": "my-app"},
+				},
+				"plainSelector": map[string]interface{}{"app": "my-app"},
+			},
+		}}
+	})
+
+	It("returns the selector of the rule", func() {
+		rule.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "web"}}
+		Expect(healthcheck.ChildrenLabelSelector(rule, parent)).To(Equal(&metav1.LabelSelector{
+			MatchLabels: map[string]string{"tier": "web"},
+		}))
+	})
+
+	It("reads a label selector from the parent", func() {
+		rule.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "web"}}
+		rule.SelectorPath = "spec.selector"
+		Expect(healthcheck.ChildrenLabelSelector(rule, parent)).To(Equal(&metav1.LabelSelector{
+			MatchLabels: map[string]string{"tier": "web", "app": "my-app"},
+		}))
+	})
+
+	It("reads a map of labels from the parent", func() {
+		rule.SelectorPath = "spec.plainSelector"
+		Expect(healthcheck.ChildrenLabelSelector(rule, parent)).To(Equal(&metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "my-app"},
+		}))
+	})
+
+	It("returns an error when the selector path does not exist", func() {
+		rule.SelectorPath = "spec.missing"
+		_, err := healthcheck.ChildrenLabelSelector(rule, parent)
+		Expect(err).To(MatchError(ContainSubstring("failed to evaluate selectorPath [spec.missing]")))
+	})
+})
+
+var _ = Describe("IsChild", func() {
+	var (
+		rule   *v1alpha1.ChildrenHealthRule
+		parent *unstructured.Unstructured
+		obj    *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		rule = &v1alpha1.ChildrenHealthRule{APIVersion: "v1", Kind: "Pod"}
+		parent = &unstructured.Unstructured{}
+		parent.SetUID("parent-uid")
+		obj = child("pod-1", "True")
+		obj.SetLabels(map[string]string{"app": "my-app"})
+	})
+
+	It("selects objects matching the selector", func() {
+		Expect(healthcheck.IsChild(rule, labels.SelectorFromSet(labels.Set{"app": "my-app"}), parent, obj)).To(BeTrue())
+		Expect(healthcheck.IsChild(rule, labels.SelectorFromSet(labels.Set{"app": "other"}), parent, obj)).To(BeFalse())
+	})
+
+	Context("the rule requires children to be owned", func() {
+		BeforeEach(func() {
+			rule.Owned = true
+		})
+
+		It("selects objects owned by the parent", func() {
+			Expect(healthcheck.IsChild(rule, labels.Everything(), parent, obj)).To(BeFalse())
+
+			obj.SetOwnerReferences([]metav1.OwnerReference{{Name: "parent", UID: "parent-uid"}})
+			Expect(healthcheck.IsChild(rule, labels.Everything(), parent, obj)).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("ChildrenHealthCondition", func() {
+	var (
+		healthRule      *v1alpha1.HealthRule
+		parentCondition metav1.Condition
+		children        []*unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		healthRule = &v1alpha1.HealthRule{
+			Children: &v1alpha1.ChildrenHealthRule{APIVersion: "v1", Kind: "Pod", Owned: true},
+		}
+		parentCondition = metav1.Condition{Type: "Healthy", Status: metav1.ConditionUnknown, Reason: "Unknown"}
+		children = []*unstructured.Unstructured{child("pod-a", "True"), child("pod-b", "False"), child("pod-c", "")}
+	})
+
+	Context("with only a children rule", func() {
+		It("is healthy when all children are ready", func() {
+			children = []*unstructured.Unstructured{child("pod-a", "True"), child("pod-b", "True")}
+			Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, children, nil)).To(MatchFields(IgnoreExtras, Fields{
+				"Type":    Equal("Healthy"),
+				"Status":  Equal(metav1.ConditionTrue),
+				"Reason":  Equal("ChildrenHealthy"),
+				"Message": Equal("2/2 children healthy"),
+			}))
+		})
+
+		It("is unhealthy, naming the failing children, when a child is not ready", func() {
+			Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, children, nil)).To(MatchFields(IgnoreExtras, Fields{
+				"Status":  Equal(metav1.ConditionFalse),
+				"Reason":  Equal("ChildrenUnhealthy"),
+				"Message": Equal("1/3 children healthy, 3 required, failing: [pod-b, pod-c]"),
+			}))
+		})
+
+		It("is unknown when no failing child is unhealthy", func() {
+			children = []*unstructured.Unstructured{child("pod-a", "True"), child("pod-c", "")}
+			Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, children, nil)).To(MatchFields(IgnoreExtras, Fields{
+				"Status": Equal(metav1.ConditionUnknown),
+				"Reason": Equal("ChildrenUnhealthy"),
+			}))
+		})
+
+		It("is unknown when there are no children", func() {
+			Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, nil, nil)).To(MatchFields(IgnoreExtras, Fields{
+				"Status":  Equal(metav1.ConditionUnknown),
+				"Reason":  Equal("NoChildren"),
+				"Message": Equal("no child objects of kind [Pod] found"),
+			}))
+		})
+
+		It("is unknown when the children cannot be listed", func() {
+			Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, nil, errors.New("forbidden"))).To(MatchFields(IgnoreExtras, Fields{
+				"Status":  Equal(metav1.ConditionUnknown),
+				"Reason":  Equal("ChildrenListFailed"),
+				"Message": Equal("forbidden"),
+			}))
+		})
+
+		It("limits the number of failing children named", func() {
+			children = nil
+			for i := 0; i < 8; i++ {
+				children = append(children, child(fmt.Sprintf("pod-%d", i), "False"))
+			}
+			Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, children, nil).Message).
+				To(Equal("0/8 children healthy, 8 required, failing: [pod-0, pod-1, pod-2, pod-3, pod-4] and 3 more"))
+		})
+
+		It("uses the configured condition type", func() {
+			healthRule.Children.ConditionType = "Available"
+			children = []*unstructured.Unstructured{child("pod-a", "True")}
+			Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, children, nil).Status).
+				To(Equal(metav1.ConditionUnknown))
+		})
+
+		Context("the policy is Any", func() {
+			BeforeEach(func() {
+				healthRule.Children.Policy = v1alpha1.ChildrenHealthPolicyAny
+			})
+
+			It("is healthy when any child is ready", func() {
+				Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, children, nil).Status).
+					To(Equal(metav1.ConditionTrue))
+			})
+		})
+
+		Context("the policy is Threshold", func() {
+			BeforeEach(func() {
+				healthRule.Children.Policy = v1alpha1.ChildrenHealthPolicyThreshold
+			})
+
+			It("is healthy when enough children are ready", func() {
+				healthRule.Children.MinHealthy = &intstr.IntOrString{Type: intstr.String, StrVal: "30%"}
+				Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, children, nil).Status).
+					To(Equal(metav1.ConditionTrue))
+			})
+
+			It("is unhealthy when too few children are ready", func() {
+				healthRule.Children.MinHealthy = &intstr.IntOrString{Type: intstr.Int, IntVal: 2}
+				Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, children, nil)).To(MatchFields(IgnoreExtras, Fields{
+					"Status":  Equal(metav1.ConditionFalse),
+					"Message": Equal("1/3 children healthy, 2 required, failing: [pod-b, pod-c]"),
+				}))
+			})
+		})
+	})
+
+	Context("alongside another rule", func() {
+		BeforeEach(func() {
+			healthRule.AlwaysHealthy = &runtime.RawExtension{Raw: []byte("{}")}
+		})
+
+		It("returns the condition of the other rule when it is not healthy", func() {
+			Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, children, nil)).To(Equal(parentCondition))
+		})
+
+		Context("the other rule is healthy", func() {
+			BeforeEach(func() {
+				parentCondition = metav1.Condition{Type: "Healthy", Status: metav1.ConditionTrue, Reason: "ReadyCondition", Message: "deployment ready"}
+			})
+
+			It("is unhealthy when the children are not", func() {
+				Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, children, nil)).To(MatchFields(IgnoreExtras, Fields{
+					"Status": Equal(metav1.ConditionFalse),
+					"Reason": Equal("ChildrenUnhealthy"),
+				}))
+			})
+
+			It("keeps the condition of the other rule when the children are healthy", func() {
+				children = []*unstructured.Unstructured{child("pod-a", "True")}
+				Expect(healthcheck.ChildrenHealthCondition(healthRule, parentCondition, children, nil)).To(MatchFields(IgnoreExtras, Fields{
+					"Status":  Equal(metav1.ConditionTrue),
+					"Reason":  Equal("ReadyCondition"),
+					"Message": Equal("deployment ready; 1/1 children healthy"),
+				}))
+			})
+		})
+	})
+})
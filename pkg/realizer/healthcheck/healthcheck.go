@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/cel"
 	"github.com/vmware-tanzu/cartographer/pkg/conditions"
 	"github.com/vmware-tanzu/cartographer/pkg/eval"
 	"github.com/vmware-tanzu/cartographer/pkg/selector"
@@ -59,7 +60,23 @@ func OwnerHealthCondition(resourceStatuses []v1alpha1.ResourceStatus, previousCo
 	return healthyCondition
 }
 
-func DetermineHealthCondition(rule *v1alpha1.HealthRule, realizedResource *v1alpha1.RealizedResource, stampedObject *unstructured.Unstructured) metav1.Condition {
+// OwnerDegradedCondition reports whether the health of any of the resources is flapping
+func OwnerDegradedCondition(resourceStatuses []v1alpha1.ResourceStatus) metav1.Condition {
+	var flapping []string
+	for _, resourceStatus := range resourceStatuses {
+		if resourceStatus.Flapping {
+			flapping = append(flapping, resourceStatus.Name)
+		}
+	}
+	if len(flapping) == 0 {
+		return conditions.ResourcesStableCondition()
+	}
+	return conditions.ResourcesFlappingCondition(flapping)
+}
+
+// DetermineHealthCondition evaluates the health rule of a resource. The prober
+// runs the probes of probe rules.
+func DetermineHealthCondition(prober *Prober, rule *v1alpha1.HealthRule, realizedResource *v1alpha1.RealizedResource, stampedObject *unstructured.Unstructured) metav1.Condition {
 	if rule == nil {
 		if realizedResource == nil {
 			return conditions.NoResourceResourcesHealthyCondition()
@@ -84,6 +101,12 @@ func DetermineHealthCondition(rule *v1alpha1.HealthRule, realizedResource *v1alp
 			if rule.MultiMatch != nil {
 				return multiMatchCondition(rule.MultiMatch, stampedObject)
 			}
+			if rule.CEL != nil {
+				return celCondition(rule.CEL, stampedObject)
+			}
+			if rule.Probe != nil {
+				return prober.probeCondition(rule.Probe, stampedObject)
+			}
 		} else if rule.AlwaysHealthy != nil {
 			return conditions.NoStampedObjectResourcesHealthyCondition()
 		}
@@ -91,11 +114,19 @@ func DetermineHealthCondition(rule *v1alpha1.HealthRule, realizedResource *v1alp
 	return conditions.UnknownResourcesHealthyCondition()
 }
 
-func DetermineStampedObjectHealth(rule *v1alpha1.HealthRule, stampedObject *unstructured.Unstructured) metav1.ConditionStatus {
+// DetermineStampedObjectHealth is Unknown while the status of the stamped
+// object is stale, unless ignoreObservedGeneration is set. Children are not
+// considered, so a rule with only children is Unknown. The prober runs the
+// probes of probe rules.
+func DetermineStampedObjectHealth(prober *Prober, rule *v1alpha1.HealthRule, stampedObject *unstructured.Unstructured, ignoreObservedGeneration bool) metav1.ConditionStatus {
 	if stampedObject == nil {
 		return metav1.ConditionUnknown
 	}
 
+	if !ignoreObservedGeneration && StaleStatusCondition(rule, stampedObject) != nil {
+		return metav1.ConditionUnknown
+	}
+
 	if rule == nil || rule.AlwaysHealthy != nil {
 		return metav1.ConditionTrue
 	}
@@ -105,10 +136,96 @@ func DetermineStampedObjectHealth(rule *v1alpha1.HealthRule, stampedObject *unst
 		return condition.Status
 	}
 
+	if rule.CEL != nil {
+		condition := celCondition(rule.CEL, stampedObject)
+		return condition.Status
+	}
+
+	if rule.Probe != nil {
+		condition := prober.probeCondition(rule.Probe, stampedObject)
+		return condition.Status
+	}
+
+	if rule.MultiMatch == nil {
+		if rule.Children != nil {
+			// the health of a rule with only children is not known until the
+			// children are evaluated by ChildrenHealthCondition
+			return metav1.ConditionUnknown
+		}
+		return metav1.ConditionTrue
+	}
+
 	condition := multiMatchCondition(rule.MultiMatch, stampedObject)
 	return condition.Status
 }
 
+// StaleStatusCondition returns an Unknown condition when the health rule would
+// be evaluated against a status written for an earlier generation of the
+// stamped object, either as a whole or for a condition the rule reads.
+func StaleStatusCondition(rule *v1alpha1.HealthRule, stampedObject *unstructured.Unstructured) *metav1.Condition {
+	if rule == nil || rule.AlwaysHealthy != nil || stampedObject == nil {
+		return nil
+	}
+
+	generation := stampedObject.GetGeneration()
+	if observedGeneration, stale := utils.StaleObservedGeneration(stampedObject); stale {
+		condition := conditions.StaleStatusResourcesHealthyCondition(
+			fmt.Sprintf("status.observedGeneration [%d] is behind metadata.generation [%d]", observedGeneration, generation))
+		return &condition
+	}
+
+	objectConditions := utils.ExtractConditions(stampedObject)
+	for _, conditionType := range ruleConditionTypes(rule) {
+		objectCondition := objectConditions.ConditionWithType(conditionType)
+		if objectCondition != nil && objectCondition.ObservedGeneration != 0 && objectCondition.ObservedGeneration < generation {
+			condition := conditions.StaleStatusResourcesHealthyCondition(
+				fmt.Sprintf("condition [%s] observedGeneration [%d] is behind metadata.generation [%d]", conditionType, objectCondition.ObservedGeneration, generation))
+			return &condition
+		}
+	}
+
+	return nil
+}
+
+// HealthTransitionTime returns the latest lastTransitionTime of the
+// conditions the health rule reads from the stamped object, or nil if the rule
+// reads no conditions or none of them has transitioned.
+func HealthTransitionTime(rule *v1alpha1.HealthRule, stampedObject *unstructured.Unstructured) *metav1.Time {
+	if rule == nil || stampedObject == nil {
+		return nil
+	}
+
+	var transitionTime *metav1.Time
+	objectConditions := utils.ExtractConditions(stampedObject)
+	for _, conditionType := range ruleConditionTypes(rule) {
+		objectCondition := objectConditions.ConditionWithType(conditionType)
+		if objectCondition == nil || objectCondition.LastTransitionTime.IsZero() {
+			continue
+		}
+		if transitionTime == nil || transitionTime.Before(&objectCondition.LastTransitionTime) {
+			transitionTime = &objectCondition.LastTransitionTime
+		}
+	}
+
+	return transitionTime
+}
+
+func ruleConditionTypes(rule *v1alpha1.HealthRule) []string {
+	var conditionTypes []string
+	if rule.SingleConditionType != "" {
+		conditionTypes = append(conditionTypes, rule.SingleConditionType)
+	}
+	if rule.MultiMatch != nil {
+		for _, matchCondition := range rule.MultiMatch.Unhealthy.MatchConditions {
+			conditionTypes = append(conditionTypes, matchCondition.Type)
+		}
+		for _, matchCondition := range rule.MultiMatch.Healthy.MatchConditions {
+			conditionTypes = append(conditionTypes, matchCondition.Type)
+		}
+	}
+	return conditionTypes
+}
+
 func singleConditionTypeCondition(singleConditionType string, stampedObject *unstructured.Unstructured) metav1.Condition {
 	singleCondition := utils.ExtractConditions(stampedObject).ConditionWithType(singleConditionType)
 	if singleCondition != nil {
@@ -133,6 +250,52 @@ func multiMatchCondition(multiMatchRule *v1alpha1.MultiMatchHealthRule, stampedO
 	return conditions.MultiMatchNoMatchesCondition()
 }
 
+// celCondition evaluates the unhealthy expression before the healthy one. An
+// expression failing to evaluate, eg: because a field is not yet set, does
+// not match; when neither matches, the first evaluation error is reported.
+func celCondition(rule *v1alpha1.CELHealthRule, stampedObject *unstructured.Unstructured) metav1.Condition {
+	var evaluationErr error
+
+	if rule.Unhealthy != "" {
+		unhealthy, err := cel.EvaluateBool(rule.Unhealthy, stampedObject.UnstructuredContent())
+		if err == nil && unhealthy {
+			return conditions.CELResourcesHealthyCondition(metav1.ConditionFalse,
+				v1alpha1.CELUnhealthyExpressionHealthyReason,
+				celMessage(rule, stampedObject))
+		}
+		evaluationErr = err
+	}
+
+	healthy, err := cel.EvaluateBool(rule.Healthy, stampedObject.UnstructuredContent())
+	if err == nil && healthy {
+		return conditions.CELResourcesHealthyCondition(metav1.ConditionTrue,
+			v1alpha1.CELHealthyExpressionHealthyReason,
+			celMessage(rule, stampedObject))
+	}
+	if evaluationErr == nil {
+		evaluationErr = err
+	}
+
+	if evaluationErr != nil {
+		return conditions.CELResourcesHealthyCondition(metav1.ConditionUnknown,
+			v1alpha1.CELEvaluationErrorHealthyReason,
+			evaluationErr.Error())
+	}
+
+	return conditions.MultiMatchNoMatchesCondition()
+}
+
+func celMessage(rule *v1alpha1.CELHealthRule, stampedObject *unstructured.Unstructured) string {
+	if rule.Message == "" {
+		return ""
+	}
+	message, err := cel.EvaluateString(rule.Message, stampedObject.UnstructuredContent())
+	if err != nil {
+		return fmt.Sprintf("unknown, error evaluating message expression: %s", err.Error())
+	}
+	return message
+}
+
 func messageForMatchingFieldRequirement(requirement v1alpha1.HealthMatchFieldSelectorRequirement, stampedObject *unstructured.Unstructured) string {
 	evaluator := eval.EvaluatorBuilder()
 	fieldValue, fieldErr := evaluator.EvaluateJsonPath(requirement.Key, stampedObject.UnstructuredContent())
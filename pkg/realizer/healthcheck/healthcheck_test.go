@@ -15,6 +15,8 @@
 package healthcheck_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
@@ -41,7 +43,7 @@ func AddConditionToUnstructured(conditionType, conditionStatus string, obj *unst
 var _ = Describe("DetermineHealthCondition", func() {
 	It("is always healthy for AlwaysHealthy health rule", func() {
 		healthRule := &v1alpha1.HealthRule{AlwaysHealthy: &runtime.RawExtension{Raw: []byte{}}}
-		Expect(healthcheck.DetermineHealthCondition(healthRule, nil, nil)).To(MatchFields(IgnoreExtras,
+		Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, nil)).To(MatchFields(IgnoreExtras,
 			Fields{
 				"Type":   Equal("Healthy"),
 				"Status": Equal(metav1.ConditionUnknown),
@@ -52,7 +54,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 
 	It("is always healthy for AlwaysHealthy health rule if a stamped object exists", func() {
 		healthRule := &v1alpha1.HealthRule{AlwaysHealthy: &runtime.RawExtension{Raw: []byte{}}}
-		Expect(healthcheck.DetermineHealthCondition(healthRule, nil, &unstructured.Unstructured{})).To(MatchFields(IgnoreExtras,
+		Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, &unstructured.Unstructured{})).To(MatchFields(IgnoreExtras,
 			Fields{
 				"Type":   Equal("Healthy"),
 				"Status": Equal(metav1.ConditionTrue),
@@ -76,7 +78,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			},
 		}
 
-		Expect(healthcheck.DetermineHealthCondition(nil, realizedResource, nil)).To(MatchFields(IgnoreExtras,
+		Expect(healthcheck.DetermineHealthCondition(nil, nil, realizedResource, nil)).To(MatchFields(IgnoreExtras,
 			Fields{
 				"Type":   Equal("Healthy"),
 				"Status": Equal(metav1.ConditionTrue),
@@ -93,7 +95,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			},
 		}
 
-		Expect(healthcheck.DetermineHealthCondition(nil, realizedResource, nil)).To(MatchFields(IgnoreExtras,
+		Expect(healthcheck.DetermineHealthCondition(nil, nil, realizedResource, nil)).To(MatchFields(IgnoreExtras,
 			Fields{
 				"Type":   Equal("Healthy"),
 				"Status": Equal(metav1.ConditionUnknown),
@@ -103,7 +105,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 	})
 
 	It("is Unknown when no health rule and no resource", func() {
-		Expect(healthcheck.DetermineHealthCondition(nil, nil, nil)).To(MatchFields(IgnoreExtras,
+		Expect(healthcheck.DetermineHealthCondition(nil, nil, nil, nil)).To(MatchFields(IgnoreExtras,
 			Fields{
 				"Type":   Equal("Healthy"),
 				"Status": Equal(metav1.ConditionUnknown),
@@ -116,7 +118,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 		realizedResource := &v1alpha1.RealizedResource{
 			Outputs: []v1alpha1.Output{},
 		}
-		Expect(healthcheck.DetermineHealthCondition(nil, realizedResource, nil)).To(MatchFields(IgnoreExtras,
+		Expect(healthcheck.DetermineHealthCondition(nil, nil, realizedResource, nil)).To(MatchFields(IgnoreExtras,
 			Fields{
 				"Type":   Equal("Healthy"),
 				"Status": Equal(metav1.ConditionUnknown),
@@ -133,7 +135,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 				},
 			},
 		}
-		Expect(healthcheck.DetermineHealthCondition(nil, realizedResource, nil)).To(MatchFields(IgnoreExtras,
+		Expect(healthcheck.DetermineHealthCondition(nil, nil, realizedResource, nil)).To(MatchFields(IgnoreExtras,
 			Fields{
 				"Type":   Equal("Healthy"),
 				"Status": Equal(metav1.ConditionTrue),
@@ -152,7 +154,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 		})
 
 		It("returns unknown if there is no stamped object", func() {
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, nil)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, nil)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":   Equal("Healthy"),
 					"Status": Equal(metav1.ConditionUnknown),
@@ -179,7 +181,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 			_, _, err := dec.Decode([]byte(stampedObjectYaml), nil, stampedObject)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":    Equal("Healthy"),
 					"Status":  Equal(metav1.ConditionTrue),
@@ -208,7 +210,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 			_, _, err := dec.Decode([]byte(stampedObjectYaml), nil, stampedObject)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":    Equal("Healthy"),
 					"Status":  Equal(metav1.ConditionFalse),
@@ -237,7 +239,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 			_, _, err := dec.Decode([]byte(stampedObjectYaml), nil, stampedObject)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":    Equal("Healthy"),
 					"Status":  Equal(metav1.ConditionUnknown),
@@ -261,7 +263,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 			_, _, err := dec.Decode([]byte(stampedObjectYaml), nil, stampedObject)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":    Equal("Healthy"),
 					"Status":  Equal(metav1.ConditionUnknown),
@@ -339,7 +341,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 		})
 
 		It("returns unknown if there is no stamped object", func() {
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, nil)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, nil)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":   Equal("Healthy"),
 					"Status": Equal(metav1.ConditionUnknown),
@@ -370,7 +372,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 			_, _, err := dec.Decode([]byte(stampedObjectYaml), nil, stampedObject)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":    Equal("Healthy"),
 					"Status":  Equal(metav1.ConditionTrue),
@@ -425,7 +427,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 			_, _, err := dec.Decode([]byte(stampedObjectYaml), nil, stampedObject)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":    Equal("Healthy"),
 					"Status":  Equal(metav1.ConditionTrue),
@@ -457,7 +459,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 			_, _, err := dec.Decode([]byte(stampedObjectYaml), nil, stampedObject)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":   Equal("Healthy"),
 					"Status": Equal(metav1.ConditionUnknown),
@@ -488,7 +490,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 			_, _, err := dec.Decode([]byte(stampedObjectYaml), nil, stampedObject)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":   Equal("Healthy"),
 					"Status": Equal(metav1.ConditionUnknown),
@@ -522,7 +524,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 			_, _, err := dec.Decode([]byte(stampedObjectYaml), nil, stampedObject)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":    Equal("Healthy"),
 					"Status":  Equal(metav1.ConditionFalse),
@@ -556,7 +558,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 			_, _, err := dec.Decode([]byte(stampedObjectYaml), nil, stampedObject)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":    Equal("Healthy"),
 					"Status":  Equal(metav1.ConditionFalse),
@@ -583,7 +585,7 @@ var _ = Describe("DetermineHealthCondition", func() {
 			dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 			_, _, err := dec.Decode([]byte(stampedObjectYaml), nil, stampedObject)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(healthcheck.DetermineHealthCondition(healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
 				Fields{
 					"Type":    Equal("Healthy"),
 					"Status":  Equal(metav1.ConditionFalse),
@@ -593,17 +595,156 @@ var _ = Describe("DetermineHealthCondition", func() {
 			))
 		})
 	})
+
+	Context("HealthRule is CEL", func() {
+		var (
+			healthRule    *v1alpha1.HealthRule
+			stampedObject *unstructured.Unstructured
+		)
+
+		BeforeEach(func() {
+			healthRule = &v1alpha1.HealthRule{
+				CEL: &v1alpha1.CELHealthRule{
+					Healthy:   `self.status.readyReplicas == self.spec.replicas`,
+					Unhealthy: `has(self.status.failed) && self.status.failed > 0`,
+					Message:   `'ready replicas: ' + string(self.status.readyReplicas) + '/' + string(self.spec.replicas)`,
+				},
+			}
+
+			stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"spec":       map[string]interface{}{"replicas": int64(3)},
+				"status":     map[string]interface{}{"readyReplicas": int64(3)},
+			}}
+		})
+
+		It("returns unknown if there is no stamped object", func() {
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, nil)).To(MatchFields(IgnoreExtras,
+				Fields{
+					"Type":   Equal("Healthy"),
+					"Status": Equal(metav1.ConditionUnknown),
+				},
+			))
+		})
+
+		It("returns True with the evaluated message when the healthy expression is true", func() {
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+				Fields{
+					"Type":    Equal("Healthy"),
+					"Status":  Equal(metav1.ConditionTrue),
+					"Reason":  Equal("MatchedHealthyExpression"),
+					"Message": Equal("ready replicas: 3/3"),
+				},
+			))
+		})
+
+		It("returns False when the unhealthy expression is true", func() {
+			Expect(unstructured.SetNestedField(stampedObject.Object, int64(1), "status", "failed")).To(Succeed())
+
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+				Fields{
+					"Type":    Equal("Healthy"),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Reason":  Equal("MatchedUnhealthyExpression"),
+					"Message": Equal("ready replicas: 3/3"),
+				},
+			))
+		})
+
+		It("returns Unknown when neither expression is true", func() {
+			Expect(unstructured.SetNestedField(stampedObject.Object, int64(1), "status", "readyReplicas")).To(Succeed())
+
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+				Fields{
+					"Type":   Equal("Healthy"),
+					"Status": Equal(metav1.ConditionUnknown),
+					"Reason": Equal("NoMatchesFulfilled"),
+				},
+			))
+		})
+
+		It("returns Unknown with the error when an expression cannot be evaluated", func() {
+			unstructured.RemoveNestedField(stampedObject.Object, "status")
+
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+				Fields{
+					"Type":    Equal("Healthy"),
+					"Status":  Equal(metav1.ConditionUnknown),
+					"Reason":  Equal("ExpressionEvaluationError"),
+					"Message": ContainSubstring("no such key: status"),
+				},
+			))
+		})
+
+		It("surfaces errors evaluating the message expression into the message", func() {
+			healthRule.CEL.Message = `'phase: ' + self.status.phase`
+
+			Expect(healthcheck.DetermineHealthCondition(nil, healthRule, nil, stampedObject)).To(MatchFields(IgnoreExtras,
+				Fields{
+					"Status":  Equal(metav1.ConditionTrue),
+					"Message": HavePrefix("unknown, error evaluating message expression: "),
+				},
+			))
+		})
+	})
 })
 
 var _ = Describe("DetermineStampedObjectHealth", func() {
 	var (
-		returnedStatus metav1.ConditionStatus
-		rule           *v1alpha1.HealthRule
-		stampedObject  *unstructured.Unstructured
+		returnedStatus           metav1.ConditionStatus
+		rule                     *v1alpha1.HealthRule
+		stampedObject            *unstructured.Unstructured
+		ignoreObservedGeneration bool
 	)
 
+	BeforeEach(func() {
+		ignoreObservedGeneration = false
+	})
+
 	JustBeforeEach(func() {
-		returnedStatus = healthcheck.DetermineStampedObjectHealth(rule, stampedObject)
+		returnedStatus = healthcheck.DetermineStampedObjectHealth(nil, rule, stampedObject, ignoreObservedGeneration)
+	})
+
+	Context("when the status of the stampedObject is stale", func() {
+		BeforeEach(func() {
+			rule = &v1alpha1.HealthRule{SingleConditionType: "Ready"}
+			stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{}}
+			stampedObject.SetGeneration(3)
+			AddConditionToUnstructured("Ready", "True", stampedObject)
+			Expect(unstructured.SetNestedField(stampedObject.Object, int64(2), "status", "observedGeneration")).To(Succeed())
+		})
+
+		It("returns unknown", func() {
+			Expect(returnedStatus).To(Equal(metav1.ConditionUnknown))
+		})
+
+		Context("and observedGeneration is ignored", func() {
+			BeforeEach(func() {
+				ignoreObservedGeneration = true
+			})
+
+			It("evaluates the rule", func() {
+				Expect(returnedStatus).To(Equal(metav1.ConditionTrue))
+			})
+		})
+	})
+
+	Context("when the healthrule only evaluates children", func() {
+		BeforeEach(func() {
+			rule = &v1alpha1.HealthRule{
+				Children: &v1alpha1.ChildrenHealthRule{
+					APIVersion: "v1",
+					Kind:       "Pod",
+					Owned:      true,
+				},
+			}
+			stampedObject = &unstructured.Unstructured{}
+		})
+
+		It("returns unknown", func() {
+			Expect(returnedStatus).To(Equal(metav1.ConditionUnknown))
+		})
 	})
 
 	Context("when healthrule is nil", func() {
@@ -883,4 +1024,158 @@ var _ = Describe("DetermineStampedObjectHealth", func() {
 			})
 		})
 	})
+
+	Context("when healthRule is CEL", func() {
+		BeforeEach(func() {
+			rule = &v1alpha1.HealthRule{
+				CEL: &v1alpha1.CELHealthRule{
+					Healthy:   `self.status.phase == 'Succeeded'`,
+					Unhealthy: `self.status.phase == 'Failed'`,
+				},
+			}
+			stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{},
+			}}
+		})
+
+		Context("and the healthy expression is true", func() {
+			BeforeEach(func() {
+				Expect(unstructured.SetNestedField(stampedObject.Object, "Succeeded", "status", "phase")).To(Succeed())
+			})
+			It("returns true", func() {
+				Expect(returnedStatus).To(Equal(metav1.ConditionTrue))
+			})
+		})
+
+		Context("and the unhealthy expression is true", func() {
+			BeforeEach(func() {
+				Expect(unstructured.SetNestedField(stampedObject.Object, "Failed", "status", "phase")).To(Succeed())
+			})
+			It("returns false", func() {
+				Expect(returnedStatus).To(Equal(metav1.ConditionFalse))
+			})
+		})
+
+		Context("and the expressions cannot be evaluated", func() {
+			It("returns unknown", func() {
+				Expect(returnedStatus).To(Equal(metav1.ConditionUnknown))
+			})
+		})
+	})
+})
+
+var _ = Describe("StaleStatusCondition", func() {
+	var (
+		healthRule    *v1alpha1.HealthRule
+		stampedObject *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		healthRule = &v1alpha1.HealthRule{SingleConditionType: "Ready"}
+		stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{}}
+		stampedObject.SetGeneration(3)
+		AddConditionToUnstructured("Ready", "True", stampedObject)
+	})
+
+	It("is nil when the object does not publish observedGeneration", func() {
+		Expect(healthcheck.StaleStatusCondition(healthRule, stampedObject)).To(BeNil())
+	})
+
+	It("is nil when the status is for the current generation", func() {
+		Expect(unstructured.SetNestedField(stampedObject.Object, int64(3), "status", "observedGeneration")).To(Succeed())
+		Expect(healthcheck.StaleStatusCondition(healthRule, stampedObject)).To(BeNil())
+	})
+
+	It("is unknown when the status is for an earlier generation", func() {
+		Expect(unstructured.SetNestedField(stampedObject.Object, int64(2), "status", "observedGeneration")).To(Succeed())
+		Expect(healthcheck.StaleStatusCondition(healthRule, stampedObject)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Type":    Equal("Healthy"),
+			"Status":  Equal(metav1.ConditionUnknown),
+			"Reason":  Equal("StaleStatus"),
+			"Message": Equal("status.observedGeneration [2] is behind metadata.generation [3]"),
+		})))
+	})
+
+	It("is unknown when a condition read by the rule is for an earlier generation", func() {
+		Expect(unstructured.SetNestedSlice(stampedObject.Object, []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True", "observedGeneration": int64(2)},
+		}, "status", "conditions")).To(Succeed())
+		Expect(healthcheck.StaleStatusCondition(healthRule, stampedObject)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Reason":  Equal("StaleStatus"),
+			"Message": Equal("condition [Ready] observedGeneration [2] is behind metadata.generation [3]"),
+		})))
+
+		healthRule = &v1alpha1.HealthRule{MultiMatch: &v1alpha1.MultiMatchHealthRule{
+			Healthy: v1alpha1.HealthMatchRule{MatchConditions: []v1alpha1.ConditionRequirement{{Type: "Ready", Status: "True"}}},
+		}}
+		Expect(healthcheck.StaleStatusCondition(healthRule, stampedObject)).NotTo(BeNil())
+
+		healthRule = &v1alpha1.HealthRule{SingleConditionType: "Succeeded"}
+		Expect(healthcheck.StaleStatusCondition(healthRule, stampedObject)).To(BeNil())
+	})
+
+	It("is nil for an always healthy rule", func() {
+		Expect(unstructured.SetNestedField(stampedObject.Object, int64(2), "status", "observedGeneration")).To(Succeed())
+		healthRule = &v1alpha1.HealthRule{AlwaysHealthy: &runtime.RawExtension{Raw: []byte("{}")}}
+		Expect(healthcheck.StaleStatusCondition(healthRule, stampedObject)).To(BeNil())
+	})
+})
+
+var _ = Describe("HealthTransitionTime", func() {
+	var stampedObject *unstructured.Unstructured
+
+	BeforeEach(func() {
+		stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{}}
+		Expect(unstructured.SetNestedSlice(stampedObject.Object, []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True", "lastTransitionTime": "2022-03-04T02:00:00Z"},
+			map[string]interface{}{"type": "Synced", "status": "True", "lastTransitionTime": "2022-03-05T02:00:00Z"},
+			map[string]interface{}{"type": "Succeeded", "status": "True"},
+		}, "status", "conditions")).To(Succeed())
+	})
+
+	It("is the transition time of the condition read by the rule", func() {
+		transitionTime := healthcheck.HealthTransitionTime(&v1alpha1.HealthRule{SingleConditionType: "Ready"}, stampedObject)
+		Expect(transitionTime.UTC().Format(time.RFC3339)).To(Equal("2022-03-04T02:00:00Z"))
+	})
+
+	It("is the latest transition time of the conditions read by the rule", func() {
+		healthRule := &v1alpha1.HealthRule{MultiMatch: &v1alpha1.MultiMatchHealthRule{
+			Healthy: v1alpha1.HealthMatchRule{MatchConditions: []v1alpha1.ConditionRequirement{{Type: "Ready", Status: "True"}, {Type: "Synced", Status: "True"}}},
+		}}
+		transitionTime := healthcheck.HealthTransitionTime(healthRule, stampedObject)
+		Expect(transitionTime.UTC().Format(time.RFC3339)).To(Equal("2022-03-05T02:00:00Z"))
+	})
+
+	It("is nil when the condition has not transitioned", func() {
+		Expect(healthcheck.HealthTransitionTime(&v1alpha1.HealthRule{SingleConditionType: "Succeeded"}, stampedObject)).To(BeNil())
+	})
+
+	It("is nil when the rule reads no conditions", func() {
+		Expect(healthcheck.HealthTransitionTime(&v1alpha1.HealthRule{AlwaysHealthy: &runtime.RawExtension{Raw: []byte("{}")}}, stampedObject)).To(BeNil())
+	})
+})
+
+var _ = Describe("OwnerDegradedCondition", func() {
+	It("is false when no resource is flapping", func() {
+		Expect(healthcheck.OwnerDegradedCondition([]v1alpha1.ResourceStatus{
+			{RealizedResource: v1alpha1.RealizedResource{Name: "resource1"}},
+		})).To(MatchFields(IgnoreExtras, Fields{
+			"Type":   Equal("Degraded"),
+			"Status": Equal(metav1.ConditionFalse),
+			"Reason": Equal("ResourcesStable"),
+		}))
+	})
+
+	It("is true, naming the resources, when a resource is flapping", func() {
+		Expect(healthcheck.OwnerDegradedCondition([]v1alpha1.ResourceStatus{
+			{RealizedResource: v1alpha1.RealizedResource{Name: "resource1"}, Flapping: true},
+			{RealizedResource: v1alpha1.RealizedResource{Name: "resource2"}},
+			{RealizedResource: v1alpha1.RealizedResource{Name: "resource3"}, Flapping: true},
+		})).To(MatchFields(IgnoreExtras, Fields{
+			"Type":    Equal("Degraded"),
+			"Status":  Equal(metav1.ConditionTrue),
+			"Reason":  Equal("ResourcesFlapping"),
+			"Message": Equal("health of resources [resource1, resource3] is flapping"),
+		}))
+	})
 })
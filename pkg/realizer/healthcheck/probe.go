@@ -0,0 +1,323 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"syscall"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions"
+	"github.com/vmware-tanzu/cartographer/pkg/eval"
+)
+
+const (
+	defaultProbeTimeout = time.Second
+	probeRetryInterval  = 250 * time.Millisecond
+	// maxProbeBodyBytes bounds how much of a response body is read to match BodyRegex
+	maxProbeBodyBytes = 1 << 20
+
+	// ProbeInterval is how long the result of a probe is served before the
+	// address is probed again
+	ProbeInterval = 30 * time.Second
+)
+
+// DefaultDeniedProbeNetworks are the networks probes may not connect to: the
+// controller's own loopback, link-local addresses, including cloud metadata
+// endpoints, and unspecified and multicast addresses.
+var DefaultDeniedProbeNetworks = mustParseCIDRs(
+	"0.0.0.0/8", "127.0.0.0/8", "169.254.0.0/16", "224.0.0.0/4",
+	"::/128", "::1/128", "fe80::/10", "ff00::/8",
+)
+
+type probeResult struct {
+	condition metav1.Condition
+	probedAt  time.Time
+}
+
+// Prober probes addresses in the background, off the reconcile path, and
+// caches the results. A result is served until it is older than the interval,
+// then it continues to be served while the address is probed again. Probes
+// are cancelled when the context passed to Start is done.
+type Prober struct {
+	ctx            context.Context
+	cancel         context.CancelFunc
+	interval       time.Duration
+	deniedNetworks []*net.IPNet
+	client         *http.Client
+	dialer         *net.Dialer
+
+	mu       sync.Mutex
+	results  map[string]probeResult
+	inFlight map[string]bool
+}
+
+func NewProber(interval time.Duration, deniedNetworks []*net.IPNet) *Prober {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Prober{
+		ctx:            ctx,
+		cancel:         cancel,
+		interval:       interval,
+		deniedNetworks: deniedNetworks,
+		results:        map[string]probeResult{},
+		inFlight:       map[string]bool{},
+	}
+	p.dialer = &net.Dialer{Control: p.checkTarget}
+	p.client = &http.Client{
+		// a proxy would be dialed in place of the target, bypassing checkTarget
+		Transport: &http.Transport{Proxy: nil, DialContext: p.dialer.DialContext},
+	}
+	return p
+}
+
+// Interval is how long the result of a probe is served before the address is
+// probed again
+func (p *Prober) Interval() time.Duration {
+	return p.interval
+}
+
+// Start implements manager.Runnable, cancelling probes once ctx is done
+func (p *Prober) Start(ctx context.Context) error {
+	<-ctx.Done()
+	p.cancel()
+	return nil
+}
+
+func (p *Prober) condition(rule *v1alpha1.ProbeHealthRule, address string) metav1.Condition {
+	key := fmt.Sprintf("%s|%s|%d|%s|%d|%d", rule.Type, address, rule.ExpectedStatus, rule.BodyRegex, rule.Retries, rule.TimeoutSeconds)
+	now := time.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result, found := p.results[key]
+	if (!found || now.Sub(result.probedAt) >= p.interval) && !p.inFlight[key] {
+		p.inFlight[key] = true
+		p.evictExpired(now)
+		go p.run(key, rule.DeepCopy(), address)
+	}
+
+	if !found {
+		return conditions.ProbeResourcesHealthyCondition(metav1.ConditionUnknown,
+			v1alpha1.ProbePendingHealthyReason,
+			fmt.Sprintf("probe of [%s] has not completed", address))
+	}
+	return result.condition
+}
+
+// evictExpired forgets results no longer read, eg: of addresses which changed
+func (p *Prober) evictExpired(now time.Time) {
+	for key, result := range p.results {
+		if now.Sub(result.probedAt) >= 2*p.interval && !p.inFlight[key] {
+			delete(p.results, key)
+		}
+	}
+}
+
+func (p *Prober) run(key string, rule *v1alpha1.ProbeHealthRule, address string) {
+	condition, ok := p.probeWithRetries(p.ctx, rule, address)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.inFlight, key)
+	if ok {
+		p.results[key] = probeResult{condition: condition, probedAt: time.Now()}
+	}
+}
+
+// probeWithRetries returns false when ctx is done before the probe completes
+func (p *Prober) probeWithRetries(ctx context.Context, rule *v1alpha1.ProbeHealthRule, address string) (metav1.Condition, bool) {
+	attempts := rule.Retries + 1
+	for attempt := 1; ; attempt++ {
+		message, err := p.probe(ctx, rule, address)
+		if ctx.Err() != nil {
+			return metav1.Condition{}, false
+		}
+		if err == nil {
+			return conditions.ProbeResourcesHealthyCondition(metav1.ConditionTrue,
+				v1alpha1.ProbeSucceededHealthyReason,
+				message), true
+		}
+		if attempt == attempts {
+			return conditions.ProbeResourcesHealthyCondition(metav1.ConditionFalse,
+				v1alpha1.ProbeFailedHealthyReason,
+				fmt.Sprintf("probe failed after %d attempt(s): %s", attempts, err.Error())), true
+		}
+		select {
+		case <-ctx.Done():
+			return metav1.Condition{}, false
+		case <-time.After(probeRetryInterval):
+		}
+	}
+}
+
+// checkTarget rejects connections to denied networks. It is called with the
+// resolved address, so it also applies to host names and redirects.
+func (p *Prober) checkTarget(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("probe target [%s] is not an IP address", address)
+	}
+	for _, network := range p.deniedNetworks {
+		if network.Contains(ip) {
+			return fmt.Errorf("probe target [%s] is not allowed", address)
+		}
+	}
+	return nil
+}
+
+func (p *Prober) probeCondition(rule *v1alpha1.ProbeHealthRule, stampedObject *unstructured.Unstructured) metav1.Condition {
+	address, err := probeAddress(rule, stampedObject)
+	if err != nil {
+		return conditions.ProbeResourcesHealthyCondition(metav1.ConditionUnknown,
+			v1alpha1.ProbeURLNotFoundHealthyReason,
+			err.Error())
+	}
+	if rule.Type != v1alpha1.ProbeTypeTCP {
+		if u, err := url.Parse(address); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return conditions.ProbeResourcesHealthyCondition(metav1.ConditionFalse,
+				v1alpha1.ProbeFailedHealthyReason,
+				fmt.Sprintf("url [%s] is not an http or https url", address))
+		}
+	}
+
+	return p.condition(rule, address)
+}
+
+func probeAddress(rule *v1alpha1.ProbeHealthRule, stampedObject *unstructured.Unstructured) (string, error) {
+	value, err := eval.EvaluatorBuilder().EvaluateJsonPath(rule.URLPath, stampedObject.UnstructuredContent())
+	if err != nil {
+		return "", fmt.Errorf("failed to evaluate urlPath [%s]: %w", rule.URLPath, err)
+	}
+	address, ok := value.(string)
+	if !ok || address == "" {
+		return "", fmt.Errorf("urlPath [%s] is not a non-empty string", rule.URLPath)
+	}
+	return address, nil
+}
+
+func (p *Prober) probe(ctx context.Context, rule *v1alpha1.ProbeHealthRule, address string) (string, error) {
+	timeout := defaultProbeTimeout
+	if rule.TimeoutSeconds > 0 {
+		timeout = time.Duration(rule.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if rule.Type == v1alpha1.ProbeTypeTCP {
+		return p.probeTCP(ctx, address)
+	}
+	return p.probeHTTP(ctx, rule, address)
+}
+
+func (p *Prober) probeHTTP(ctx context.Context, rule *v1alpha1.ProbeHealthRule, address string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid url [%s]: %w", address, err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	expectedStatus := http.StatusOK
+	if rule.ExpectedStatus != 0 {
+		expectedStatus = rule.ExpectedStatus
+	}
+	if resp.StatusCode != expectedStatus {
+		return "", fmt.Errorf("GET [%s] returned status %d, expected %d", address, resp.StatusCode, expectedStatus)
+	}
+
+	if rule.BodyRegex != "" {
+		bodyRegex, err := regexp.Compile(rule.BodyRegex)
+		if err != nil {
+			return "", fmt.Errorf("invalid bodyRegex: %w", err)
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBodyBytes))
+		if err != nil {
+			return "", fmt.Errorf("failed to read body of GET [%s]: %w", address, err)
+		}
+		if !bodyRegex.Match(body) {
+			return "", fmt.Errorf("body of GET [%s] does not match [%s]", address, rule.BodyRegex)
+		}
+	}
+
+	return fmt.Sprintf("GET [%s] returned status %d", address, resp.StatusCode), nil
+}
+
+func (p *Prober) probeTCP(ctx context.Context, address string) (string, error) {
+	hostPort, err := tcpHostPort(address)
+	if err != nil {
+		return "", err
+	}
+
+	conn, err := p.dialer.DialContext(ctx, "tcp", hostPort)
+	if err != nil {
+		return "", err
+	}
+	_ = conn.Close()
+
+	return fmt.Sprintf("connected to [%s]", hostPort), nil
+}
+
+// tcpHostPort accepts either a URL, whose port defaults by scheme, or host:port
+func tcpHostPort(address string) (string, error) {
+	u, err := url.Parse(address)
+	if err == nil && u.Scheme != "" && u.Host != "" {
+		if u.Port() != "" {
+			return u.Host, nil
+		}
+		switch u.Scheme {
+		case "http":
+			return net.JoinHostPort(u.Hostname(), "80"), nil
+		case "https":
+			return net.JoinHostPort(u.Hostname(), "443"), nil
+		}
+		return "", fmt.Errorf("url [%s] has no port", address)
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return "", fmt.Errorf("invalid address [%s]: %w", address, err)
+	}
+	return address, nil
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
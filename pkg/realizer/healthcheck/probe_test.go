@@ -0,0 +1,271 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
+)
+
+var _ = Describe("Probe health rule", func() {
+	var (
+		rule          *v1alpha1.HealthRule
+		stampedObject *unstructured.Unstructured
+		server        *httptest.Server
+		requests      int32
+		handler       http.HandlerFunc
+		prober        *healthcheck.Prober
+	)
+
+	health := func() metav1.Condition {
+		return healthcheck.DetermineHealthCondition(prober, rule, &v1alpha1.RealizedResource{}, stampedObject)
+	}
+
+	withURL := func(url string) {
+		Expect(unstructured.SetNestedField(stampedObject.Object, url, "status", "url")).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		// probe the loopback address of the test server
+		prober = healthcheck.NewProber(healthcheck.ProbeInterval, nil)
+
+		rule = &v1alpha1.HealthRule{
+			Probe: &v1alpha1.ProbeHealthRule{URLPath: "status.url"},
+		}
+		stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{}}
+		atomic.StoreInt32(&requests, 0)
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("hello world"))
+		}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			handler(w, r)
+		}))
+		withURL(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("an HTTP probe", func() {
+		It("is healthy when the response has the expected status", func() {
+			Eventually(health).Should(MatchFields(IgnoreExtras, Fields{
+				"Type":    Equal("Healthy"),
+				"Status":  Equal(metav1.ConditionTrue),
+				"Reason":  Equal("ProbeSucceeded"),
+				"Message": Equal("GET [" + server.URL + "] returned status 200"),
+			}))
+		})
+
+		It("is unhealthy when the response has another status", func() {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			Eventually(health).Should(MatchFields(IgnoreExtras, Fields{
+				"Status":  Equal(metav1.ConditionFalse),
+				"Reason":  Equal("ProbeFailed"),
+				"Message": Equal("probe failed after 1 attempt(s): GET [" + server.URL + "] returned status 503, expected 200"),
+			}))
+		})
+
+		It("uses the expected status of the rule", func() {
+			rule.Probe.ExpectedStatus = http.StatusNoContent
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}
+			Eventually(health).Should(HaveField("Status", metav1.ConditionTrue))
+		})
+
+		It("matches the body against the body regex", func() {
+			rule.Probe.BodyRegex = "^hello w.rld$"
+			Eventually(health).Should(HaveField("Status", metav1.ConditionTrue))
+
+			rule.Probe.BodyRegex = "goodbye"
+			Eventually(health).Should(MatchFields(IgnoreExtras, Fields{
+				"Status":  Equal(metav1.ConditionFalse),
+				"Message": HaveSuffix("body of GET [" + server.URL + "] does not match [goodbye]"),
+			}))
+		})
+
+		It("retries a failed probe", func() {
+			rule.Probe.Retries = 2
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				if atomic.LoadInt32(&requests) < 3 {
+					w.WriteHeader(http.StatusBadGateway)
+				}
+			}
+			Eventually(health).Should(HaveField("Status", metav1.ConditionTrue))
+			Expect(atomic.LoadInt32(&requests)).To(Equal(int32(3)))
+		})
+
+		It("is unhealthy when every attempt fails", func() {
+			rule.Probe.Retries = 1
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+			Eventually(health).Should(MatchFields(IgnoreExtras, Fields{
+				"Status":  Equal(metav1.ConditionFalse),
+				"Message": HavePrefix("probe failed after 2 attempt(s)"),
+			}))
+			Expect(atomic.LoadInt32(&requests)).To(Equal(int32(2)))
+		})
+
+		It("fails an attempt which times out", func() {
+			rule.Probe.TimeoutSeconds = 1
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(3 * time.Second):
+				}
+			}
+			start := time.Now()
+			Eventually(health, 3*time.Second).Should(MatchFields(IgnoreExtras, Fields{
+				"Status":  Equal(metav1.ConditionFalse),
+				"Message": ContainSubstring("context deadline exceeded"),
+			}))
+			Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+		})
+	})
+
+	Context("a TCP probe", func() {
+		BeforeEach(func() {
+			rule.Probe.Type = v1alpha1.ProbeTypeTCP
+		})
+
+		It("is healthy when a connection is opened to the host and port of a url", func() {
+			Eventually(health).Should(MatchFields(IgnoreExtras, Fields{
+				"Status":  Equal(metav1.ConditionTrue),
+				"Reason":  Equal("ProbeSucceeded"),
+				"Message": Equal("connected to [" + server.Listener.Addr().String() + "]"),
+			}))
+		})
+
+		It("accepts a host and port", func() {
+			withURL(server.Listener.Addr().String())
+			Eventually(health).Should(HaveField("Status", metav1.ConditionTrue))
+		})
+
+		It("is unhealthy when nothing is listening", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			address := listener.Addr().String()
+			Expect(listener.Close()).To(Succeed())
+
+			withURL(address)
+			Eventually(health).Should(MatchFields(IgnoreExtras, Fields{
+				"Status": Equal(metav1.ConditionFalse),
+				"Reason": Equal("ProbeFailed"),
+			}))
+		})
+	})
+
+	It("is unknown when the url is not yet set on the resource", func() {
+		stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{}}
+		Expect(health()).To(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionUnknown),
+			"Reason": Equal("ProbeURLNotFound"),
+		}))
+	})
+
+	It("is unhealthy when an HTTP probe's url is not an http url", func() {
+		withURL("file:///etc/passwd")
+		Expect(health()).To(MatchFields(IgnoreExtras, Fields{
+			"Status":  Equal(metav1.ConditionFalse),
+			"Reason":  Equal("ProbeFailed"),
+			"Message": Equal("url [file:///etc/passwd] is not an http or https url"),
+		}))
+	})
+
+	It("is unknown until the probe completes", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}
+		Expect(health()).To(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionUnknown),
+			"Reason": Equal("ProbePending"),
+		}))
+		Eventually(health).Should(HaveField("Status", metav1.ConditionTrue))
+		Expect(atomic.LoadInt32(&requests)).To(Equal(int32(1)))
+	})
+
+	It("serves the result until it is older than the interval, then probes again", func() {
+		prober = healthcheck.NewProber(100*time.Millisecond, nil)
+		Eventually(health).Should(HaveField("Status", metav1.ConditionTrue))
+		Expect(health().Status).To(Equal(metav1.ConditionTrue))
+		Expect(atomic.LoadInt32(&requests)).To(Equal(int32(1)))
+
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		Eventually(health).Should(HaveField("Status", metav1.ConditionFalse))
+		Expect(atomic.LoadInt32(&requests)).To(BeNumerically(">=", 2))
+	})
+
+	It("does not probe denied networks", func() {
+		prober = healthcheck.NewProber(healthcheck.ProbeInterval, healthcheck.DefaultDeniedProbeNetworks)
+		Eventually(health).Should(MatchFields(IgnoreExtras, Fields{
+			"Status":  Equal(metav1.ConditionFalse),
+			"Reason":  Equal("ProbeFailed"),
+			"Message": ContainSubstring("probe target [" + server.Listener.Addr().String() + "] is not allowed"),
+		}))
+		Expect(atomic.LoadInt32(&requests)).To(BeZero())
+	})
+
+	It("cancels probes once the prober is stopped", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			_ = prober.Start(ctx)
+		}()
+
+		released := make(chan struct{})
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+			close(released)
+		}
+		rule.Probe.TimeoutSeconds = 30
+		Expect(health().Reason).To(Equal("ProbePending"))
+		Eventually(func() int32 { return atomic.LoadInt32(&requests) }).Should(Equal(int32(1)))
+
+		cancel()
+		Eventually(stopped).Should(BeClosed())
+		Eventually(released).Should(BeClosed())
+	})
+
+	It("serves the interval it was created with", func() {
+		Expect(healthcheck.NewProber(100*time.Millisecond, nil).Interval()).To(Equal(100 * time.Millisecond))
+	})
+
+	It("determines the health of a stamped object", func() {
+		Eventually(func() metav1.ConditionStatus {
+			return healthcheck.DetermineStampedObjectHealth(prober, rule, stampedObject, false)
+		}).Should(Equal(metav1.ConditionTrue))
+	})
+})
@@ -0,0 +1,40 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/tekton"
+	"github.com/vmware-tanzu/cartographer/pkg/utils"
+)
+
+// TektonFailureCondition replaces the message of an unhealthy condition of a
+// Tekton run with a description of why the run failed, read from the reason
+// and message of its Succeeded condition
+func TektonFailureCondition(condition metav1.Condition, stampedObject *unstructured.Unstructured) metav1.Condition {
+	if condition.Status != metav1.ConditionFalse || stampedObject == nil || !tekton.IsRun(stampedObject) {
+		return condition
+	}
+
+	succeeded := utils.ExtractConditions(stampedObject).ConditionWithType("Succeeded")
+	if succeeded == nil || succeeded.Status != metav1.ConditionFalse {
+		return condition
+	}
+
+	condition.Message = tekton.FailureMessage(stampedObject, *succeeded)
+	return condition
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
+)
+
+var _ = Describe("TektonFailureCondition", func() {
+	var (
+		pipelineRun *unstructured.Unstructured
+		condition   metav1.Condition
+	)
+
+	BeforeEach(func() {
+		pipelineRun = &unstructured.Unstructured{}
+		pipelineRun.SetAPIVersion("tekton.dev/v1beta1")
+		pipelineRun.SetKind("PipelineRun")
+		Expect(unstructured.SetNestedSlice(pipelineRun.Object, []interface{}{
+			map[string]interface{}{
+				"type":    "Succeeded",
+				"status":  "False",
+				"reason":  "PipelineRunTimeout",
+				"message": `PipelineRun "my-run" failed to finish within "1h0m0s"`,
+			},
+		}, "status", "conditions")).To(Succeed())
+
+		condition = metav1.Condition{
+			Type:    "Healthy",
+			Status:  metav1.ConditionFalse,
+			Reason:  "SucceededCondition",
+			Message: `PipelineRun "my-run" failed to finish within "1h0m0s"`,
+		}
+	})
+
+	It("describes the failure reason of the run in the message", func() {
+		Expect(healthcheck.TektonFailureCondition(condition, pipelineRun)).To(Equal(metav1.Condition{
+			Type:    "Healthy",
+			Status:  metav1.ConditionFalse,
+			Reason:  "SucceededCondition",
+			Message: `PipelineRun timed out [PipelineRunTimeout]: PipelineRun "my-run" failed to finish within "1h0m0s"`,
+		}))
+	})
+
+	It("does not change a condition that is not unhealthy", func() {
+		condition.Status = metav1.ConditionTrue
+		Expect(healthcheck.TektonFailureCondition(condition, pipelineRun)).To(Equal(condition))
+	})
+
+	It("does not change the condition of an object that is not a Tekton run", func() {
+		pipelineRun.SetAPIVersion("example.com/v1")
+		Expect(healthcheck.TektonFailureCondition(condition, pipelineRun)).To(Equal(condition))
+	})
+
+	It("does not change the condition without a stamped object", func() {
+		Expect(healthcheck.TektonFailureCondition(condition, nil)).To(Equal(condition))
+	})
+})
@@ -0,0 +1,75 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realizer
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+)
+
+// StampMetadata returns the labels and annotations to set on objects stamped
+// from resourceTemplate for owner: those of the owner selected by policy,
+// overridden by those specified in the template's objectMetadata.
+func StampMetadata(policy *v1alpha1.MetadataPolicy, owner client.Object, resourceTemplate v1alpha1.TemplateSpec) templates.Metadata {
+	metadata := templates.Metadata{}
+
+	if policy != nil {
+		metadata.Labels = propagate(owner.GetLabels(), policy.Labels, metadata.Labels)
+		metadata.Annotations = propagate(owner.GetAnnotations(), policy.Annotations, metadata.Annotations)
+	}
+
+	if resourceTemplate.ObjectMetadata != nil {
+		metadata.Labels = merge(metadata.Labels, resourceTemplate.ObjectMetadata.Labels)
+		metadata.Annotations = merge(metadata.Annotations, resourceTemplate.ObjectMetadata.Annotations)
+	}
+
+	return metadata
+}
+
+func propagate(source map[string]string, selector v1alpha1.MetadataKeySelector, target map[string]string) map[string]string {
+	for key, value := range source {
+		if selector.Matches(key) {
+			target = merge(target, map[string]string{key: value})
+		}
+	}
+	return target
+}
+
+func merge(target, source map[string]string) map[string]string {
+	if len(source) == 0 {
+		return target
+	}
+	if target == nil {
+		target = make(map[string]string, len(source))
+	}
+	for key, value := range source {
+		target[key] = value
+	}
+	return target
+}
+
+// RunRequestMetadata adds the owner's run request, if any, to the annotations
+// of objects stamped from immutable templates
+func RunRequestMetadata(owner client.Object, metadata templates.Metadata) templates.Metadata {
+	runRequest, ok := owner.GetAnnotations()[v1alpha1.RunRequestAnnotation]
+	if !ok {
+		return metadata
+	}
+
+	metadata.Annotations = merge(metadata.Annotations, map[string]string{v1alpha1.RunRequestAnnotation: runRequest})
+	return metadata
+}
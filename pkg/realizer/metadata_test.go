@@ -0,0 +1,146 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realizer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+)
+
+var _ = Describe("StampMetadata", func() {
+	var (
+		workload         *v1alpha1.Workload
+		policy           *v1alpha1.MetadataPolicy
+		resourceTemplate v1alpha1.TemplateSpec
+	)
+
+	BeforeEach(func() {
+		workload = &v1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-workload",
+				Labels: map[string]string{
+					"app.kubernetes.io/part-of": "my-app",
+					"team.example.com/name":     "payments",
+					"unrelated":                 "label",
+				},
+				Annotations: map[string]string{
+					"example.com/cost-center":  "1234",
+					"on-call.example.com/team": "payments-oncall",
+					"unrelated":                "annotation",
+				},
+			},
+		}
+		resourceTemplate = v1alpha1.TemplateSpec{}
+	})
+
+	Context("without a policy or template metadata", func() {
+		It("returns no metadata", func() {
+			Expect(realizer.StampMetadata(nil, workload, resourceTemplate)).To(BeZero())
+		})
+	})
+
+	Context("with a policy", func() {
+		BeforeEach(func() {
+			policy = &v1alpha1.MetadataPolicy{
+				Labels: v1alpha1.MetadataKeySelector{
+					Keys:     []string{"app.kubernetes.io/part-of"},
+					Prefixes: []string{"team.example.com/"},
+				},
+				Annotations: v1alpha1.MetadataKeySelector{
+					Keys:     []string{"example.com/cost-center"},
+					Prefixes: []string{"on-call.example.com/"},
+				},
+			}
+		})
+
+		It("propagates the selected labels and annotations of the owner", func() {
+			metadata := realizer.StampMetadata(policy, workload, resourceTemplate)
+			Expect(metadata.Labels).To(Equal(map[string]string{
+				"app.kubernetes.io/part-of": "my-app",
+				"team.example.com/name":     "payments",
+			}))
+			Expect(metadata.Annotations).To(Equal(map[string]string{
+				"example.com/cost-center":  "1234",
+				"on-call.example.com/team": "payments-oncall",
+			}))
+		})
+
+		Context("and template metadata", func() {
+			BeforeEach(func() {
+				resourceTemplate.ObjectMetadata = &v1alpha1.ObjectMetadata{
+					Labels:      map[string]string{"team.example.com/name": "platform", "tier": "backend"},
+					Annotations: map[string]string{"example.com/docs": "https://example.com"},
+				}
+			})
+
+			It("lets the template metadata override the propagated metadata", func() {
+				metadata := realizer.StampMetadata(policy, workload, resourceTemplate)
+				Expect(metadata.Labels).To(Equal(map[string]string{
+					"app.kubernetes.io/part-of": "my-app",
+					"team.example.com/name":     "platform",
+					"tier":                      "backend",
+				}))
+				Expect(metadata.Annotations).To(Equal(map[string]string{
+					"example.com/cost-center":  "1234",
+					"on-call.example.com/team": "payments-oncall",
+					"example.com/docs":         "https://example.com",
+				}))
+			})
+		})
+	})
+})
+
+var _ = Describe("RunRequestMetadata", func() {
+	var workload *v1alpha1.Workload
+
+	BeforeEach(func() {
+		workload = &v1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "my-workload",
+				Annotations: map[string]string{"unrelated": "annotation"},
+			},
+		}
+	})
+
+	Context("when the owner does not request a run", func() {
+		It("returns the metadata unchanged", func() {
+			metadata := templates.Metadata{Annotations: map[string]string{"example.com/docs": "https://example.com"}}
+			Expect(realizer.RunRequestMetadata(workload, metadata)).To(Equal(metadata))
+		})
+	})
+
+	Context("when the owner requests a run", func() {
+		BeforeEach(func() {
+			workload.Annotations[v1alpha1.RunRequestAnnotation] = "2022-03-05T02:00:00Z"
+		})
+
+		It("adds the run request to the annotations", func() {
+			metadata := realizer.RunRequestMetadata(workload, templates.Metadata{
+				Labels:      map[string]string{"tier": "backend"},
+				Annotations: map[string]string{"example.com/docs": "https://example.com"},
+			})
+			Expect(metadata.Labels).To(Equal(map[string]string{"tier": "backend"}))
+			Expect(metadata.Annotations).To(Equal(map[string]string{
+				"example.com/docs":            "https://example.com",
+				v1alpha1.RunRequestAnnotation: "2022-03-05T02:00:00Z",
+			}))
+		})
+	})
+})
@@ -17,14 +17,18 @@ package realizer
 import "github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 
 type OwnerResource struct {
-	TemplateRef     v1alpha1.TemplateReference
-	TemplateOptions []v1alpha1.TemplateOption
-	Params          []v1alpha1.BlueprintParam
-	Name            string
-	Sources         []v1alpha1.ResourceReference
-	Images          []v1alpha1.ResourceReference
-	Configs         []v1alpha1.ResourceReference
-	Deployment      *v1alpha1.DeploymentReference
+	TemplateRef      v1alpha1.TemplateReference
+	TemplateOptions  []v1alpha1.TemplateOption
+	TemplateRevision int64
+	TemplateRollout  *v1alpha1.TemplateRollout
+	Params           []v1alpha1.BlueprintParam
+	Name             string
+	Sources          []v1alpha1.ResourceReference
+	Images           []v1alpha1.ResourceReference
+	Configs          []v1alpha1.ResourceReference
+	Deployment       *v1alpha1.DeploymentReference
+	MetadataPolicy   *v1alpha1.MetadataPolicy
+	PendingRun       *v1alpha1.PendingRun
 }
 
 func (o OwnerResource) GetImages() []v1alpha1.ResourceReference {
@@ -0,0 +1,155 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realizer
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/errors"
+)
+
+// ParamValueResolver resolves the value of a param sourced from a ConfigMap or Secret.
+// A nil value with no error means the source is optional and could not be found.
+type ParamValueResolver interface {
+	ResolveParamValue(ctx context.Context, source v1alpha1.ParamValueSource) (*apiextensionsv1.JSON, error)
+}
+
+// UnstructuredGetter gets an object, returning nil when it does not exist.
+// It is satisfied by repository.Repository.
+type UnstructuredGetter interface {
+	GetUnstructured(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+}
+
+// NewParamValueResolver returns a resolver that reads ConfigMaps and Secrets
+// in namespace through repo. Values read from Secrets are added to the
+// Redactor carried by the context.
+func NewParamValueResolver(repo UnstructuredGetter, namespace string) ParamValueResolver {
+	return &paramValueResolver{
+		repo:      repo,
+		namespace: namespace,
+		resolved:  make(map[string]*apiextensionsv1.JSON),
+	}
+}
+
+type paramValueResolver struct {
+	repo      UnstructuredGetter
+	namespace string
+	resolved  map[string]*apiextensionsv1.JSON
+}
+
+func (p *paramValueResolver) ResolveParamValue(ctx context.Context, source v1alpha1.ParamValueSource) (*apiextensionsv1.JSON, error) {
+	var kind, name, key string
+	var optional *bool
+
+	switch {
+	case source.ConfigMapKeyRef != nil && source.SecretKeyRef != nil:
+		return nil, errors.InvalidParamError{
+			Err: fmt.Errorf("valueFrom must set exactly one of configMapKeyRef and secretKeyRef"),
+		}
+	case source.ConfigMapKeyRef != nil:
+		kind, name, key, optional = "ConfigMap", source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Key, source.ConfigMapKeyRef.Optional
+	case source.SecretKeyRef != nil:
+		kind, name, key, optional = "Secret", source.SecretKeyRef.Name, source.SecretKeyRef.Key, source.SecretKeyRef.Optional
+	default:
+		return nil, errors.InvalidParamError{
+			Err: fmt.Errorf("valueFrom must set one of configMapKeyRef and secretKeyRef"),
+		}
+	}
+
+	cacheKey := fmt.Sprintf("%s/%s/%s", kind, name, key)
+	if value, ok := p.resolved[cacheKey]; ok {
+		return value, nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(kind))
+	obj.SetNamespace(p.namespace)
+	obj.SetName(name)
+
+	found, err := p.repo.GetUnstructured(ctx, obj)
+	if err != nil {
+		if kerrors.IsForbidden(err) {
+			return nil, errors.InvalidParamError{
+				Err: fmt.Errorf("unable to read %s [%s/%s]: %w", kind, p.namespace, name, err),
+			}
+		}
+		return nil, fmt.Errorf("failed to get %s [%s/%s]: %w", kind, p.namespace, name, err)
+	}
+
+	value, ok, err := readKey(found, kind, key)
+	if err != nil {
+		return nil, errors.InvalidParamError{
+			Err: fmt.Errorf("unable to read key [%s] of %s [%s/%s]: %w", key, kind, p.namespace, name, err),
+		}
+	}
+
+	if !ok {
+		if optional != nil && *optional {
+			p.resolved[cacheKey] = nil
+			return nil, nil
+		}
+		if found == nil {
+			return nil, errors.InvalidParamError{
+				Err: fmt.Errorf("%s [%s/%s] not found", kind, p.namespace, name),
+			}
+		}
+		return nil, errors.InvalidParamError{
+			Err: fmt.Errorf("key [%s] not found in %s [%s/%s]", key, kind, p.namespace, name),
+		}
+	}
+
+	if kind == "Secret" {
+		RedactorFromContext(ctx).Add(value)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value of key [%s] of %s [%s/%s]: %w", key, kind, p.namespace, name, err)
+	}
+
+	resolved := &apiextensionsv1.JSON{Raw: raw}
+	p.resolved[cacheKey] = resolved
+	return resolved, nil
+}
+
+func readKey(obj *unstructured.Unstructured, kind, key string) (string, bool, error) {
+	if obj == nil {
+		return "", false, nil
+	}
+
+	value, ok, err := unstructured.NestedString(obj.Object, "data", key)
+	if err != nil || !ok {
+		return "", ok, err
+	}
+
+	if kind != "Secret" {
+		return value, true, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", false, err
+	}
+	return string(decoded), true, nil
+}
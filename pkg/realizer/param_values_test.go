@@ -0,0 +1,284 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realizer_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/errors"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer"
+	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+)
+
+var _ = Describe("ParamValueResolver", func() {
+	var (
+		ctx      context.Context
+		repo     *repositoryfakes.FakeRepository
+		redactor *realizer.Redactor
+		resolver realizer.ParamValueResolver
+	)
+
+	BeforeEach(func() {
+		redactor = &realizer.Redactor{}
+		ctx = realizer.NewRedactorContext(context.Background(), redactor)
+		repo = &repositoryfakes.FakeRepository{}
+		resolver = realizer.NewParamValueResolver(repo, "some-namespace")
+	})
+
+	Context("configMapKeyRef", func() {
+		var source v1alpha1.ParamValueSource
+
+		BeforeEach(func() {
+			source = v1alpha1.ParamValueSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "some-config-map"},
+					Key:                  "registry",
+				},
+			}
+			repo.GetUnstructuredReturns(&unstructured.Unstructured{Object: map[string]interface{}{
+				"data": map[string]interface{}{"registry": "registry.example.com"},
+			}}, nil)
+		})
+
+		It("reads the key from the config map in the owner namespace", func() {
+			value, err := resolver.ResolveParamValue(ctx, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(value.Raw)).To(Equal(`"registry.example.com"`))
+
+			Expect(repo.GetUnstructuredCallCount()).To(Equal(1))
+			_, obj := repo.GetUnstructuredArgsForCall(0)
+			Expect(obj.GetNamespace()).To(Equal("some-namespace"))
+			Expect(obj.GetName()).To(Equal("some-config-map"))
+			Expect(obj.GroupVersionKind()).To(Equal(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}))
+		})
+
+		It("does not redact the value", func() {
+			_, err := resolver.ResolveParamValue(ctx, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(redactor.Redact("registry.example.com")).To(Equal("registry.example.com"))
+		})
+
+		It("reads each source only once", func() {
+			_, err := resolver.ResolveParamValue(ctx, source)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = resolver.ResolveParamValue(ctx, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.GetUnstructuredCallCount()).To(Equal(1))
+		})
+
+		Context("the key does not exist", func() {
+			BeforeEach(func() {
+				source.ConfigMapKeyRef.Key = "missing"
+			})
+
+			It("returns an InvalidParamError", func() {
+				_, err := resolver.ResolveParamValue(ctx, source)
+				Expect(err).To(BeAssignableToTypeOf(errors.InvalidParamError{}))
+				Expect(err).To(MatchError(ContainSubstring("key [missing] not found in ConfigMap [some-namespace/some-config-map]")))
+			})
+
+			Context("and the ref is optional", func() {
+				BeforeEach(func() {
+					optional := true
+					source.ConfigMapKeyRef.Optional = &optional
+				})
+
+				It("returns no value", func() {
+					value, err := resolver.ResolveParamValue(ctx, source)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(value).To(BeNil())
+				})
+			})
+		})
+
+		Context("the config map does not exist", func() {
+			BeforeEach(func() {
+				repo.GetUnstructuredReturns(nil, nil)
+			})
+
+			It("returns an InvalidParamError", func() {
+				_, err := resolver.ResolveParamValue(ctx, source)
+				Expect(err).To(BeAssignableToTypeOf(errors.InvalidParamError{}))
+				Expect(err).To(MatchError(ContainSubstring("ConfigMap [some-namespace/some-config-map] not found")))
+			})
+		})
+
+		Context("the service account may not read the config map", func() {
+			BeforeEach(func() {
+				repo.GetUnstructuredReturns(nil, fmt.Errorf("get: %w", kerrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "some-config-map", fmt.Errorf("denied"))))
+			})
+
+			It("returns an InvalidParamError", func() {
+				_, err := resolver.ResolveParamValue(ctx, source)
+				Expect(err).To(BeAssignableToTypeOf(errors.InvalidParamError{}))
+			})
+		})
+
+		Context("the repository fails", func() {
+			BeforeEach(func() {
+				repo.GetUnstructuredReturns(nil, fmt.Errorf("some error"))
+			})
+
+			It("returns an unhandled error", func() {
+				_, err := resolver.ResolveParamValue(ctx, source)
+				Expect(err).To(MatchError(ContainSubstring("some error")))
+				Expect(errors.IsUnhandledErrorType(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("secretKeyRef", func() {
+		var source v1alpha1.ParamValueSource
+
+		BeforeEach(func() {
+			source = v1alpha1.ParamValueSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "some-secret"},
+					Key:                  "password",
+				},
+			}
+			repo.GetUnstructuredReturns(&unstructured.Unstructured{Object: map[string]interface{}{
+				"data": map[string]interface{}{"password": base64.StdEncoding.EncodeToString([]byte("hunter2"))},
+			}}, nil)
+		})
+
+		It("decodes the key from the secret", func() {
+			value, err := resolver.ResolveParamValue(ctx, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(value.Raw)).To(Equal(`"hunter2"`))
+
+			_, obj := repo.GetUnstructuredArgsForCall(0)
+			Expect(obj.GetKind()).To(Equal("Secret"))
+		})
+
+		It("registers the value with the redactor", func() {
+			_, err := resolver.ResolveParamValue(ctx, source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(redactor.Redact("user:hunter2")).To(Equal("user:" + realizer.RedactedValue))
+		})
+	})
+
+	Context("both configMapKeyRef and secretKeyRef", func() {
+		It("returns an InvalidParamError without reading either", func() {
+			_, err := resolver.ResolveParamValue(ctx, v1alpha1.ParamValueSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "some-config-map"},
+					Key:                  "registry",
+				},
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "some-secret"},
+					Key:                  "password",
+				},
+			})
+			Expect(err).To(BeAssignableToTypeOf(errors.InvalidParamError{}))
+			Expect(repo.GetUnstructuredCallCount()).To(Equal(0))
+		})
+	})
+})
+
+var _ = Describe("ContextGenerator params", func() {
+	var (
+		repo     *repositoryfakes.FakeRepository
+		generate func(ownerParams []v1alpha1.OwnerParam, blueprintParams []v1alpha1.BlueprintParam) error
+	)
+
+	BeforeEach(func() {
+		repo = &repositoryfakes.FakeRepository{}
+		repo.GetUnstructuredReturns(&unstructured.Unstructured{Object: map[string]interface{}{
+			"data": map[string]interface{}{"some-key": "resolved"},
+		}}, nil)
+		resolver := realizer.NewParamValueResolver(repo, "some-namespace")
+
+		generate = func(ownerParams []v1alpha1.OwnerParam, blueprintParams []v1alpha1.BlueprintParam) error {
+			generator := realizer.NewContextGenerator(&v1alpha1.Workload{}, ownerParams, blueprintParams)
+			_, err := generator.Generate(context.Background(), resolver, nil, realizer.OwnerResource{}, realizer.NewOutputs(), nil)
+			return err
+		}
+	})
+
+	It("rejects an owner param that sets both value and valueFrom", func() {
+		err := generate([]v1alpha1.OwnerParam{{
+			Name:      "some-param",
+			Value:     apiextensionsv1.JSON{Raw: []byte(`"value"`)},
+			ValueFrom: &v1alpha1.ParamValueSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "some-key"}},
+		}}, nil)
+		Expect(err).To(BeAssignableToTypeOf(errors.InvalidParamError{}))
+		Expect(err).To(MatchError(ContainSubstring("param [some-param]: must set exactly one of value and valueFrom")))
+		Expect(repo.GetUnstructuredCallCount()).To(Equal(0))
+	})
+
+	It("rejects an owner param that sets neither value nor valueFrom", func() {
+		err := generate([]v1alpha1.OwnerParam{{Name: "some-param"}}, nil)
+		Expect(err).To(BeAssignableToTypeOf(errors.InvalidParamError{}))
+	})
+
+	It("rejects a blueprint param that sets a default alongside valueFrom", func() {
+		err := generate(nil, []v1alpha1.BlueprintParam{{
+			Name:         "some-param",
+			DefaultValue: &apiextensionsv1.JSON{Raw: []byte(`"default"`)},
+			ValueFrom:    &v1alpha1.ParamValueSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "some-key"}},
+		}})
+		Expect(err).To(BeAssignableToTypeOf(errors.InvalidParamError{}))
+		Expect(repo.GetUnstructuredCallCount()).To(Equal(0))
+	})
+
+	It("resolves an owner param sourced with valueFrom", func() {
+		err := generate([]v1alpha1.OwnerParam{{
+			Name:      "some-param",
+			ValueFrom: &v1alpha1.ParamValueSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{Key: "some-key"}},
+		}}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repo.GetUnstructuredCallCount()).To(Equal(1))
+	})
+})
+
+var _ = Describe("Redactor", func() {
+	It("redacts registered values in nested structures without modifying them", func() {
+		redactor := &realizer.Redactor{}
+		redactor.Add("secret")
+
+		original := map[string]interface{}{
+			"spec": map[string]interface{}{
+				"args":  []interface{}{"--token=secret", 3},
+				"other": "value",
+			},
+		}
+
+		Expect(redactor.Redact(original)).To(Equal(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"args":  []interface{}{"--token=[REDACTED]", 3},
+				"other": "value",
+			},
+		}))
+		Expect(original["spec"].(map[string]interface{})["args"]).To(Equal([]interface{}{"--token=secret", 3}))
+	})
+
+	It("redacts nothing when nil", func() {
+		var redactor *realizer.Redactor
+		redactor.Add("secret")
+		Expect(redactor.Redact("secret")).To(Equal("secret"))
+	})
+})
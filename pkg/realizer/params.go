@@ -15,9 +15,13 @@
 package realizer
 
 import (
+	"fmt"
+	"sort"
+
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/errors"
 )
 
 type TemplateParams interface {
@@ -38,12 +42,14 @@ type ParamMerger struct {
 	ownerParams     []v1alpha1.OwnerParam
 }
 
-func (p ParamMerger) Merge(templateParams TemplateParams) map[string]apiextensionsv1.JSON {
+func (p ParamMerger) Merge(templateParams TemplateParams) (map[string]apiextensionsv1.JSON, error) {
 	newParams := map[string]apiextensionsv1.JSON{}
 
 	if templateParams != nil {
 		for _, param := range templateParams.GetDefaultParams() {
-			newParams[param.Name] = param.DefaultValue
+			if len(param.DefaultValue.Raw) != 0 {
+				newParams[param.Name] = param.DefaultValue
+			}
 		}
 	}
 
@@ -54,7 +60,7 @@ func (p ParamMerger) Merge(templateParams TemplateParams) map[string]apiextensio
 		if blueprintOverride.Value != nil {
 			newParams[key] = *blueprintOverride.Value
 			protectedFromOwnerOverride[key] = true
-		} else {
+		} else if blueprintOverride.DefaultValue != nil {
 			newParams[key] = *blueprintOverride.DefaultValue
 			protectedFromOwnerOverride[key] = false
 		}
@@ -65,7 +71,7 @@ func (p ParamMerger) Merge(templateParams TemplateParams) map[string]apiextensio
 		if resourceOverride.Value != nil {
 			newParams[key] = *resourceOverride.Value
 			protectedFromOwnerOverride[key] = true
-		} else {
+		} else if resourceOverride.DefaultValue != nil {
 			newParams[key] = *resourceOverride.DefaultValue
 			protectedFromOwnerOverride[key] = false
 		}
@@ -78,11 +84,126 @@ func (p ParamMerger) Merge(templateParams TemplateParams) map[string]apiextensio
 		}
 	}
 
-	return newParams
+	if templateParams != nil {
+		if err := validateParams(templateParams.GetDefaultParams(), newParams); err != nil {
+			return nil, err
+		}
+	}
 
+	return newParams, nil
+}
+
+func validateParams(templateParams v1alpha1.TemplateParams, params map[string]apiextensionsv1.JSON) error {
+	for _, param := range templateParams {
+		value, ok := params[param.Name]
+		if !ok {
+			if param.Required {
+				return errors.InvalidParamError{
+					Err:       fmt.Errorf("param is required but no value was specified"),
+					ParamName: param.Name,
+				}
+			}
+			continue
+		}
+
+		if param.Schema != nil {
+			if err := param.Schema.Validate(value); err != nil {
+				return errors.InvalidParamError{
+					Err:       err,
+					ParamName: param.Name,
+				}
+			}
+		}
+	}
+	return nil
 }
 
 func ownerCanOverride(isProtected map[string]bool, key string) bool {
 	protected, written := isProtected[key]
 	return !written || !protected
 }
+
+// ResourceTemplateParams are the params declared by a template selected by a blueprint resource,
+// along with the params the resource itself specifies.
+type ResourceTemplateParams struct {
+	ResourceName   string
+	ResourceParams []v1alpha1.BlueprintParam
+	TemplateParams v1alpha1.TemplateParams
+}
+
+// AcceptedParams aggregates the params of a blueprint's resource templates into the set of params
+// an Owner may specify. Params whose value is fixed by the blueprint or resource are omitted.
+func AcceptedParams(blueprintParams []v1alpha1.BlueprintParam, resources []ResourceTemplateParams) []v1alpha1.AcceptedParam {
+	blueprintOverrides := make(map[string]v1alpha1.BlueprintParam)
+	for _, param := range blueprintParams {
+		blueprintOverrides[param.Name] = param
+	}
+
+	accepted := make(map[string]*v1alpha1.AcceptedParam)
+
+	for _, resource := range resources {
+		resourceOverrides := make(map[string]v1alpha1.BlueprintParam)
+		for _, param := range resource.ResourceParams {
+			resourceOverrides[param.Name] = param
+		}
+
+		for _, templateParam := range resource.TemplateParams {
+			defaultValue, protected := overriddenDefault(templateParam, blueprintOverrides[templateParam.Name], resourceOverrides[templateParam.Name])
+			if protected {
+				continue
+			}
+
+			param, ok := accepted[templateParam.Name]
+			if !ok {
+				param = &v1alpha1.AcceptedParam{Name: templateParam.Name}
+				accepted[templateParam.Name] = param
+			}
+
+			if param.Description == "" {
+				param.Description = templateParam.Description
+			}
+			if param.Schema == nil {
+				param.Schema = templateParam.Schema
+			}
+			if param.DefaultValue == nil {
+				param.DefaultValue = defaultValue
+			}
+			param.Required = param.Required || (templateParam.Required && defaultValue == nil)
+
+			if len(param.Resources) == 0 || param.Resources[len(param.Resources)-1] != resource.ResourceName {
+				param.Resources = append(param.Resources, resource.ResourceName)
+			}
+		}
+	}
+
+	var result []v1alpha1.AcceptedParam
+	for _, param := range accepted {
+		result = append(result, *param)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func overriddenDefault(templateParam v1alpha1.TemplateParam, blueprintParam, resourceParam v1alpha1.BlueprintParam) (*apiextensionsv1.JSON, bool) {
+	if resourceParam.Value != nil {
+		return nil, true
+	}
+	if resourceParam.DefaultValue != nil {
+		return resourceParam.DefaultValue, false
+	}
+	if blueprintParam.Value != nil {
+		return nil, true
+	}
+	if blueprintParam.DefaultValue != nil {
+		return blueprintParam.DefaultValue, false
+	}
+	if len(templateParam.DefaultValue.Raw) != 0 {
+		defaultValue := templateParam.DefaultValue
+		return &defaultValue, false
+	}
+	return nil, false
+}
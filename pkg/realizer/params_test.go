@@ -21,6 +21,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/errors"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer"
 )
 
@@ -89,7 +90,8 @@ var _ = Describe("Params", func() {
 				ownerParams = append(ownerParams, *ownerParam)
 			}
 
-			actual := realizer.NewParamMerger(resourceParams, blueprintParams, ownerParams).Merge(templateParams)
+			actual, err := realizer.NewParamMerger(resourceParams, blueprintParams, ownerParams).Merge(templateParams)
+			Expect(err).NotTo(HaveOccurred())
 
 			if expected == "" {
 				Expect(actual).To(BeEmpty())
@@ -184,4 +186,169 @@ var _ = Describe("Params", func() {
 			ownerParam,
 			"from the owner"),
 	)
+
+	Describe("validating params", func() {
+		var (
+			templateParams v1alpha1.TemplateParams
+			ownerParams    []v1alpha1.OwnerParam
+		)
+
+		BeforeEach(func() {
+			templateParams = v1alpha1.TemplateParams{
+				{
+					Name:     "replicas",
+					Required: true,
+					Schema: &v1alpha1.ParamSchema{
+						Type: "integer",
+					},
+				},
+				{
+					Name:         "registry",
+					DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"registry.example.com/team"`)},
+					Schema: &v1alpha1.ParamSchema{
+						Type:    "string",
+						Pattern: `^registry\.example\.com/`,
+					},
+				},
+				{
+					Name:         "tier",
+					DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"dev"`)},
+					Schema: &v1alpha1.ParamSchema{
+						Enum: []apiextensionsv1.JSON{
+							{Raw: []byte(`"dev"`)},
+							{Raw: []byte(`"prod"`)},
+						},
+					},
+				},
+				{
+					Name: "optional-without-default",
+				},
+			}
+			ownerParams = []v1alpha1.OwnerParam{
+				{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`3`)}},
+			}
+		})
+
+		It("merges params which satisfy their schemas", func() {
+			actual, err := realizer.NewParamMerger(nil, nil, ownerParams).Merge(Template{params: templateParams})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(actual).To(HaveLen(3))
+			Expect(string(actual["replicas"].Raw)).To(Equal(`3`))
+			Expect(string(actual["registry"].Raw)).To(Equal(`"registry.example.com/team"`))
+			Expect(string(actual["tier"].Raw)).To(Equal(`"dev"`))
+			Expect(actual).NotTo(HaveKey("optional-without-default"))
+		})
+
+		DescribeTable("invalid params",
+			func(ownerParams []v1alpha1.OwnerParam, expectedParam string, expectedMessage string) {
+				_, err := realizer.NewParamMerger(nil, nil, ownerParams).Merge(Template{params: templateParams})
+				Expect(err).To(HaveOccurred())
+
+				paramErr, ok := err.(errors.InvalidParamError)
+				Expect(ok).To(BeTrue())
+				Expect(paramErr.ParamName).To(Equal(expectedParam))
+				Expect(err.Error()).To(ContainSubstring(expectedMessage))
+			},
+
+			Entry("a required param is missing",
+				[]v1alpha1.OwnerParam{},
+				"replicas",
+				"param is required but no value was specified"),
+
+			Entry("a value has the wrong type",
+				[]v1alpha1.OwnerParam{
+					{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`"three"`)}},
+				},
+				"replicas",
+				"value must be of type [integer]"),
+
+			Entry("a number is not an integer",
+				[]v1alpha1.OwnerParam{
+					{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`1.5`)}},
+				},
+				"replicas",
+				"value must be of type [integer]"),
+
+			Entry("a value does not match the pattern",
+				[]v1alpha1.OwnerParam{
+					{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`1`)}},
+					{Name: "registry", Value: apiextensionsv1.JSON{Raw: []byte(`"docker.io/team"`)}},
+				},
+				"registry",
+				`value "docker.io/team" does not match pattern`),
+
+			Entry("a value is not in the enum",
+				[]v1alpha1.OwnerParam{
+					{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`1`)}},
+					{Name: "tier", Value: apiextensionsv1.JSON{Raw: []byte(`"staging"`)}},
+				},
+				"tier",
+				`value "staging" is not one of ["dev", "prod"]`),
+		)
+
+		It("accepts a required param supplied by the blueprint", func() {
+			blueprintParams := []v1alpha1.BlueprintParam{
+				{Name: "replicas", Value: &apiextensionsv1.JSON{Raw: []byte(`2`)}},
+			}
+
+			actual, err := realizer.NewParamMerger(nil, blueprintParams, nil).Merge(Template{params: templateParams})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(actual["replicas"].Raw)).To(Equal(`2`))
+		})
+	})
+
+	Describe("AcceptedParams", func() {
+		It("aggregates the params an owner may specify across resources", func() {
+			schema := &v1alpha1.ParamSchema{Type: "string"}
+
+			accepted := realizer.AcceptedParams(
+				[]v1alpha1.BlueprintParam{
+					{Name: "fixed-by-blueprint", Value: &apiextensionsv1.JSON{Raw: []byte(`"fixed"`)}},
+					{Name: "defaulted-by-blueprint", DefaultValue: &apiextensionsv1.JSON{Raw: []byte(`"blueprint"`)}},
+				},
+				[]realizer.ResourceTemplateParams{
+					{
+						ResourceName: "source",
+						TemplateParams: v1alpha1.TemplateParams{
+							{Name: "fixed-by-blueprint", DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"template"`)}},
+							{Name: "defaulted-by-blueprint", Required: true},
+							{Name: "shared", Required: true, Description: "used everywhere", Schema: schema},
+						},
+					},
+					{
+						ResourceName: "image",
+						ResourceParams: []v1alpha1.BlueprintParam{
+							{Name: "fixed-by-resource", Value: &apiextensionsv1.JSON{Raw: []byte(`"fixed"`)}},
+						},
+						TemplateParams: v1alpha1.TemplateParams{
+							{Name: "fixed-by-resource", Required: true},
+							{Name: "shared", Required: true},
+							{Name: "optional", DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"template"`)}},
+						},
+					},
+				},
+			)
+
+			Expect(accepted).To(Equal([]v1alpha1.AcceptedParam{
+				{
+					Name:         "defaulted-by-blueprint",
+					DefaultValue: &apiextensionsv1.JSON{Raw: []byte(`"blueprint"`)},
+					Resources:    []string{"source"},
+				},
+				{
+					Name:         "optional",
+					DefaultValue: &apiextensionsv1.JSON{Raw: []byte(`"template"`)},
+					Resources:    []string{"image"},
+				},
+				{
+					Name:        "shared",
+					Description: "used everywhere",
+					Required:    true,
+					Schema:      schema,
+					Resources:   []string{"source", "image"},
+				},
+			}))
+		})
+	})
 })
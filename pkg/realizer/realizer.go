@@ -50,11 +50,14 @@ func MakeSupplychainOwnerResources(supplyChain *v1alpha1.ClusterSupplyChain) []O
 				Kind: resource.TemplateRef.Kind,
 				Name: resource.TemplateRef.Name,
 			},
-			TemplateOptions: resource.TemplateRef.Options,
-			Params:          resource.Params,
-			Sources:         resource.Sources,
-			Images:          resource.Images,
-			Configs:         resource.Configs,
+			TemplateOptions:  resource.TemplateRef.Options,
+			TemplateRevision: resource.TemplateRef.Revision,
+			TemplateRollout:  resource.TemplateRef.Rollout,
+			Params:           resource.Params,
+			Sources:          resource.Sources,
+			Images:           resource.Images,
+			Configs:          resource.Configs,
+			MetadataPolicy:   supplyChain.Spec.MetadataPolicy,
 		})
 	}
 	return resources
@@ -69,11 +72,14 @@ func MakeDeliveryOwnerResources(delivery *v1alpha1.ClusterDelivery) []OwnerResou
 				Kind: resource.TemplateRef.Kind,
 				Name: resource.TemplateRef.Name,
 			},
-			TemplateOptions: resource.TemplateRef.Options,
-			Params:          resource.Params,
-			Sources:         resource.Sources,
-			Configs:         resource.Configs,
-			Deployment:      resource.Deployment,
+			TemplateOptions:  resource.TemplateRef.Options,
+			TemplateRevision: resource.TemplateRef.Revision,
+			TemplateRollout:  resource.TemplateRef.Rollout,
+			Params:           resource.Params,
+			Sources:          resource.Sources,
+			Configs:          resource.Configs,
+			Deployment:       resource.Deployment,
+			MetadataPolicy:   delivery.Spec.MetadataPolicy,
 		})
 	}
 	return resources
@@ -82,6 +88,9 @@ func MakeDeliveryOwnerResources(delivery *v1alpha1.ClusterDelivery) []OwnerResou
 //counterfeiter:generate . ResourceRealizer
 type ResourceRealizer interface {
 	Do(ctx context.Context, resource OwnerResource, blueprintName string, outputs Outputs, mapper meta.RESTMapper) (templates.Reader, *unstructured.Unstructured, *templates.Output, bool, string, error)
+	ListChildren(ctx context.Context, rule *v1alpha1.ChildrenHealthRule, parent *unstructured.Unstructured) ([]*unstructured.Unstructured, error)
+	PendingRun(resourceName string) *v1alpha1.PendingRun
+	CleanupAfter(resourceName string) time.Duration
 }
 
 type realizer struct {
@@ -92,9 +101,11 @@ type realizer struct {
 type HealthyConditionEvaluator func(rule *v1alpha1.HealthRule, realizedResource *v1alpha1.RealizedResource, stampedObject *unstructured.Unstructured) metav1.Condition
 
 //counterfeiter:generate k8s.io/apimachinery/pkg/api/meta.RESTMapper
-func NewRealizer(healthyConditionEvaluator HealthyConditionEvaluator, mapper meta.RESTMapper) *realizer {
+func NewRealizer(healthyConditionEvaluator HealthyConditionEvaluator, prober *healthcheck.Prober, mapper meta.RESTMapper) *realizer {
 	if healthyConditionEvaluator == nil {
-		healthyConditionEvaluator = healthcheck.DetermineHealthCondition
+		healthyConditionEvaluator = func(rule *v1alpha1.HealthRule, realizedResource *v1alpha1.RealizedResource, stampedObject *unstructured.Unstructured) metav1.Condition {
+			return healthcheck.DetermineHealthCondition(prober, rule, realizedResource, stampedObject)
+		}
 	}
 	return &realizer{
 		healthyConditionEvaluator: healthyConditionEvaluator,
@@ -106,17 +117,29 @@ func (r *realizer) Realize(ctx context.Context, resourceRealizer ResourceRealize
 	log := logr.FromContextOrDiscard(ctx)
 	log.V(logger.DEBUG).Info("Realize")
 
+	redactor := RedactorFromContext(ctx)
+	if redactor == nil {
+		redactor = &Redactor{}
+		ctx = NewRedactorContext(ctx, redactor)
+	}
+
 	outs := NewOutputs()
 	var firstError error
 
 	for _, resource := range ownerResources {
 		log = log.WithValues("resource", resource.Name)
 		ctx = logr.NewContext(ctx, log)
+
+		previousResourceStatus := resourceStatuses.GetPreviousResourceStatus(resource.Name)
+		if previousResourceStatus != nil {
+			resource.PendingRun = previousResourceStatus.RealizedResource.PendingRun
+		}
+
 		template, stampedObject, out, isPassThrough, templateName, err := resourceRealizer.Do(ctx, resource, blueprintName, outs, r.mapper)
 
 		if stampedObject != nil {
 			log.V(logger.DEBUG).Info("realized resource as object",
-				"object", stampedObject)
+				"object", redactor.RedactObject(stampedObject))
 		}
 
 		if err != nil {
@@ -129,8 +152,6 @@ func (r *realizer) Realize(ctx context.Context, resourceRealizer ResourceRealize
 
 		outs.AddOutput(resource.Name, out)
 
-		previousResourceStatus := resourceStatuses.GetPreviousResourceStatus(resource.Name)
-
 		var realizedResource *v1alpha1.RealizedResource
 
 		var additionalConditions []metav1.Condition
@@ -145,6 +166,7 @@ func (r *realizer) Realize(ctx context.Context, resourceRealizer ResourceRealize
 				previousRealizedResource = &previousResourceStatus.RealizedResource
 			}
 			realizedResource = r.generateRealizedResource(ctx, resource, template, stampedObject, out, previousRealizedResource, isPassThrough, templateName)
+			realizedResource.PendingRun = resourceRealizer.PendingRun(resource.Name)
 
 			var previousOutputs []v1alpha1.Output
 			if previousRealizedResource != nil {
@@ -161,7 +183,24 @@ func (r *realizer) Realize(ctx context.Context, resourceRealizer ResourceRealize
 			}
 
 			if template != nil {
-				additionalConditions = []metav1.Condition{r.healthyConditionEvaluator(template.GetHealthRule(), realizedResource, stampedObject)}
+				healthRule := template.GetHealthRule()
+				healthCondition := r.healthyConditionEvaluator(healthRule, realizedResource, stampedObject)
+				if *template.GetLifecycle() == templates.Tekton && template.GetResourceTemplate().HealthRule == nil {
+					healthCondition = healthcheck.TektonFailureCondition(healthCondition, stampedObject)
+				}
+				if !template.GetResourceTemplate().IgnoreObservedGeneration {
+					if staleCondition := healthcheck.StaleStatusCondition(healthRule, stampedObject); staleCondition != nil {
+						healthCondition = *staleCondition
+					}
+				}
+				if healthRule != nil && healthRule.Children != nil && stampedObject != nil {
+					children, listErr := resourceRealizer.ListChildren(ctx, healthRule.Children, stampedObject)
+					if listErr != nil {
+						log.Error(listErr, "failed to list children")
+					}
+					healthCondition = healthcheck.ChildrenHealthCondition(healthRule, healthCondition, children, listErr)
+				}
+				additionalConditions = []metav1.Condition{healthCondition}
 			}
 		}
 		resourceStatuses.Add(realizedResource, err, isPassThrough, additionalConditions...)
@@ -205,6 +244,8 @@ func (r *realizer) generateRealizedResource(ctx context.Context, resource OwnerR
 	}
 
 	var templateRef *corev1.ObjectReference
+	var templateRevision int64
+	var baseTemplateRefs []corev1.ObjectReference
 	var outputs []v1alpha1.Output
 
 	if template != nil {
@@ -213,18 +254,28 @@ func (r *realizer) generateRealizedResource(ctx context.Context, resource OwnerR
 			Name:       templateName,
 			APIVersion: v1alpha1.SchemeGroupVersion.String(),
 		}
-		outputs = getOutputs(previousRealizedResource, output)
+		templateRevision = template.GetRevision()
+		for _, base := range template.GetBaseTemplates() {
+			baseTemplateRefs = append(baseTemplateRefs, corev1.ObjectReference{
+				Kind:       base.Kind,
+				Name:       base.Name,
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			})
+		}
+		outputs = getOutputs(previousRealizedResource, output, RedactorFromContext(ctx))
 	}
 
 	if isPassThrough {
-		outputs = getOutputs(previousRealizedResource, output)
+		outputs = getOutputs(previousRealizedResource, output, RedactorFromContext(ctx))
 	}
 
 	var stampedRef *v1alpha1.StampedRef
+	var runRequest string
 	if stampedObject != nil {
+		runRequest = stampedObject.GetAnnotations()[v1alpha1.RunRequestAnnotation]
 		qualifiedResource, err := utils.GetQualifiedResource(r.mapper, stampedObject)
 		if err != nil {
-			log.Error(err, "failed to retrieve qualified resource name", "object", stampedObject)
+			log.Error(err, "failed to retrieve qualified resource name", "object", RedactorFromContext(ctx).RedactObject(stampedObject))
 			qualifiedResource = "could not fetch - see logs for 'failed to retrieve qualified resource name'"
 		}
 
@@ -240,16 +291,19 @@ func (r *realizer) generateRealizedResource(ctx context.Context, resource OwnerR
 	}
 
 	return &v1alpha1.RealizedResource{
-		Name:        resource.Name,
-		StampedRef:  stampedRef,
-		TemplateRef: templateRef,
-		Inputs:      inputs,
-		Outputs:     outputs,
+		Name:             resource.Name,
+		StampedRef:       stampedRef,
+		TemplateRef:      templateRef,
+		TemplateRevision: templateRevision,
+		BaseTemplateRefs: baseTemplateRefs,
+		Inputs:           inputs,
+		Outputs:          outputs,
+		RunRequest:       runRequest,
 	}
 }
 
-func getOutputs(previousRealizedResource *v1alpha1.RealizedResource, output *templates.Output) []v1alpha1.Output {
-	outputs, err := generateResourceOutput(output)
+func getOutputs(previousRealizedResource *v1alpha1.RealizedResource, output *templates.Output, redactor *Redactor) []v1alpha1.Output {
+	outputs, err := generateResourceOutput(output, redactor)
 	if err != nil {
 		outputs = previousRealizedResource.Outputs
 	} else {
@@ -272,7 +326,7 @@ func getOutputs(previousRealizedResource *v1alpha1.RealizedResource, output *tem
 
 // TODO: This should be polymorphic
 
-func generateResourceOutput(output *templates.Output) ([]v1alpha1.Output, error) {
+func generateResourceOutput(output *templates.Output, redactor *Redactor) ([]v1alpha1.Output, error) {
 	if output == nil {
 		return nil, nil
 	}
@@ -280,25 +334,25 @@ func generateResourceOutput(output *templates.Output) ([]v1alpha1.Output, error)
 	var result []v1alpha1.Output
 
 	if output.Source != nil {
-		urlOut, err := buildOneOutput("url", output.Source.URL)
+		urlOut, err := buildOneOutput("url", output.Source.URL, redactor)
 		if err != nil {
 			return nil, err
 		}
 		result = append(result, urlOut)
 
-		revisionOut, err := buildOneOutput("revision", output.Source.Revision)
+		revisionOut, err := buildOneOutput("revision", output.Source.Revision, redactor)
 		if err != nil {
 			return nil, err
 		}
 		result = append(result, revisionOut)
 	} else if output.Image != nil {
-		out, err := buildOneOutput("image", output.Image)
+		out, err := buildOneOutput("image", output.Image, redactor)
 		if err != nil {
 			return nil, err
 		}
 		result = append(result, out)
 	} else if output.Config != nil {
-		out, err := buildOneOutput("config", output.Config)
+		out, err := buildOneOutput("config", output.Config, redactor)
 		if err != nil {
 			return nil, err
 		}
@@ -312,7 +366,7 @@ func generateResourceOutput(output *templates.Output) ([]v1alpha1.Output, error)
 
 const PreviewCharacterLimit = 1024
 
-func buildOneOutput(name string, value any) (v1alpha1.Output, error) {
+func buildOneOutput(name string, value any, redactor *Redactor) (v1alpha1.Output, error) {
 	bytes, err := yaml.Marshal(value)
 	if err != nil {
 		return v1alpha1.Output{}, err
@@ -320,9 +374,16 @@ func buildOneOutput(name string, value any) (v1alpha1.Output, error) {
 
 	sha := sha256.Sum256(bytes)
 
+	// the digest covers the real value so that changes are still detected,
+	// while the preview never shows values sourced from secrets
+	previewBytes, err := yaml.Marshal(redactor.Redact(value))
+	if err != nil {
+		return v1alpha1.Output{}, err
+	}
+
 	return v1alpha1.Output{
 		Name:    name,
-		Preview: strings.ShortenString(string(bytes), PreviewCharacterLimit),
+		Preview: strings.ShortenString(string(previewBytes), PreviewCharacterLimit),
 		Digest:  fmt.Sprintf("sha256:%x", sha),
 	}, nil
 
@@ -90,7 +90,7 @@ var _ = Describe("Realize", func() {
 			}
 		}
 		fakeMapper = &realizerfakes.FakeRESTMapper{}
-		rlzr = realizer.NewRealizer(healthyConditionEvaluator, fakeMapper)
+		rlzr = realizer.NewRealizer(healthyConditionEvaluator, nil, fakeMapper)
 		resourceRealizer = &realizerfakes.FakeResourceRealizer{}
 	})
 
@@ -166,6 +166,7 @@ var _ = Describe("Realize", func() {
 					Expect(err).NotTo(HaveOccurred())
 					stampedObj := &unstructured.Unstructured{}
 					stampedObj.SetName("obj1")
+					stampedObj.SetAnnotations(map[string]string{v1alpha1.RunRequestAnnotation: "2022-03-05T02:00:00Z"})
 					return reader, stampedObj, outputFromFirstResource, false, "returned val that would generally equal template 1 name", nil
 				}
 
@@ -216,6 +217,7 @@ var _ = Describe("Realize", func() {
 			Expect(currentResourceStatuses[0].TemplateRef.Name).To(Equal("returned val that would generally equal template 1 name"))
 			Expect(currentResourceStatuses[0].StampedRef.Name).To(Equal("obj1"))
 			Expect(currentResourceStatuses[0].StampedRef.Resource).To(Equal("FOO.EXAMPLE.COM"))
+			Expect(currentResourceStatuses[0].RunRequest).To(Equal("2022-03-05T02:00:00Z"))
 			Expect(currentResourceStatuses[0].Inputs).To(BeNil())
 			Expect(len(currentResourceStatuses[0].Outputs)).To(Equal(1))
 			Expect(currentResourceStatuses[0].Outputs[0]).To(MatchFields(IgnoreExtras,
@@ -245,6 +247,7 @@ var _ = Describe("Realize", func() {
 			Expect(currentResourceStatuses[1].TemplateRef.Name).To(Equal("returned val that would generally equal template 2 name"))
 			Expect(currentResourceStatuses[1].StampedRef.Name).To(Equal("obj2"))
 			Expect(currentResourceStatuses[1].StampedRef.Resource).To(Equal("FOO.EXAMPLE.COM"))
+			Expect(currentResourceStatuses[1].RunRequest).To(BeEmpty())
 			Expect(len(currentResourceStatuses[1].Inputs)).To(Equal(1))
 			Expect(currentResourceStatuses[1].Inputs).To(Equal([]v1alpha1.Input{{Name: "resource1"}}))
 			Expect(currentResourceStatuses[1].Outputs).To(BeNil())
@@ -264,6 +267,21 @@ var _ = Describe("Realize", func() {
 			})))
 		})
 
+		It("redacts values sourced from secrets from output previews", func() {
+			redactor := &realizer.Redactor{}
+			redactor.Add("whatever")
+			ctx = realizer.NewRedactorContext(ctx, redactor)
+
+			resourceStatuses := statuses.NewResourceStatuses(nil, conditions.AddConditionForResourceSubmittedWorkload)
+			err := rlzr.Realize(ctx, resourceRealizer, supplyChain.Name, realizer.MakeSupplychainOwnerResources(supplyChain), resourceStatuses)
+			Expect(err).ToNot(HaveOccurred())
+
+			currentResourceStatuses := resourceStatuses.GetCurrent()
+			Expect(currentResourceStatuses[0].Outputs).To(HaveLen(1))
+			Expect(currentResourceStatuses[0].Outputs[0].Preview).To(Equal("'[REDACTED]'\n"))
+			Expect(currentResourceStatuses[0].Outputs[0].Digest).To(Equal(fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("whatever\n")))))
+		})
+
 		It("records an event for resource output changes and health status", func() {
 			resourceStatuses := statuses.NewResourceStatuses(nil, conditions.AddConditionForResourceSubmittedWorkload)
 			Expect(rlzr.Realize(ctx, resourceRealizer, supplyChain.Name, realizer.MakeSupplychainOwnerResources(supplyChain), resourceStatuses)).To(Succeed())
@@ -325,6 +343,153 @@ var _ = Describe("Realize", func() {
 				Expect(currentResourceStatuses[1].TemplateRef.Name).To(Equal(template2.Name))
 			})
 		})
+
+		Context("the status of a stamped object is for an earlier generation", func() {
+			BeforeEach(func() {
+				template2.Spec.HealthRule = &v1alpha1.HealthRule{SingleConditionType: "Ready"}
+
+				resourceRealizer.DoCalls(func(ctx context.Context, resource realizer.OwnerResource, blueprintName string, outputs realizer.Outputs, mapper meta.RESTMapper) (templates.Reader, *unstructured.Unstructured, *templates.Output, bool, string, error) {
+					reader, err := templates.NewReaderFromAPI(template2)
+					Expect(err).NotTo(HaveOccurred())
+					stampedObj := &unstructured.Unstructured{}
+					stampedObj.SetName("stale-obj")
+					stampedObj.SetGeneration(2)
+					Expect(unstructured.SetNestedField(stampedObj.Object, int64(1), "status", "observedGeneration")).To(Succeed())
+					return reader, stampedObj, &templates.Output{}, false, template2.Name, nil
+				})
+			})
+
+			It("reports the health of the resource as unknown", func() {
+				resourceStatuses := statuses.NewResourceStatuses(nil, conditions.AddConditionForResourceSubmittedWorkload)
+				Expect(rlzr.Realize(ctx, resourceRealizer, supplyChain.Name, realizer.MakeSupplychainOwnerResources(supplyChain), resourceStatuses)).To(Succeed())
+
+				currentResourceStatuses := resourceStatuses.GetCurrent()
+				Expect(currentResourceStatuses[0].Conditions).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal("Healthy"),
+					"Status":  Equal(metav1.ConditionUnknown),
+					"Reason":  Equal("StaleStatus"),
+					"Message": Equal("status.observedGeneration [1] is behind metadata.generation [2]"),
+				})))
+			})
+
+			Context("the template ignores observedGeneration", func() {
+				BeforeEach(func() {
+					template2.Spec.IgnoreObservedGeneration = true
+				})
+
+				It("evaluates the health rule", func() {
+					resourceStatuses := statuses.NewResourceStatuses(nil, conditions.AddConditionForResourceSubmittedWorkload)
+					Expect(rlzr.Realize(ctx, resourceRealizer, supplyChain.Name, realizer.MakeSupplychainOwnerResources(supplyChain), resourceStatuses)).To(Succeed())
+
+					currentResourceStatuses := resourceStatuses.GetCurrent()
+					Expect(currentResourceStatuses[0].Conditions).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+						"Type":   Equal("Healthy"),
+						"Status": Equal(metav1.ConditionTrue),
+					})))
+				})
+			})
+		})
+
+		Context("a run stamped from a template with the tekton lifecycle failed", func() {
+			BeforeEach(func() {
+				rlzr = realizer.NewRealizer(nil, nil, fakeMapper)
+				template2.Spec.Lifecycle = "tekton"
+				template2.Spec.HealthRule = nil
+
+				resourceRealizer.DoCalls(func(ctx context.Context, resource realizer.OwnerResource, blueprintName string, outputs realizer.Outputs, mapper meta.RESTMapper) (templates.Reader, *unstructured.Unstructured, *templates.Output, bool, string, error) {
+					reader, err := templates.NewReaderFromAPI(template2)
+					Expect(err).NotTo(HaveOccurred())
+					stampedObj := &unstructured.Unstructured{}
+					stampedObj.SetAPIVersion("tekton.dev/v1beta1")
+					stampedObj.SetKind("PipelineRun")
+					stampedObj.SetName("failed-run")
+					Expect(unstructured.SetNestedSlice(stampedObj.Object, []interface{}{
+						map[string]interface{}{
+							"type":    "Succeeded",
+							"status":  "False",
+							"reason":  "CouldntGetPipeline",
+							"message": `Error retrieving pipeline for pipelinerun "failed-run": pipelines.tekton.dev "my-pipeline" not found`,
+						},
+					}, "status", "conditions")).To(Succeed())
+					return reader, stampedObj, &templates.Output{}, false, template2.Name, nil
+				})
+			})
+
+			It("reports the reason the run failed", func() {
+				resourceStatuses := statuses.NewResourceStatuses(nil, conditions.AddConditionForResourceSubmittedWorkload)
+				Expect(rlzr.Realize(ctx, resourceRealizer, supplyChain.Name, realizer.MakeSupplychainOwnerResources(supplyChain), resourceStatuses)).To(Succeed())
+
+				currentResourceStatuses := resourceStatuses.GetCurrent()
+				Expect(currentResourceStatuses[0].Conditions).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal("Healthy"),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Reason":  Equal("SucceededCondition"),
+					"Message": Equal(`PipelineRun references a pipeline that could not be found [CouldntGetPipeline]: Error retrieving pipeline for pipelinerun "failed-run": pipelines.tekton.dev "my-pipeline" not found`),
+				})))
+			})
+		})
+
+		Context("a template health rule aggregates child objects", func() {
+			BeforeEach(func() {
+				template2.Spec.HealthRule.Children = &v1alpha1.ChildrenHealthRule{
+					APIVersion: "v1",
+					Kind:       "Pod",
+					Owned:      true,
+				}
+			})
+
+			It("lists the children of the stamped object", func() {
+				resourceStatuses := statuses.NewResourceStatuses(nil, conditions.AddConditionForResourceSubmittedWorkload)
+				Expect(rlzr.Realize(ctx, resourceRealizer, supplyChain.Name, realizer.MakeSupplychainOwnerResources(supplyChain), resourceStatuses)).To(Succeed())
+
+				Expect(resourceRealizer.ListChildrenCallCount()).To(Equal(1))
+				_, rule, parent := resourceRealizer.ListChildrenArgsForCall(0)
+				Expect(rule).To(Equal(template2.Spec.HealthRule.Children))
+				Expect(parent.GetName()).To(Equal("obj2"))
+			})
+
+			Context("a child is unhealthy", func() {
+				BeforeEach(func() {
+					child := &unstructured.Unstructured{}
+					child.SetName("pod-1")
+					Expect(unstructured.SetNestedSlice(child.Object, []interface{}{
+						map[string]interface{}{"type": "Ready", "status": "False"},
+					}, "status", "conditions")).To(Succeed())
+					resourceRealizer.ListChildrenReturns([]*unstructured.Unstructured{child}, nil)
+				})
+
+				It("reports the resource as unhealthy", func() {
+					resourceStatuses := statuses.NewResourceStatuses(nil, conditions.AddConditionForResourceSubmittedWorkload)
+					Expect(rlzr.Realize(ctx, resourceRealizer, supplyChain.Name, realizer.MakeSupplychainOwnerResources(supplyChain), resourceStatuses)).To(Succeed())
+
+					currentResourceStatuses := resourceStatuses.GetCurrent()
+					Expect(currentResourceStatuses[1].Conditions).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+						"Type":    Equal("Healthy"),
+						"Status":  Equal(metav1.ConditionFalse),
+						"Reason":  Equal("ChildrenUnhealthy"),
+						"Message": Equal("0/1 children healthy, 1 required, failing: [pod-1]"),
+					})))
+				})
+			})
+
+			Context("the children cannot be listed", func() {
+				BeforeEach(func() {
+					resourceRealizer.ListChildrenReturns(nil, errors.New("forbidden"))
+				})
+
+				It("reports the resource health as unknown", func() {
+					resourceStatuses := statuses.NewResourceStatuses(nil, conditions.AddConditionForResourceSubmittedWorkload)
+					Expect(rlzr.Realize(ctx, resourceRealizer, supplyChain.Name, realizer.MakeSupplychainOwnerResources(supplyChain), resourceStatuses)).To(Succeed())
+
+					currentResourceStatuses := resourceStatuses.GetCurrent()
+					Expect(currentResourceStatuses[1].Conditions).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+						"Type":   Equal("Healthy"),
+						"Status": Equal(metav1.ConditionUnknown),
+						"Reason": Equal("ChildrenListFailed"),
+					})))
+				})
+			})
+		})
 	})
 
 	Context("one of the resources is passed through", func() {
@@ -743,6 +908,56 @@ var _ = Describe("Realize", func() {
 			Expect(resourceObj).To(Equal(stampedObj1))
 		})
 
+		Context("a resource has a pending run", func() {
+			var previousPendingRun, pendingRun *v1alpha1.PendingRun
+
+			BeforeEach(func() {
+				previousPendingRun = &v1alpha1.PendingRun{
+					InputsDigest: "sha256:previous",
+					RunAfter:     previousTime,
+				}
+				previousResources[1].PendingRun = previousPendingRun
+
+				pendingRun = &v1alpha1.PendingRun{
+					InputsDigest: "sha256:current",
+					RunAfter:     metav1.NewTime(previousTime.Add(time.Minute)),
+				}
+				resourceRealizer.PendingRunStub = func(resourceName string) *v1alpha1.PendingRun {
+					if resourceName == "resource3" {
+						return pendingRun
+					}
+					return nil
+				}
+			})
+
+			It("passes the previous pending run to the resource realizer", func() {
+				resourceStatuses := statuses.NewResourceStatuses(previousResources, conditions.AddConditionForResourceSubmittedWorkload)
+				err := rlzr.Realize(ctx, resourceRealizer, supplyChain.Name, realizer.MakeSupplychainOwnerResources(supplyChain), resourceStatuses)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, resource, _, _, _ := resourceRealizer.DoArgsForCall(0)
+				Expect(resource.PendingRun).To(BeNil())
+				_, resource, _, _, _ = resourceRealizer.DoArgsForCall(2)
+				Expect(resource.PendingRun).To(Equal(previousPendingRun))
+			})
+
+			It("records the pending run in the resource status", func() {
+				resourceStatuses := statuses.NewResourceStatuses(previousResources, conditions.AddConditionForResourceSubmittedWorkload)
+				err := rlzr.Realize(ctx, resourceRealizer, supplyChain.Name, realizer.MakeSupplychainOwnerResources(supplyChain), resourceStatuses)
+				Expect(err).ToNot(HaveOccurred())
+
+				currentStatuses := resourceStatuses.GetCurrent()
+				Expect(currentStatuses).To(HaveLen(3))
+				for _, status := range currentStatuses {
+					if status.Name == "resource3" {
+						Expect(status.PendingRun).To(Equal(pendingRun))
+					} else {
+						Expect(status.PendingRun).To(BeNil())
+					}
+				}
+			})
+		})
+
 		Context("there is an error realizing resource 1 and resource 2", func() {
 			BeforeEach(func() {
 				resourceRealizer.DoReturnsOnCall(0, nil, nil, nil, false, "", errors.New("im in a bad state"))
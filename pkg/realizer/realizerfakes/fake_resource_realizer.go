@@ -4,7 +4,9 @@ package realizerfakes
 import (
 	"context"
 	"sync"
+	"time"
 
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -12,6 +14,17 @@ import (
 )
 
 type FakeResourceRealizer struct {
+	CleanupAfterStub        func(string) time.Duration
+	cleanupAfterMutex       sync.RWMutex
+	cleanupAfterArgsForCall []struct {
+		arg1 string
+	}
+	cleanupAfterReturns struct {
+		result1 time.Duration
+	}
+	cleanupAfterReturnsOnCall map[int]struct {
+		result1 time.Duration
+	}
 	DoStub        func(context.Context, realizer.OwnerResource, string, realizer.Outputs, meta.RESTMapper) (templates.Reader, *unstructured.Unstructured, *templates.Output, bool, string, error)
 	doMutex       sync.RWMutex
 	doArgsForCall []struct {
@@ -37,10 +50,97 @@ type FakeResourceRealizer struct {
 		result5 string
 		result6 error
 	}
+	ListChildrenStub        func(context.Context, *v1alpha1.ChildrenHealthRule, *unstructured.Unstructured) ([]*unstructured.Unstructured, error)
+	listChildrenMutex       sync.RWMutex
+	listChildrenArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1alpha1.ChildrenHealthRule
+		arg3 *unstructured.Unstructured
+	}
+	listChildrenReturns struct {
+		result1 []*unstructured.Unstructured
+		result2 error
+	}
+	listChildrenReturnsOnCall map[int]struct {
+		result1 []*unstructured.Unstructured
+		result2 error
+	}
+	PendingRunStub        func(string) *v1alpha1.PendingRun
+	pendingRunMutex       sync.RWMutex
+	pendingRunArgsForCall []struct {
+		arg1 string
+	}
+	pendingRunReturns struct {
+		result1 *v1alpha1.PendingRun
+	}
+	pendingRunReturnsOnCall map[int]struct {
+		result1 *v1alpha1.PendingRun
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeResourceRealizer) CleanupAfter(arg1 string) time.Duration {
+	fake.cleanupAfterMutex.Lock()
+	ret, specificReturn := fake.cleanupAfterReturnsOnCall[len(fake.cleanupAfterArgsForCall)]
+	fake.cleanupAfterArgsForCall = append(fake.cleanupAfterArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.CleanupAfterStub
+	fakeReturns := fake.cleanupAfterReturns
+	fake.recordInvocation("CleanupAfter", []interface{}{arg1})
+	fake.cleanupAfterMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeResourceRealizer) CleanupAfterCallCount() int {
+	fake.cleanupAfterMutex.RLock()
+	defer fake.cleanupAfterMutex.RUnlock()
+	return len(fake.cleanupAfterArgsForCall)
+}
+
+func (fake *FakeResourceRealizer) CleanupAfterCalls(stub func(string) time.Duration) {
+	fake.cleanupAfterMutex.Lock()
+	defer fake.cleanupAfterMutex.Unlock()
+	fake.CleanupAfterStub = stub
+}
+
+func (fake *FakeResourceRealizer) CleanupAfterArgsForCall(i int) string {
+	fake.cleanupAfterMutex.RLock()
+	defer fake.cleanupAfterMutex.RUnlock()
+	argsForCall := fake.cleanupAfterArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeResourceRealizer) CleanupAfterReturns(result1 time.Duration) {
+	fake.cleanupAfterMutex.Lock()
+	defer fake.cleanupAfterMutex.Unlock()
+	fake.CleanupAfterStub = nil
+	fake.cleanupAfterReturns = struct {
+		result1 time.Duration
+	}{result1}
+}
+
+func (fake *FakeResourceRealizer) CleanupAfterReturnsOnCall(i int, result1 time.Duration) {
+	fake.cleanupAfterMutex.Lock()
+	defer fake.cleanupAfterMutex.Unlock()
+	fake.CleanupAfterStub = nil
+	if fake.cleanupAfterReturnsOnCall == nil {
+		fake.cleanupAfterReturnsOnCall = make(map[int]struct {
+			result1 time.Duration
+		})
+	}
+	fake.cleanupAfterReturnsOnCall[i] = struct {
+		result1 time.Duration
+	}{result1}
+}
+
 func (fake *FakeResourceRealizer) Do(arg1 context.Context, arg2 realizer.OwnerResource, arg3 string, arg4 realizer.Outputs, arg5 meta.RESTMapper) (templates.Reader, *unstructured.Unstructured, *templates.Output, bool, string, error) {
 	fake.doMutex.Lock()
 	ret, specificReturn := fake.doReturnsOnCall[len(fake.doArgsForCall)]
@@ -121,11 +221,144 @@ func (fake *FakeResourceRealizer) DoReturnsOnCall(i int, result1 templates.Reade
 	}{result1, result2, result3, result4, result5, result6}
 }
 
+func (fake *FakeResourceRealizer) ListChildren(arg1 context.Context, arg2 *v1alpha1.ChildrenHealthRule, arg3 *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	fake.listChildrenMutex.Lock()
+	ret, specificReturn := fake.listChildrenReturnsOnCall[len(fake.listChildrenArgsForCall)]
+	fake.listChildrenArgsForCall = append(fake.listChildrenArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1alpha1.ChildrenHealthRule
+		arg3 *unstructured.Unstructured
+	}{arg1, arg2, arg3})
+	stub := fake.ListChildrenStub
+	fakeReturns := fake.listChildrenReturns
+	fake.recordInvocation("ListChildren", []interface{}{arg1, arg2, arg3})
+	fake.listChildrenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeResourceRealizer) ListChildrenCallCount() int {
+	fake.listChildrenMutex.RLock()
+	defer fake.listChildrenMutex.RUnlock()
+	return len(fake.listChildrenArgsForCall)
+}
+
+func (fake *FakeResourceRealizer) ListChildrenCalls(stub func(context.Context, *v1alpha1.ChildrenHealthRule, *unstructured.Unstructured) ([]*unstructured.Unstructured, error)) {
+	fake.listChildrenMutex.Lock()
+	defer fake.listChildrenMutex.Unlock()
+	fake.ListChildrenStub = stub
+}
+
+func (fake *FakeResourceRealizer) ListChildrenArgsForCall(i int) (context.Context, *v1alpha1.ChildrenHealthRule, *unstructured.Unstructured) {
+	fake.listChildrenMutex.RLock()
+	defer fake.listChildrenMutex.RUnlock()
+	argsForCall := fake.listChildrenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeResourceRealizer) ListChildrenReturns(result1 []*unstructured.Unstructured, result2 error) {
+	fake.listChildrenMutex.Lock()
+	defer fake.listChildrenMutex.Unlock()
+	fake.ListChildrenStub = nil
+	fake.listChildrenReturns = struct {
+		result1 []*unstructured.Unstructured
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeResourceRealizer) ListChildrenReturnsOnCall(i int, result1 []*unstructured.Unstructured, result2 error) {
+	fake.listChildrenMutex.Lock()
+	defer fake.listChildrenMutex.Unlock()
+	fake.ListChildrenStub = nil
+	if fake.listChildrenReturnsOnCall == nil {
+		fake.listChildrenReturnsOnCall = make(map[int]struct {
+			result1 []*unstructured.Unstructured
+			result2 error
+		})
+	}
+	fake.listChildrenReturnsOnCall[i] = struct {
+		result1 []*unstructured.Unstructured
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeResourceRealizer) PendingRun(arg1 string) *v1alpha1.PendingRun {
+	fake.pendingRunMutex.Lock()
+	ret, specificReturn := fake.pendingRunReturnsOnCall[len(fake.pendingRunArgsForCall)]
+	fake.pendingRunArgsForCall = append(fake.pendingRunArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.PendingRunStub
+	fakeReturns := fake.pendingRunReturns
+	fake.recordInvocation("PendingRun", []interface{}{arg1})
+	fake.pendingRunMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeResourceRealizer) PendingRunCallCount() int {
+	fake.pendingRunMutex.RLock()
+	defer fake.pendingRunMutex.RUnlock()
+	return len(fake.pendingRunArgsForCall)
+}
+
+func (fake *FakeResourceRealizer) PendingRunCalls(stub func(string) *v1alpha1.PendingRun) {
+	fake.pendingRunMutex.Lock()
+	defer fake.pendingRunMutex.Unlock()
+	fake.PendingRunStub = stub
+}
+
+func (fake *FakeResourceRealizer) PendingRunArgsForCall(i int) string {
+	fake.pendingRunMutex.RLock()
+	defer fake.pendingRunMutex.RUnlock()
+	argsForCall := fake.pendingRunArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeResourceRealizer) PendingRunReturns(result1 *v1alpha1.PendingRun) {
+	fake.pendingRunMutex.Lock()
+	defer fake.pendingRunMutex.Unlock()
+	fake.PendingRunStub = nil
+	fake.pendingRunReturns = struct {
+		result1 *v1alpha1.PendingRun
+	}{result1}
+}
+
+func (fake *FakeResourceRealizer) PendingRunReturnsOnCall(i int, result1 *v1alpha1.PendingRun) {
+	fake.pendingRunMutex.Lock()
+	defer fake.pendingRunMutex.Unlock()
+	fake.PendingRunStub = nil
+	if fake.pendingRunReturnsOnCall == nil {
+		fake.pendingRunReturnsOnCall = make(map[int]struct {
+			result1 *v1alpha1.PendingRun
+		})
+	}
+	fake.pendingRunReturnsOnCall[i] = struct {
+		result1 *v1alpha1.PendingRun
+	}{result1}
+}
+
 func (fake *FakeResourceRealizer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.cleanupAfterMutex.RLock()
+	defer fake.cleanupAfterMutex.RUnlock()
 	fake.doMutex.RLock()
 	defer fake.doMutex.RUnlock()
+	fake.listChildrenMutex.RLock()
+	defer fake.listChildrenMutex.RUnlock()
+	fake.pendingRunMutex.RLock()
+	defer fake.pendingRunMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
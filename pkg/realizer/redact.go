@@ -0,0 +1,127 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realizer
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const RedactedValue = "[REDACTED]"
+
+// Redactor replaces sensitive values, such as params sourced from Secrets,
+// wherever they appear in output previews and logged objects.
+// A nil Redactor redacts nothing.
+type Redactor struct {
+	mu     sync.Mutex
+	values []string
+}
+
+// Add registers a value that must be redacted
+func (r *Redactor) Add(value string) {
+	if r == nil || value == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.values {
+		if existing == value {
+			return
+		}
+	}
+	r.values = append(r.values, value)
+
+	// replace longer values first, so that a value containing another is fully redacted
+	sort.Slice(r.values, func(i, j int) bool {
+		return len(r.values[i]) > len(r.values[j])
+	})
+}
+
+// Redact returns a copy of value with every registered value replaced.
+// Maps, slices and strings are traversed; other values are returned as is.
+func (r *Redactor) Redact(value interface{}) interface{} {
+	if r == nil {
+		return value
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.values) == 0 {
+		return value
+	}
+
+	return r.redact(value)
+}
+
+// RedactObject returns a copy of obj with every registered value replaced
+func (r *Redactor) RedactObject(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if obj == nil {
+		return nil
+	}
+
+	redacted, ok := r.Redact(obj.Object).(map[string]interface{})
+	if !ok {
+		return obj
+	}
+
+	return &unstructured.Unstructured{Object: redacted}
+}
+
+func (r *Redactor) redact(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case string:
+		for _, sensitive := range r.values {
+			typedValue = strings.ReplaceAll(typedValue, sensitive, RedactedValue)
+		}
+		return typedValue
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typedValue))
+		for key, item := range typedValue {
+			result[key] = r.redact(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typedValue))
+		for i, item := range typedValue {
+			result[i] = r.redact(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+type redactorContextKey struct{}
+
+// RedactorFromContext returns the Redactor carried by ctx, or nil if there is none
+func RedactorFromContext(ctx context.Context) *Redactor {
+	if v, ok := ctx.Value(redactorContextKey{}).(*Redactor); ok {
+		return v
+	}
+	return nil
+}
+
+// NewRedactorContext returns a new Context, derived from ctx, which carries the
+// provided Redactor.
+func NewRedactorContext(ctx context.Context, redactor *Redactor) context.Context {
+	return context.WithValue(ctx, redactorContextKey{}, redactor)
+}
@@ -17,14 +17,17 @@ package gc
 import (
 	"context"
 	"sort"
+	"time"
 
 	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/cartographer/pkg/logger"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
 	"github.com/vmware-tanzu/cartographer/pkg/stamp"
+	"github.com/vmware-tanzu/cartographer/pkg/utils"
 )
 
 type ByCreationTimestamp []*stamp.ExaminedObject
@@ -35,7 +38,13 @@ func (a ByCreationTimestamp) Less(i, j int) bool {
 }
 func (a ByCreationTimestamp) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
 
-func CleanupRunnableStampedObjects(ctx context.Context, examinedObjects []*stamp.ExaminedObject, retentionPolicy v1alpha1.RetentionPolicy, repo repository.Repository) {
+// CleanupRunnableStampedObjects deletes the objects not retained by the retention
+// policy and returns the objects that remain, most recent first, along with
+// how soon the next retained object expires by a TTL, or 0 if none will. The
+// most recent successful object is always retained, whatever its age, as is
+// current, the object matching the current stamp, which would otherwise be
+// created again.
+func CleanupRunnableStampedObjects(ctx context.Context, examinedObjects []*stamp.ExaminedObject, retentionPolicy v1alpha1.RetentionPolicy, repo repository.Repository, current *unstructured.Unstructured, now time.Time) ([]*stamp.ExaminedObject, time.Duration) {
 	log := logr.FromContextOrDiscard(ctx).WithName("runnable-stamped-object-cleanup")
 	ctx = logr.NewContext(ctx, log)
 
@@ -43,27 +52,92 @@ func CleanupRunnableStampedObjects(ctx context.Context, examinedObjects []*stamp
 
 	var successfulFound int64
 	var failedFound int64
+	var retained []*stamp.ExaminedObject
+	var nextExpiry time.Duration
 	for _, examinedObject := range examinedObjects {
 		runnableStampedObject := examinedObject.StampedObject
 		runnableHealth := examinedObject.Health
-		shouldDelete := false
+		isCurrent := current != nil &&
+			runnableStampedObject.GetName() == current.GetName() &&
+			runnableStampedObject.GetNamespace() == current.GetNamespace()
+
+		var ttl *metav1.Duration
+		var since time.Time
+		var deleteReason string
 		if runnableHealth == metav1.ConditionTrue {
 			successfulFound++
-			shouldDelete = successfulFound > retentionPolicy.MaxSuccessfulRuns
+			if successfulFound > 1 {
+				ttl, since = retentionPolicy.SuccessfulRunTTL, completionTime(runnableStampedObject)
+			}
+			if successfulFound > retentionPolicy.MaxSuccessfulRuns {
+				deleteReason = "exceeds maxSuccessfulRuns"
+			} else if expired(ttl, since, now) {
+				deleteReason = "exceeds successfulRunTTL"
+			}
 		} else if runnableHealth == metav1.ConditionFalse {
 			failedFound++
-			shouldDelete = failedFound > retentionPolicy.MaxFailedRuns
+			ttl, since = retentionPolicy.FailedRunTTL, completionTime(runnableStampedObject)
+			if failedFound > retentionPolicy.MaxFailedRuns {
+				deleteReason = "exceeds maxFailedRuns"
+			} else if expired(ttl, since, now) {
+				deleteReason = "exceeds failedRunTTL"
+			}
 		} else {
-			log.V(logger.INFO).Info("not considered for cleanup because object health has not resolved",
-				"stampedObject", runnableStampedObject)
+			ttl, since = retentionPolicy.UnresolvedRunTTL, runnableStampedObject.GetCreationTimestamp().Time
+			if expired(ttl, since, now) {
+				deleteReason = "exceeds unresolvedRunTTL"
+			} else {
+				log.V(logger.INFO).Info("not considered for cleanup because object health has not resolved",
+					"stampedObject", runnableStampedObject)
+			}
 		}
 
-		if shouldDelete {
-			log.V(logger.INFO).Info("deleting runnable stamped object", "stampedObject", runnableStampedObject)
+		if isCurrent {
+			if deleteReason != "" {
+				log.V(logger.INFO).Info("not deleting runnable stamped object matching the current stamp", "stampedObject", runnableStampedObject, "reason", deleteReason)
+			}
+			retained = append(retained, examinedObject)
+			continue
+		}
+
+		if deleteReason != "" {
+			if retentionPolicy.DryRun {
+				log.V(logger.INFO).Info("dry run: not deleting runnable stamped object", "stampedObject", runnableStampedObject, "reason", deleteReason)
+				retained = append(retained, examinedObject)
+				continue
+			}
+
+			log.V(logger.INFO).Info("deleting runnable stamped object", "stampedObject", runnableStampedObject, "reason", deleteReason)
 			err := repo.Delete(ctx, runnableStampedObject)
-			if err != nil {
-				log.Error(err, "failed to delete runnable stamped object", "stampedObject", runnableStampedObject)
+			if err == nil {
+				continue
 			}
+			log.Error(err, "failed to delete runnable stamped object", "stampedObject", runnableStampedObject)
+		} else if ttl != nil {
+			expiresAfter := since.Add(ttl.Duration).Sub(now) + time.Second
+			if nextExpiry == 0 || expiresAfter < nextExpiry {
+				nextExpiry = expiresAfter
+			}
+		}
+
+		retained = append(retained, examinedObject)
+	}
+
+	return retained, nextExpiry
+}
+
+func expired(ttl *metav1.Duration, since time.Time, now time.Time) bool {
+	return ttl != nil && now.Sub(since) > ttl.Duration
+}
+
+// completionTime is the most recent transition of the object's conditions, or
+// its creation time if it has none.
+func completionTime(obj *unstructured.Unstructured) time.Time {
+	completed := obj.GetCreationTimestamp().Time
+	for _, condition := range utils.ExtractConditions(obj) {
+		if condition.LastTransitionTime.After(completed) {
+			completed = condition.LastTransitionTime.Time
 		}
 	}
+	return completed
 }
@@ -17,6 +17,7 @@ package gc_test
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo"
@@ -60,6 +61,7 @@ var _ = Describe("CleanupRunnableStampedObjects", func() {
 		retentionPolicy    v1alpha1.RetentionPolicy
 		ctx                context.Context
 		out                *Buffer
+		now                time.Time
 	)
 
 	BeforeEach(func() {
@@ -93,6 +95,7 @@ var _ = Describe("CleanupRunnableStampedObjects", func() {
 		repo = &repositoryfakes.FakeRepository{}
 
 		retentionPolicy = v1alpha1.RetentionPolicy{MaxFailedRuns: 2, MaxSuccessfulRuns: 3}
+		now = time.Date(2022, 1, 13, 17, 0, 7, 0, time.UTC)
 	})
 
 	It("should not error, but log a warning, when a stamped object that doesnt have a Succeeded status is handled", func() {
@@ -122,7 +125,7 @@ var _ = Describe("CleanupRunnableStampedObjects", func() {
 
 		allExaminedObjects = append([]*stamp.ExaminedObject{&examinedObjToStillExist, &examinedObjToBeDeleted}, allExaminedObjects...)
 
-		gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo)
+		gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
 
 		Expect(repo.DeleteCallCount()).To(Equal(1))
 		_, deletedObject1 := repo.DeleteArgsForCall(0)
@@ -168,9 +171,28 @@ var _ = Describe("CleanupRunnableStampedObjects", func() {
 			)
 		})
 
+		It("returns the retained objects, most recent first", func() {
+			retained, _ := gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
+
+			var retainedNames []string
+			for _, examinedObject := range retained {
+				retainedNames = append(retainedNames, examinedObject.StampedObject.GetName())
+			}
+			Expect(retainedNames).To(HaveLen(5))
+			Expect(retainedNames[:2]).To(ConsistOf("MostRecentSuccess", "MostRecentFailure"))
+			Expect(retainedNames[2:4]).To(ConsistOf("RecentSuccessRetainedByPolicy1", "RecentFailureRetainedByPolicy2"))
+			Expect(retainedNames[4]).To(Equal("RecentSuccessRetainedByPolicy2"))
+		})
+
+		It("retains the objects it fails to delete", func() {
+			repo.DeleteReturns(errors.New("deleting is hard"))
+			retained, _ := gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
+			Expect(retained).To(HaveLen(len(allExaminedObjects)))
+		})
+
 		It("continues processing all elements and logs an error if deleting a runnable stamped object fails", func() {
 			repo.DeleteReturns(errors.New("deleting is hard"))
-			gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo)
+			gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
 
 			Expect(repo.DeleteCallCount()).To(Equal(4))
 			Expect(out).To(Say("failed to delete runnable stamped object.*RecentFailureToBeDeleted1.*deleting is hard"))
@@ -180,7 +202,7 @@ var _ = Describe("CleanupRunnableStampedObjects", func() {
 		})
 
 		It("deletes successful and failed runnable stamped objects according to retention policy", func() {
-			gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo)
+			gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
 
 			Expect(repo.DeleteCallCount()).To(Equal(4))
 			_, deletedObject1 := repo.DeleteArgsForCall(0)
@@ -214,7 +236,7 @@ var _ = Describe("CleanupRunnableStampedObjects", func() {
 					Health:        metav1.ConditionUnknown,
 				}}, allExaminedObjects...)
 
-			gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo)
+			gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
 
 			Expect(repo.DeleteCallCount()).To(Equal(4))
 			_, deletedObject1 := repo.DeleteArgsForCall(0)
@@ -229,4 +251,127 @@ var _ = Describe("CleanupRunnableStampedObjects", func() {
 			Expect(out).To(Say("deleting runnable stamped object"))
 		})
 	})
+
+	It("does not return an expiry when the retention policy has no TTLs", func() {
+		_, cleanupAfter := gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
+		Expect(cleanupAfter).To(BeZero())
+	})
+
+	Context("when the retention policy has TTLs", func() {
+		names := func(examinedObjects []*stamp.ExaminedObject) []string {
+			var result []string
+			for _, examinedObject := range examinedObjects {
+				result = append(result, examinedObject.StampedObject.GetName())
+			}
+			return result
+		}
+
+		deletedNames := func() []string {
+			var result []string
+			for i := 0; i < repo.DeleteCallCount(); i++ {
+				_, deleted := repo.DeleteArgsForCall(i)
+				result = append(result, deleted.GetName())
+			}
+			return result
+		}
+
+		BeforeEach(func() {
+			retentionPolicy.SuccessfulRunTTL = &metav1.Duration{Duration: 48 * time.Hour}
+			retentionPolicy.FailedRunTTL = &metav1.Duration{Duration: 24 * time.Hour}
+		})
+
+		It("deletes successful and failed runs that completed before their TTL", func() {
+			retained, _ := gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
+
+			Expect(deletedNames()).To(ConsistOf("RecentFailureRetainedByPolicy2", "RecentSuccessRetainedByPolicy2"))
+			Expect(names(retained)).To(ConsistOf("MostRecentSuccess", "MostRecentFailure", "RecentSuccessRetainedByPolicy1"))
+		})
+
+		It("always retains the most recent successful run", func() {
+			retentionPolicy.SuccessfulRunTTL = &metav1.Duration{Duration: time.Hour}
+
+			retained, _ := gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
+
+			Expect(deletedNames()).NotTo(ContainElement("MostRecentSuccess"))
+			Expect(names(retained)).To(ContainElement("MostRecentSuccess"))
+		})
+
+		It("retains the run matching the current stamp, even once its TTL has passed", func() {
+			retentionPolicy.FailedRunTTL = &metav1.Duration{Duration: time.Hour}
+			current := allExaminedObjects[2].StampedObject
+
+			retained, _ := gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, current, now)
+
+			Expect(deletedNames()).To(ConsistOf("RecentFailureRetainedByPolicy2", "RecentSuccessRetainedByPolicy2"))
+			Expect(names(retained)).To(ContainElement("MostRecentFailure"))
+			Expect(out).To(Say(`not deleting runnable stamped object matching the current stamp.*MostRecentFailure.*"reason":"exceeds failedRunTTL"`))
+		})
+
+		It("returns how soon the next retained run expires", func() {
+			retentionPolicy.SuccessfulRunTTL = &metav1.Duration{Duration: 60 * time.Hour}
+			retentionPolicy.FailedRunTTL = &metav1.Duration{Duration: 36 * time.Hour}
+
+			retained, cleanupAfter := gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
+
+			Expect(names(retained)).To(ConsistOf("MostRecentSuccess", "MostRecentFailure", "RecentSuccessRetainedByPolicy1"))
+			Expect(cleanupAfter).To(Equal(12*time.Hour + time.Second))
+		})
+
+		It("does not return an expiry for the run matching the current stamp", func() {
+			retentionPolicy.SuccessfulRunTTL = nil
+			retentionPolicy.FailedRunTTL = &metav1.Duration{Duration: 36 * time.Hour}
+			allExaminedObjects = allExaminedObjects[:3]
+
+			_, cleanupAfter := gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, allExaminedObjects[2].StampedObject, now)
+
+			Expect(cleanupAfter).To(BeZero())
+		})
+
+		It("measures the TTL from the most recent condition transition", func() {
+			Expect(unstructured.SetNestedSlice(allExaminedObjects[4].StampedObject.Object, []interface{}{
+				map[string]interface{}{"type": "Succeeded", "status": "True", "lastTransitionTime": "2022-01-12T17:00:07Z"},
+			}, "status", "conditions")).To(Succeed())
+
+			gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
+
+			Expect(deletedNames()).To(ConsistOf("RecentFailureRetainedByPolicy2"))
+		})
+
+		Context("and runs have not resolved", func() {
+			BeforeEach(func() {
+				retentionPolicy.UnresolvedRunTTL = &metav1.Duration{Duration: 12 * time.Hour}
+				allExaminedObjects = append(allExaminedObjects,
+					&stamp.ExaminedObject{
+						StampedObject: MakeRunnableStampedObject("Unknown", "StuckRun", "2022-01-12T17:00:07Z"),
+						Health:        metav1.ConditionUnknown,
+					},
+					&stamp.ExaminedObject{
+						StampedObject: MakeRunnableStampedObject("Unknown", "RunningRun", "2022-01-13T16:00:07Z"),
+						Health:        metav1.ConditionUnknown,
+					},
+				)
+			})
+
+			It("deletes unresolved runs created before the unresolved TTL", func() {
+				gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
+
+				Expect(deletedNames()).To(ContainElement("StuckRun"))
+				Expect(deletedNames()).NotTo(ContainElement("RunningRun"))
+			})
+		})
+
+		Context("and the policy is a dry run", func() {
+			BeforeEach(func() {
+				retentionPolicy.DryRun = true
+			})
+
+			It("logs the runs it would delete without deleting them", func() {
+				retained, _ := gc.CleanupRunnableStampedObjects(ctx, allExaminedObjects, retentionPolicy, repo, nil, now)
+
+				Expect(repo.DeleteCallCount()).To(Equal(0))
+				Expect(retained).To(HaveLen(len(allExaminedObjects)))
+				Expect(out).To(Say(`dry run: not deleting runnable stamped object.*RecentFailureRetainedByPolicy2.*"reason":"exceeds failedRunTTL"`))
+			})
+		})
+	})
 })
@@ -0,0 +1,143 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runnable
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
+	"github.com/vmware-tanzu/cartographer/pkg/stamp"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+	"github.com/vmware-tanzu/cartographer/pkg/utils"
+)
+
+// runHistory builds the run history of a runnable from the objects retained
+// after garbage collection, most recent first. Records already in the
+// previous history keep their inputs digest and finish time; the inputs
+// digest of a new record is only known for the object stamped in this
+// reconcile. The finish time is read from the transition of the conditions
+// the health rule reads, or is now if the object has no such transition.
+func runHistory(
+	runnable *v1alpha1.Runnable,
+	template templates.ClusterRunTemplate,
+	mapper meta.RESTMapper,
+	retained []*stamp.ExaminedObject,
+	stampedObject *unstructured.Unstructured,
+	inputsDigest string,
+	now time.Time,
+) []v1alpha1.RunRecord {
+	previousRecords := map[string]v1alpha1.RunRecord{}
+	for _, record := range runnable.Status.RunHistory {
+		if record.StampedRef != nil && record.StampedRef.ObjectReference != nil {
+			previousRecords[record.StampedRef.Name] = record
+		}
+	}
+
+	limit := int(runnable.Spec.RetentionPolicy.MaxSuccessfulRuns + runnable.Spec.RetentionPolicy.MaxFailedRuns)
+
+	var history []v1alpha1.RunRecord
+	for _, examinedObject := range retained {
+		if limit > 0 && len(history) == limit {
+			break
+		}
+
+		obj := examinedObject.StampedObject
+		record, ok := previousRecords[obj.GetName()]
+		if !ok {
+			record = v1alpha1.RunRecord{StampedRef: stampedRef(mapper, obj)}
+			if stampedObject != nil && obj.GetName() == stampedObject.GetName() {
+				record.InputsDigest = inputsDigest
+			}
+		}
+
+		record.StartTime = obj.GetCreationTimestamp()
+		record.Health = examinedObject.Health
+
+		if record.Health == metav1.ConditionUnknown {
+			record.FinishTime = nil
+		} else if record.FinishTime == nil {
+			record.FinishTime = healthcheck.HealthTransitionTime(template.GetHealthRule(), obj)
+			if record.FinishTime == nil {
+				record.FinishTime = &metav1.Time{Time: now}
+			}
+		}
+
+		if record.Health == metav1.ConditionTrue && record.OutputsDigest == "" {
+			if outputs, err := template.GetOutputs(obj); err == nil {
+				record.OutputsDigest = digest(outputs)
+			}
+		}
+
+		history = append(history, record)
+	}
+
+	return history
+}
+
+func stampedRef(mapper meta.RESTMapper, obj *unstructured.Unstructured) *v1alpha1.StampedRef {
+	ref := &v1alpha1.StampedRef{
+		ObjectReference: &corev1.ObjectReference{
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+			APIVersion: obj.GetAPIVersion(),
+		},
+	}
+
+	if qualifiedResource, err := utils.GetQualifiedResource(mapper, obj); err == nil {
+		ref.Resource = qualifiedResource
+	}
+
+	return ref
+}
+
+func digest(value interface{}) string {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(bytes))
+}
+
+// normalized is the content of obj without its status and the metadata
+// written by the API server, so that its digest only changes when what was
+// written to the object changes.
+func normalized(obj *unstructured.Unstructured) map[string]interface{} {
+	content := map[string]interface{}{}
+	for key, value := range obj.Object {
+		if key != "metadata" && key != "status" {
+			content[key] = value
+		}
+	}
+
+	metadata := map[string]interface{}{}
+	for _, key := range []string{"name", "generateName", "namespace", "labels", "annotations"} {
+		if value, ok := obj.Object["metadata"].(map[string]interface{})[key]; ok {
+			metadata[key] = value
+		}
+	}
+	content["metadata"] = metadata
+
+	return content
+}
@@ -20,48 +20,74 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"time"
 
 	"github.com/go-logr/logr"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
+	"k8s.io/utils/clock"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions"
 	"github.com/vmware-tanzu/cartographer/pkg/errors"
+	"github.com/vmware-tanzu/cartographer/pkg/eval"
 	"github.com/vmware-tanzu/cartographer/pkg/events"
 	"github.com/vmware-tanzu/cartographer/pkg/logger"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/runnable/gc"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"github.com/vmware-tanzu/cartographer/pkg/selector"
 	"github.com/vmware-tanzu/cartographer/pkg/stamp"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
 	"github.com/vmware-tanzu/cartographer/pkg/utils"
 )
 
+// Realizer stamps the object of a runnable. Along with the stamped object and
+// outputs it returns the runnable's StampedObjectCondition, or nil if the
+// health of the stamped object is not yet known, and how soon a retained run
+// expires by a TTL of the retention policy, or 0 if none will. The run history, run request
+// and pending run in the runnable's status are updated in place.
+//
 //counterfeiter:generate . Realizer
 type Realizer interface {
-	Realize(ctx context.Context, runnable *v1alpha1.Runnable, systemRepo repository.Repository, runnableRepo repository.Repository, discoveryClient discovery.DiscoveryInterface) (*unstructured.Unstructured, templates.Outputs, error)
+	Realize(ctx context.Context, runnable *v1alpha1.Runnable, systemRepo repository.Repository, runnableRepo repository.Repository, discoveryClient discovery.DiscoveryInterface) (*unstructured.Unstructured, templates.Outputs, *metav1.Condition, time.Duration, error)
 }
 
-func NewRealizer(mapper meta.RESTMapper) Realizer {
+func NewRealizer(mapper meta.RESTMapper, stampCache templates.StampCache, prober *healthcheck.Prober, clock clock.PassiveClock) Realizer {
 	return &runnableRealizer{
-		mapper: mapper,
+		mapper:     mapper,
+		stampCache: stampCache,
+		prober:     prober,
+		clock:      clock,
 	}
 }
 
+// SelectedDigestAnnotation is set on objects stamped by a runnable whose
+// selector selects many objects, to a digest of the selected objects without
+// their status and the metadata written by the API server. A change to the
+// content of any selected object changes the annotation, which forces a new
+// immutable object to be created.
+const SelectedDigestAnnotation = "carto.run/runnable-selected-digest"
+
 type runnableRealizer struct {
-	mapper meta.RESTMapper
+	mapper     meta.RESTMapper
+	stampCache templates.StampCache
+	prober     *healthcheck.Prober
+	clock      clock.PassiveClock
 }
 
 type TemplatingContext struct {
-	Runnable *v1alpha1.Runnable     `json:"runnable"`
-	Selected map[string]interface{} `json:"selected"`
+	Runnable *v1alpha1.Runnable `json:"runnable"`
+	Selected interface{}        `json:"selected"`
 }
 
 //counterfeiter:generate k8s.io/client-go/discovery.DiscoveryInterface
-func (r *runnableRealizer) Realize(ctx context.Context, runnable *v1alpha1.Runnable, systemRepo repository.Repository, runnableRepo repository.Repository, discoveryClient discovery.DiscoveryInterface) (*unstructured.Unstructured, templates.Outputs, error) {
+func (r *runnableRealizer) Realize(ctx context.Context, runnable *v1alpha1.Runnable, systemRepo repository.Repository, runnableRepo repository.Repository, discoveryClient discovery.DiscoveryInterface) (*unstructured.Unstructured, templates.Outputs, *metav1.Condition, time.Duration, error) {
 	log := logr.FromContextOrDiscard(ctx).WithValues("template", runnable.Spec.RunTemplateRef)
 	ctx = logr.NewContext(ctx, log)
 
@@ -70,7 +96,7 @@ func (r *runnableRealizer) Realize(ctx context.Context, runnable *v1alpha1.Runna
 
 	if err != nil {
 		log.Error(err, "failed to get runnable cluster template")
-		return nil, nil, errors.RunnableGetRunTemplateError{
+		return nil, nil, nil, 0, errors.RunnableGetRunTemplateError{
 			Err:         err,
 			TemplateRef: &runnable.Spec.RunTemplateRef,
 		}
@@ -83,10 +109,18 @@ func (r *runnableRealizer) Realize(ctx context.Context, runnable *v1alpha1.Runna
 		"carto.run/run-template-name": template.GetName(),
 	}
 
+	stampLabels := labels
+	if runnable.Spec.Schedule != nil && runnable.Status.LastScheduleTime != nil {
+		stampLabels = map[string]string{ScheduleTimeLabel: scheduleTimeLabelValue(runnable.Status.LastScheduleTime.Time)}
+		for k, v := range labels {
+			stampLabels[k] = v
+		}
+	}
+
 	selected, err := r.resolveSelector(ctx, runnable.Spec.Selector, runnableRepo, discoveryClient, runnable.GetNamespace())
 	if err != nil {
 		log.Error(err, "failed to resolve selector", "selector", runnable.Spec.Selector)
-		return nil, nil, errors.RunnableResolveSelectorError{
+		return nil, nil, nil, 0, errors.RunnableResolveSelectorError{
 			Err:      err,
 			Selector: runnable.Spec.Selector,
 		}
@@ -98,44 +132,97 @@ func (r *runnableRealizer) Realize(ctx context.Context, runnable *v1alpha1.Runna
 			Runnable: runnable,
 			Selected: selected,
 		},
-		labels,
+		stampLabels,
 	)
+	if selectsMany(runnable.Spec.Selector) {
+		stampContext.Metadata.Annotations = map[string]string{SelectedDigestAnnotation: selectedDigest(selected)}
+	}
+	if runRequest, ok := runnable.GetAnnotations()[v1alpha1.RunRequestAnnotation]; ok {
+		if stampContext.Metadata.Annotations == nil {
+			stampContext.Metadata.Annotations = map[string]string{}
+		}
+		stampContext.Metadata.Annotations[v1alpha1.RunRequestAnnotation] = runRequest
+	}
 
-	stampedObject, err := stampContext.Stamp(ctx, template.GetResourceTemplate())
+	stampedObject, err := stampContext.StampCached(ctx, r.stampCache, apiRunTemplate, template.GetResourceTemplate())
 	if err != nil {
 		log.Error(err, "failed to stamp resource")
-		return nil, nil, errors.RunnableStampError{
+		return nil, nil, nil, 0, errors.RunnableStampError{
 			Err:         err,
 			TemplateRef: &runnable.Spec.RunTemplateRef,
 		}
 	}
 
-	err = runnableRepo.EnsureImmutableObjectExistsOnCluster(ctx, stampedObject, map[string]string{"carto.run/runnable-name": runnable.Name})
-	if err != nil {
-		log.Error(err, "failed to ensure object exists on cluster", "object", stampedObject)
-		return nil, nil, errors.RunnableApplyStampedObjectError{
-			Err:           err,
-			StampedObject: stampedObject,
-			TemplateRef:   &runnable.Spec.RunTemplateRef,
+	inputsDigest := digest(normalized(stampedObject))
+
+	healthRule := template.GetHealthRule()
+	ignoreObservedGeneration := template.GetResourceTemplate().IgnoreObservedGeneration
+
+	var existingObjects []*unstructured.Unstructured
+	if runnable.Spec.ConcurrencyPolicy == v1alpha1.ForbidConcurrent || runnable.Spec.ConcurrencyPolicy == v1alpha1.ReplaceConcurrent || runnable.Spec.Debounce != nil {
+		existingObjects, err = runnableRepo.ListUnstructured(ctx, stampedObject.GroupVersionKind(), stampedObject.GetNamespace(), labels)
+		if err != nil {
+			log.Error(err, "failed to list objects")
+			return nil, nil, nil, 0, errors.ListCreatedObjectsError{
+				Err:       err,
+				Namespace: stampedObject.GetNamespace(),
+				Labels:    labels,
+			}
 		}
 	}
+	inFlightObjects := r.inFlight(healthRule, ignoreObservedGeneration, existingObjects)
+
+	now := r.clock.Now()
+
+	var debouncedObject *unstructured.Unstructured
+	pendingRun := runnable.Status.PendingRun
+	runnable.Status.PendingRun = nil
+	if runnable.Spec.Debounce != nil {
+		runnable.Status.PendingRun, debouncedObject = stamp.Debounce(runnable.Spec.Debounce.Duration, stampedObject, existingObjects, pendingRun, now)
+	}
+
+	if debouncedObject != nil {
+		stampedObject = debouncedObject
+		inputsDigest = ""
+		log.Info("not creating object until inputs are stable", "run after", runnable.Status.PendingRun.RunAfter)
+	} else if runnable.Spec.ConcurrencyPolicy == v1alpha1.ForbidConcurrent && len(inFlightObjects) > 0 {
+		stampedObject = mostRecent(inFlightObjects)
+		inputsDigest = ""
+		log.Info("not creating object while another is in flight", "in flight", stampedObject)
+	} else {
+		err = runnableRepo.EnsureImmutableObjectExistsOnCluster(ctx, stampedObject, map[string]string{"carto.run/runnable-name": runnable.Name})
+		if err != nil {
+			log.Error(err, "failed to ensure object exists on cluster", "object", stampedObject)
+			return nil, nil, nil, 0, errors.RunnableApplyStampedObjectError{
+				Err:           err,
+				StampedObject: stampedObject,
+				TemplateRef:   &runnable.Spec.RunTemplateRef,
+			}
+		}
+
+		if runnable.Spec.ConcurrencyPolicy == v1alpha1.ReplaceConcurrent {
+			cancelReplaced(ctx, runnable, apiRunTemplate, stampedObject, inFlightObjects, runnableRepo)
+		}
+	}
+
+	runnable.Status.RunRequest = stampedObject.GetAnnotations()[v1alpha1.RunRequestAnnotation]
+
+	stampedCondition := r.stampedObjectCondition(apiRunTemplate, healthRule, stampedObject)
 
 	allRunnableStampedObjects, err := runnableRepo.ListUnstructured(ctx, stampedObject.GroupVersionKind(), stampedObject.GetNamespace(), labels)
 	if err != nil {
 		log.Error(err, "failed to list objects")
-		return stampedObject, nil, errors.ListCreatedObjectsError{
+		return stampedObject, nil, stampedCondition, 0, errors.ListCreatedObjectsError{
 			Err:       err,
 			Namespace: stampedObject.GetNamespace(),
 			Labels:    labels,
 		}
 	}
 
-	healthRule := &v1alpha1.HealthRule{SingleConditionType: "Succeeded"}
-
 	var examinedObjects []*stamp.ExaminedObject
 
 	for _, someStampedObject := range allRunnableStampedObjects {
-		health := healthcheck.DetermineStampedObjectHealth(healthRule, someStampedObject)
+		health := healthcheck.DetermineStampedObjectHealth(r.prober, healthRule, someStampedObject, ignoreObservedGeneration)
 
 		examinedObjects = append(examinedObjects, &stamp.ExaminedObject{
 			StampedObject: someStampedObject,
@@ -143,9 +230,12 @@ func (r *runnableRealizer) Realize(ctx context.Context, runnable *v1alpha1.Runna
 		})
 	}
 
-	gc.CleanupRunnableStampedObjects(ctx, examinedObjects, runnable.Spec.RetentionPolicy, runnableRepo)
+	retainedObjects, cleanupAfter := gc.CleanupRunnableStampedObjects(ctx, examinedObjects, runnable.Spec.RetentionPolicy, runnableRepo, stampedObject, now)
+	runnable.Status.RunHistory = runHistory(runnable, template, r.mapper, retainedObjects, stampedObject, inputsDigest, now)
 
-	outputs, outputSource, err := template.GetLatestSuccessfulOutput(allRunnableStampedObjects)
+	outputs, outputSource, err := template.GetLatestSuccessfulOutput(allRunnableStampedObjects, func(obj *unstructured.Unstructured) bool {
+		return healthcheck.DetermineStampedObjectHealth(r.prober, healthRule, obj, ignoreObservedGeneration) == metav1.ConditionTrue
+	})
 	if err != nil {
 		for _, obj := range allRunnableStampedObjects {
 			log.V(logger.DEBUG).Info("failed to retrieve output from any object", "considered", obj)
@@ -157,7 +247,7 @@ func (r *runnableRealizer) Realize(ctx context.Context, runnable *v1alpha1.Runna
 			qualifiedResource = "could not fetch - see logs for 'failed to retrieve qualified resource name'"
 		}
 
-		return stampedObject, nil, errors.RunnableRetrieveOutputError{
+		return stampedObject, nil, stampedCondition, cleanupAfter, errors.RunnableRetrieveOutputError{
 			Err:               err,
 			StampedObject:     stampedObject,
 			TemplateRef:       &runnable.Spec.RunTemplateRef,
@@ -178,10 +268,75 @@ func (r *runnableRealizer) Realize(ctx context.Context, runnable *v1alpha1.Runna
 		outputs = runnable.Status.Outputs
 	}
 
-	return stampedObject, outputs, nil
+	return stampedObject, outputs, stampedCondition, cleanupAfter, nil
+}
+
+// stampedObjectCondition reports the health of the stamped object. Without a
+// health rule on the template, the object's Succeeded condition is reported
+// as is, and nil is returned if the object has none.
+func (r *runnableRealizer) stampedObjectCondition(runTemplate *v1alpha1.ClusterRunTemplate, healthRule *v1alpha1.HealthRule, stampedObject *unstructured.Unstructured) *metav1.Condition {
+	if runTemplate.Spec.HealthRule == nil {
+		succeededCondition := utils.ExtractConditions(stampedObject).ConditionWithType("Succeeded")
+		if succeededCondition == nil {
+			return nil
+		}
+		condition := conditions.StampedObjectConditionKnown(succeededCondition)
+		return &condition
+	}
+
+	condition := conditions.StampedObjectHealthRuleCondition(healthcheck.DetermineHealthCondition(r.prober, healthRule, nil, stampedObject))
+	return &condition
 }
 
-func (r *runnableRealizer) resolveSelector(ctx context.Context, selector *v1alpha1.ResourceSelector, repository repository.Repository, discoveryClient discovery.DiscoveryInterface, namespace string) (map[string]interface{}, error) {
+// inFlight returns the objects whose health has not resolved to True or False.
+func (r *runnableRealizer) inFlight(healthRule *v1alpha1.HealthRule, ignoreObservedGeneration bool, objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	var inFlightObjects []*unstructured.Unstructured
+	for _, obj := range objs {
+		if healthcheck.DetermineStampedObjectHealth(r.prober, healthRule, obj, ignoreObservedGeneration) == metav1.ConditionUnknown {
+			inFlightObjects = append(inFlightObjects, obj)
+		}
+	}
+	return inFlightObjects
+}
+
+func mostRecent(objs []*unstructured.Unstructured) *unstructured.Unstructured {
+	latest := objs[0]
+	for _, obj := range objs[1:] {
+		if obj.GetCreationTimestamp().After(latest.GetCreationTimestamp().Time) {
+			latest = obj
+		}
+	}
+	return latest
+}
+
+// cancelReplaced cancels the in-flight objects that have been replaced by the
+// stamped object, by applying the template's cancel patch or by deleting them.
+// Failures are logged rather than returned, as the replacement already exists.
+func cancelReplaced(ctx context.Context, runnable *v1alpha1.Runnable, runTemplate *v1alpha1.ClusterRunTemplate, stampedObject *unstructured.Unstructured, inFlightObjects []*unstructured.Unstructured, repo repository.Repository) {
+	log := logr.FromContextOrDiscard(ctx)
+	rec := events.FromContextOrDie(ctx)
+
+	for _, obj := range inFlightObjects {
+		if obj.GetName() == stampedObject.GetName() {
+			continue
+		}
+
+		var err error
+		if runTemplate.Spec.CancelPatch != nil {
+			err = repo.MergePatch(ctx, obj, runTemplate.Spec.CancelPatch.Raw)
+		} else {
+			err = repo.Delete(ctx, obj)
+		}
+		if err != nil {
+			log.Error(err, "failed to cancel replaced object", "object", obj)
+			continue
+		}
+
+		rec.ResourceEventf(events.NormalType, events.RunCancelledReason, "Runnable [%s] cancelled in-flight object [%Q]", obj, runnable.Name)
+	}
+}
+
+func (r *runnableRealizer) resolveSelector(ctx context.Context, selector *v1alpha1.ResourceSelector, repository repository.Repository, discoveryClient discovery.DiscoveryInterface, namespace string) (interface{}, error) {
 	log := logr.FromContextOrDiscard(ctx)
 
 	if selector == nil {
@@ -217,6 +372,19 @@ func (r *runnableRealizer) resolveSelector(ctx context.Context, selector *v1alph
 		}
 	}
 
+	results, err = matchingFields(selector.MatchFields, results)
+	if err != nil {
+		return nil, err
+	}
+
+	if selectsMany(selector) {
+		if selector.MaxSelected != nil && int64(len(results)) > *selector.MaxSelected {
+			log.V(logger.DEBUG).Info("selector matched more than the maximum number of objects", "matched", len(results))
+			return nil, fmt.Errorf("selector matched [%d] objects, more than maxSelected [%d]", len(results), *selector.MaxSelected)
+		}
+		return selectedSet(selector.Mode, results), nil
+	}
+
 	if len(results) == 0 {
 		log.V(logger.DEBUG).Info("selector did not match any objects")
 		return nil, fmt.Errorf("selector did not match any objects")
@@ -226,3 +394,81 @@ func (r *runnableRealizer) resolveSelector(ctx context.Context, selector *v1alph
 	}
 	return results[0].Object, nil
 }
+
+func selectsMany(resourceSelector *v1alpha1.ResourceSelector) bool {
+	return resourceSelector != nil &&
+		(resourceSelector.Mode == v1alpha1.ListSelection || resourceSelector.Mode == v1alpha1.MapSelection)
+}
+
+// selectedDigest is a digest of the normalized content of the selected
+// objects, so that writes to their status or server-side metadata do not
+// force a new run.
+func selectedDigest(selected interface{}) string {
+	switch set := selected.(type) {
+	case []interface{}:
+		objs := []interface{}{}
+		for _, obj := range set {
+			objs = append(objs, normalized(&unstructured.Unstructured{Object: obj.(map[string]interface{})}))
+		}
+		return digest(objs)
+	case map[string]interface{}:
+		objs := map[string]interface{}{}
+		for name, obj := range set {
+			objs[name] = normalized(&unstructured.Unstructured{Object: obj.(map[string]interface{})})
+		}
+		return digest(objs)
+	}
+	return digest(selected)
+}
+
+func matchingFields(reqs []v1alpha1.FieldSelectorRequirement, objs []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	if len(reqs) == 0 {
+		return objs, nil
+	}
+
+	var matched []*unstructured.Unstructured
+	for _, obj := range objs {
+		matchesAll := true
+		for _, req := range reqs {
+			matches, err := selector.Matches(req, obj.UnstructuredContent())
+			if err != nil {
+				if _, ok := err.(eval.JsonPathDoesNotExistError); !ok {
+					return nil, fmt.Errorf("failed to evaluate field selector [%s]: %w", req.Key, err)
+				}
+			}
+			if !matches {
+				matchesAll = false
+				break
+			}
+		}
+		if matchesAll {
+			matched = append(matched, obj)
+		}
+	}
+	return matched, nil
+}
+
+// selectedSet makes the selected objects available to the template as a list
+// sorted by namespace and name, or as a map keyed by name.
+func selectedSet(mode v1alpha1.SelectionMode, objs []*unstructured.Unstructured) interface{} {
+	sort.SliceStable(objs, func(i, j int) bool {
+		if objs[i].GetNamespace() != objs[j].GetNamespace() {
+			return objs[i].GetNamespace() < objs[j].GetNamespace()
+		}
+		return objs[i].GetName() < objs[j].GetName()
+	})
+
+	if mode == v1alpha1.MapSelection {
+		byName := map[string]interface{}{}
+		for _, obj := range objs {
+			byName[obj.GetName()] = obj.Object
+		}
+		return byName
+	}
+
+	list := []interface{}{}
+	for _, obj := range objs {
+		list = append(list, obj.Object)
+	}
+	return list
+}
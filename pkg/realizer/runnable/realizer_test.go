@@ -26,6 +26,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,6 +34,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/cartographer/pkg/events"
@@ -41,6 +44,7 @@ import (
 	realizer "github.com/vmware-tanzu/cartographer/pkg/realizer/runnable"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/runnable/runnablefakes"
 	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/stamp"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
 	"github.com/vmware-tanzu/cartographer/pkg/utils"
 	"github.com/vmware-tanzu/cartographer/tests/resources"
@@ -68,7 +72,7 @@ var _ = Describe("Realizer", func() {
 		runnableRepo = &repositoryfakes.FakeRepository{}
 		discoveryClient = &runnablefakes.FakeDiscoveryInterface{}
 		fakeMapper = &realizerfakes.FakeRESTMapper{}
-		rlzr = realizer.NewRealizer(fakeMapper)
+		rlzr = realizer.NewRealizer(fakeMapper, nil, nil, clock.RealClock{})
 
 		runnable = &v1alpha1.Runnable{
 			ObjectMeta: metav1.ObjectMeta{
@@ -85,6 +89,8 @@ var _ = Describe("Realizer", func() {
 	})
 
 	Context("with a valid ClusterRunTemplate", func() {
+		var templateAPI *v1alpha1.ClusterRunTemplate
+
 		BeforeEach(func() {
 			testObj := resources.TestObj{
 				TypeMeta: metav1.TypeMeta{
@@ -112,7 +118,7 @@ var _ = Describe("Realizer", func() {
 			dbytes, err := json.Marshal(testObj)
 			Expect(err).ToNot(HaveOccurred())
 
-			var templateAPI = &v1alpha1.ClusterRunTemplate{
+			templateAPI = &v1alpha1.ClusterRunTemplate{
 				Spec: v1alpha1.RunTemplateSpec{
 					Outputs: map[string]string{
 						"myout": "spec.foo",
@@ -133,6 +139,14 @@ var _ = Describe("Realizer", func() {
 
 			runnableRepo.ListUnstructuredReturns([]*unstructured.Unstructured{createdUnstructured}, nil)
 
+			fakeMapper.RESTMappingReturns(&meta.RESTMapping{
+				Resource: schema.GroupVersionResource{
+					Group:    "test.run",
+					Version:  "v1alpha1",
+					Resource: "testobjs",
+				},
+			}, nil)
+
 			discoveryClient.ServerResourcesForGroupVersionReturns(&metav1.APIResourceList{
 				APIResources: []metav1.APIResource{
 					{
@@ -144,7 +158,7 @@ var _ = Describe("Realizer", func() {
 		})
 
 		It("stamps out the resource from the template", func() {
-			_, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+			_, _, _, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 
 			Expect(systemRepo.GetRunTemplateCallCount()).To(Equal(1))
 			_, actualTemplate := systemRepo.GetRunTemplateArgsForCall(0)
@@ -175,12 +189,515 @@ var _ = Describe("Realizer", func() {
 		})
 
 		It("does not return an error", func() {
-			_, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+			_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("reports the Succeeded condition of the stamped object", func() {
+			_, _, stampedCondition, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stampedCondition).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(v1alpha1.StampedObjectCondition),
+				"Status": Equal(metav1.ConditionTrue),
+				"Reason": Equal(v1alpha1.SucceededStampedObjectConditionReason),
+			})))
+		})
+
+		Context("when the run template has a health rule", func() {
+			BeforeEach(func() {
+				templateAPI.Spec.HealthRule = &v1alpha1.HealthRule{
+					MultiMatch: &v1alpha1.MultiMatchHealthRule{
+						Healthy: v1alpha1.HealthMatchRule{
+							MatchFields: []v1alpha1.HealthMatchFieldSelectorRequirement{{
+								FieldSelectorRequirement: v1alpha1.FieldSelectorRequirement{
+									Key:      "spec.foo",
+									Operator: v1alpha1.FieldSelectorOpIn,
+									Values:   []string{"is a string"},
+								},
+								MessagePath: "spec.foo",
+							}},
+						},
+						Unhealthy: v1alpha1.HealthMatchRule{
+							MatchFields: []v1alpha1.HealthMatchFieldSelectorRequirement{{
+								FieldSelectorRequirement: v1alpha1.FieldSelectorRequirement{
+									Key:      "spec.foo",
+									Operator: v1alpha1.FieldSelectorOpIn,
+									Values:   []string{"failed"},
+								},
+							}},
+						},
+					},
+				}
+			})
+
+			It("reports the health of the stamped object using the rule", func() {
+				_, _, stampedCondition, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stampedCondition).To(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":   Equal(v1alpha1.StampedObjectCondition),
+					"Status": Equal(metav1.ConditionTrue),
+					"Reason": Equal(v1alpha1.HealthRuleStampedObjectConditionReason),
+				})))
+			})
+
+			It("reads outputs from objects that are healthy according to the rule", func() {
+				_, outputs, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(outputs["myout"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`"is a string"`)}))
+			})
+
+			Context("and the stamped object does not satisfy the rule", func() {
+				BeforeEach(func() {
+					templateAPI.Spec.HealthRule.MultiMatch.Healthy.MatchFields[0].Values = []string{"something else"}
+				})
+
+				It("does not read outputs from it, even though it has a Succeeded condition", func() {
+					_, outputs, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(outputs).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("when the runnable requests a run", func() {
+			BeforeEach(func() {
+				runnable.Annotations = map[string]string{v1alpha1.RunRequestAnnotation: "2022-03-05T02:00:00Z"}
+			})
+
+			It("annotates the stamped object with the run request", func() {
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, stamped, _ := runnableRepo.EnsureImmutableObjectExistsOnClusterArgsForCall(0)
+				Expect(stamped.GetAnnotations()).To(HaveKeyWithValue(v1alpha1.RunRequestAnnotation, "2022-03-05T02:00:00Z"))
+			})
+
+			It("records the run request in the runnable's status", func() {
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(runnable.Status.RunRequest).To(Equal("2022-03-05T02:00:00Z"))
+			})
+
+			Context("and a new run is requested", func() {
+				It("stamps an object that differs only in its run request", func() {
+					_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+
+					runnable.Annotations[v1alpha1.RunRequestAnnotation] = "2022-03-05T03:00:00Z"
+					_, _, _, _, err = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, first, _ := runnableRepo.EnsureImmutableObjectExistsOnClusterArgsForCall(0)
+					_, second, _ := runnableRepo.EnsureImmutableObjectExistsOnClusterArgsForCall(1)
+					Expect(second.GetAnnotations()).To(HaveKeyWithValue(v1alpha1.RunRequestAnnotation, "2022-03-05T03:00:00Z"))
+
+					second.SetAnnotations(first.GetAnnotations())
+					Expect(second.Object).To(Equal(first.Object))
+					Expect(runnable.Status.RunRequest).To(Equal("2022-03-05T03:00:00Z"))
+				})
+			})
+		})
+
+		Context("run history", func() {
+			var now time.Time
+
+			BeforeEach(func() {
+				now = time.Date(2022, 3, 5, 2, 0, 0, 0, time.UTC)
+				rlzr = realizer.NewRealizer(fakeMapper, nil, nil, clocktesting.NewFakePassiveClock(now))
+				runnable.Spec.Inputs = map[string]apiextensionsv1.JSON{"revision": {Raw: []byte(`"abc123"`)}}
+				runnable.Spec.RetentionPolicy = v1alpha1.RetentionPolicy{MaxFailedRuns: 1, MaxSuccessfulRuns: 1}
+				runnableRepo.EnsureImmutableObjectExistsOnClusterStub = func(ctx context.Context, obj *unstructured.Unstructured, labels map[string]string) error {
+					obj.SetName("my-stamped-resource-abcde")
+					createdUnstructured.Object = obj.Object
+					return nil
+				}
+			})
+
+			It("records the stamped object", func() {
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(runnable.Status.RunHistory).To(HaveLen(1))
+				record := runnable.Status.RunHistory[0]
+				Expect(record.StampedRef.Name).To(Equal("my-stamped-resource-abcde"))
+				Expect(record.StampedRef.Kind).To(Equal("TestObj"))
+				Expect(record.StampedRef.Resource).To(Equal("testobjs.test.run"))
+				Expect(record.InputsDigest).To(HavePrefix("sha256:"))
+				Expect(record.Health).To(Equal(metav1.ConditionTrue))
+				Expect(record.FinishTime).NotTo(BeNil())
+				Expect(record.OutputsDigest).To(HavePrefix("sha256:"))
+			})
+
+			It("digests the stamped content, not only the inputs", func() {
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+				firstDigest := runnable.Status.RunHistory[0].InputsDigest
+
+				runnable.Status.RunHistory = nil
+				runnable.Annotations = map[string]string{v1alpha1.RunRequestAnnotation: "2022-03-05T03:00:00Z"}
+				_, _, _, _, err = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(runnable.Status.RunHistory[0].InputsDigest).To(HavePrefix("sha256:"))
+				Expect(runnable.Status.RunHistory[0].InputsDigest).NotTo(Equal(firstDigest))
+			})
+
+			It("keeps what was recorded for objects already in the history", func() {
+				finishTime := metav1.NewTime(time.Date(2022, 3, 4, 2, 0, 0, 0, time.UTC))
+				runnable.Status.RunHistory = []v1alpha1.RunRecord{{
+					StampedRef:    &v1alpha1.StampedRef{ObjectReference: &corev1.ObjectReference{Name: "my-stamped-resource-abcde"}},
+					InputsDigest:  "sha256:earlier-inputs",
+					FinishTime:    &finishTime,
+					OutputsDigest: "sha256:earlier-outputs",
+				}}
+
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(runnable.Status.RunHistory).To(HaveLen(1))
+				record := runnable.Status.RunHistory[0]
+				Expect(record.InputsDigest).To(Equal("sha256:earlier-inputs"))
+				Expect(record.FinishTime).To(Equal(&finishTime))
+				Expect(record.OutputsDigest).To(Equal("sha256:earlier-outputs"))
+				Expect(record.Health).To(Equal(metav1.ConditionTrue))
+			})
+
+			Context("with objects from earlier runs", func() {
+				var earlierSuccess, earlierFailure, running *unstructured.Unstructured
+
+				makeRun := func(name string, status string, created time.Time) *unstructured.Unstructured {
+					obj := &unstructured.Unstructured{}
+					obj.SetAPIVersion("test.run/v1alpha1")
+					obj.SetKind("TestObj")
+					obj.SetName(name)
+					obj.SetCreationTimestamp(metav1.NewTime(created))
+					Expect(unstructured.SetNestedField(obj.Object, name, "spec", "foo")).To(Succeed())
+					Expect(unstructured.SetNestedSlice(obj.Object, []interface{}{
+						map[string]interface{}{"type": "Succeeded", "status": status},
+					}, "status", "conditions")).To(Succeed())
+					return obj
+				}
+
+				BeforeEach(func() {
+					earlierSuccess = makeRun("earlier-success", "True", time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC))
+					earlierFailure = makeRun("earlier-failure", "False", time.Date(2022, 3, 2, 0, 0, 0, 0, time.UTC))
+					running = makeRun("running", "Unknown", time.Date(2022, 3, 3, 0, 0, 0, 0, time.UTC))
+					runnableRepo.ListUnstructuredReturns([]*unstructured.Unstructured{earlierSuccess, earlierFailure, running}, nil)
+				})
+
+				It("records the retained objects most recent first, without inputs digests it cannot know", func() {
+					_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(runnable.Status.RunHistory).To(HaveLen(2))
+					Expect(runnable.Status.RunHistory[0].StampedRef.Name).To(Equal("running"))
+					Expect(runnable.Status.RunHistory[0].Health).To(Equal(metav1.ConditionUnknown))
+					Expect(runnable.Status.RunHistory[0].FinishTime).To(BeNil())
+					Expect(runnable.Status.RunHistory[0].InputsDigest).To(BeEmpty())
+					Expect(runnable.Status.RunHistory[1].StampedRef.Name).To(Equal("earlier-failure"))
+					Expect(runnable.Status.RunHistory[1].Health).To(Equal(metav1.ConditionFalse))
+					Expect(runnable.Status.RunHistory[1].OutputsDigest).To(BeEmpty())
+					Expect(runnable.Status.RunHistory[1].FinishTime).To(Equal(&metav1.Time{Time: now}))
+				})
+
+				It("records when the health condition transitioned as the finish time, if it did", func() {
+					transitioned := time.Date(2022, 3, 2, 1, 0, 0, 0, time.UTC)
+					Expect(unstructured.SetNestedSlice(earlierFailure.Object, []interface{}{
+						map[string]interface{}{"type": "Succeeded", "status": "False", "lastTransitionTime": transitioned.Format(time.RFC3339)},
+					}, "status", "conditions")).To(Succeed())
+
+					_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(runnable.Status.RunHistory[1].StampedRef.Name).To(Equal("earlier-failure"))
+					Expect(runnable.Status.RunHistory[1].FinishTime.Time).To(BeTemporally("==", transitioned))
+				})
+
+				It("does not garbage collect the object it stamped, even once its TTL has passed", func() {
+					runnable.Spec.RetentionPolicy.UnresolvedRunTTL = &metav1.Duration{Duration: time.Hour}
+					running.SetNamespace(runnable.Namespace)
+					runnableRepo.EnsureImmutableObjectExistsOnClusterStub = func(ctx context.Context, obj *unstructured.Unstructured, labels map[string]string) error {
+						obj.SetName("running")
+						return nil
+					}
+
+					_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(runnableRepo.DeleteCallCount()).To(Equal(0))
+					Expect(runnable.Status.RunHistory[0].StampedRef.Name).To(Equal("running"))
+				})
+
+				It("returns how soon a retained run expires", func() {
+					runnable.Spec.RetentionPolicy.FailedRunTTL = &metav1.Duration{Duration: 96 * time.Hour}
+
+					_, _, _, cleanupAfter, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(cleanupAfter).To(Equal(22*time.Hour + time.Second))
+				})
+
+				It("does not record objects that are garbage collected", func() {
+					runnable.Spec.RetentionPolicy = v1alpha1.RetentionPolicy{MaxFailedRuns: 1, MaxSuccessfulRuns: 10}
+					runnableRepo.ListUnstructuredReturns([]*unstructured.Unstructured{earlierSuccess, earlierFailure, makeRun("oldest-failure", "False", time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC))}, nil)
+
+					_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+
+					var names []string
+					for _, record := range runnable.Status.RunHistory {
+						names = append(names, record.StampedRef.Name)
+					}
+					Expect(names).To(Equal([]string{"earlier-failure", "earlier-success"}))
+				})
+			})
+		})
+
+		Context("when the runnable has a concurrency policy", func() {
+			var inFlightObject *unstructured.Unstructured
+
+			BeforeEach(func() {
+				inFlightObject = &unstructured.Unstructured{}
+				inFlightObject.SetAPIVersion("test.run/v1alpha1")
+				inFlightObject.SetKind("TestObj")
+				inFlightObject.SetName("earlier-run")
+				inFlightObject.SetNamespace("my-important-ns")
+				Expect(unstructured.SetNestedSlice(inFlightObject.Object, []interface{}{
+					map[string]interface{}{"type": "Succeeded", "status": "Unknown"},
+				}, "status", "conditions")).To(Succeed())
+			})
+
+			Context("of Forbid", func() {
+				BeforeEach(func() {
+					runnable.Spec.ConcurrencyPolicy = v1alpha1.ForbidConcurrent
+				})
+
+				Context("and an earlier run is in flight", func() {
+					BeforeEach(func() {
+						runnableRepo.ListUnstructuredReturns([]*unstructured.Unstructured{inFlightObject}, nil)
+					})
+
+					It("does not create a new object", func() {
+						_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(runnableRepo.EnsureImmutableObjectExistsOnClusterCallCount()).To(Equal(0))
+					})
+
+					It("returns the in-flight object", func() {
+						stampedObject, _, _, _, _ := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						Expect(stampedObject).To(Equal(inFlightObject))
+					})
+				})
+
+				Context("and no run is in flight", func() {
+					It("creates the object", func() {
+						Expect(unstructured.SetNestedSlice(inFlightObject.Object, []interface{}{
+							map[string]interface{}{"type": "Succeeded", "status": "True"},
+						}, "status", "conditions")).To(Succeed())
+						runnableRepo.ListUnstructuredReturns([]*unstructured.Unstructured{inFlightObject}, nil)
+
+						_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(runnableRepo.EnsureImmutableObjectExistsOnClusterCallCount()).To(Equal(1))
+					})
+				})
+
+				Context("and an earlier run has succeeded for an earlier generation", func() {
+					It("considers the run in flight", func() {
+						inFlightObject.SetGeneration(2)
+						Expect(unstructured.SetNestedSlice(inFlightObject.Object, []interface{}{
+							map[string]interface{}{"type": "Succeeded", "status": "True", "observedGeneration": int64(1)},
+						}, "status", "conditions")).To(Succeed())
+						runnableRepo.ListUnstructuredReturns([]*unstructured.Unstructured{inFlightObject}, nil)
+
+						_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(runnableRepo.EnsureImmutableObjectExistsOnClusterCallCount()).To(Equal(0))
+					})
+
+					Context("and the run template ignores observed generation", func() {
+						It("considers the run complete and creates the object", func() {
+							templateAPI.Spec.IgnoreObservedGeneration = true
+							inFlightObject.SetGeneration(2)
+							Expect(unstructured.SetNestedSlice(inFlightObject.Object, []interface{}{
+								map[string]interface{}{"type": "Succeeded", "status": "True", "observedGeneration": int64(1)},
+							}, "status", "conditions")).To(Succeed())
+							runnableRepo.ListUnstructuredReturns([]*unstructured.Unstructured{inFlightObject}, nil)
+
+							_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(runnableRepo.EnsureImmutableObjectExistsOnClusterCallCount()).To(Equal(1))
+						})
+					})
+				})
+
+				Context("and listing the existing runs fails", func() {
+					It("returns ListCreatedObjectsError", func() {
+						runnableRepo.ListUnstructuredReturns(nil, errors.New("some list error"))
+						_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						Expect(reflect.TypeOf(err).String()).To(Equal("errors.ListCreatedObjectsError"))
+						Expect(runnableRepo.EnsureImmutableObjectExistsOnClusterCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("of Replace", func() {
+				BeforeEach(func() {
+					runnable.Spec.ConcurrencyPolicy = v1alpha1.ReplaceConcurrent
+					runnableRepo.ListUnstructuredReturns([]*unstructured.Unstructured{inFlightObject}, nil)
+				})
+
+				It("creates the new object", func() {
+					_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(runnableRepo.EnsureImmutableObjectExistsOnClusterCallCount()).To(Equal(1))
+				})
+
+				Context("and the run template has no cancel patch", func() {
+					It("deletes the in-flight object", func() {
+						_, _, _, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						Expect(runnableRepo.DeleteCallCount()).To(Equal(1))
+						_, deleted := runnableRepo.DeleteArgsForCall(0)
+						Expect(deleted).To(Equal(inFlightObject))
+						Expect(runnableRepo.MergePatchCallCount()).To(Equal(0))
+					})
+
+					It("emits a RunCancelled event", func() {
+						_, _, _, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						Expect(rec.ResourceEventfCallCount()).To(BeNumerically(">=", 1))
+						evType, reason, messageFmt, resourceObj, fmtArgs := rec.ResourceEventfArgsForCall(0)
+						Expect(evType).To(Equal("Normal"))
+						Expect(reason).To(Equal(events.RunCancelledReason))
+						Expect(messageFmt).To(Equal("Runnable [%s] cancelled in-flight object [%Q]"))
+						Expect(resourceObj).To(Equal(inFlightObject))
+						Expect(fmtArgs).To(Equal([]interface{}{"my-runnable"}))
+					})
+				})
+
+				Context("and the run template has a cancel patch", func() {
+					BeforeEach(func() {
+						templateAPI.Spec.CancelPatch = &runtime.RawExtension{Raw: []byte(`{"spec":{"status":"Cancelled"}}`)}
+					})
+
+					It("patches the in-flight object", func() {
+						_, _, _, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						Expect(runnableRepo.MergePatchCallCount()).To(Equal(1))
+						_, patched, patch := runnableRepo.MergePatchArgsForCall(0)
+						Expect(patched).To(Equal(inFlightObject))
+						Expect(string(patch)).To(Equal(`{"spec":{"status":"Cancelled"}}`))
+						Expect(runnableRepo.DeleteCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("and cancelling fails", func() {
+					It("logs the failure and does not return an error", func() {
+						runnableRepo.DeleteReturns(errors.New("some delete error"))
+						_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						Expect(err).NotTo(HaveOccurred())
+					})
+				})
+
+				Context("and the stamped object is the in-flight object", func() {
+					It("does not cancel it", func() {
+						runnableRepo.EnsureImmutableObjectExistsOnClusterStub = func(ctx context.Context, obj *unstructured.Unstructured, labels map[string]string) error {
+							*obj = *inFlightObject
+							return nil
+						}
+						_, _, _, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						Expect(runnableRepo.DeleteCallCount()).To(Equal(0))
+					})
+				})
+			})
+		})
+
+		Context("when the runnable has a debounce window", func() {
+			var earlierRun *unstructured.Unstructured
+
+			BeforeEach(func() {
+				runnable.Spec.Debounce = &metav1.Duration{Duration: time.Minute}
+
+				earlierRun = &unstructured.Unstructured{}
+				earlierRun.SetAPIVersion("test.run/v1alpha1")
+				earlierRun.SetKind("TestObj")
+				earlierRun.SetName("earlier-run")
+				earlierRun.SetNamespace("my-important-ns")
+				earlierRun.SetAnnotations(map[string]string{stamp.StampDigestAnnotation: "sha256:earlier-inputs"})
+				Expect(unstructured.SetNestedField(earlierRun.Object, "from the earlier run", "spec", "foo")).To(Succeed())
+				runnableRepo.ListUnstructuredReturns([]*unstructured.Unstructured{earlierRun}, nil)
+			})
+
+			Context("and the inputs have changed", func() {
+				It("does not create a new object", func() {
+					_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(runnableRepo.EnsureImmutableObjectExistsOnClusterCallCount()).To(Equal(0))
+				})
+
+				It("returns the earlier object", func() {
+					stampedObject, _, _, _, _ := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(stampedObject).To(Equal(earlierRun))
+				})
+
+				It("records a pending run after the debounce window", func() {
+					before := time.Now()
+					_, _, _, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+
+					Expect(runnable.Status.PendingRun).NotTo(BeNil())
+					Expect(runnable.Status.PendingRun.InputsDigest).To(HavePrefix("sha256:"))
+					Expect(runnable.Status.PendingRun.RunAfter.Time).To(BeTemporally(">=", before.Add(time.Minute)))
+				})
+
+				Context("and the pending run is due", func() {
+					It("creates the object and clears the pending run", func() {
+						_, _, _, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						runnable.Status.PendingRun.RunAfter = metav1.NewTime(time.Now().Add(-time.Second))
+
+						_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(runnableRepo.EnsureImmutableObjectExistsOnClusterCallCount()).To(Equal(1))
+						Expect(runnable.Status.PendingRun).To(BeNil())
+
+						_, stamped, _ := runnableRepo.EnsureImmutableObjectExistsOnClusterArgsForCall(0)
+						Expect(stamped.GetAnnotations()).To(HaveKeyWithValue(stamp.StampDigestAnnotation, HavePrefix("sha256:")))
+					})
+				})
+			})
+
+			Context("and no object has been stamped before", func() {
+				It("creates the object without waiting", func() {
+					runnableRepo.ListUnstructuredReturns([]*unstructured.Unstructured{}, nil)
+
+					_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(runnableRepo.EnsureImmutableObjectExistsOnClusterCallCount()).To(Equal(1))
+					Expect(runnable.Status.PendingRun).To(BeNil())
+				})
+			})
+		})
+
+		Context("when the runnable is scheduled", func() {
+			BeforeEach(func() {
+				runnable.Spec.Schedule = &v1alpha1.RunnableSchedule{Cron: "0 2 * * *"}
+				runnable.Status.LastScheduleTime = &metav1.Time{Time: time.Date(2022, 3, 4, 2, 0, 0, 0, time.UTC)}
+			})
+
+			It("labels the stamped object with the schedule tick", func() {
+				stampedObject, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stampedObject.GetLabels()).To(HaveKeyWithValue(realizer.ScheduleTimeLabel, "1646359200"))
+			})
+
+			It("lists all runs of the runnable regardless of their tick", func() {
+				_, _, _, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(runnableRepo.ListUnstructuredCallCount()).To(Equal(1))
+				_, _, _, labels := runnableRepo.ListUnstructuredArgsForCall(0)
+				Expect(labels).NotTo(HaveKey(realizer.ScheduleTimeLabel))
+			})
+		})
+
 		It("emits a ResourceOutputChangedReason event when the output changes", func() {
-			stampedObject, _, _ := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+			stampedObject, _, _, _, _ := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 			Expect(rec.ResourceEventfCallCount()).To(Equal(1))
 			evType, reason, messageFmt, resourceObj, fmtArgs := rec.ResourceEventfArgsForCall(0)
 			Expect(evType).To(Equal("Normal"))
@@ -192,18 +709,18 @@ var _ = Describe("Realizer", func() {
 
 		It("does not emit any event when the output has not changed", func() {
 			runnable.Status.Outputs = templates.Outputs{"myout": apiextensionsv1.JSON{Raw: []byte(`"is a string"`)}}
-			_, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+			_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(rec.Invocations()).To(BeEmpty())
 		})
 
 		It("returns the outputs", func() {
-			_, outputs, _ := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+			_, outputs, _, _, _ := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 			Expect(outputs["myout"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`"is a string"`)}))
 		})
 
 		It("returns the stampedObject", func() {
-			stampedObject, _, _ := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+			stampedObject, _, _, _, _ := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 			Expect(stampedObject.Object["spec"]).To(Equal(map[string]interface{}{
 				"foo":   "is a string",
 				"value": nil,
@@ -284,7 +801,7 @@ var _ = Describe("Realizer", func() {
 				return nil
 			}
 
-			_, _, err = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+			_, _, _, _, err = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(runnableRepo.DeleteCallCount()).To(Equal(2))
@@ -301,7 +818,7 @@ var _ = Describe("Realizer", func() {
 			})
 
 			It("returns ApplyStampedObjectError", func() {
-				_, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("some bad error"))
 				Expect(reflect.TypeOf(err).String()).To(Equal("errors.RunnableApplyStampedObjectError"))
@@ -314,7 +831,7 @@ var _ = Describe("Realizer", func() {
 			})
 
 			It("returns ListCreatedObjectsError", func() {
-				_, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("some list error"))
 				Expect(reflect.TypeOf(err).String()).To(Equal("errors.ListCreatedObjectsError"))
@@ -346,7 +863,7 @@ var _ = Describe("Realizer", func() {
 				})
 
 				It("makes the selected object available in the templating context", func() {
-					_, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					_, _, _, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 
 					Expect(runnableRepo.ListUnstructuredCallCount()).To(Equal(2))
 					_, gvk, namespace, labels := runnableRepo.ListUnstructuredArgsForCall(0)
@@ -391,7 +908,7 @@ var _ = Describe("Realizer", func() {
 				})
 
 				It("makes the selected object available in the templating context", func() {
-					_, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					_, _, _, _, _ = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 
 					Expect(runnableRepo.ListUnstructuredCallCount()).To(Equal(2))
 					_, gvk, namespace, labels := runnableRepo.ListUnstructuredArgsForCall(0)
@@ -438,7 +955,7 @@ var _ = Describe("Realizer", func() {
 			})
 
 			It("returns ResolveSelectorError", func() {
-				_, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring(`unable to resolve selector [map[expected-label:expected-value]], apiVersion [apiversion-to-be-selected], kind [kind-to-be-selected]: selector matched multiple objects`))
 				Expect(reflect.TypeOf(err).String()).To(Equal("errors.RunnableResolveSelectorError"))
@@ -458,7 +975,7 @@ var _ = Describe("Realizer", func() {
 			})
 
 			It("returns ResolveSelectorError", func() {
-				_, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring(`unable to resolve selector [map[expected-label:expected-value]], apiVersion [apiversion-to-be-selected], kind [kind-to-be-selected]: selector did not match any objects`))
 				Expect(reflect.TypeOf(err).String()).To(Equal("errors.RunnableResolveSelectorError"))
@@ -478,12 +995,201 @@ var _ = Describe("Realizer", func() {
 			})
 
 			It("returns ResolveSelectorError", func() {
-				_, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring(`unable to resolve selector [map[expected-label:expected-value]], apiVersion [apiversion-to-be-selected], kind [kind-to-be-selected]: failed to list objects in namespace matching selector [map[expected-label:expected-value]]: listing unstructured is hard`))
 				Expect(reflect.TypeOf(err).String()).To(Equal("errors.RunnableResolveSelectorError"))
 			})
 		})
+
+		Context("runnable selector selects many objects", func() {
+			var fixtures []*unstructured.Unstructured
+
+			fixture := func(namespace, name, suite string) *unstructured.Unstructured {
+				obj := &unstructured.Unstructured{}
+				obj.SetNamespace(namespace)
+				obj.SetName(name)
+				Expect(unstructured.SetNestedField(obj.Object, suite, "data", "suite")).To(Succeed())
+				return obj
+			}
+
+			selectedValue := func() interface{} {
+				Expect(runnableRepo.EnsureImmutableObjectExistsOnClusterCallCount()).To(Equal(1))
+				_, stamped, _ := runnableRepo.EnsureImmutableObjectExistsOnClusterArgsForCall(0)
+				value, _, err := unstructured.NestedFieldCopy(stamped.Object, "spec", "value")
+				Expect(err).NotTo(HaveOccurred())
+				return value
+			}
+
+			BeforeEach(func() {
+				runnable.Spec.Selector = &v1alpha1.ResourceSelector{
+					Resource: v1alpha1.ResourceType{
+						APIVersion: "apiversion-to-be-selected",
+						Kind:       "kind-to-be-selected",
+					},
+					MatchingLabels: map[string]string{"expected-label": "expected-value"},
+					Mode:           v1alpha1.ListSelection,
+				}
+
+				discoveryClient.ServerResourcesForGroupVersionReturns(&metav1.APIResourceList{
+					APIResources: []metav1.APIResource{{Kind: "kind-to-be-selected", Namespaced: true}},
+				}, nil)
+
+				fixtures = []*unstructured.Unstructured{
+					fixture("my-important-ns", "fixture-c", "smoke"),
+					fixture("my-important-ns", "fixture-a", "integration"),
+					fixture("my-important-ns", "fixture-b", "smoke"),
+				}
+				runnableRepo.ListUnstructuredReturnsOnCall(0, fixtures, nil)
+			})
+
+			It("makes the selected objects available as a list sorted by name", func() {
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(selectedValue()).To(Equal([]interface{}{
+					fixture("my-important-ns", "fixture-a", "integration").Object,
+					fixture("my-important-ns", "fixture-b", "smoke").Object,
+					fixture("my-important-ns", "fixture-c", "smoke").Object,
+				}))
+			})
+
+			It("annotates the stamped object with a digest of the selected objects", func() {
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, stamped, _ := runnableRepo.EnsureImmutableObjectExistsOnClusterArgsForCall(0)
+				firstDigest := stamped.GetAnnotations()[realizer.SelectedDigestAnnotation]
+				Expect(firstDigest).To(HavePrefix("sha256:"))
+
+				fixtures[0] = fixture("my-important-ns", "fixture-c", "changed")
+				runnableRepo.ListUnstructuredReturnsOnCall(2, fixtures, nil)
+
+				_, _, _, _, err = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, stamped, _ = runnableRepo.EnsureImmutableObjectExistsOnClusterArgsForCall(1)
+				Expect(stamped.GetAnnotations()[realizer.SelectedDigestAnnotation]).To(HavePrefix("sha256:"))
+				Expect(stamped.GetAnnotations()[realizer.SelectedDigestAnnotation]).NotTo(Equal(firstDigest))
+			})
+
+			It("ignores the status and server-side metadata of the selected objects in the digest", func() {
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, stamped, _ := runnableRepo.EnsureImmutableObjectExistsOnClusterArgsForCall(0)
+				firstDigest := stamped.GetAnnotations()[realizer.SelectedDigestAnnotation]
+
+				written := fixture("my-important-ns", "fixture-c", "smoke")
+				written.SetResourceVersion("12345")
+				written.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+				Expect(unstructured.SetNestedField(written.Object, "Ready", "status", "phase")).To(Succeed())
+				runnableRepo.ListUnstructuredReturnsOnCall(2, []*unstructured.Unstructured{
+					written,
+					fixture("my-important-ns", "fixture-a", "integration"),
+					fixture("my-important-ns", "fixture-b", "smoke"),
+				}, nil)
+
+				_, _, _, _, err = rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, stamped, _ = runnableRepo.EnsureImmutableObjectExistsOnClusterArgsForCall(1)
+				Expect(stamped.GetAnnotations()[realizer.SelectedDigestAnnotation]).To(Equal(firstDigest))
+			})
+
+			Context("in map mode", func() {
+				BeforeEach(func() {
+					runnable.Spec.Selector.Mode = v1alpha1.MapSelection
+				})
+
+				It("makes the selected objects available keyed by name", func() {
+					_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(selectedValue()).To(Equal(map[string]interface{}{
+						"fixture-a": fixture("my-important-ns", "fixture-a", "integration").Object,
+						"fixture-b": fixture("my-important-ns", "fixture-b", "smoke").Object,
+						"fixture-c": fixture("my-important-ns", "fixture-c", "smoke").Object,
+					}))
+				})
+			})
+
+			Context("with field selectors", func() {
+				BeforeEach(func() {
+					runnable.Spec.Selector.MatchFields = []v1alpha1.FieldSelectorRequirement{{
+						Key:      "data.suite",
+						Operator: v1alpha1.FieldSelectorOpIn,
+						Values:   []string{"smoke"},
+					}}
+				})
+
+				It("only selects the objects matching the field selectors", func() {
+					_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(selectedValue()).To(Equal([]interface{}{
+						fixture("my-important-ns", "fixture-b", "smoke").Object,
+						fixture("my-important-ns", "fixture-c", "smoke").Object,
+					}))
+				})
+
+				Context("and no object matches", func() {
+					BeforeEach(func() {
+						runnable.Spec.Selector.MatchFields[0].Values = []string{"performance"}
+					})
+
+					It("makes an empty list available", func() {
+						_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(selectedValue()).To(Equal([]interface{}{}))
+					})
+				})
+			})
+
+			Context("with more matches than maxSelected", func() {
+				BeforeEach(func() {
+					maxSelected := int64(2)
+					runnable.Spec.Selector.MaxSelected = &maxSelected
+				})
+
+				It("returns ResolveSelectorError", func() {
+					_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("selector matched [3] objects, more than maxSelected [2]"))
+					Expect(reflect.TypeOf(err).String()).To(Equal("errors.RunnableResolveSelectorError"))
+				})
+			})
+		})
+
+		Context("runnable selector in single mode with field selectors", func() {
+			BeforeEach(func() {
+				runnable.Spec.Selector = &v1alpha1.ResourceSelector{
+					Resource: v1alpha1.ResourceType{
+						APIVersion: "apiversion-to-be-selected",
+						Kind:       "kind-to-be-selected",
+					},
+					MatchingLabels: map[string]string{"expected-label": "expected-value"},
+					MatchFields: []v1alpha1.FieldSelectorRequirement{{
+						Key:      "useful-value",
+						Operator: v1alpha1.FieldSelectorOpExists,
+					}},
+				}
+				runnableRepo.ListUnstructuredReturnsOnCall(0, []*unstructured.Unstructured{
+					{Object: map[string]interface{}{"useful-value": "from-selected-object"}},
+					{Object: map[string]interface{}{"other-value": "from-another-object"}},
+				}, nil)
+			})
+
+			It("selects the single object matching the field selectors", func() {
+				_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, stamped, _ := runnableRepo.EnsureImmutableObjectExistsOnClusterArgsForCall(0)
+				Expect(stamped.Object["spec"]).To(HaveKeyWithValue("value", map[string]interface{}{"useful-value": "from-selected-object"}))
+				Expect(stamped.GetAnnotations()).NotTo(HaveKey(realizer.SelectedDigestAnnotation))
+			})
+		})
 	})
 
 	Context("with unsatisfied output paths", func() {
@@ -533,7 +1239,7 @@ var _ = Describe("Realizer", func() {
 		})
 
 		It("returns RetrieveOutputError", func() {
-			_, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+			_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring(`unable to retrieve outputs from stamped object [my-important-ns/my-stamped-resource-] of type [athing.EXAMPLE.COM] for run template [my-template]: failed to evaluate path [data.hasnot]: jsonpath returned empty list: data.hasnot`))
 			Expect(reflect.TypeOf(err).String()).To(Equal("errors.RunnableRetrieveOutputError"))
@@ -551,7 +1257,7 @@ var _ = Describe("Realizer", func() {
 		})
 
 		It("returns StampError", func() {
-			_, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+			_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring(`unable to stamp object for run template [my-template]: failed to unmarshal json resource template: unexpected end of JSON input`))
 			Expect(reflect.TypeOf(err).String()).To(Equal("errors.RunnableStampError"))
@@ -571,7 +1277,7 @@ var _ = Describe("Realizer", func() {
 		})
 
 		It("returns GetRunTemplateError", func() {
-			_, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
+			_, _, _, _, err := rlzr.Realize(ctx, runnable, systemRepo, runnableRepo, discoveryClient)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring(`unable to get run template [my-template]: Errol mcErrorFace`))
 			Expect(reflect.TypeOf(err).String()).To(Equal("errors.RunnableGetRunTemplateError"))
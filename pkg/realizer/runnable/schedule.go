@@ -0,0 +1,130 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runnable
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// ScheduleTimeLabel is set on objects stamped by a scheduled runnable to the
+// unix time of the schedule tick they were created for. A new tick changes the
+// label, which forces a new immutable object to be created.
+const ScheduleTimeLabel = "carto.run/runnable-schedule-time"
+
+// maxMissedScheduleTicks bounds the number of ticks counted between the last
+// scheduled run and now. Beyond it, only the most recent tick is looked for.
+const maxMissedScheduleTicks = 100
+
+// ScheduledRun is the result of evaluating a runnable's schedule.
+type ScheduledRun struct {
+	// Tick is the most recent tick that is due, or nil if no tick is due.
+	Tick *time.Time
+	// Missed is the number of earlier due ticks that were skipped in favor of
+	// Tick, at most maxMissedScheduleTicks.
+	Missed int
+	// TooManyMissed is set when more than maxMissedScheduleTicks were skipped.
+	TooManyMissed bool
+	// Next is the first tick after now.
+	Next time.Time
+}
+
+// EvaluateSchedule returns the tick, if any, that a run should be created for,
+// given the time of the last scheduled run (or the runnable's creation time).
+// When a starting deadline is set, ticks older than the deadline are ignored.
+// As with a CronJob, when too many ticks were missed, eg: after the controller
+// was down, a run is still created for the most recent tick.
+func EvaluateSchedule(schedule *v1alpha1.RunnableSchedule, last time.Time, now time.Time) (ScheduledRun, error) {
+	parsed, location, err := ParseSchedule(schedule)
+	if err != nil {
+		return ScheduledRun{}, err
+	}
+
+	earliest := last
+	if schedule.StartingDeadlineSeconds != nil {
+		deadline := now.Add(-time.Duration(*schedule.StartingDeadlineSeconds) * time.Second)
+		if deadline.After(earliest) {
+			earliest = deadline
+		}
+	}
+
+	result := ScheduledRun{Next: parsed.Next(now.In(location))}
+	ticks := 0
+	for t := parsed.Next(earliest.In(location)); !t.After(now); t = parsed.Next(t) {
+		ticks++
+		if ticks > maxMissedScheduleTicks {
+			result.TooManyMissed = true
+			result.Missed = maxMissedScheduleTicks
+			result.Tick = mostRecentTick(parsed, earliest.In(location), now)
+			return result, nil
+		}
+		tick := t
+		if result.Tick != nil {
+			result.Missed++
+		}
+		result.Tick = &tick
+	}
+
+	return result, nil
+}
+
+// mostRecentTick returns the last tick after earliest and not after now. Ticks
+// are at least a minute apart, so it looks back over a window which starts at
+// an hour and doubles until a tick is found within it.
+func mostRecentTick(parsed cron.Schedule, earliest time.Time, now time.Time) *time.Time {
+	for window := time.Hour; ; window *= 2 {
+		start := now.Add(-window)
+		if start.Before(earliest) {
+			start = earliest
+		}
+
+		var tick *time.Time
+		for t := parsed.Next(start); !t.After(now); t = parsed.Next(t) {
+			found := t
+			tick = &found
+		}
+		if tick != nil || !start.After(earliest) {
+			return tick
+		}
+	}
+}
+
+// ParseSchedule parses the cron expression and time zone of a schedule.
+func ParseSchedule(schedule *v1alpha1.RunnableSchedule) (cron.Schedule, *time.Location, error) {
+	location := time.UTC
+	if schedule.TimeZone != "" {
+		var err error
+		location, err = time.LoadLocation(schedule.TimeZone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid time zone [%s]: %w", schedule.TimeZone, err)
+		}
+	}
+
+	parsed, err := cron.ParseStandard(schedule.Cron)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid cron [%s]: %w", schedule.Cron, err)
+	}
+
+	return parsed, location, nil
+}
+
+func scheduleTimeLabelValue(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runnable_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	realizer "github.com/vmware-tanzu/cartographer/pkg/realizer/runnable"
+)
+
+var _ = Describe("EvaluateSchedule", func() {
+	var (
+		schedule *v1alpha1.RunnableSchedule
+		last     time.Time
+	)
+
+	BeforeEach(func() {
+		schedule = &v1alpha1.RunnableSchedule{Cron: "0 2 * * *"}
+		last = time.Date(2022, 3, 4, 2, 0, 0, 0, time.UTC)
+	})
+
+	Context("when no tick is due", func() {
+		It("returns no tick and the next tick", func() {
+			run, err := realizer.EvaluateSchedule(schedule, last, time.Date(2022, 3, 4, 12, 0, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(run.Tick).To(BeNil())
+			Expect(run.Missed).To(Equal(0))
+			Expect(run.Next).To(Equal(time.Date(2022, 3, 5, 2, 0, 0, 0, time.UTC)))
+		})
+	})
+
+	Context("when a single tick is due", func() {
+		It("returns the tick", func() {
+			run, err := realizer.EvaluateSchedule(schedule, last, time.Date(2022, 3, 5, 2, 0, 30, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(run.Tick).To(PointTo(Equal(time.Date(2022, 3, 5, 2, 0, 0, 0, time.UTC))))
+			Expect(run.Missed).To(Equal(0))
+			Expect(run.Next).To(Equal(time.Date(2022, 3, 6, 2, 0, 0, 0, time.UTC)))
+		})
+	})
+
+	Context("when several ticks have been missed", func() {
+		It("returns the most recent tick and counts the missed ones", func() {
+			run, err := realizer.EvaluateSchedule(schedule, last, time.Date(2022, 3, 8, 3, 0, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(run.Tick).To(PointTo(Equal(time.Date(2022, 3, 8, 2, 0, 0, 0, time.UTC))))
+			Expect(run.Missed).To(Equal(3))
+		})
+
+		Context("and the most recent tick is older than the starting deadline", func() {
+			BeforeEach(func() {
+				deadline := int64(600)
+				schedule.StartingDeadlineSeconds = &deadline
+			})
+
+			It("returns no tick", func() {
+				run, err := realizer.EvaluateSchedule(schedule, last, time.Date(2022, 3, 8, 3, 0, 0, 0, time.UTC))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(run.Tick).To(BeNil())
+				Expect(run.Next).To(Equal(time.Date(2022, 3, 9, 2, 0, 0, 0, time.UTC)))
+			})
+
+			It("only counts the ticks within the deadline", func() {
+				run, err := realizer.EvaluateSchedule(schedule, last, time.Date(2022, 3, 8, 2, 5, 0, 0, time.UTC))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(run.Tick).To(PointTo(Equal(time.Date(2022, 3, 8, 2, 0, 0, 0, time.UTC))))
+				Expect(run.Missed).To(Equal(0))
+			})
+		})
+
+		Context("and there are too many missed ticks to consider", func() {
+			BeforeEach(func() {
+				schedule.Cron = "* * * * *"
+			})
+
+			It("returns the most recent tick", func() {
+				run, err := realizer.EvaluateSchedule(schedule, last, last.Add(time.Hour*3+30*time.Second))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(run.Tick).To(PointTo(Equal(last.Add(time.Hour * 3))))
+				Expect(run.TooManyMissed).To(BeTrue())
+				Expect(run.Missed).To(Equal(100))
+				Expect(run.Next).To(Equal(last.Add(time.Hour*3 + time.Minute)))
+			})
+		})
+
+		Context("and the runnable has not been scheduled for more ticks than are counted", func() {
+			It("returns the most recent tick", func() {
+				now := time.Date(2023, 3, 8, 1, 0, 0, 0, time.UTC)
+				run, err := realizer.EvaluateSchedule(schedule, last, now)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(run.Tick).To(PointTo(Equal(time.Date(2023, 3, 7, 2, 0, 0, 0, time.UTC))))
+				Expect(run.TooManyMissed).To(BeTrue())
+				Expect(run.Next).To(Equal(time.Date(2023, 3, 8, 2, 0, 0, 0, time.UTC)))
+			})
+		})
+	})
+
+	Context("when a time zone is specified", func() {
+		BeforeEach(func() {
+			schedule.TimeZone = "America/New_York"
+		})
+
+		It("evaluates the cron in that time zone", func() {
+			run, err := realizer.EvaluateSchedule(schedule, last, time.Date(2022, 3, 4, 12, 0, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(run.Tick).To(PointTo(BeTemporally("==", time.Date(2022, 3, 4, 7, 0, 0, 0, time.UTC))))
+			Expect(run.Next).To(BeTemporally("==", time.Date(2022, 3, 5, 7, 0, 0, 0, time.UTC)))
+		})
+	})
+
+	Context("when the cron is invalid", func() {
+		It("returns an error", func() {
+			schedule.Cron = "not a cron"
+			_, err := realizer.EvaluateSchedule(schedule, last, last)
+			Expect(err).To(MatchError(ContainSubstring("invalid cron [not a cron]")))
+		})
+	})
+
+	Context("when the time zone is invalid", func() {
+		It("returns an error", func() {
+			schedule.TimeZone = "Nowhere/Special"
+			_, err := realizer.EvaluateSchedule(schedule, last, last)
+			Expect(err).To(MatchError(ContainSubstring("invalid time zone [Nowhere/Special]")))
+		})
+	})
+})
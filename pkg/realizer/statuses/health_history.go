@@ -0,0 +1,98 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuses
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/utils"
+)
+
+const (
+	// MaxHealthHistory is the number of health transitions kept for each resource
+	MaxHealthHistory = 10
+
+	// A resource is flapping when its health changed between True and False more
+	// than FlappingThreshold times within the last FlappingWindow
+	FlappingThreshold = 3
+	FlappingWindow    = 30 * time.Minute
+)
+
+// healthHistory returns the health history of the previous status, with a new
+// transition appended when the status of the Healthy condition has changed
+func healthHistory(previous *v1alpha1.ResourceStatus, conditions []metav1.Condition) []v1alpha1.HealthTransition {
+	var history []v1alpha1.HealthTransition
+	if previous != nil {
+		history = previous.HealthHistory
+	}
+
+	healthyCondition := utils.ConditionList(conditions).ConditionWithType(v1alpha1.ResourceHealthy)
+	if healthyCondition == nil {
+		return history
+	}
+	if len(history) > 0 && history[len(history)-1].Status == healthyCondition.Status {
+		return history
+	}
+
+	history = append(append([]v1alpha1.HealthTransition{}, history...), v1alpha1.HealthTransition{
+		Status: healthyCondition.Status,
+		Reason: healthyCondition.Reason,
+		Time:   healthyCondition.LastTransitionTime,
+	})
+	if len(history) > MaxHealthHistory {
+		history = history[len(history)-MaxHealthHistory:]
+	}
+	return history
+}
+
+// IsFlapping reports whether the health in history changed between True and
+// False more than FlappingThreshold times within FlappingWindow before now.
+// Transitions to and from Unknown, eg: while a resource is progressing, are not
+// counted.
+func IsFlapping(history []v1alpha1.HealthTransition, now time.Time) bool {
+	return len(changesInWindow(history, now)) > FlappingThreshold
+}
+
+// FlappingEndsAfter is how long until the health in history, if flapping, stops
+// flapping when it does not change again, or 0 when it is not flapping
+func FlappingEndsAfter(history []v1alpha1.HealthTransition, now time.Time) time.Duration {
+	changes := changesInWindow(history, now)
+	if len(changes) <= FlappingThreshold {
+		return 0
+	}
+	// flapping ends once all but FlappingThreshold changes have left the window
+	lastToExpire := changes[len(changes)-FlappingThreshold-1]
+	return lastToExpire.Add(FlappingWindow).Sub(now) + time.Second
+}
+
+// changesInWindow returns the times, oldest first, of the changes between True
+// and False within FlappingWindow before now
+func changesInWindow(history []v1alpha1.HealthTransition, now time.Time) []time.Time {
+	var lastKnown metav1.ConditionStatus
+	var changes []time.Time
+	for _, transition := range history {
+		if transition.Status != metav1.ConditionTrue && transition.Status != metav1.ConditionFalse {
+			continue
+		}
+		if lastKnown != "" && transition.Status != lastKnown && now.Sub(transition.Time.Time) <= FlappingWindow {
+			changes = append(changes, transition.Time.Time)
+		}
+		lastKnown = transition.Status
+	}
+	return changes
+}
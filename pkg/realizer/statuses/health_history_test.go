@@ -0,0 +1,153 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statuses_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer/statuses"
+)
+
+var _ = Describe("Health history", func() {
+	healthy := func(status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{Type: v1alpha1.ResourceHealthy, Status: status, Reason: "SomeReason"}
+	}
+
+	transition := func(status metav1.ConditionStatus, ago time.Duration) v1alpha1.HealthTransition {
+		return v1alpha1.HealthTransition{Status: status, Time: metav1.NewTime(time.Now().Add(-ago))}
+	}
+
+	Describe("#Add", func() {
+		var previous []v1alpha1.ResourceStatus
+
+		BeforeEach(func() {
+			previous = []v1alpha1.ResourceStatus{{
+				RealizedResource: v1alpha1.RealizedResource{Name: "resource1"},
+				Conditions:       []metav1.Condition{healthy(metav1.ConditionTrue)},
+				HealthHistory:    []v1alpha1.HealthTransition{transition(metav1.ConditionTrue, time.Hour)},
+			}}
+		})
+
+		It("records a change in the status of the Healthy condition", func() {
+			resourceStatuses := statuses.NewResourceStatuses(previous, conditions.AddConditionForResourceSubmittedWorkload)
+			resourceStatuses.Add(&v1alpha1.RealizedResource{Name: "resource1"}, nil, false, healthy(metav1.ConditionFalse))
+
+			current := resourceStatuses.GetCurrent()[0]
+			Expect(current.HealthHistory).To(HaveLen(2))
+			Expect(current.HealthHistory[1]).To(MatchFields(IgnoreExtras, Fields{
+				"Status": Equal(metav1.ConditionFalse),
+				"Reason": Equal("SomeReason"),
+			}))
+			Expect(time.Since(current.HealthHistory[1].Time.Time)).To(BeNumerically("<", time.Minute))
+			Expect(previous[0].HealthHistory).To(HaveLen(1))
+		})
+
+		It("does not record an unchanged status", func() {
+			resourceStatuses := statuses.NewResourceStatuses(previous, conditions.AddConditionForResourceSubmittedWorkload)
+			resourceStatuses.Add(&v1alpha1.RealizedResource{Name: "resource1"}, nil, false, healthy(metav1.ConditionTrue))
+
+			Expect(resourceStatuses.GetCurrent()[0].HealthHistory).To(Equal(previous[0].HealthHistory))
+		})
+
+		It("keeps a bounded history", func() {
+			previous[0].HealthHistory = nil
+			for i := 0; i < statuses.MaxHealthHistory; i++ {
+				previous[0].HealthHistory = append(previous[0].HealthHistory, transition(metav1.ConditionTrue, time.Duration(statuses.MaxHealthHistory-i)*time.Hour))
+			}
+			resourceStatuses := statuses.NewResourceStatuses(previous, conditions.AddConditionForResourceSubmittedWorkload)
+			resourceStatuses.Add(&v1alpha1.RealizedResource{Name: "resource1"}, nil, false, healthy(metav1.ConditionFalse))
+
+			history := resourceStatuses.GetCurrent()[0].HealthHistory
+			Expect(history).To(HaveLen(statuses.MaxHealthHistory))
+			Expect(history[0]).To(Equal(previous[0].HealthHistory[1]))
+			Expect(history[statuses.MaxHealthHistory-1].Status).To(Equal(metav1.ConditionFalse))
+		})
+
+		It("marks a resource which is flapping", func() {
+			previous[0].HealthHistory = []v1alpha1.HealthTransition{
+				transition(metav1.ConditionTrue, 20*time.Minute),
+				transition(metav1.ConditionFalse, 15*time.Minute),
+				transition(metav1.ConditionTrue, 10*time.Minute),
+				transition(metav1.ConditionFalse, 5*time.Minute),
+				transition(metav1.ConditionTrue, time.Minute),
+			}
+			resourceStatuses := statuses.NewResourceStatuses(previous, conditions.AddConditionForResourceSubmittedWorkload)
+			resourceStatuses.Add(&v1alpha1.RealizedResource{Name: "resource1"}, nil, false, healthy(metav1.ConditionTrue))
+
+			Expect(resourceStatuses.GetCurrent()[0].Flapping).To(BeTrue())
+			Expect(resourceStatuses.IsChanged()).To(BeTrue())
+		})
+	})
+
+	Describe("IsFlapping", func() {
+		It("is true when health changed more than the threshold within the window", func() {
+			Expect(statuses.IsFlapping([]v1alpha1.HealthTransition{
+				transition(metav1.ConditionTrue, 20*time.Minute),
+				transition(metav1.ConditionFalse, 15*time.Minute),
+				transition(metav1.ConditionTrue, 10*time.Minute),
+				transition(metav1.ConditionFalse, 5*time.Minute),
+				transition(metav1.ConditionTrue, time.Minute),
+			}, time.Now())).To(BeTrue())
+		})
+
+		It("is false when the changes are outside the window", func() {
+			Expect(statuses.IsFlapping([]v1alpha1.HealthTransition{
+				transition(metav1.ConditionTrue, 5*time.Hour),
+				transition(metav1.ConditionFalse, 4*time.Hour),
+				transition(metav1.ConditionTrue, 3*time.Hour),
+				transition(metav1.ConditionFalse, 2*time.Hour),
+				transition(metav1.ConditionTrue, time.Minute),
+			}, time.Now())).To(BeFalse())
+		})
+
+		It("does not count transitions through Unknown", func() {
+			Expect(statuses.IsFlapping([]v1alpha1.HealthTransition{
+				transition(metav1.ConditionTrue, 20*time.Minute),
+				transition(metav1.ConditionUnknown, 15*time.Minute),
+				transition(metav1.ConditionTrue, 10*time.Minute),
+				transition(metav1.ConditionUnknown, 5*time.Minute),
+				transition(metav1.ConditionTrue, time.Minute),
+			}, time.Now())).To(BeFalse())
+		})
+	})
+
+	Describe("FlappingEndsAfter", func() {
+		It("is when all but the threshold of the changes have left the window", func() {
+			now := time.Now()
+			Expect(statuses.FlappingEndsAfter([]v1alpha1.HealthTransition{
+				transition(metav1.ConditionTrue, 25*time.Minute),
+				transition(metav1.ConditionFalse, 20*time.Minute),
+				transition(metav1.ConditionTrue, 15*time.Minute),
+				transition(metav1.ConditionFalse, 10*time.Minute),
+				transition(metav1.ConditionTrue, 5*time.Minute),
+				transition(metav1.ConditionFalse, time.Minute),
+			}, now)).To(BeNumerically("~", 15*time.Minute, 2*time.Second))
+		})
+
+		It("is 0 when health is not flapping", func() {
+			Expect(statuses.FlappingEndsAfter([]v1alpha1.HealthTransition{
+				transition(metav1.ConditionTrue, 20*time.Minute),
+				transition(metav1.ConditionFalse, 15*time.Minute),
+			}, time.Now())).To(BeZero())
+		})
+	})
+})
@@ -16,6 +16,7 @@ package statuses
 
 import (
 	"reflect"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -85,6 +86,9 @@ func (r *resourceStatuses) IsChanged() bool {
 		if status.conditionsChanged {
 			return true
 		}
+		if status.current.Flapping != status.previous.Flapping {
+			return true
+		}
 		if !reflect.DeepEqual(status.current.RealizedResource, status.previous.RealizedResource) {
 			return true
 		}
@@ -136,6 +140,8 @@ func (r *resourceStatuses) Add(realizedResource *v1alpha1.RealizedResource, err
 		RealizedResource: *realizedResource,
 		Conditions:       r.createConditions(name, err, isPassThrough, furtherConditions...),
 	}
+	existingStatus.current.HealthHistory = healthHistory(existingStatus.previous, existingStatus.current.Conditions)
+	existingStatus.current.Flapping = IsFlapping(existingStatus.current.HealthHistory, time.Now())
 }
 
 func (r *resourceStatuses) ChangedConditionTypes(realizedResourceName string) []string {
@@ -0,0 +1,39 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realizer
+
+import (
+	"hash/fnv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TemplateRevisionFor returns the revision of the resource's template that the owner
+// is stamped with, or zero when the latest spec of the template is used.
+func TemplateRevisionFor(resource OwnerResource, owner client.Object) int64 {
+	if resource.TemplateRollout != nil && inRollout(owner, resource.TemplateRollout.Percent) {
+		return resource.TemplateRollout.Revision
+	}
+	return resource.TemplateRevision
+}
+
+// inRollout reports whether the owner falls within the first percent of owners.
+// Owners are bucketed by a stable hash of their namespace and name, so an owner
+// stays in the rollout as percent grows.
+func inRollout(owner client.Object, percent int32) bool {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(owner.GetNamespace() + "/" + owner.GetName()))
+	return int32(hash.Sum32()%100) < percent
+}
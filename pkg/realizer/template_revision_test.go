@@ -0,0 +1,82 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realizer_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer"
+)
+
+var _ = Describe("TemplateRevisionFor", func() {
+	var (
+		resource realizer.OwnerResource
+		owner    *v1alpha1.Workload
+	)
+
+	BeforeEach(func() {
+		resource = realizer.OwnerResource{TemplateRevision: 2}
+		owner = &v1alpha1.Workload{ObjectMeta: metav1.ObjectMeta{Name: "my-workload", Namespace: "my-namespace"}}
+	})
+
+	It("returns the pinned revision", func() {
+		Expect(realizer.TemplateRevisionFor(resource, owner)).To(Equal(int64(2)))
+	})
+
+	It("returns zero when no revision is pinned", func() {
+		resource.TemplateRevision = 0
+		Expect(realizer.TemplateRevisionFor(resource, owner)).To(Equal(int64(0)))
+	})
+
+	Context("a rollout is in progress", func() {
+		It("uses the pinned revision for no owners at 0 percent", func() {
+			resource.TemplateRollout = &v1alpha1.TemplateRollout{Revision: 3, Percent: 0}
+			Expect(realizer.TemplateRevisionFor(resource, owner)).To(Equal(int64(2)))
+		})
+
+		It("uses the rollout revision for all owners at 100 percent", func() {
+			resource.TemplateRollout = &v1alpha1.TemplateRollout{Revision: 3, Percent: 100}
+			Expect(realizer.TemplateRevisionFor(resource, owner)).To(Equal(int64(3)))
+		})
+
+		It("moves roughly the given percent of owners, keeping them as the percent grows", func() {
+			rolledOut := func(percent int32) map[string]bool {
+				resource.TemplateRollout = &v1alpha1.TemplateRollout{Revision: 3, Percent: percent}
+				result := map[string]bool{}
+				for i := 0; i < 1000; i++ {
+					o := &v1alpha1.Workload{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("workload-%d", i), Namespace: "my-namespace"}}
+					if realizer.TemplateRevisionFor(resource, o) == 3 {
+						result[o.Name] = true
+					}
+				}
+				return result
+			}
+
+			quarter := rolledOut(25)
+			half := rolledOut(50)
+
+			Expect(len(quarter)).To(BeNumerically("~", 250, 60))
+			Expect(len(half)).To(BeNumerically("~", 500, 60))
+			for name := range quarter {
+				Expect(half).To(HaveKey(name))
+			}
+		})
+	})
+})
@@ -43,9 +43,12 @@ type Repository interface {
 	EnsureImmutableObjectExistsOnCluster(ctx context.Context, obj *unstructured.Unstructured, labels map[string]string) error
 	EnsureMutableObjectExistsOnCluster(ctx context.Context, obj *unstructured.Unstructured) error
 	GetTemplate(ctx context.Context, name, kind string) (client.Object, error)
+	GetTemplateRevision(ctx context.Context, name, kind string, revision int64) (client.Object, error)
+	EnsureTemplateRevisionExistsOnCluster(ctx context.Context, revision *v1alpha1.ClusterTemplateRevision) error
+	DeleteTemplateRevision(ctx context.Context, name string) error
 	GetRunTemplate(ctx context.Context, ref v1alpha1.TemplateReference) (*v1alpha1.ClusterRunTemplate, error)
-	GetSupplyChainsForWorkload(ctx context.Context, workload *v1alpha1.Workload) ([]*v1alpha1.ClusterSupplyChain, error)
-	GetDeliveriesForDeliverable(ctx context.Context, deliverable *v1alpha1.Deliverable) ([]*v1alpha1.ClusterDelivery, error)
+	GetSupplyChainsForWorkload(ctx context.Context, workload *v1alpha1.Workload) ([]*v1alpha1.ClusterSupplyChain, string, error)
+	GetDeliveriesForDeliverable(ctx context.Context, deliverable *v1alpha1.Deliverable) ([]*v1alpha1.ClusterDelivery, string, error)
 	GetWorkload(ctx context.Context, name string, namespace string) (*v1alpha1.Workload, error)
 	GetDeliverable(ctx context.Context, name string, namespace string) (*v1alpha1.Deliverable, error)
 	GetSupplyChain(ctx context.Context, name string) (*v1alpha1.ClusterSupplyChain, error)
@@ -57,6 +60,7 @@ type Repository interface {
 	GetScheme() *runtime.Scheme
 	GetServiceAccount(ctx context.Context, serviceAccountName, ns string) (*corev1.ServiceAccount, error)
 	Delete(ctx context.Context, objToDelete *unstructured.Unstructured) error
+	MergePatch(ctx context.Context, obj *unstructured.Unstructured, patch []byte) error
 }
 
 type RepositoryBuilder func(client client.Client, repoCache RepoCache) Repository
@@ -91,6 +95,20 @@ func (r *repository) Delete(ctx context.Context, objToDelete *unstructured.Unstr
 	return nil
 }
 
+func (r *repository) MergePatch(ctx context.Context, obj *unstructured.Unstructured, patch []byte) error {
+	log := logr.FromContextOrDiscard(ctx).WithValues("patch object", fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName()))
+	log.V(logger.DEBUG).Info("MergePatch")
+
+	err := r.cl.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch))
+	if err != nil {
+		log.Error(err, "failed to patch object")
+		return fmt.Errorf("failed to patch object [%s/%s]: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	log.V(logger.DEBUG).Info("object patched successfully")
+	return nil
+}
+
 func (r *repository) GetServiceAccount(ctx context.Context, name, namespace string) (*corev1.ServiceAccount, error) {
 	log := logr.FromContextOrDiscard(ctx).WithValues("service account", fmt.Sprintf("%s/%s", namespace, name))
 	ctx = logr.NewContext(ctx, log)
@@ -263,9 +281,83 @@ func (r *repository) GetTemplate(ctx context.Context, name string, kind string)
 		return nil, fmt.Errorf("failed to get template object from api server [%s/%s]: %w", kind, name, err)
 	}
 
+	effectiveTemplate, err := r.resolveTemplateExtension(ctx, apiTemplate)
+	if err != nil {
+		log.Error(err, "failed to resolve extended template")
+		return nil, fmt.Errorf("failed to resolve extended template [%s/%s]: %w", kind, name, err)
+	}
+
+	return effectiveTemplate, nil
+}
+
+func (r *repository) GetTemplateRevision(ctx context.Context, name string, kind string, revision int64) (client.Object, error) {
+	log := logr.FromContextOrDiscard(ctx)
+	log.V(logger.DEBUG).Info("GetTemplateRevision")
+
+	templateRevision := &v1alpha1.ClusterTemplateRevision{}
+	revisionName := v1alpha1.TemplateRevisionName(kind, name, revision)
+
+	err := r.getObject(ctx, revisionName, "", templateRevision)
+	if kerrors.IsNotFound(err) {
+		log.V(logger.DEBUG).Info("template revision is not found on api server", "revision", revisionName)
+		return nil, nil
+	}
+	if err != nil {
+		log.Error(err, "failed to get template revision object from api server")
+		return nil, fmt.Errorf("failed to get template revision object from api server [%s]: %w", revisionName, err)
+	}
+
+	// the revision holds the effective template, so it is served as is rather than
+	// resolved against the current spec of the templates it extended
+	apiTemplate, err := templateRevision.GetTemplate()
+	if err != nil {
+		log.Error(err, "failed to read template from revision")
+		return nil, fmt.Errorf("failed to read template from revision [%s]: %w", revisionName, err)
+	}
+
 	return apiTemplate, nil
 }
 
+func (r *repository) EnsureTemplateRevisionExistsOnCluster(ctx context.Context, revision *v1alpha1.ClusterTemplateRevision) error {
+	log := logr.FromContextOrDiscard(ctx).WithValues("revision", revision.Name)
+	log.V(logger.DEBUG).Info("EnsureTemplateRevisionExistsOnCluster")
+
+	existing := &v1alpha1.ClusterTemplateRevision{}
+	err := r.getObject(ctx, revision.Name, "", existing)
+	if err == nil {
+		log.V(logger.DEBUG).Info("template revision already exists")
+		return nil
+	}
+	if !kerrors.IsNotFound(err) {
+		log.Error(err, "failed to get template revision object from api server")
+		return fmt.Errorf("failed to get template revision object from api server [%s]: %w", revision.Name, err)
+	}
+
+	err = r.cl.Create(ctx, revision)
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		log.Error(err, "failed to create template revision")
+		return fmt.Errorf("failed to create template revision [%s]: %w", revision.Name, err)
+	}
+
+	return nil
+}
+
+func (r *repository) DeleteTemplateRevision(ctx context.Context, name string) error {
+	log := logr.FromContextOrDiscard(ctx).WithValues("revision", name)
+	log.V(logger.DEBUG).Info("DeleteTemplateRevision")
+
+	revision := &v1alpha1.ClusterTemplateRevision{}
+	revision.SetName(name)
+
+	err := r.cl.Delete(ctx, revision)
+	if err != nil && !kerrors.IsNotFound(err) {
+		log.Error(err, "failed to delete template revision")
+		return fmt.Errorf("failed to delete template revision [%s]: %w", name, err)
+	}
+
+	return nil
+}
+
 func (r *repository) GetRunTemplate(ctx context.Context, ref v1alpha1.TemplateReference) (*v1alpha1.ClusterRunTemplate, error) {
 	log := logr.FromContextOrDiscard(ctx)
 	log.V(logger.DEBUG).Info("GetRunTemplate")
@@ -311,27 +403,36 @@ func (r *repository) patchUnstructured(ctx context.Context, existingObj *unstruc
 	return nil
 }
 
-func (r *repository) GetSupplyChainsForWorkload(ctx context.Context, workload *v1alpha1.Workload) ([]*v1alpha1.ClusterSupplyChain, error) {
+func (r *repository) GetSupplyChainsForWorkload(ctx context.Context, workload *v1alpha1.Workload) ([]*v1alpha1.ClusterSupplyChain, string, error) {
 	log := logr.FromContextOrDiscard(ctx)
 	log.V(logger.DEBUG).Info("GetSupplyChainsForWorkload")
 
 	list := &v1alpha1.ClusterSupplyChainList{}
 	if err := r.cl.List(ctx, list); err != nil {
 		log.Error(err, "unable to list supply chains from api server")
-		return nil, fmt.Errorf("unable to list supply chains from api server: %w", err)
+		return nil, "", fmt.Errorf("unable to list supply chains from api server: %w", err)
 	}
 
 	var supplyChains []*v1alpha1.ClusterSupplyChain
+	var selectorGetters []SelectingObject
 
 	for _, item := range list.Items {
 		itemValue := item
 		supplyChains = append(supplyChains, &itemValue)
+		selectorGetters = append(selectorGetters, &itemValue)
+	}
+
+	namespaceLabels, err := r.getNamespaceLabels(ctx, workload.Namespace, selectorGetters)
+	if err != nil {
+		return nil, "", err
 	}
 
-	return GetSelectedSupplyChain(supplyChains, workload, log)
+	return GetSelectedSupplyChain(supplyChains, workload, namespaceLabels, log)
 }
 
-func GetSelectedSupplyChain(allSupplyChains []*v1alpha1.ClusterSupplyChain, workload *v1alpha1.Workload, log logr.Logger) ([]*v1alpha1.ClusterSupplyChain, error) {
+// GetSelectedSupplyChain returns the supply chains that best match the workload, out of
+// those that allow a namespace with the given labels.
+func GetSelectedSupplyChain(allSupplyChains []*v1alpha1.ClusterSupplyChain, workload *v1alpha1.Workload, namespaceLabels map[string]string, log logr.Logger) ([]*v1alpha1.ClusterSupplyChain, string, error) {
 	var selectorGetters []SelectingObject
 	for _, item := range allSupplyChains {
 		itemValue := item
@@ -339,9 +440,9 @@ func GetSelectedSupplyChain(allSupplyChains []*v1alpha1.ClusterSupplyChain, work
 	}
 
 	var supplyChains []*v1alpha1.ClusterSupplyChain
-	matches, err := BestSelectorMatch(workload, selectorGetters)
+	matches, reason, err := BestSelectorMatchInNamespace(workload, workload.Namespace, namespaceLabels, selectorGetters)
 	if err != nil {
-		return nil, fmt.Errorf("evaluating supply chain selectors against workload [%s/%s] failed: %w", workload.Namespace, workload.Name, err)
+		return nil, "", fmt.Errorf("evaluating supply chain selectors against workload [%s/%s] failed: %w", workload.Namespace, workload.Name, err)
 	}
 	for _, matchingObject := range matches {
 		log.V(logger.DEBUG).Info("supply chain matched workload",
@@ -349,17 +450,17 @@ func GetSelectedSupplyChain(allSupplyChains []*v1alpha1.ClusterSupplyChain, work
 		supplyChains = append(supplyChains, matchingObject.(*v1alpha1.ClusterSupplyChain))
 	}
 
-	return supplyChains, nil
+	return supplyChains, reason, nil
 }
 
-func (r *repository) GetDeliveriesForDeliverable(ctx context.Context, deliverable *v1alpha1.Deliverable) ([]*v1alpha1.ClusterDelivery, error) {
+func (r *repository) GetDeliveriesForDeliverable(ctx context.Context, deliverable *v1alpha1.Deliverable) ([]*v1alpha1.ClusterDelivery, string, error) {
 	log := logr.FromContextOrDiscard(ctx)
 	log.V(logger.DEBUG).Info("GetDeliveriesForDeliverable")
 
 	list := &v1alpha1.ClusterDeliveryList{}
 	if err := r.cl.List(ctx, list); err != nil {
 		log.Error(err, "unable to list deliveries from api server")
-		return nil, fmt.Errorf("unable to list deliveries from api server: %w", err)
+		return nil, "", fmt.Errorf("unable to list deliveries from api server: %w", err)
 	}
 
 	var selectorGetters []SelectingObject
@@ -368,10 +469,15 @@ func (r *repository) GetDeliveriesForDeliverable(ctx context.Context, deliverabl
 		selectorGetters = append(selectorGetters, &itemValue)
 	}
 
+	namespaceLabels, err := r.getNamespaceLabels(ctx, deliverable.Namespace, selectorGetters)
+	if err != nil {
+		return nil, "", err
+	}
+
 	var deliveries []*v1alpha1.ClusterDelivery
-	matches, err := BestSelectorMatch(deliverable, selectorGetters)
+	matches, reason, err := BestSelectorMatchInNamespace(deliverable, deliverable.Namespace, namespaceLabels, selectorGetters)
 	if err != nil {
-		return nil, fmt.Errorf("evaluating supply chain selectors against deliverable [%s/%s] failed: %w", deliverable.Namespace, deliverable.Name, err)
+		return nil, "", fmt.Errorf("evaluating supply chain selectors against deliverable [%s/%s] failed: %w", deliverable.Namespace, deliverable.Name, err)
 	}
 	for _, matchingObject := range matches {
 		log.V(logger.DEBUG).Info("delivery matched deliverable",
@@ -381,7 +487,7 @@ func (r *repository) GetDeliveriesForDeliverable(ctx context.Context, deliverabl
 
 	log.V(logger.DEBUG).Info("deliveries matched deliverable",
 		"deliveries", deliveries)
-	return deliveries, nil
+	return deliveries, reason, nil
 }
 func getNamespacedName(name string, namespace string) string {
 	var namespacedName string
@@ -393,6 +499,22 @@ func getNamespacedName(name string, namespace string) string {
 	return namespacedName
 }
 
+// getNamespaceLabels returns the labels of the namespace, looking it up only when one of
+// the selecting objects has a namespaceSelector.
+func (r *repository) getNamespaceLabels(ctx context.Context, name string, selectingObjects []SelectingObject) (map[string]string, error) {
+	if !hasNamespaceSelector(selectingObjects) {
+		return nil, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.getObject(ctx, name, "", namespace); err != nil {
+		logr.FromContextOrDiscard(ctx).Error(err, "unable to get namespace from api server")
+		return nil, fmt.Errorf("unable to get namespace [%s]: %w", name, err)
+	}
+
+	return namespace.Labels, nil
+}
+
 func (r *repository) getObject(ctx context.Context, name string, namespace string, obj client.Object) error {
 	log := logr.FromContextOrDiscard(ctx)
 	log.V(logger.DEBUG).Info("getObject")
@@ -26,6 +26,7 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
 	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -555,7 +556,7 @@ spec:
 			})
 
 			It("attempts to list the object from the apiServer", func() {
-				_, err := repo.GetSupplyChainsForWorkload(ctx, &v1alpha1.Workload{})
+				_, _, err := repo.GetSupplyChainsForWorkload(ctx, &v1alpha1.Workload{})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("unable to list supply chains from api server: some list error"))
 			})
@@ -875,6 +876,197 @@ spec:
 			})
 		})
 
+		Context("GetTemplate of a template that extends another", func() {
+			var (
+				baseTemplate      *v1alpha1.ClusterTemplate
+				extendingTemplate *v1alpha1.ClusterTemplate
+			)
+
+			BeforeEach(func() {
+				baseTemplate = &v1alpha1.ClusterTemplate{
+					ObjectMeta: metav1.ObjectMeta{Name: "base"},
+					Spec: v1alpha1.TemplateSpec{
+						Template: &runtime.RawExtension{Raw: []byte(`{
+							"apiVersion": "apps/v1",
+							"kind": "Deployment",
+							"metadata": {"name": "app"},
+							"spec": {
+								"replicas": 1,
+								"template": {"spec": {"containers": [{"name": "app", "image": "app:1"}, {"name": "sidecar", "image": "sidecar:1"}]}}
+							}
+						}`)},
+						HealthRule: &v1alpha1.HealthRule{SingleConditionType: "Ready"},
+						Lifecycle:  "immutable",
+					},
+				}
+				extendingTemplate = &v1alpha1.ClusterTemplate{
+					ObjectMeta: metav1.ObjectMeta{Name: "extending", Generation: 3},
+					Spec: v1alpha1.TemplateSpec{
+						Extends: &v1alpha1.TemplateReference{Kind: "ClusterTemplate", Name: "base"},
+						Overlay: &v1alpha1.TemplateOverlay{
+							StrategicMergePatch: &runtime.RawExtension{Raw: []byte(`{"spec": {"template": {"spec": {"containers": [{"name": "app", "image": "app:2"}]}}}}`)},
+							JSONPatch: []v1alpha1.JSONPatchOperation{
+								{Op: "replace", Path: "/spec/replicas", Value: &apiextensionsv1.JSON{Raw: []byte(`3`)}},
+							},
+						},
+						Params: v1alpha1.TemplateParams{{Name: "some-param", DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"value"`)}}},
+					},
+				}
+				clientObjects = []client.Object{baseTemplate, extendingTemplate}
+			})
+
+			It("returns the effective template", func() {
+				template, err := repo.GetTemplate(ctx, "extending", "ClusterTemplate")
+				Expect(err).ToNot(HaveOccurred())
+
+				effective, ok := template.(*v1alpha1.ClusterTemplate)
+				Expect(ok).To(BeTrue())
+				Expect(effective.Name).To(Equal("extending"))
+				Expect(effective.Generation).To(Equal(int64(3)))
+				Expect(effective.Spec.Extends).To(BeNil())
+				Expect(effective.Spec.Overlay).To(BeNil())
+				Expect(effective.Spec.HealthRule).To(Equal(&v1alpha1.HealthRule{SingleConditionType: "Ready"}))
+				Expect(effective.Spec.Params).To(HaveLen(1))
+				Expect(effective.Spec.Lifecycle).To(BeEmpty())
+
+				Expect(string(effective.Spec.Template.Raw)).To(MatchJSON(`{
+					"apiVersion": "apps/v1",
+					"kind": "Deployment",
+					"metadata": {"name": "app"},
+					"spec": {
+						"replicas": 3,
+						"template": {"spec": {"containers": [{"name": "app", "image": "app:2"}, {"name": "sidecar", "image": "sidecar:1"}]}}
+					}
+				}`))
+			})
+
+			It("records the extended templates", func() {
+				template, err := repo.GetTemplate(ctx, "extending", "ClusterTemplate")
+				Expect(err).ToNot(HaveOccurred())
+
+				bases, err := v1alpha1.GetTemplateBases(template)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bases).To(Equal([]v1alpha1.TemplateReference{{Kind: "ClusterTemplate", Name: "base"}}))
+			})
+
+			Context("the template is not a kind known to kubernetes", func() {
+				BeforeEach(func() {
+					baseTemplate.Spec.Template = &runtime.RawExtension{Raw: []byte(`{
+						"apiVersion": "example.com/v1",
+						"kind": "Thing",
+						"spec": {"list": [{"name": "a"}, {"name": "b"}], "keep": "me"}
+					}`)}
+					extendingTemplate.Spec.Overlay = &v1alpha1.TemplateOverlay{
+						StrategicMergePatch: &runtime.RawExtension{Raw: []byte(`{"spec": {"list": [{"name": "c"}]}}`)},
+					}
+				})
+
+				It("merges the overlay as a JSON merge patch", func() {
+					template, err := repo.GetTemplate(ctx, "extending", "ClusterTemplate")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(string(template.(*v1alpha1.ClusterTemplate).Spec.Template.Raw)).To(MatchJSON(`{
+						"apiVersion": "example.com/v1",
+						"kind": "Thing",
+						"spec": {"list": [{"name": "c"}], "keep": "me"}
+					}`))
+				})
+			})
+
+			Context("the extended template extends another", func() {
+				BeforeEach(func() {
+					rootTemplate := baseTemplate.DeepCopy()
+					rootTemplate.Name = "root"
+					rootTemplate.Spec.HealthRule = &v1alpha1.HealthRule{SingleConditionType: "Succeeded"}
+
+					baseTemplate.Spec.Template = nil
+					baseTemplate.Spec.HealthRule = nil
+					baseTemplate.Spec.Extends = &v1alpha1.TemplateReference{Kind: "ClusterTemplate", Name: "root"}
+
+					clientObjects = append(clientObjects, rootTemplate)
+				})
+
+				It("resolves the whole chain", func() {
+					template, err := repo.GetTemplate(ctx, "extending", "ClusterTemplate")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(template.(*v1alpha1.ClusterTemplate).Spec.HealthRule.SingleConditionType).To(Equal("Succeeded"))
+
+					bases, err := v1alpha1.GetTemplateBases(template)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(bases).To(Equal([]v1alpha1.TemplateReference{
+						{Kind: "ClusterTemplate", Name: "base"},
+						{Kind: "ClusterTemplate", Name: "root"},
+					}))
+				})
+			})
+
+			Context("the chain forms a cycle", func() {
+				BeforeEach(func() {
+					baseTemplate.Spec.Extends = &v1alpha1.TemplateReference{Kind: "ClusterTemplate", Name: "extending"}
+				})
+
+				It("returns an error", func() {
+					_, err := repo.GetTemplate(ctx, "extending", "ClusterTemplate")
+					Expect(err).To(MatchError(ContainSubstring("extends forms a cycle")))
+				})
+			})
+
+			Context("the extended template does not exist", func() {
+				BeforeEach(func() {
+					clientObjects = []client.Object{extendingTemplate}
+				})
+
+				It("returns an error", func() {
+					_, err := repo.GetTemplate(ctx, "extending", "ClusterTemplate")
+					Expect(err).To(MatchError(ContainSubstring("extended template [ClusterTemplate/base] not found")))
+				})
+			})
+
+			Context("the overlay cannot be applied", func() {
+				BeforeEach(func() {
+					extendingTemplate.Spec.Overlay = &v1alpha1.TemplateOverlay{
+						JSONPatch: []v1alpha1.JSONPatchOperation{{Op: "remove", Path: "/spec/missing"}},
+					}
+				})
+
+				It("returns an error", func() {
+					_, err := repo.GetTemplate(ctx, "extending", "ClusterTemplate")
+					Expect(err).To(MatchError(ContainSubstring("failed to apply overlay.jsonPatch")))
+				})
+			})
+		})
+
+		Context("GetTemplateRevision of a template that extends another", func() {
+			BeforeEach(func() {
+				baseTemplate := &v1alpha1.ClusterTemplate{
+					ObjectMeta: metav1.ObjectMeta{Name: "base"},
+					Spec: v1alpha1.TemplateSpec{
+						Template:   &runtime.RawExtension{Raw: []byte(`{"kind":"ConfigMap","data":{"version":"2"}}`)},
+						HealthRule: &v1alpha1.HealthRule{SingleConditionType: "Succeeded"},
+					},
+				}
+				revision := &v1alpha1.ClusterTemplateRevision{
+					ObjectMeta: metav1.ObjectMeta{Name: "clustertemplate-extending-3"},
+					Spec: v1alpha1.TemplateRevisionSpec{
+						TemplateRef: v1alpha1.TemplateReference{Kind: "ClusterTemplate", Name: "extending"},
+						Revision:    3,
+						Template:    runtime.RawExtension{Raw: []byte(`{"template":{"kind":"ConfigMap","data":{"version":"1"}},"healthRule":{"singleConditionType":"Ready"}}`)},
+					},
+				}
+				clientObjects = []client.Object{baseTemplate, revision}
+			})
+
+			It("returns the effective template snapshotted in the revision, regardless of later changes to the base", func() {
+				template, err := repo.GetTemplateRevision(ctx, "extending", "ClusterTemplate", 3)
+				Expect(err).ToNot(HaveOccurred())
+
+				snapshot := template.(*v1alpha1.ClusterTemplate)
+				Expect(snapshot.Name).To(Equal("extending"))
+				Expect(snapshot.Generation).To(Equal(int64(3)))
+				Expect(snapshot.Spec.HealthRule.SingleConditionType).To(Equal("Ready"))
+				Expect(string(snapshot.Spec.Template.Raw)).To(MatchJSON(`{"kind":"ConfigMap","data":{"version":"1"}}`))
+			})
+		})
+
 		Context("GetRunTemplate", func() {
 			BeforeEach(func() {
 				clientObjects = []client.Object{
@@ -1048,7 +1240,7 @@ spec:
 						Spec:   v1alpha1.WorkloadSpec{},
 						Status: v1alpha1.WorkloadStatus{},
 					}
-					_, err := repo.GetSupplyChainsForWorkload(ctx, workload)
+					_, _, err := repo.GetSupplyChainsForWorkload(ctx, workload)
 					Expect(err).To(MatchError(ContainSubstring("evaluating supply chain selectors against workload [myNS/workload-name] failed")))
 					Expect(err).To(MatchError(ContainSubstring("error handling selectors, selectorMatchExpressions or selectorMatchFields of [ClusterSupplyChain/supplychain-name]")))
 					Expect(err).To(MatchError(ContainSubstring("unable to match field requirement with key [spec.env[asdfasdfadkf3] operator [Exists] values [[]]")))
@@ -1081,13 +1273,112 @@ spec:
 						Spec:   v1alpha1.WorkloadSpec{},
 						Status: v1alpha1.WorkloadStatus{},
 					}
-					supplyChains, err := repo.GetSupplyChainsForWorkload(ctx, workload)
+					supplyChains, _, err := repo.GetSupplyChainsForWorkload(ctx, workload)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(len(supplyChains)).To(Equal(1))
 					Expect(supplyChains[0].Name).To(Equal("supplychain-name"))
 				})
 			})
 
+			Context("supply chains with a namespaceSelector", func() {
+				var workload *v1alpha1.Workload
+
+				BeforeEach(func() {
+					restricted := &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "restricted",
+						},
+						Spec: v1alpha1.SupplyChainSpec{
+							LegacySelector: v1alpha1.LegacySelector{
+								Selector: map[string]string{"foo": "bar"},
+							},
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"env": "prod"},
+							},
+						},
+					}
+					namespace := &v1.Namespace{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "dev",
+							Labels: map[string]string{"env": "dev"},
+						},
+					}
+					clientObjects = []client.Object{restricted, namespace}
+
+					workload = &v1alpha1.Workload{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "workload-name",
+							Namespace: "dev",
+							Labels:    map[string]string{"foo": "bar"},
+						},
+					}
+				})
+
+				It("returns a NamespaceNotAllowedError when the workload's namespace is not allowed", func() {
+					_, _, err := repo.GetSupplyChainsForWorkload(ctx, workload)
+					var namespaceNotAllowedErr repository.NamespaceNotAllowedError
+					Expect(errors.As(err, &namespaceNotAllowedErr)).To(BeTrue())
+					Expect(namespaceNotAllowedErr.Namespace).To(Equal("dev"))
+					Expect(namespaceNotAllowedErr.Blueprints).To(Equal([]string{"restricted"}))
+				})
+
+				It("returns the supply chain when the workload's namespace is allowed", func() {
+					workload.Namespace = "prod"
+					Expect(cl.Create(ctx, &v1.Namespace{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "prod",
+							Labels: map[string]string{"env": "prod"},
+						},
+					})).To(Succeed())
+
+					supplyChains, _, err := repo.GetSupplyChainsForWorkload(ctx, workload)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(supplyChains).To(HaveLen(1))
+					Expect(supplyChains[0].Name).To(Equal("restricted"))
+				})
+
+				It("returns an error when the namespace can not be found", func() {
+					workload.Namespace = "missing"
+					_, _, err := repo.GetSupplyChainsForWorkload(ctx, workload)
+					Expect(err).To(MatchError(ContainSubstring("unable to get namespace [missing]")))
+				})
+			})
+
+			Context("explaining supply chain selection", func() {
+				BeforeEach(func() {
+					clientObjects = []client.Object{
+						&v1alpha1.ClusterSupplyChain{
+							ObjectMeta: metav1.ObjectMeta{Name: "web"},
+							Spec: v1alpha1.SupplyChainSpec{
+								LegacySelector: v1alpha1.LegacySelector{Selector: map[string]string{"type": "web"}},
+							},
+						},
+						&v1alpha1.ClusterSupplyChain{
+							ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+							Spec: v1alpha1.SupplyChainSpec{
+								LegacySelector:    v1alpha1.LegacySelector{Selector: map[string]string{"type": "web"}},
+								NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+							},
+						},
+						&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev"}},
+					}
+				})
+
+				It("explains the supply chains that allow the workload's namespace when none match", func() {
+					workload := &v1alpha1.Workload{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "workload-name",
+							Namespace: "dev",
+							Labels:    map[string]string{"type": "api"},
+						},
+					}
+					supplyChains, reason, err := repo.GetSupplyChainsForWorkload(ctx, workload)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(supplyChains).To(BeEmpty())
+					Expect(reason).To(Equal("[web]: did not match: unsatisfied label [type=web]"))
+				})
+			})
+
 			Context("More than one supply chain", func() {
 				BeforeEach(func() {
 					supplyChain := &v1alpha1.ClusterSupplyChain{
@@ -1125,7 +1416,7 @@ spec:
 						Spec:   v1alpha1.WorkloadSpec{},
 						Status: v1alpha1.WorkloadStatus{},
 					}
-					supplyChains, err := repo.GetSupplyChainsForWorkload(ctx, workload)
+					supplyChains, _, err := repo.GetSupplyChainsForWorkload(ctx, workload)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(len(supplyChains)).To(Equal(1))
 					Expect(supplyChains[0].Name).To(Equal("supplychain-name"))
@@ -1141,7 +1432,7 @@ spec:
 						Spec:   v1alpha1.WorkloadSpec{},
 						Status: v1alpha1.WorkloadStatus{},
 					}
-					supplyChains, err := repo.GetSupplyChainsForWorkload(ctx, workload)
+					supplyChains, _, err := repo.GetSupplyChainsForWorkload(ctx, workload)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(len(supplyChains)).To(Equal(0))
 				})
@@ -1214,5 +1505,41 @@ spec:
 				})
 			})
 		})
+
+		Context("MergePatch", func() {
+			var testObj *unstructured.Unstructured
+
+			BeforeEach(func() {
+				testObj = &unstructured.Unstructured{}
+				stampedObjManifest := utils.HereYaml(`
+					apiVersion: test.run/v1alpha1
+					kind: TestObj
+					metadata:
+					  name: hello
+					  namespace: default
+					spec:
+					  foo: running
+					`)
+				dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
+				_, _, err := dec.Decode([]byte(stampedObjManifest), nil, testObj)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when the object exists", func() {
+				BeforeEach(func() {
+					clientObjects = []client.Object{testObj.DeepCopy()}
+				})
+
+				It("applies the patch", func() {
+					err := repo.MergePatch(ctx, testObj, []byte(`{"spec":{"foo":"Cancelled"}}`))
+					Expect(err).NotTo(HaveOccurred())
+
+					obj := &resources.TestObj{}
+					err = cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "hello"}, obj)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(obj.Spec.Foo).To(Equal("Cancelled"))
+				})
+			})
+		})
 	})
 })
@@ -27,6 +27,18 @@ type FakeRepository struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteTemplateRevisionStub        func(context.Context, string) error
+	deleteTemplateRevisionMutex       sync.RWMutex
+	deleteTemplateRevisionArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	deleteTemplateRevisionReturns struct {
+		result1 error
+	}
+	deleteTemplateRevisionReturnsOnCall map[int]struct {
+		result1 error
+	}
 	EnsureImmutableObjectExistsOnClusterStub        func(context.Context, *unstructured.Unstructured, map[string]string) error
 	ensureImmutableObjectExistsOnClusterMutex       sync.RWMutex
 	ensureImmutableObjectExistsOnClusterArgsForCall []struct {
@@ -52,6 +64,18 @@ type FakeRepository struct {
 	ensureMutableObjectExistsOnClusterReturnsOnCall map[int]struct {
 		result1 error
 	}
+	EnsureTemplateRevisionExistsOnClusterStub        func(context.Context, *v1alpha1.ClusterTemplateRevision) error
+	ensureTemplateRevisionExistsOnClusterMutex       sync.RWMutex
+	ensureTemplateRevisionExistsOnClusterArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1alpha1.ClusterTemplateRevision
+	}
+	ensureTemplateRevisionExistsOnClusterReturns struct {
+		result1 error
+	}
+	ensureTemplateRevisionExistsOnClusterReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetDeliverableStub        func(context.Context, string, string) (*v1alpha1.Deliverable, error)
 	getDeliverableMutex       sync.RWMutex
 	getDeliverableArgsForCall []struct {
@@ -67,7 +91,7 @@ type FakeRepository struct {
 		result1 *v1alpha1.Deliverable
 		result2 error
 	}
-	GetDeliveriesForDeliverableStub        func(context.Context, *v1alpha1.Deliverable) ([]*v1alpha1.ClusterDelivery, error)
+	GetDeliveriesForDeliverableStub        func(context.Context, *v1alpha1.Deliverable) ([]*v1alpha1.ClusterDelivery, string, error)
 	getDeliveriesForDeliverableMutex       sync.RWMutex
 	getDeliveriesForDeliverableArgsForCall []struct {
 		arg1 context.Context
@@ -75,11 +99,13 @@ type FakeRepository struct {
 	}
 	getDeliveriesForDeliverableReturns struct {
 		result1 []*v1alpha1.ClusterDelivery
-		result2 error
+		result2 string
+		result3 error
 	}
 	getDeliveriesForDeliverableReturnsOnCall map[int]struct {
 		result1 []*v1alpha1.ClusterDelivery
-		result2 error
+		result2 string
+		result3 error
 	}
 	GetDeliveryStub        func(context.Context, string) (*v1alpha1.ClusterDelivery, error)
 	getDeliveryMutex       sync.RWMutex
@@ -163,7 +189,7 @@ type FakeRepository struct {
 		result1 *v1alpha1.ClusterSupplyChain
 		result2 error
 	}
-	GetSupplyChainsForWorkloadStub        func(context.Context, *v1alpha1.Workload) ([]*v1alpha1.ClusterSupplyChain, error)
+	GetSupplyChainsForWorkloadStub        func(context.Context, *v1alpha1.Workload) ([]*v1alpha1.ClusterSupplyChain, string, error)
 	getSupplyChainsForWorkloadMutex       sync.RWMutex
 	getSupplyChainsForWorkloadArgsForCall []struct {
 		arg1 context.Context
@@ -171,11 +197,13 @@ type FakeRepository struct {
 	}
 	getSupplyChainsForWorkloadReturns struct {
 		result1 []*v1alpha1.ClusterSupplyChain
-		result2 error
+		result2 string
+		result3 error
 	}
 	getSupplyChainsForWorkloadReturnsOnCall map[int]struct {
 		result1 []*v1alpha1.ClusterSupplyChain
-		result2 error
+		result2 string
+		result3 error
 	}
 	GetTemplateStub        func(context.Context, string, string) (client.Object, error)
 	getTemplateMutex       sync.RWMutex
@@ -192,6 +220,22 @@ type FakeRepository struct {
 		result1 client.Object
 		result2 error
 	}
+	GetTemplateRevisionStub        func(context.Context, string, string, int64) (client.Object, error)
+	getTemplateRevisionMutex       sync.RWMutex
+	getTemplateRevisionArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 int64
+	}
+	getTemplateRevisionReturns struct {
+		result1 client.Object
+		result2 error
+	}
+	getTemplateRevisionReturnsOnCall map[int]struct {
+		result1 client.Object
+		result2 error
+	}
 	GetUnstructuredStub        func(context.Context, *unstructured.Unstructured) (*unstructured.Unstructured, error)
 	getUnstructuredMutex       sync.RWMutex
 	getUnstructuredArgsForCall []struct {
@@ -237,6 +281,19 @@ type FakeRepository struct {
 		result1 []*unstructured.Unstructured
 		result2 error
 	}
+	MergePatchStub        func(context.Context, *unstructured.Unstructured, []byte) error
+	mergePatchMutex       sync.RWMutex
+	mergePatchArgsForCall []struct {
+		arg1 context.Context
+		arg2 *unstructured.Unstructured
+		arg3 []byte
+	}
+	mergePatchReturns struct {
+		result1 error
+	}
+	mergePatchReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StatusUpdateStub        func(context.Context, client.Object) error
 	statusUpdateMutex       sync.RWMutex
 	statusUpdateArgsForCall []struct {
@@ -315,6 +372,68 @@ func (fake *FakeRepository) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRepository) DeleteTemplateRevision(arg1 context.Context, arg2 string) error {
+	fake.deleteTemplateRevisionMutex.Lock()
+	ret, specificReturn := fake.deleteTemplateRevisionReturnsOnCall[len(fake.deleteTemplateRevisionArgsForCall)]
+	fake.deleteTemplateRevisionArgsForCall = append(fake.deleteTemplateRevisionArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DeleteTemplateRevisionStub
+	fakeReturns := fake.deleteTemplateRevisionReturns
+	fake.recordInvocation("DeleteTemplateRevision", []interface{}{arg1, arg2})
+	fake.deleteTemplateRevisionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) DeleteTemplateRevisionCallCount() int {
+	fake.deleteTemplateRevisionMutex.RLock()
+	defer fake.deleteTemplateRevisionMutex.RUnlock()
+	return len(fake.deleteTemplateRevisionArgsForCall)
+}
+
+func (fake *FakeRepository) DeleteTemplateRevisionCalls(stub func(context.Context, string) error) {
+	fake.deleteTemplateRevisionMutex.Lock()
+	defer fake.deleteTemplateRevisionMutex.Unlock()
+	fake.DeleteTemplateRevisionStub = stub
+}
+
+func (fake *FakeRepository) DeleteTemplateRevisionArgsForCall(i int) (context.Context, string) {
+	fake.deleteTemplateRevisionMutex.RLock()
+	defer fake.deleteTemplateRevisionMutex.RUnlock()
+	argsForCall := fake.deleteTemplateRevisionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) DeleteTemplateRevisionReturns(result1 error) {
+	fake.deleteTemplateRevisionMutex.Lock()
+	defer fake.deleteTemplateRevisionMutex.Unlock()
+	fake.DeleteTemplateRevisionStub = nil
+	fake.deleteTemplateRevisionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) DeleteTemplateRevisionReturnsOnCall(i int, result1 error) {
+	fake.deleteTemplateRevisionMutex.Lock()
+	defer fake.deleteTemplateRevisionMutex.Unlock()
+	fake.DeleteTemplateRevisionStub = nil
+	if fake.deleteTemplateRevisionReturnsOnCall == nil {
+		fake.deleteTemplateRevisionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteTemplateRevisionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRepository) EnsureImmutableObjectExistsOnCluster(arg1 context.Context, arg2 *unstructured.Unstructured, arg3 map[string]string) error {
 	fake.ensureImmutableObjectExistsOnClusterMutex.Lock()
 	ret, specificReturn := fake.ensureImmutableObjectExistsOnClusterReturnsOnCall[len(fake.ensureImmutableObjectExistsOnClusterArgsForCall)]
@@ -440,6 +559,68 @@ func (fake *FakeRepository) EnsureMutableObjectExistsOnClusterReturnsOnCall(i in
 	}{result1}
 }
 
+func (fake *FakeRepository) EnsureTemplateRevisionExistsOnCluster(arg1 context.Context, arg2 *v1alpha1.ClusterTemplateRevision) error {
+	fake.ensureTemplateRevisionExistsOnClusterMutex.Lock()
+	ret, specificReturn := fake.ensureTemplateRevisionExistsOnClusterReturnsOnCall[len(fake.ensureTemplateRevisionExistsOnClusterArgsForCall)]
+	fake.ensureTemplateRevisionExistsOnClusterArgsForCall = append(fake.ensureTemplateRevisionExistsOnClusterArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1alpha1.ClusterTemplateRevision
+	}{arg1, arg2})
+	stub := fake.EnsureTemplateRevisionExistsOnClusterStub
+	fakeReturns := fake.ensureTemplateRevisionExistsOnClusterReturns
+	fake.recordInvocation("EnsureTemplateRevisionExistsOnCluster", []interface{}{arg1, arg2})
+	fake.ensureTemplateRevisionExistsOnClusterMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) EnsureTemplateRevisionExistsOnClusterCallCount() int {
+	fake.ensureTemplateRevisionExistsOnClusterMutex.RLock()
+	defer fake.ensureTemplateRevisionExistsOnClusterMutex.RUnlock()
+	return len(fake.ensureTemplateRevisionExistsOnClusterArgsForCall)
+}
+
+func (fake *FakeRepository) EnsureTemplateRevisionExistsOnClusterCalls(stub func(context.Context, *v1alpha1.ClusterTemplateRevision) error) {
+	fake.ensureTemplateRevisionExistsOnClusterMutex.Lock()
+	defer fake.ensureTemplateRevisionExistsOnClusterMutex.Unlock()
+	fake.EnsureTemplateRevisionExistsOnClusterStub = stub
+}
+
+func (fake *FakeRepository) EnsureTemplateRevisionExistsOnClusterArgsForCall(i int) (context.Context, *v1alpha1.ClusterTemplateRevision) {
+	fake.ensureTemplateRevisionExistsOnClusterMutex.RLock()
+	defer fake.ensureTemplateRevisionExistsOnClusterMutex.RUnlock()
+	argsForCall := fake.ensureTemplateRevisionExistsOnClusterArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) EnsureTemplateRevisionExistsOnClusterReturns(result1 error) {
+	fake.ensureTemplateRevisionExistsOnClusterMutex.Lock()
+	defer fake.ensureTemplateRevisionExistsOnClusterMutex.Unlock()
+	fake.EnsureTemplateRevisionExistsOnClusterStub = nil
+	fake.ensureTemplateRevisionExistsOnClusterReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) EnsureTemplateRevisionExistsOnClusterReturnsOnCall(i int, result1 error) {
+	fake.ensureTemplateRevisionExistsOnClusterMutex.Lock()
+	defer fake.ensureTemplateRevisionExistsOnClusterMutex.Unlock()
+	fake.EnsureTemplateRevisionExistsOnClusterStub = nil
+	if fake.ensureTemplateRevisionExistsOnClusterReturnsOnCall == nil {
+		fake.ensureTemplateRevisionExistsOnClusterReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.ensureTemplateRevisionExistsOnClusterReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRepository) GetDeliverable(arg1 context.Context, arg2 string, arg3 string) (*v1alpha1.Deliverable, error) {
 	fake.getDeliverableMutex.Lock()
 	ret, specificReturn := fake.getDeliverableReturnsOnCall[len(fake.getDeliverableArgsForCall)]
@@ -506,7 +687,7 @@ func (fake *FakeRepository) GetDeliverableReturnsOnCall(i int, result1 *v1alpha1
 	}{result1, result2}
 }
 
-func (fake *FakeRepository) GetDeliveriesForDeliverable(arg1 context.Context, arg2 *v1alpha1.Deliverable) ([]*v1alpha1.ClusterDelivery, error) {
+func (fake *FakeRepository) GetDeliveriesForDeliverable(arg1 context.Context, arg2 *v1alpha1.Deliverable) ([]*v1alpha1.ClusterDelivery, string, error) {
 	fake.getDeliveriesForDeliverableMutex.Lock()
 	ret, specificReturn := fake.getDeliveriesForDeliverableReturnsOnCall[len(fake.getDeliveriesForDeliverableArgsForCall)]
 	fake.getDeliveriesForDeliverableArgsForCall = append(fake.getDeliveriesForDeliverableArgsForCall, struct {
@@ -521,9 +702,9 @@ func (fake *FakeRepository) GetDeliveriesForDeliverable(arg1 context.Context, ar
 		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1, ret.result2, ret.result3
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
 func (fake *FakeRepository) GetDeliveriesForDeliverableCallCount() int {
@@ -532,7 +713,7 @@ func (fake *FakeRepository) GetDeliveriesForDeliverableCallCount() int {
 	return len(fake.getDeliveriesForDeliverableArgsForCall)
 }
 
-func (fake *FakeRepository) GetDeliveriesForDeliverableCalls(stub func(context.Context, *v1alpha1.Deliverable) ([]*v1alpha1.ClusterDelivery, error)) {
+func (fake *FakeRepository) GetDeliveriesForDeliverableCalls(stub func(context.Context, *v1alpha1.Deliverable) ([]*v1alpha1.ClusterDelivery, string, error)) {
 	fake.getDeliveriesForDeliverableMutex.Lock()
 	defer fake.getDeliveriesForDeliverableMutex.Unlock()
 	fake.GetDeliveriesForDeliverableStub = stub
@@ -545,30 +726,33 @@ func (fake *FakeRepository) GetDeliveriesForDeliverableArgsForCall(i int) (conte
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeRepository) GetDeliveriesForDeliverableReturns(result1 []*v1alpha1.ClusterDelivery, result2 error) {
+func (fake *FakeRepository) GetDeliveriesForDeliverableReturns(result1 []*v1alpha1.ClusterDelivery, result2 string, result3 error) {
 	fake.getDeliveriesForDeliverableMutex.Lock()
 	defer fake.getDeliveriesForDeliverableMutex.Unlock()
 	fake.GetDeliveriesForDeliverableStub = nil
 	fake.getDeliveriesForDeliverableReturns = struct {
 		result1 []*v1alpha1.ClusterDelivery
-		result2 error
-	}{result1, result2}
+		result2 string
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *FakeRepository) GetDeliveriesForDeliverableReturnsOnCall(i int, result1 []*v1alpha1.ClusterDelivery, result2 error) {
+func (fake *FakeRepository) GetDeliveriesForDeliverableReturnsOnCall(i int, result1 []*v1alpha1.ClusterDelivery, result2 string, result3 error) {
 	fake.getDeliveriesForDeliverableMutex.Lock()
 	defer fake.getDeliveriesForDeliverableMutex.Unlock()
 	fake.GetDeliveriesForDeliverableStub = nil
 	if fake.getDeliveriesForDeliverableReturnsOnCall == nil {
 		fake.getDeliveriesForDeliverableReturnsOnCall = make(map[int]struct {
 			result1 []*v1alpha1.ClusterDelivery
-			result2 error
+			result2 string
+			result3 error
 		})
 	}
 	fake.getDeliveriesForDeliverableReturnsOnCall[i] = struct {
 		result1 []*v1alpha1.ClusterDelivery
-		result2 error
-	}{result1, result2}
+		result2 string
+		result3 error
+	}{result1, result2, result3}
 }
 
 func (fake *FakeRepository) GetDelivery(arg1 context.Context, arg2 string) (*v1alpha1.ClusterDelivery, error) {
@@ -951,7 +1135,7 @@ func (fake *FakeRepository) GetSupplyChainReturnsOnCall(i int, result1 *v1alpha1
 	}{result1, result2}
 }
 
-func (fake *FakeRepository) GetSupplyChainsForWorkload(arg1 context.Context, arg2 *v1alpha1.Workload) ([]*v1alpha1.ClusterSupplyChain, error) {
+func (fake *FakeRepository) GetSupplyChainsForWorkload(arg1 context.Context, arg2 *v1alpha1.Workload) ([]*v1alpha1.ClusterSupplyChain, string, error) {
 	fake.getSupplyChainsForWorkloadMutex.Lock()
 	ret, specificReturn := fake.getSupplyChainsForWorkloadReturnsOnCall[len(fake.getSupplyChainsForWorkloadArgsForCall)]
 	fake.getSupplyChainsForWorkloadArgsForCall = append(fake.getSupplyChainsForWorkloadArgsForCall, struct {
@@ -966,9 +1150,9 @@ func (fake *FakeRepository) GetSupplyChainsForWorkload(arg1 context.Context, arg
 		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1, ret.result2, ret.result3
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
 func (fake *FakeRepository) GetSupplyChainsForWorkloadCallCount() int {
@@ -977,7 +1161,7 @@ func (fake *FakeRepository) GetSupplyChainsForWorkloadCallCount() int {
 	return len(fake.getSupplyChainsForWorkloadArgsForCall)
 }
 
-func (fake *FakeRepository) GetSupplyChainsForWorkloadCalls(stub func(context.Context, *v1alpha1.Workload) ([]*v1alpha1.ClusterSupplyChain, error)) {
+func (fake *FakeRepository) GetSupplyChainsForWorkloadCalls(stub func(context.Context, *v1alpha1.Workload) ([]*v1alpha1.ClusterSupplyChain, string, error)) {
 	fake.getSupplyChainsForWorkloadMutex.Lock()
 	defer fake.getSupplyChainsForWorkloadMutex.Unlock()
 	fake.GetSupplyChainsForWorkloadStub = stub
@@ -990,30 +1174,33 @@ func (fake *FakeRepository) GetSupplyChainsForWorkloadArgsForCall(i int) (contex
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeRepository) GetSupplyChainsForWorkloadReturns(result1 []*v1alpha1.ClusterSupplyChain, result2 error) {
+func (fake *FakeRepository) GetSupplyChainsForWorkloadReturns(result1 []*v1alpha1.ClusterSupplyChain, result2 string, result3 error) {
 	fake.getSupplyChainsForWorkloadMutex.Lock()
 	defer fake.getSupplyChainsForWorkloadMutex.Unlock()
 	fake.GetSupplyChainsForWorkloadStub = nil
 	fake.getSupplyChainsForWorkloadReturns = struct {
 		result1 []*v1alpha1.ClusterSupplyChain
-		result2 error
-	}{result1, result2}
+		result2 string
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *FakeRepository) GetSupplyChainsForWorkloadReturnsOnCall(i int, result1 []*v1alpha1.ClusterSupplyChain, result2 error) {
+func (fake *FakeRepository) GetSupplyChainsForWorkloadReturnsOnCall(i int, result1 []*v1alpha1.ClusterSupplyChain, result2 string, result3 error) {
 	fake.getSupplyChainsForWorkloadMutex.Lock()
 	defer fake.getSupplyChainsForWorkloadMutex.Unlock()
 	fake.GetSupplyChainsForWorkloadStub = nil
 	if fake.getSupplyChainsForWorkloadReturnsOnCall == nil {
 		fake.getSupplyChainsForWorkloadReturnsOnCall = make(map[int]struct {
 			result1 []*v1alpha1.ClusterSupplyChain
-			result2 error
+			result2 string
+			result3 error
 		})
 	}
 	fake.getSupplyChainsForWorkloadReturnsOnCall[i] = struct {
 		result1 []*v1alpha1.ClusterSupplyChain
-		result2 error
-	}{result1, result2}
+		result2 string
+		result3 error
+	}{result1, result2, result3}
 }
 
 func (fake *FakeRepository) GetTemplate(arg1 context.Context, arg2 string, arg3 string) (client.Object, error) {
@@ -1082,6 +1269,73 @@ func (fake *FakeRepository) GetTemplateReturnsOnCall(i int, result1 client.Objec
 	}{result1, result2}
 }
 
+func (fake *FakeRepository) GetTemplateRevision(arg1 context.Context, arg2 string, arg3 string, arg4 int64) (client.Object, error) {
+	fake.getTemplateRevisionMutex.Lock()
+	ret, specificReturn := fake.getTemplateRevisionReturnsOnCall[len(fake.getTemplateRevisionArgsForCall)]
+	fake.getTemplateRevisionArgsForCall = append(fake.getTemplateRevisionArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 int64
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.GetTemplateRevisionStub
+	fakeReturns := fake.getTemplateRevisionReturns
+	fake.recordInvocation("GetTemplateRevision", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getTemplateRevisionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) GetTemplateRevisionCallCount() int {
+	fake.getTemplateRevisionMutex.RLock()
+	defer fake.getTemplateRevisionMutex.RUnlock()
+	return len(fake.getTemplateRevisionArgsForCall)
+}
+
+func (fake *FakeRepository) GetTemplateRevisionCalls(stub func(context.Context, string, string, int64) (client.Object, error)) {
+	fake.getTemplateRevisionMutex.Lock()
+	defer fake.getTemplateRevisionMutex.Unlock()
+	fake.GetTemplateRevisionStub = stub
+}
+
+func (fake *FakeRepository) GetTemplateRevisionArgsForCall(i int) (context.Context, string, string, int64) {
+	fake.getTemplateRevisionMutex.RLock()
+	defer fake.getTemplateRevisionMutex.RUnlock()
+	argsForCall := fake.getTemplateRevisionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeRepository) GetTemplateRevisionReturns(result1 client.Object, result2 error) {
+	fake.getTemplateRevisionMutex.Lock()
+	defer fake.getTemplateRevisionMutex.Unlock()
+	fake.GetTemplateRevisionStub = nil
+	fake.getTemplateRevisionReturns = struct {
+		result1 client.Object
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetTemplateRevisionReturnsOnCall(i int, result1 client.Object, result2 error) {
+	fake.getTemplateRevisionMutex.Lock()
+	defer fake.getTemplateRevisionMutex.Unlock()
+	fake.GetTemplateRevisionStub = nil
+	if fake.getTemplateRevisionReturnsOnCall == nil {
+		fake.getTemplateRevisionReturnsOnCall = make(map[int]struct {
+			result1 client.Object
+			result2 error
+		})
+	}
+	fake.getTemplateRevisionReturnsOnCall[i] = struct {
+		result1 client.Object
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRepository) GetUnstructured(arg1 context.Context, arg2 *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 	fake.getUnstructuredMutex.Lock()
 	ret, specificReturn := fake.getUnstructuredReturnsOnCall[len(fake.getUnstructuredArgsForCall)]
@@ -1280,6 +1534,74 @@ func (fake *FakeRepository) ListUnstructuredReturnsOnCall(i int, result1 []*unst
 	}{result1, result2}
 }
 
+func (fake *FakeRepository) MergePatch(arg1 context.Context, arg2 *unstructured.Unstructured, arg3 []byte) error {
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.mergePatchMutex.Lock()
+	ret, specificReturn := fake.mergePatchReturnsOnCall[len(fake.mergePatchArgsForCall)]
+	fake.mergePatchArgsForCall = append(fake.mergePatchArgsForCall, struct {
+		arg1 context.Context
+		arg2 *unstructured.Unstructured
+		arg3 []byte
+	}{arg1, arg2, arg3Copy})
+	stub := fake.MergePatchStub
+	fakeReturns := fake.mergePatchReturns
+	fake.recordInvocation("MergePatch", []interface{}{arg1, arg2, arg3Copy})
+	fake.mergePatchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) MergePatchCallCount() int {
+	fake.mergePatchMutex.RLock()
+	defer fake.mergePatchMutex.RUnlock()
+	return len(fake.mergePatchArgsForCall)
+}
+
+func (fake *FakeRepository) MergePatchCalls(stub func(context.Context, *unstructured.Unstructured, []byte) error) {
+	fake.mergePatchMutex.Lock()
+	defer fake.mergePatchMutex.Unlock()
+	fake.MergePatchStub = stub
+}
+
+func (fake *FakeRepository) MergePatchArgsForCall(i int) (context.Context, *unstructured.Unstructured, []byte) {
+	fake.mergePatchMutex.RLock()
+	defer fake.mergePatchMutex.RUnlock()
+	argsForCall := fake.mergePatchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRepository) MergePatchReturns(result1 error) {
+	fake.mergePatchMutex.Lock()
+	defer fake.mergePatchMutex.Unlock()
+	fake.MergePatchStub = nil
+	fake.mergePatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) MergePatchReturnsOnCall(i int, result1 error) {
+	fake.mergePatchMutex.Lock()
+	defer fake.mergePatchMutex.Unlock()
+	fake.MergePatchStub = nil
+	if fake.mergePatchReturnsOnCall == nil {
+		fake.mergePatchReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.mergePatchReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRepository) StatusUpdate(arg1 context.Context, arg2 client.Object) error {
 	fake.statusUpdateMutex.Lock()
 	ret, specificReturn := fake.statusUpdateReturnsOnCall[len(fake.statusUpdateArgsForCall)]
@@ -1347,10 +1669,14 @@ func (fake *FakeRepository) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
+	fake.deleteTemplateRevisionMutex.RLock()
+	defer fake.deleteTemplateRevisionMutex.RUnlock()
 	fake.ensureImmutableObjectExistsOnClusterMutex.RLock()
 	defer fake.ensureImmutableObjectExistsOnClusterMutex.RUnlock()
 	fake.ensureMutableObjectExistsOnClusterMutex.RLock()
 	defer fake.ensureMutableObjectExistsOnClusterMutex.RUnlock()
+	fake.ensureTemplateRevisionExistsOnClusterMutex.RLock()
+	defer fake.ensureTemplateRevisionExistsOnClusterMutex.RUnlock()
 	fake.getDeliverableMutex.RLock()
 	defer fake.getDeliverableMutex.RUnlock()
 	fake.getDeliveriesForDeliverableMutex.RLock()
@@ -1371,12 +1697,16 @@ func (fake *FakeRepository) Invocations() map[string][][]interface{} {
 	defer fake.getSupplyChainsForWorkloadMutex.RUnlock()
 	fake.getTemplateMutex.RLock()
 	defer fake.getTemplateMutex.RUnlock()
+	fake.getTemplateRevisionMutex.RLock()
+	defer fake.getTemplateRevisionMutex.RUnlock()
 	fake.getUnstructuredMutex.RLock()
 	defer fake.getUnstructuredMutex.RUnlock()
 	fake.getWorkloadMutex.RLock()
 	defer fake.getWorkloadMutex.RUnlock()
 	fake.listUnstructuredMutex.RLock()
 	defer fake.listUnstructuredMutex.RUnlock()
+	fake.mergePatchMutex.RLock()
+	defer fake.mergePatchMutex.RUnlock()
 	fake.statusUpdateMutex.RLock()
 	defer fake.statusUpdateMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -16,8 +16,10 @@ package repository
 
 import (
 	"fmt"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
@@ -26,10 +28,105 @@ import (
 
 type SelectingObject interface {
 	GetSelectors() v1alpha1.LegacySelector
+	GetPriority() int32
+	GetNamespaceSelector() *metav1.LabelSelector
 	GetObjectKind() schema.ObjectKind
 	GetName() string
 }
 
+// NamespaceNotAllowedError is returned when an owner's labels are matched only by
+// blueprints whose namespaceSelector does not allow the owner's namespace.
+type NamespaceNotAllowedError struct {
+	Namespace  string
+	Blueprints []string
+}
+
+func (e NamespaceNotAllowedError) Error() string {
+	return fmt.Sprintf("namespace [%s] is not allowed by the namespaceSelector of [%s]", e.Namespace, strings.Join(e.Blueprints, ", "))
+}
+
+func hasNamespaceSelector(selectingObjects []SelectingObject) bool {
+	for _, selectingObject := range selectingObjects {
+		if selectingObject.GetNamespaceSelector() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByNamespace splits the selecting objects into those whose namespaceSelector
+// allows a namespace with the given labels, and those whose namespaceSelector does not.
+func FilterByNamespace(selectingObjects []SelectingObject, namespaceLabels map[string]string) ([]SelectingObject, []SelectingObject, error) {
+	var allowed, disallowed []SelectingObject
+	for _, selectingObject := range selectingObjects {
+		if selectingObject.GetNamespaceSelector() == nil {
+			allowed = append(allowed, selectingObject)
+			continue
+		}
+
+		namespaceSelector, err := metav1.LabelSelectorAsSelector(selectingObject.GetNamespaceSelector())
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"error handling namespaceSelector of [%s/%s]: %w",
+				selectingObject.GetObjectKind().GroupVersionKind().Kind,
+				selectingObject.GetName(),
+				err,
+			)
+		}
+
+		if namespaceSelector.Matches(labels.Set(namespaceLabels)) {
+			allowed = append(allowed, selectingObject)
+		} else {
+			disallowed = append(disallowed, selectingObject)
+		}
+	}
+	return allowed, disallowed, nil
+}
+
+// maxSelectionExplanations limits how many selecting objects are described
+// when explaining why there is not exactly one match
+const maxSelectionExplanations = 5
+
+// BestSelectorMatchInNamespace behaves as BestSelectorMatch over the selecting objects that
+// allow the selectable's namespace. If none of those match, but some disallowed selecting
+// objects would have, a NamespaceNotAllowedError is returned. When there is not exactly one
+// match, the reason explains how each allowed selecting object, or each match, was evaluated.
+func BestSelectorMatchInNamespace(selectable selector.Selectable, namespace string, namespaceLabels map[string]string, selectingObjects []SelectingObject) ([]SelectingObject, string, error) {
+	allowed, disallowed, err := FilterByNamespace(selectingObjects, namespaceLabels)
+	if err != nil {
+		return nil, "", err
+	}
+
+	matches, reason, err := BestSelectorMatch(selectable, allowed)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(matches) != 1 {
+		explanations := ExplainSelectorMatch(selectable, allowed)
+		if len(matches) > 1 {
+			explanations = SelectedExplanations(explanations)
+		}
+		reason = FormatSelectionExplanations(explanations, maxSelectionExplanations)
+	}
+	if len(matches) > 0 || len(disallowed) == 0 {
+		return matches, reason, nil
+	}
+
+	disallowedMatches, _, err := BestSelectorMatch(selectable, disallowed)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(disallowedMatches) > 0 {
+		var names []string
+		for _, disallowedMatch := range disallowedMatches {
+			names = append(names, disallowedMatch.GetName())
+		}
+		return nil, "", NamespaceNotAllowedError{Namespace: namespace, Blueprints: names}
+	}
+
+	return nil, reason, nil
+}
+
 func legacySelectorToSelector(legacySelector v1alpha1.LegacySelector) v1alpha1.Selector {
 	return v1alpha1.Selector{
 		LabelSelector: metav1.LabelSelector{
@@ -49,13 +146,15 @@ func selectingObjectsSelectors(selectingObjects []SelectingObject) []v1alpha1.Se
 }
 
 // BestSelectorMatch attempts at finding the selectors that best match their selectors
-// against the selectors.
-func BestSelectorMatch(selectable selector.Selectable, selectingObjects []SelectingObject) ([]SelectingObject, error) {
+// against the selectors. When more than one selecting object is equally specific, only
+// those with the highest priority are returned. The returned reason describes why the
+// matches were chosen.
+func BestSelectorMatch(selectable selector.Selectable, selectingObjects []SelectingObject) ([]SelectingObject, string, error) {
 	bestMatchingSelectingObjectIndices, err := selector.BestSelectorMatchIndices(selectable, selectingObjectsSelectors(selectingObjects))
 
 	if err != nil {
 		target := selectingObjects[err.SelectorIndex()]
-		return nil, fmt.Errorf(
+		return nil, "", fmt.Errorf(
 			"error handling selectors, selectorMatchExpressions or selectorMatchFields of [%s/%s]: %w",
 			target.GetObjectKind().GroupVersionKind().Kind,
 			target.GetName(),
@@ -66,12 +165,102 @@ func BestSelectorMatch(selectable selector.Selectable, selectingObjects []Select
 	numMatches := len(bestMatchingSelectingObjectIndices)
 
 	if numMatches == 0 {
-		return nil, nil
+		return nil, "", nil
 	}
 
-	matches := make([]SelectingObject, numMatches)
+	mostSpecific := make([]SelectingObject, numMatches)
 	for idx, selectingObjectIdx := range bestMatchingSelectingObjectIndices {
-		matches[idx] = selectingObjects[selectingObjectIdx]
+		mostSpecific[idx] = selectingObjects[selectingObjectIdx]
+	}
+
+	if numMatches == 1 {
+		return mostSpecific, "selected as the most specific match", nil
+	}
+
+	return highestPriority(mostSpecific)
+}
+
+func highestPriority(selectingObjects []SelectingObject) ([]SelectingObject, string, error) {
+	highest := selectingObjects[0].GetPriority()
+	for _, selectingObject := range selectingObjects[1:] {
+		if selectingObject.GetPriority() > highest {
+			highest = selectingObject.GetPriority()
+		}
+	}
+
+	var matches []SelectingObject
+	var outranked []string
+	for _, selectingObject := range selectingObjects {
+		if selectingObject.GetPriority() == highest {
+			matches = append(matches, selectingObject)
+		} else {
+			outranked = append(outranked, selectingObject.GetName())
+		}
+	}
+
+	if len(matches) > 1 {
+		return matches, "", nil
+	}
+
+	return matches, fmt.Sprintf("selected by priority [%d] over equally specific matches [%s]", highest, strings.Join(outranked, ", ")), nil
+}
+
+// SelectionExplanation explains how the selectors of a selecting object were
+// evaluated against a selectable. Selected is set for the objects that
+// BestSelectorMatch returns.
+type SelectionExplanation struct {
+	Kind     string
+	Name     string
+	Priority int32
+	Selected bool
+	selector.Explanation
+}
+
+// ExplainSelectorMatch explains, for each selecting object, which of its requirements
+// the selectable satisfies and the resulting specificity score.
+func ExplainSelectorMatch(selectable selector.Selectable, selectingObjects []SelectingObject) []SelectionExplanation {
+	explanations := selector.Explain(selectable, selectingObjectsSelectors(selectingObjects))
+
+	selected := map[SelectingObject]bool{}
+	if matches, _, err := BestSelectorMatch(selectable, selectingObjects); err == nil {
+		for _, match := range matches {
+			selected[match] = true
+		}
+	}
+
+	result := make([]SelectionExplanation, len(selectingObjects))
+	for idx, selectingObject := range selectingObjects {
+		result[idx] = SelectionExplanation{
+			Kind:        selectingObject.GetObjectKind().GroupVersionKind().Kind,
+			Name:        selectingObject.GetName(),
+			Priority:    selectingObject.GetPriority(),
+			Selected:    selected[selectingObject],
+			Explanation: explanations[idx],
+		}
+	}
+	return result
+}
+
+// SelectedExplanations returns the explanations of the selected objects.
+func SelectedExplanations(explanations []SelectionExplanation) []SelectionExplanation {
+	var selected []SelectionExplanation
+	for _, explanation := range explanations {
+		if explanation.Selected {
+			selected = append(selected, explanation)
+		}
+	}
+	return selected
+}
+
+// FormatSelectionExplanations summarizes at most limit explanations on a single line.
+func FormatSelectionExplanations(explanations []SelectionExplanation, limit int) string {
+	var summaries []string
+	for idx, explanation := range explanations {
+		if idx == limit {
+			summaries = append(summaries, fmt.Sprintf("and %d more", len(explanations)-limit))
+			break
+		}
+		summaries = append(summaries, fmt.Sprintf("[%s]: %s", explanation.Name, explanation.Summary()))
 	}
-	return matches, nil
+	return strings.Join(summaries, "; ")
 }
@@ -36,7 +36,7 @@ var _ = Describe("BestSelectorMatch", func() {
 
 	DescribeTable("cases",
 		func(tc testcase) {
-			actual, _ := repository.BestSelectorMatch(
+			actual, _, _ := repository.BestSelectorMatch(
 				tc.selectable, tc.selectingObjects,
 			)
 
@@ -311,6 +311,158 @@ var _ = Describe("BestSelectorMatch", func() {
 		}),
 	)
 
+	Describe("priority", func() {
+		var (
+			target       selectable
+			low, high    *selectingObject
+			otherHigh    *selectingObject
+			lessSpecific *selectingObject
+		)
+
+		BeforeEach(func() {
+			target = selectable{labels: labels2.Set{"type": "web", "tier": "frontend"}}
+			low = withPriority(newSelectingObjectWithID("low", "Test", labels2.Set{"type": "web"}, nil, nil), 1)
+			high = withPriority(newSelectingObjectWithID("high", "Test", labels2.Set{"type": "web"}, nil, nil), 10)
+			otherHigh = withPriority(newSelectingObjectWithID("other-high", "Test", labels2.Set{"tier": "frontend"}, nil, nil), 10)
+			lessSpecific = withPriority(newSelectingObjectWithID("less-specific", "Test", nil, nil, nil), 100)
+		})
+
+		Context("when a single object is the most specific match", func() {
+			It("ignores the priority of less specific matches", func() {
+				matches, reason, err := repository.BestSelectorMatch(target, []repository.SelectingObject{lessSpecific, low})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(ConsistOf(low))
+				Expect(reason).To(Equal("selected as the most specific match"))
+			})
+		})
+
+		Context("when equally specific objects have different priorities", func() {
+			It("returns the object with the highest priority", func() {
+				matches, reason, err := repository.BestSelectorMatch(target, []repository.SelectingObject{low, high})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(ConsistOf(high))
+				Expect(reason).To(Equal("selected by priority [10] over equally specific matches [low]"))
+			})
+		})
+
+		Context("when equally specific objects share the highest priority", func() {
+			It("returns all of them without a reason", func() {
+				matches, reason, err := repository.BestSelectorMatch(target, []repository.SelectingObject{low, high, otherHigh})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(ConsistOf(high, otherHigh))
+				Expect(reason).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("BestSelectorMatchInNamespace", func() {
+		var (
+			target            selectable
+			namespaceLabels   map[string]string
+			unrestricted      *selectingObject
+			productionOnly    *selectingObject
+			developmentOnly   *selectingObject
+			productionOnlyWeb *selectingObject
+		)
+
+		BeforeEach(func() {
+			target = selectable{labels: labels2.Set{"type": "web", "tier": "frontend"}}
+			namespaceLabels = map[string]string{"env": "dev"}
+			unrestricted = newSelectingObjectWithID("unrestricted", "Test", labels2.Set{"type": "web"}, nil, nil)
+			productionOnly = withNamespaceSelector(
+				newSelectingObjectWithID("production-only", "Test", labels2.Set{"type": "web", "tier": "frontend"}, nil, nil),
+				&metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			)
+			productionOnlyWeb = withNamespaceSelector(
+				newSelectingObjectWithID("production-only-web", "Test", labels2.Set{"type": "web"}, nil, nil),
+				&metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			)
+			developmentOnly = withNamespaceSelector(
+				newSelectingObjectWithID("development-only", "Test", labels2.Set{"type": "web"}, nil, nil),
+				&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"dev", "test"}}}},
+			)
+		})
+
+		It("ignores more specific matches that do not allow the namespace", func() {
+			matches, _, err := repository.BestSelectorMatchInNamespace(target, "my-ns", namespaceLabels, []repository.SelectingObject{unrestricted, productionOnly})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matches).To(ConsistOf(unrestricted))
+		})
+
+		It("matches objects whose namespace selector allows the namespace", func() {
+			matches, _, err := repository.BestSelectorMatchInNamespace(target, "my-ns", namespaceLabels, []repository.SelectingObject{developmentOnly, productionOnlyWeb})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matches).To(ConsistOf(developmentOnly))
+		})
+
+		It("returns a NamespaceNotAllowedError when only disallowed objects match", func() {
+			_, _, err := repository.BestSelectorMatchInNamespace(target, "my-ns", namespaceLabels, []repository.SelectingObject{productionOnly, productionOnlyWeb})
+			Expect(err).To(Equal(repository.NamespaceNotAllowedError{Namespace: "my-ns", Blueprints: []string{"production-only"}}))
+			Expect(err).To(MatchError("namespace [my-ns] is not allowed by the namespaceSelector of [production-only]"))
+		})
+
+		It("returns no matches when nothing matches the labels", func() {
+			matches, _, err := repository.BestSelectorMatchInNamespace(selectable{labels: labels2.Set{"type": "batch"}}, "my-ns", namespaceLabels, []repository.SelectingObject{productionOnly, unrestricted})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matches).To(BeEmpty())
+		})
+
+		It("returns an error when a namespace selector is invalid", func() {
+			invalid := withNamespaceSelector(
+				newSelectingObjectWithID("invalid", "Special", labels2.Set{"type": "web"}, nil, nil),
+				&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Sometimes"}}},
+			)
+			_, _, err := repository.BestSelectorMatchInNamespace(target, "my-ns", namespaceLabels, []repository.SelectingObject{invalid})
+			Expect(err).To(MatchError(ContainSubstring("error handling namespaceSelector of [Special/invalid]")))
+		})
+	})
+
+	Describe("ExplainSelectorMatch", func() {
+		var (
+			target       selectable
+			low, high    *selectingObject
+			mismatched   *selectingObject
+			explanations []repository.SelectionExplanation
+		)
+
+		BeforeEach(func() {
+			target = selectable{labels: labels2.Set{"type": "web"}}
+			low = withPriority(newSelectingObjectWithID("low", "Test", labels2.Set{"type": "web"}, nil, nil), 1)
+			high = withPriority(newSelectingObjectWithID("high", "Test", labels2.Set{"type": "web"}, nil, nil), 10)
+			mismatched = newSelectingObjectWithID("mismatched", "Test", labels2.Set{"type": "batch"}, nil, nil)
+
+			explanations = repository.ExplainSelectorMatch(target, []repository.SelectingObject{low, high, mismatched})
+		})
+
+		It("explains each selecting object in order", func() {
+			Expect(explanations).To(HaveLen(3))
+			Expect(explanations[0].Name).To(Equal("low"))
+			Expect(explanations[0].Kind).To(Equal("Test"))
+			Expect(explanations[0].Priority).To(Equal(int32(1)))
+			Expect(explanations[0].Matched).To(BeTrue())
+			Expect(explanations[2].Matched).To(BeFalse())
+		})
+
+		It("marks the objects BestSelectorMatch selects, taking priority into account", func() {
+			Expect(explanations[0].Selected).To(BeFalse())
+			Expect(explanations[1].Selected).To(BeTrue())
+			Expect(explanations[2].Selected).To(BeFalse())
+			Expect(repository.SelectedExplanations(explanations)).To(ConsistOf(explanations[1]))
+		})
+
+		It("formats the explanations on a single line", func() {
+			Expect(repository.FormatSelectionExplanations(explanations, 5)).To(Equal(
+				"[low]: matched with score [1]; [high]: matched with score [1]; [mismatched]: did not match: unsatisfied label [type=batch]",
+			))
+		})
+
+		It("limits the number of formatted explanations", func() {
+			Expect(repository.FormatSelectionExplanations(explanations, 1)).To(Equal(
+				"[low]: matched with score [1]; and 2 more",
+			))
+		})
+	})
+
 	Describe("malformed selectors", func() {
 		Context("label selector invalid", func() {
 			var sel []repository.SelectingObject
@@ -329,7 +481,7 @@ var _ = Describe("BestSelectorMatch", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := repository.BestSelectorMatch(selectable{}, sel)
+				_, _, err := repository.BestSelectorMatch(selectable{}, sel)
 				Expect(err).To(MatchError(ContainSubstring("error handling selectors, selectorMatchExpressions or selectorMatchFields of [Special/my-selector]")))
 				Expect(err).To(MatchError(ContainSubstring("selector labels or matchExpressions are not valid")))
 				Expect(err).To(MatchError(ContainSubstring("key: Invalid value")))
@@ -357,7 +509,7 @@ var _ = Describe("BestSelectorMatch", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := repository.BestSelectorMatch(selectable{}, sel)
+				_, _, err := repository.BestSelectorMatch(selectable{}, sel)
 				Expect(err).To(MatchError(ContainSubstring("error handling selectors, selectorMatchExpressions or selectorMatchFields of [Special/my-selector]")))
 				// TODO: 'pod' - Hmmmmm - perhaps we shouldn't be using v1 code?
 				Expect(err).To(MatchError(ContainSubstring("\"Matchingest\" is not a valid pod selector operator")))
@@ -386,6 +538,8 @@ type selectingObject struct {
 	metav1.TypeMeta
 	metav1.ObjectMeta
 	v1alpha1.LegacySelector
+	priority          int32
+	namespaceSelector *metav1.LabelSelector
 }
 
 func newSelectingObject(labels labels2.Set, expressions []metav1.LabelSelectorRequirement, fields []v1alpha1.FieldSelectorRequirement) *selectingObject {
@@ -412,3 +566,21 @@ func newSelectingObjectWithID(name, kind string, labels labels2.Set, expressions
 func (b *selectingObject) GetSelectors() v1alpha1.LegacySelector {
 	return b.LegacySelector
 }
+
+func (b *selectingObject) GetPriority() int32 {
+	return b.priority
+}
+
+func withPriority(obj *selectingObject, priority int32) *selectingObject {
+	obj.priority = priority
+	return obj
+}
+
+func (b *selectingObject) GetNamespaceSelector() *metav1.LabelSelector {
+	return b.namespaceSelector
+}
+
+func withNamespaceSelector(obj *selectingObject, namespaceSelector *metav1.LabelSelector) *selectingObject {
+	obj.namespaceSelector = namespaceSelector
+	return obj
+}
@@ -0,0 +1,227 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// spec fields of an extending template which are never inherited from the extended template
+var notInheritedSpecFields = []string{"extends", "overlay", "lifecycle", "retentionPolicy"}
+
+// resolveTemplateExtension returns the effective template of a template that
+// extends another, with the extended templates recorded in the
+// v1alpha1.TemplateBasesAnnotation. Templates that do not extend another are
+// returned as is.
+func (r *repository) resolveTemplateExtension(ctx context.Context, template client.Object) (client.Object, error) {
+	spec, err := v1alpha1.GetTemplateSpec(template)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Extends == nil {
+		return template, nil
+	}
+
+	chain := []client.Object{template}
+	var bases []v1alpha1.TemplateReference
+
+	for ref := spec.Extends; ref != nil; ref = spec.Extends {
+		for _, base := range bases {
+			if base == *ref {
+				return nil, fmt.Errorf("extends forms a cycle at template [%s/%s]", ref.Kind, ref.Name)
+			}
+		}
+		if len(bases) == v1alpha1.MaxTemplateExtensionDepth {
+			return nil, fmt.Errorf("extends chain is longer than %d templates", v1alpha1.MaxTemplateExtensionDepth)
+		}
+		bases = append(bases, *ref)
+
+		base, err := v1alpha1.GetAPITemplate(ref.Kind)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get api template [%s/%s]: %w", ref.Kind, ref.Name, err)
+		}
+
+		err = r.getObject(ctx, ref.Name, "", base)
+		if kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("extended template [%s/%s] not found", ref.Kind, ref.Name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get extended template [%s/%s]: %w", ref.Kind, ref.Name, err)
+		}
+
+		spec, err = v1alpha1.GetTemplateSpec(base)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, base)
+	}
+
+	effective := chain[len(chain)-1]
+	for i := len(chain) - 2; i >= 0; i-- {
+		effective, err = extendTemplate(effective, chain[i])
+		if err != nil {
+			return nil, fmt.Errorf("unable to extend template [%s/%s]: %w", bases[i].Kind, bases[i].Name, err)
+		}
+	}
+
+	if err := v1alpha1.SetTemplateBases(effective, bases); err != nil {
+		return nil, err
+	}
+
+	return effective, nil
+}
+
+// extendTemplate returns a copy of extending whose spec is the spec of base,
+// overridden by the fields set on extending and with the overlay of extending
+// applied to the template.
+func extendTemplate(base, extending client.Object) (client.Object, error) {
+	baseObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert extended template: %w", err)
+	}
+
+	extendingObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(extending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert template: %w", err)
+	}
+
+	baseSpec, _ := baseObj["spec"].(map[string]interface{})
+	extendingSpec, _ := extendingObj["spec"].(map[string]interface{})
+
+	spec := make(map[string]interface{}, len(baseSpec))
+	for key, value := range baseSpec {
+		spec[key] = value
+	}
+	for _, key := range notInheritedSpecFields {
+		delete(spec, key)
+	}
+
+	for key, value := range extendingSpec {
+		if key == "template" || key == "ytt" || key == "overlay" || key == "extends" || isEmptyValue(value) {
+			continue
+		}
+		spec[key] = value
+	}
+
+	extendingSpecTyped, err := v1alpha1.GetTemplateSpec(extending)
+	if err != nil {
+		return nil, err
+	}
+
+	if overlay := extendingSpecTyped.Overlay; overlay != nil {
+		template, ok := spec["template"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("overlay requires the extended template to specify template")
+		}
+
+		template, err = applyOverlay(template, overlay)
+		if err != nil {
+			return nil, err
+		}
+		spec["template"] = template
+	}
+
+	extendingObj["spec"] = spec
+
+	effective := reflect.New(reflect.TypeOf(extending).Elem()).Interface().(client.Object)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(extendingObj, effective); err != nil {
+		return nil, fmt.Errorf("failed to convert effective template: %w", err)
+	}
+
+	return effective, nil
+}
+
+func applyOverlay(template map[string]interface{}, overlay *v1alpha1.TemplateOverlay) (map[string]interface{}, error) {
+	original, err := json.Marshal(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal template: %w", err)
+	}
+
+	patched := original
+
+	if overlay.StrategicMergePatch != nil {
+		patched, err = strategicMergePatch(template, patched, overlay.StrategicMergePatch.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply overlay.strategicMergePatch: %w", err)
+		}
+	}
+
+	if len(overlay.JSONPatch) > 0 {
+		operations, err := json.Marshal(overlay.JSONPatch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal overlay.jsonPatch: %w", err)
+		}
+
+		patch, err := jsonpatch.DecodePatch(operations)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode overlay.jsonPatch: %w", err)
+		}
+
+		patched, err = patch.Apply(patched)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply overlay.jsonPatch: %w", err)
+		}
+	}
+
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(patched, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal patched template: %w", err)
+	}
+	return result, nil
+}
+
+// strategicMergePatch merges patch into original using the patch strategies
+// of the kind of template when it is known to Kubernetes, and as a JSON merge
+// patch otherwise.
+func strategicMergePatch(template map[string]interface{}, original, patch []byte) ([]byte, error) {
+	apiVersion, _ := template["apiVersion"].(string)
+	kind, _ := template["kind"].(string)
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err == nil {
+		if dataStruct, err := clientgoscheme.Scheme.New(gv.WithKind(kind)); err == nil {
+			return strategicpatch.StrategicMergePatch(original, patch, dataStruct)
+		}
+	}
+
+	return jsonpatch.MergePatch(original, patch)
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch typedValue := value.(type) {
+	case nil:
+		return true
+	case string:
+		return typedValue == ""
+	case map[string]interface{}:
+		return len(typedValue) == 0
+	case []interface{}:
+		return len(typedValue) == 0
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// RequirementResult records whether a single label, expression or field
+// requirement of a selector is satisfied.
+type RequirementResult struct {
+	Requirement string
+	Satisfied   bool
+}
+
+// Explanation records how a selector was evaluated against a selectable.
+// Score is the specificity used by BestSelectorMatchIndices and is only
+// meaningful when Matched. Best is set for the most specific matches.
+type Explanation struct {
+	Requirements []RequirementResult
+	Matched      bool
+	Score        int
+	Best         bool
+	Err          error
+}
+
+// Explain evaluates every requirement of each selector against the selectable,
+// as BestSelectorMatchIndices does. Explanations are returned in the order of
+// the selectors.
+func Explain(selectable Selectable, selectors []v1alpha1.Selector) []Explanation {
+	explanations := make([]Explanation, len(selectors))
+	valid := true
+	for idx, selector := range selectors {
+		explanations[idx] = evaluate(selectable, selector)
+		if explanations[idx].Err != nil {
+			valid = false
+		}
+	}
+
+	if valid {
+		for _, idx := range mostSpecific(explanations) {
+			explanations[idx].Best = true
+		}
+	}
+
+	return explanations
+}
+
+// Unsatisfied returns the requirements that are not satisfied.
+func (e Explanation) Unsatisfied() []string {
+	var unsatisfied []string
+	for _, requirement := range e.Requirements {
+		if !requirement.Satisfied {
+			unsatisfied = append(unsatisfied, requirement.Requirement)
+		}
+	}
+	return unsatisfied
+}
+
+// Summary is a single line describing the outcome of the explanation.
+func (e Explanation) Summary() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("invalid: %s", e.Err)
+	case e.Matched:
+		return fmt.Sprintf("matched with score [%d]", e.Score)
+	case len(e.Requirements) == 0:
+		return "did not match: no requirements"
+	default:
+		return fmt.Sprintf("did not match: unsatisfied %s", strings.Join(e.Unsatisfied(), ", "))
+	}
+}
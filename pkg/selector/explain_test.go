@@ -0,0 +1,143 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/selector"
+)
+
+var _ = Describe("Explain", func() {
+	var target selectable
+
+	BeforeEach(func() {
+		target = selectable{
+			labels: map[string]string{"type": "web", "tier": "frontend"},
+			Spec:   Spec{Color: "red", Age: 4},
+		}
+	})
+
+	It("records the outcome of each requirement and the specificity of matches", func() {
+		explanations := selector.Explain(target, []v1alpha1.Selector{
+			{
+				LabelSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"type": "web", "tier": "frontend"},
+				},
+			},
+			{
+				LabelSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"type": "web"},
+				},
+				MatchFields: []v1alpha1.FieldSelectorRequirement{
+					{Key: "spec.age", Operator: v1alpha1.FieldSelectorOpGt, Values: []string{"10"}},
+				},
+			},
+			{
+				LabelSelector: metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"frontend"}},
+					},
+				},
+			},
+		})
+
+		Expect(explanations).To(HaveLen(3))
+
+		Expect(explanations[0].Matched).To(BeTrue())
+		Expect(explanations[0].Score).To(Equal(2))
+		Expect(explanations[0].Best).To(BeTrue())
+		Expect(explanations[0].Requirements).To(Equal([]selector.RequirementResult{
+			{Requirement: "label [tier=frontend]", Satisfied: true},
+			{Requirement: "label [type=web]", Satisfied: true},
+		}))
+		Expect(explanations[0].Summary()).To(Equal("matched with score [2]"))
+
+		Expect(explanations[1].Matched).To(BeFalse())
+		Expect(explanations[1].Score).To(Equal(0))
+		Expect(explanations[1].Best).To(BeFalse())
+		Expect(explanations[1].Unsatisfied()).To(Equal([]string{"field [spec.age Gt [10]]"}))
+		Expect(explanations[1].Summary()).To(Equal("did not match: unsatisfied field [spec.age Gt [10]]"))
+
+		Expect(explanations[2].Matched).To(BeTrue())
+		Expect(explanations[2].Score).To(Equal(1))
+		Expect(explanations[2].Best).To(BeFalse())
+		Expect(explanations[2].Requirements).To(Equal([]selector.RequirementResult{
+			{Requirement: "expression [tier In [frontend]]", Satisfied: true},
+		}))
+	})
+
+	It("treats a missing field as unsatisfied", func() {
+		explanations := selector.Explain(target, []v1alpha1.Selector{
+			{MatchFields: []v1alpha1.FieldSelectorRequirement{{Key: "spec.missing", Operator: v1alpha1.FieldSelectorOpExists}}},
+		})
+		Expect(explanations[0].Err).NotTo(HaveOccurred())
+		Expect(explanations[0].Matched).To(BeFalse())
+	})
+
+	It("does not match a selector without requirements", func() {
+		explanations := selector.Explain(target, []v1alpha1.Selector{{}})
+		Expect(explanations[0].Matched).To(BeFalse())
+		Expect(explanations[0].Summary()).To(Equal("did not match: no requirements"))
+	})
+
+	It("agrees with BestSelectorMatchIndices", func() {
+		selectors := []v1alpha1.Selector{
+			{},
+			{LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"type": "web"}}},
+			{
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "frontend"}},
+				MatchFields:   []v1alpha1.FieldSelectorRequirement{{Key: "spec.color", Operator: v1alpha1.FieldSelectorOpIn, Values: []string{"red"}}},
+			},
+			{
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "backend"}},
+				MatchFields:   []v1alpha1.FieldSelectorRequirement{{Key: "spec.color", Operator: "NotAnOperator"}},
+			},
+		}
+
+		best, err := selector.BestSelectorMatchIndices(target, selectors)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(best).To(Equal([]int{2}))
+
+		explanations := selector.Explain(target, selectors)
+		var explainedBest []int
+		for idx, explanation := range explanations {
+			Expect(explanation.Err).NotTo(HaveOccurred())
+			if explanation.Best {
+				explainedBest = append(explainedBest, idx)
+			}
+		}
+		Expect(explainedBest).To(Equal(best))
+		Expect(explanations[3].Unsatisfied()).To(Equal([]string{"label [tier=backend]", "field [spec.color NotAnOperator []]"}))
+	})
+
+	It("records invalid selectors without marking any selector best", func() {
+		explanations := selector.Explain(target, []v1alpha1.Selector{
+			{LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"type": "web"}}},
+			{
+				LabelSelector: metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "Sometimes"}},
+				},
+			},
+		})
+		Expect(explanations[0].Matched).To(BeTrue())
+		Expect(explanations[0].Best).To(BeFalse())
+		Expect(explanations[1].Err).To(MatchError(ContainSubstring("selector labels or matchExpressions are not valid")))
+		Expect(explanations[1].Summary()).To(HavePrefix("invalid: "))
+	})
+})
@@ -16,6 +16,7 @@ package selector
 
 import (
 	"fmt"
+	"sort"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -50,63 +51,93 @@ func (e selectorMatchError) SelectorIndex() int {
 // `selectable` with the most specificity. Any error processing a selector includes the index of the
 // offending Selector.
 func BestSelectorMatchIndices(selectable Selectable, selectors []v1alpha1.Selector) ([]int, MatchError) {
-	var mostSpecificMatchingSelectors []int
-	var highWaterMark = 1
-
+	explanations := make([]Explanation, len(selectors))
 	for idx, selector := range selectors {
-		matchScore := 0
-
-		// -- Labels
-		sel, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
-		if err != nil {
+		explanations[idx] = evaluate(selectable, selector)
+		if explanations[idx].Err != nil {
 			return nil, selectorMatchError{
-				Err:                  fmt.Errorf("selector labels or matchExpressions are not valid: %w", err),
+				Err:                  explanations[idx].Err,
 				SelectingObjectIndex: idx,
 			}
 		}
-		if !sel.Matches(labels.Set(selectable.GetLabels())) {
-			continue // Bail early!
-		}
+	}
 
-		matchScore += len(selector.MatchLabels)
-		matchScore += len(selector.MatchExpressions)
+	return mostSpecific(explanations), nil
+}
 
-		// -- Fields
-		allFieldsMatched, err := matchesAllFields(selectable, selector.MatchFields)
-		if err != nil {
-			return nil, selectorMatchError{
-				Err:                  fmt.Errorf("failed to evaluate selector matchFields: %w", err),
-				SelectingObjectIndex: idx,
-			}
-		}
-		if !allFieldsMatched {
-			continue // Bail early!
-		}
-		matchScore += len(selector.MatchFields)
+// mostSpecific returns the indices of the matching explanations with the
+// highest score
+func mostSpecific(explanations []Explanation) []int {
+	var mostSpecificMatchingSelectors []int
+	var highWaterMark = 1
 
-		// -- decision time
-		if matchScore == highWaterMark {
+	for idx, explanation := range explanations {
+		if !explanation.Matched {
+			continue
+		}
+		if explanation.Score == highWaterMark {
 			mostSpecificMatchingSelectors = append(mostSpecificMatchingSelectors, idx)
-		} else if matchScore > highWaterMark {
-			highWaterMark = matchScore
+		} else if explanation.Score > highWaterMark {
+			highWaterMark = explanation.Score
 			mostSpecificMatchingSelectors = []int{idx}
 		}
 	}
 
-	return mostSpecificMatchingSelectors, nil
+	return mostSpecificMatchingSelectors
 }
 
-func matchesAllFields(source interface{}, requirements []v1alpha1.FieldSelectorRequirement) (bool, error) {
-	for _, requirement := range requirements {
-		match, err := Matches(requirement, source)
+// evaluate evaluates each requirement of the selector against the selectable,
+// labels first, then expressions, then fields. An error evaluating a field
+// requirement fails the selector only when every requirement before it is
+// satisfied; otherwise the selector could not match regardless.
+func evaluate(selectable Selectable, selector v1alpha1.Selector) Explanation {
+	var explanation Explanation
+
+	if _, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector); err != nil {
+		explanation.Err = fmt.Errorf("selector labels or matchExpressions are not valid: %w", err)
+		return explanation
+	}
+
+	allSatisfied := true
+	satisfied := func(requirement string, ok bool) {
+		explanation.Requirements = append(explanation.Requirements, RequirementResult{Requirement: requirement, Satisfied: ok})
+		allSatisfied = allSatisfied && ok
+	}
+
+	selectableLabels := labels.Set(selectable.GetLabels())
+
+	var keys []string
+	for key := range selector.MatchLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := selector.MatchLabels[key]
+		satisfied(fmt.Sprintf("label [%s=%s]", key, value), selectableLabels.Has(key) && selectableLabels.Get(key) == value)
+	}
+
+	for _, expression := range selector.MatchExpressions {
+		sel, _ := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{expression},
+		})
+		satisfied(fmt.Sprintf("expression [%s %s %v]", expression.Key, expression.Operator, expression.Values), sel.Matches(selectableLabels))
+	}
+
+	for _, field := range selector.MatchFields {
+		match, err := Matches(field, selectable)
 		if err != nil {
-			if _, ok := err.(eval.JsonPathDoesNotExistError); !ok {
-				return false, fmt.Errorf("unable to match field requirement with key [%s] operator [%s] values [%v]: %w", requirement.Key, requirement.Operator, requirement.Values, err)
+			if _, ok := err.(eval.JsonPathDoesNotExistError); !ok && allSatisfied {
+				explanation.Err = fmt.Errorf("failed to evaluate selector matchFields: unable to match field requirement with key [%s] operator [%s] values [%v]: %w", field.Key, field.Operator, field.Values, err)
+				return explanation
 			}
 		}
-		if !match {
-			return false, nil
-		}
+		satisfied(fmt.Sprintf("field [%s %s %v]", field.Key, field.Operator, field.Values), match)
+	}
+
+	explanation.Matched = allSatisfied && len(explanation.Requirements) > 0
+	if explanation.Matched {
+		explanation.Score = len(explanation.Requirements)
 	}
-	return true, nil
+
+	return explanation
 }
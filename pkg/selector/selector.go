@@ -15,7 +15,10 @@
 package selector
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/cartographer/pkg/eval"
@@ -49,7 +52,71 @@ func Matches(req v1alpha1.FieldSelectorRequirement, context interface{}) (bool,
 		return actualValue != nil, nil
 	case v1alpha1.FieldSelectorOpDoesNotExist:
 		return actualValue == nil, nil
+	case v1alpha1.FieldSelectorOpGt, v1alpha1.FieldSelectorOpLt:
+		if len(req.Values) != 1 {
+			return false, fmt.Errorf("operator %s requires a single value", req.Operator)
+		}
+		expected, err := strconv.ParseFloat(req.Values[0], 64)
+		if err != nil {
+			return false, fmt.Errorf("value [%s] for operator %s is not a number: %w", req.Values[0], req.Operator, err)
+		}
+		actual, ok := asNumber(actualValue)
+		if !ok {
+			return false, nil
+		}
+		if req.Operator == v1alpha1.FieldSelectorOpGt {
+			return actual > expected, nil
+		}
+		return actual < expected, nil
+	case v1alpha1.FieldSelectorOpMatches, v1alpha1.FieldSelectorOpNotMatches:
+		if len(req.Values) != 1 {
+			return false, fmt.Errorf("operator %s requires a single value", req.Operator)
+		}
+		re, err := regexp.Compile(req.Values[0])
+		if err != nil {
+			return false, fmt.Errorf("value [%s] for operator %s is not a valid regular expression: %w", req.Values[0], req.Operator, err)
+		}
+		matched := actualValue != nil && re.MatchString(asString(actualValue))
+		if req.Operator == v1alpha1.FieldSelectorOpMatches {
+			return matched, nil
+		}
+		return !matched, nil
 	default:
 		return false, fmt.Errorf("invalid operator %s for field selector", req.Operator)
 	}
 }
+
+// normalize returns the value as it would appear after a round trip through
+// JSON, so that typed fields (e.g. apiextensionsv1.JSON param values) compare
+// the same as their unstructured equivalents.
+func normalize(value interface{}) interface{} {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+func asNumber(value interface{}) (float64, bool) {
+	switch v := normalize(value).(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func asString(value interface{}) string {
+	normalized := normalize(value)
+	if s, ok := normalized.(string); ok {
+		return s
+	}
+	return fmt.Sprint(normalized)
+}
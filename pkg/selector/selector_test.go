@@ -16,7 +16,9 @@ package selector_test
 
 import (
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/cartographer/pkg/selector"
@@ -201,4 +203,71 @@ var _ = Describe("Selector", func() {
 
 	})
 
+	Context("numeric and regular expression operators", func() {
+		var context map[string]interface{}
+
+		BeforeEach(func() {
+			context = map[string]interface{}{
+				"replicas":       int64(3),
+				"ratio":          0.5,
+				"quotedReplicas": "3",
+				"url":            "https://github.com/acme/app.git",
+				"name":           "not-a-number",
+				"param":          apiextensionsv1.JSON{Raw: []byte(`2`)},
+			}
+		})
+
+		DescribeTable("matching",
+			func(key string, operator v1alpha1.FieldSelectorOperator, value string, expected bool) {
+				ret, err := selector.Matches(v1alpha1.FieldSelectorRequirement{
+					Key:      key,
+					Operator: operator,
+					Values:   []string{value},
+				}, context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ret).To(Equal(expected))
+			},
+			Entry("Gt with a greater integer", "replicas", v1alpha1.FieldSelectorOpGt, "1", true),
+			Entry("Gt with an equal integer", "replicas", v1alpha1.FieldSelectorOpGt, "3", false),
+			Entry("Gt with a float", "ratio", v1alpha1.FieldSelectorOpGt, "0.25", true),
+			Entry("Gt with a numeric string", "quotedReplicas", v1alpha1.FieldSelectorOpGt, "2", true),
+			Entry("Gt with a JSON value", "param", v1alpha1.FieldSelectorOpGt, "1", true),
+			Entry("Gt with a non numeric value", "name", v1alpha1.FieldSelectorOpGt, "1", false),
+			Entry("Lt with a lesser integer", "replicas", v1alpha1.FieldSelectorOpLt, "5", true),
+			Entry("Lt with an equal integer", "replicas", v1alpha1.FieldSelectorOpLt, "3", false),
+			Entry("Matches a matching string", "url", v1alpha1.FieldSelectorOpMatches, "^https://github.com/acme/", true),
+			Entry("Matches a mismatching string", "url", v1alpha1.FieldSelectorOpMatches, "^https://gitlab.com/", false),
+			Entry("Matches a number", "replicas", v1alpha1.FieldSelectorOpMatches, "^[0-9]+$", true),
+			Entry("NotMatches a matching string", "url", v1alpha1.FieldSelectorOpNotMatches, "^https://github.com/acme/", false),
+			Entry("NotMatches a mismatching string", "url", v1alpha1.FieldSelectorOpNotMatches, "^https://gitlab.com/", true),
+		)
+
+		It("errors when Gt is given a value that is not a number", func() {
+			_, err := selector.Matches(v1alpha1.FieldSelectorRequirement{
+				Key:      "replicas",
+				Operator: v1alpha1.FieldSelectorOpGt,
+				Values:   []string{"many"},
+			}, context)
+			Expect(err).To(MatchError(ContainSubstring("value [many] for operator Gt is not a number")))
+		})
+
+		It("errors when Matches is given an invalid regular expression", func() {
+			_, err := selector.Matches(v1alpha1.FieldSelectorRequirement{
+				Key:      "url",
+				Operator: v1alpha1.FieldSelectorOpMatches,
+				Values:   []string{"("},
+			}, context)
+			Expect(err).To(MatchError(ContainSubstring("value [(] for operator Matches is not a valid regular expression")))
+		})
+
+		It("errors when given more than one value", func() {
+			_, err := selector.Matches(v1alpha1.FieldSelectorRequirement{
+				Key:      "replicas",
+				Operator: v1alpha1.FieldSelectorOpLt,
+				Values:   []string{"1", "2"},
+			}, context)
+			Expect(err).To(MatchError("operator Lt requires a single value"))
+		})
+	})
+
 })
@@ -0,0 +1,95 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stamp
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// StampDigestAnnotation is set on objects stamped with a debounce window to a
+// digest of their content. Comparing it with the digest of a newly stamped
+// object tells whether the inputs have changed since the last run.
+const StampDigestAnnotation = "carto.run/stamp-digest"
+
+// Debounce annotates stampedObject with its digest and decides whether it may
+// be created, given the objects previously stamped for the same owner and the
+// run pending from a previous reconcile. A new run is pending until the
+// stamped content has been unchanged for window. While a run is pending,
+// Debounce returns it along with the most recent existing object, which
+// should be used in place of stampedObject.
+func Debounce(window time.Duration, stampedObject *unstructured.Unstructured, existingObjects []*unstructured.Unstructured, pendingRun *v1alpha1.PendingRun, now time.Time) (*v1alpha1.PendingRun, *unstructured.Unstructured) {
+	digest := stampDigest(stampedObject)
+
+	annotations := stampedObject.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[StampDigestAnnotation] = digest
+	stampedObject.SetAnnotations(annotations)
+
+	latest := mostRecent(existingObjects)
+	if latest == nil || latest.GetAnnotations()[StampDigestAnnotation] == digest {
+		return nil, nil
+	}
+
+	if pendingRun == nil || pendingRun.InputsDigest != digest {
+		return &v1alpha1.PendingRun{
+			InputsDigest: digest,
+			RunAfter:     metav1.NewTime(now.Add(window)),
+		}, latest
+	}
+
+	if now.Before(pendingRun.RunAfter.Time) {
+		return pendingRun, latest
+	}
+
+	return nil, nil
+}
+
+// stampDigest is a digest of the content of a stamped object, ignoring its
+// metadata so that labels and annotations set by Cartographer do not count as
+// changed inputs.
+func stampDigest(obj *unstructured.Unstructured) string {
+	content := map[string]interface{}{}
+	for key, value := range obj.Object {
+		if key != "metadata" {
+			content[key] = value
+		}
+	}
+
+	bytes, err := json.Marshal(content)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(bytes))
+}
+
+func mostRecent(objs []*unstructured.Unstructured) *unstructured.Unstructured {
+	var latest *unstructured.Unstructured
+	for _, obj := range objs {
+		if latest == nil || obj.GetCreationTimestamp().After(latest.GetCreationTimestamp().Time) {
+			latest = obj
+		}
+	}
+	return latest
+}
@@ -0,0 +1,175 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stamp_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/stamp"
+)
+
+var _ = Describe("Debounce", func() {
+	var (
+		now           time.Time
+		window        time.Duration
+		stampedObject *unstructured.Unstructured
+		pendingRun    *v1alpha1.PendingRun
+	)
+
+	makeObject := func(name string, value string, createdAgo time.Duration) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("test.run/v1alpha1")
+		obj.SetKind("TestObj")
+		obj.SetName(name)
+		obj.SetCreationTimestamp(metav1.NewTime(now.Add(-createdAgo)))
+		Expect(unstructured.SetNestedField(obj.Object, value, "spec", "foo")).To(Succeed())
+		return obj
+	}
+
+	stampedWith := func(value string) *unstructured.Unstructured {
+		obj := makeObject("", value, 0)
+		stamp.Debounce(window, obj, nil, nil, now)
+		return obj
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+		window = time.Minute
+		stampedObject = makeObject("", "new-value", 0)
+		stampedObject.SetGenerateName("my-run-")
+		pendingRun = nil
+	})
+
+	It("annotates the stamped object with a digest of its content", func() {
+		stamp.Debounce(window, stampedObject, nil, nil, now)
+
+		Expect(stampedObject.GetAnnotations()).To(HaveKeyWithValue(stamp.StampDigestAnnotation, HavePrefix("sha256:")))
+	})
+
+	It("ignores metadata in the digest", func() {
+		other := makeObject("", "new-value", 0)
+		other.SetLabels(map[string]string{"some": "label"})
+
+		stamp.Debounce(window, stampedObject, nil, nil, now)
+		stamp.Debounce(window, other, nil, nil, now)
+
+		Expect(other.GetAnnotations()[stamp.StampDigestAnnotation]).To(Equal(stampedObject.GetAnnotations()[stamp.StampDigestAnnotation]))
+	})
+
+	Context("when no object has been stamped before", func() {
+		It("does not debounce the first run", func() {
+			pending, latest := stamp.Debounce(window, stampedObject, nil, pendingRun, now)
+
+			Expect(pending).To(BeNil())
+			Expect(latest).To(BeNil())
+		})
+	})
+
+	Context("when the latest object was stamped with the same content", func() {
+		It("does not debounce", func() {
+			older := stampedWith("old-value")
+			older.SetCreationTimestamp(metav1.NewTime(now.Add(-2 * time.Hour)))
+			latest := stampedWith("new-value")
+			latest.SetCreationTimestamp(metav1.NewTime(now.Add(-time.Hour)))
+
+			pending, debounced := stamp.Debounce(window, stampedObject, []*unstructured.Unstructured{latest, older}, pendingRun, now)
+
+			Expect(pending).To(BeNil())
+			Expect(debounced).To(BeNil())
+		})
+	})
+
+	Context("when the content differs from the latest object", func() {
+		var existingObjects []*unstructured.Unstructured
+
+		BeforeEach(func() {
+			older := stampedWith("older-value")
+			older.SetName("older")
+			older.SetCreationTimestamp(metav1.NewTime(now.Add(-2 * time.Hour)))
+			latest := stampedWith("old-value")
+			latest.SetName("latest")
+			latest.SetCreationTimestamp(metav1.NewTime(now.Add(-time.Hour)))
+			existingObjects = []*unstructured.Unstructured{older, latest}
+		})
+
+		Context("and no run is pending", func() {
+			It("schedules a pending run after the window and returns the latest object", func() {
+				pending, debounced := stamp.Debounce(window, stampedObject, existingObjects, pendingRun, now)
+
+				Expect(pending).NotTo(BeNil())
+				Expect(pending.InputsDigest).To(Equal(stampedObject.GetAnnotations()[stamp.StampDigestAnnotation]))
+				Expect(pending.RunAfter.Time).To(Equal(now.Add(window)))
+				Expect(debounced.GetName()).To(Equal("latest"))
+			})
+		})
+
+		Context("and a run is pending for other inputs", func() {
+			BeforeEach(func() {
+				pendingRun = &v1alpha1.PendingRun{
+					InputsDigest: "sha256:other",
+					RunAfter:     metav1.NewTime(now.Add(-time.Second)),
+				}
+			})
+
+			It("restarts the window", func() {
+				pending, debounced := stamp.Debounce(window, stampedObject, existingObjects, pendingRun, now)
+
+				Expect(pending.InputsDigest).To(Equal(stampedObject.GetAnnotations()[stamp.StampDigestAnnotation]))
+				Expect(pending.RunAfter.Time).To(Equal(now.Add(window)))
+				Expect(debounced.GetName()).To(Equal("latest"))
+			})
+		})
+
+		Context("and a run is pending for the same inputs", func() {
+			BeforeEach(func() {
+				pendingRun = &v1alpha1.PendingRun{
+					InputsDigest: stampedWith("new-value").GetAnnotations()[stamp.StampDigestAnnotation],
+				}
+			})
+
+			Context("and the window has not passed", func() {
+				BeforeEach(func() {
+					pendingRun.RunAfter = metav1.NewTime(now.Add(30 * time.Second))
+				})
+
+				It("keeps the pending run", func() {
+					pending, debounced := stamp.Debounce(window, stampedObject, existingObjects, pendingRun, now)
+
+					Expect(pending).To(Equal(pendingRun))
+					Expect(debounced.GetName()).To(Equal("latest"))
+				})
+			})
+
+			Context("and the window has passed", func() {
+				BeforeEach(func() {
+					pendingRun.RunAfter = metav1.NewTime(now.Add(-time.Second))
+				})
+
+				It("lets the run be created", func() {
+					pending, debounced := stamp.Debounce(window, stampedObject, existingObjects, pendingRun, now)
+
+					Expect(pending).To(BeNil())
+					Expect(debounced).To(BeNil())
+				})
+			})
+		})
+	})
+})
@@ -79,3 +79,12 @@ func NewDeploymentFailedConditionMetError(err error) DeploymentFailedConditionMe
 func (e DeploymentFailedConditionMetError) Error() string {
 	return e.Err.Error()
 }
+
+type StaleStatusError struct {
+	Generation         int64
+	ObservedGeneration int64
+}
+
+func (e StaleStatusError) Error() string {
+	return fmt.Sprintf("status.observedGeneration [%d] is behind metadata.generation [%d]", e.ObservedGeneration, e.Generation)
+}
@@ -24,7 +24,9 @@ import (
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/cartographer/pkg/eval"
+	"github.com/vmware-tanzu/cartographer/pkg/tekton"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
+	"github.com/vmware-tanzu/cartographer/pkg/utils"
 )
 
 type DeploymentInput interface {
@@ -64,19 +66,58 @@ func NewReader(template client.Object, inputReader DeploymentInput) (Outputter,
 	switch v := template.(type) {
 
 	case *v1alpha1.ClusterSourceTemplate:
-		return NewSourceOutputReader(v), nil
+		return withStaleStatusCheck(v.Spec.TemplateSpec, NewSourceOutputReader(v)), nil
 	case *v1alpha1.ClusterImageTemplate:
-		return NewImageOutputReader(v), nil
+		return withStaleStatusCheck(v.Spec.TemplateSpec, NewImageOutputReader(v)), nil
 	case *v1alpha1.ClusterConfigTemplate:
-		return NewConfigOutputReader(v), nil
+		return withStaleStatusCheck(v.Spec.TemplateSpec, NewConfigOutputReader(v)), nil
 	case *v1alpha1.ClusterDeploymentTemplate:
-		return NewDeploymentPassThroughReader(inputReader, v), nil
+		if v.Spec.ObservedCompletion != nil {
+			// observedCompletion already waits for observedGeneration to match generation
+			return NewDeploymentPassThroughReader(inputReader, v), nil
+		}
+		return withStaleStatusCheck(v.Spec.TemplateSpec, NewDeploymentPassThroughReader(inputReader, v)), nil
 	case *v1alpha1.ClusterTemplate:
 		return NewNoOutputReader(), nil
 	}
 	return nil, fmt.Errorf("template does not match a known template")
 }
 
+// StaleStatusReader reads outputs only once the status of the stamped object
+// reflects its current generation.
+type StaleStatusReader struct {
+	reader Outputter
+}
+
+func (r *StaleStatusReader) Output(stampedObject *unstructured.Unstructured) (*templates.Output, error) {
+	if stampedObject != nil {
+		if observedGeneration, stale := utils.StaleObservedGeneration(stampedObject); stale {
+			return nil, StaleStatusError{
+				Generation:         stampedObject.GetGeneration(),
+				ObservedGeneration: observedGeneration,
+			}
+		}
+	}
+	return r.reader.Output(stampedObject)
+}
+
+func withStaleStatusCheck(spec v1alpha1.TemplateSpec, reader Outputter) Outputter {
+	if spec.IgnoreObservedGeneration {
+		return reader
+	}
+	return &StaleStatusReader{reader: reader}
+}
+
+// outputPath is the jsonpath of an output of the stamped object. Templates
+// with the tekton lifecycle may leave the path unset to read the output from
+// the result of the same name.
+func outputPath(spec v1alpha1.TemplateSpec, path string, resultName string, stampedObject *unstructured.Unstructured) string {
+	if path == "" && spec.Lifecycle == "tekton" {
+		return tekton.ResultPath(stampedObject, resultName)
+	}
+	return path
+}
+
 type SourceOutputReader struct {
 	template *v1alpha1.ClusterSourceTemplate
 }
@@ -87,21 +128,23 @@ func (r *SourceOutputReader) Output(stampedObject *unstructured.Unstructured) (*
 	}
 	// TODO: We don't need a Builder
 	evaluator := eval.EvaluatorBuilder()
-	url, err := evaluator.EvaluateJsonPath(r.template.Spec.URLPath, stampedObject.UnstructuredContent())
+	urlPath := outputPath(r.template.Spec.TemplateSpec, r.template.Spec.URLPath, "url", stampedObject)
+	url, err := evaluator.EvaluateJsonPath(urlPath, stampedObject.UnstructuredContent())
 	if err != nil {
 		return nil, JsonPathError{
 			Err: fmt.Errorf("failed to evaluate the url path [%s]: %w",
-				r.template.Spec.URLPath, err),
-			expression: r.template.Spec.URLPath,
+				urlPath, err),
+			expression: urlPath,
 		}
 	}
 
-	revision, err := evaluator.EvaluateJsonPath(r.template.Spec.RevisionPath, stampedObject.UnstructuredContent())
+	revisionPath := outputPath(r.template.Spec.TemplateSpec, r.template.Spec.RevisionPath, "revision", stampedObject)
+	revision, err := evaluator.EvaluateJsonPath(revisionPath, stampedObject.UnstructuredContent())
 	if err != nil {
 		return nil, JsonPathError{
 			Err: fmt.Errorf("failed to evaluate the revision path [%s]: %w",
-				r.template.Spec.RevisionPath, err),
-			expression: r.template.Spec.RevisionPath,
+				revisionPath, err),
+			expression: revisionPath,
 		}
 	}
 	return &templates.Output{
@@ -125,12 +168,13 @@ func (r *ConfigOutputReader) Output(stampedObject *unstructured.Unstructured) (*
 		return nil, fmt.Errorf("failed to evaluate path of empty object")
 	}
 	evaluator := eval.EvaluatorBuilder()
-	config, err := evaluator.EvaluateJsonPath(r.template.Spec.ConfigPath, stampedObject.UnstructuredContent())
+	configPath := outputPath(r.template.Spec.TemplateSpec, r.template.Spec.ConfigPath, "config", stampedObject)
+	config, err := evaluator.EvaluateJsonPath(configPath, stampedObject.UnstructuredContent())
 	if err != nil {
 		return nil, JsonPathError{
 			Err: fmt.Errorf("failed to evaluate spec.configPath [%s]: %w",
-				r.template.Spec.ConfigPath, err),
-			expression: r.template.Spec.ConfigPath,
+				configPath, err),
+			expression: configPath,
 		}
 	}
 
@@ -154,12 +198,13 @@ func (r *ImageOutputReader) Output(stampedObject *unstructured.Unstructured) (*t
 		return nil, fmt.Errorf("failed to evaluate path of empty object")
 	}
 	evaluator := eval.EvaluatorBuilder()
-	image, err := evaluator.EvaluateJsonPath(r.template.Spec.ImagePath, stampedObject.UnstructuredContent())
+	imagePath := outputPath(r.template.Spec.TemplateSpec, r.template.Spec.ImagePath, "image", stampedObject)
+	image, err := evaluator.EvaluateJsonPath(imagePath, stampedObject.UnstructuredContent())
 	if err != nil {
 		return nil, JsonPathError{
 			Err: fmt.Errorf("failed to evaluate the url path [%s]: %w",
-				r.template.Spec.ImagePath, err),
-			expression: r.template.Spec.ImagePath,
+				imagePath, err),
+			expression: imagePath,
 		}
 	}
 
@@ -120,6 +120,47 @@ var _ = Describe("Outputter", func() {
 				Expect(output.Source.URL).To(Equal("my-url"))
 				Expect(output.Source.Revision).To(Equal("my-revision"))
 			})
+
+			Context("the status of the stamped object is for an earlier generation", func() {
+				BeforeEach(func() {
+					stampedObject.SetGeneration(3)
+					Expect(unstructured.SetNestedField(stampedObject.Object, int64(2), "status", "observedGeneration")).To(Succeed())
+				})
+
+				It("returns a stale status error", func() {
+					_, err := reader.Output(stampedObject)
+					Expect(err).To(Equal(stamp.StaleStatusError{Generation: 3, ObservedGeneration: 2}))
+					Expect(err).To(MatchError("status.observedGeneration [2] is behind metadata.generation [3]"))
+				})
+
+				Context("the template ignores observedGeneration", func() {
+					BeforeEach(func() {
+						template.Spec.IgnoreObservedGeneration = true
+
+						var err error
+						reader, err = stamp.NewReader(template, noInputFake{})
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("returns the output", func() {
+						output, err := reader.Output(stampedObject)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(output.Source.URL).To(Equal("my-url"))
+					})
+				})
+			})
+
+			Context("the status of the stamped object is for the current generation", func() {
+				BeforeEach(func() {
+					stampedObject.SetGeneration(3)
+					Expect(unstructured.SetNestedField(stampedObject.Object, int64(3), "status", "observedGeneration")).To(Succeed())
+				})
+
+				It("returns the output", func() {
+					_, err := reader.Output(stampedObject)
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
 		})
 
 		Context("where the evaluator can not return a value", func() {
@@ -164,6 +205,83 @@ var _ = Describe("Outputter", func() {
 		})
 	})
 
+	Context("using the outputters of templates with the tekton lifecycle", func() {
+		var pipelineRun *unstructured.Unstructured
+
+		tektonSpec := v1alpha1.TemplateSpec{Lifecycle: "tekton"}
+
+		BeforeEach(func() {
+			pipelineRun = &unstructured.Unstructured{}
+			pipelineRun.SetAPIVersion("tekton.dev/v1beta1")
+			pipelineRun.SetKind("PipelineRun")
+			Expect(unstructured.SetNestedSlice(pipelineRun.Object, []interface{}{
+				map[string]interface{}{"name": "url", "value": "my-url"},
+				map[string]interface{}{"name": "revision", "value": "my-revision"},
+				map[string]interface{}{"name": "image", "value": "my-image"},
+				map[string]interface{}{"name": "config", "value": "my-config"},
+			}, "status", "pipelineResults")).To(Succeed())
+		})
+
+		It("reads the source from the url and revision results", func() {
+			reader, err := stamp.NewReader(&v1alpha1.ClusterSourceTemplate{
+				Spec: v1alpha1.SourceTemplateSpec{TemplateSpec: tektonSpec},
+			}, noInputFake{})
+			Expect(err).NotTo(HaveOccurred())
+
+			output, err := reader.Output(pipelineRun)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.Source.URL).To(Equal("my-url"))
+			Expect(output.Source.Revision).To(Equal("my-revision"))
+		})
+
+		It("reads the image from the image result", func() {
+			reader, err := stamp.NewReader(&v1alpha1.ClusterImageTemplate{
+				Spec: v1alpha1.ImageTemplateSpec{TemplateSpec: tektonSpec},
+			}, noInputFake{})
+			Expect(err).NotTo(HaveOccurred())
+
+			output, err := reader.Output(pipelineRun)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.Image).To(Equal("my-image"))
+		})
+
+		It("reads the config from the config result", func() {
+			reader, err := stamp.NewReader(&v1alpha1.ClusterConfigTemplate{
+				Spec: v1alpha1.ConfigTemplateSpec{TemplateSpec: tektonSpec},
+			}, noInputFake{})
+			Expect(err).NotTo(HaveOccurred())
+
+			output, err := reader.Output(pipelineRun)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.Config).To(Equal("my-config"))
+		})
+
+		It("prefers a path set on the template", func() {
+			Expect(unstructured.SetNestedField(pipelineRun.Object, "other-url", "status", "url")).To(Succeed())
+			reader, err := stamp.NewReader(&v1alpha1.ClusterSourceTemplate{
+				Spec: v1alpha1.SourceTemplateSpec{TemplateSpec: tektonSpec, URLPath: ".status.url"},
+			}, noInputFake{})
+			Expect(err).NotTo(HaveOccurred())
+
+			output, err := reader.Output(pipelineRun)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output.Source.URL).To(Equal("other-url"))
+			Expect(output.Source.Revision).To(Equal("my-revision"))
+		})
+
+		It("reports the path of a result the run has not reported", func() {
+			reader, err := stamp.NewReader(&v1alpha1.ClusterImageTemplate{
+				Spec: v1alpha1.ImageTemplateSpec{TemplateSpec: tektonSpec},
+			}, noInputFake{})
+			Expect(err).NotTo(HaveOccurred())
+
+			pipelineRun.Object["status"] = map[string]interface{}{}
+			_, err = reader.Output(pipelineRun)
+			Expect(err).To(BeAssignableToTypeOf(stamp.JsonPathError{}))
+			Expect(err.(stamp.JsonPathError).JsonPathExpression()).To(Equal(`.status.pipelineResults[?(@.name=="image")].value`))
+		})
+	})
+
 	Context("using an image outputter", func() {
 		var (
 			template *v1alpha1.ClusterImageTemplate
@@ -0,0 +1,121 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tekton holds the conventions of Tekton PipelineRuns and TaskRuns
+// that Cartographer relies on for templates with the tekton lifecycle.
+package tekton
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	Group = "tekton.dev"
+
+	PipelineRunKind = "PipelineRun"
+	TaskRunKind     = "TaskRun"
+)
+
+// cancelledStatuses are the values of spec.status with which a run is
+// cancelled or stopped
+var cancelledStatuses = map[string]bool{
+	"Cancelled":            true,
+	"CancelledRunFinally":  true,
+	"StoppedRunFinally":    true,
+	"PipelineRunCancelled": true,
+	"TaskRunCancelled":     true,
+}
+
+// failureDescriptions describe the reasons of a failed Succeeded condition
+var failureDescriptions = map[string]string{
+	"Failed":                   "failed",
+	"PipelineRunTimeout":       "timed out",
+	"TaskRunTimeout":           "timed out",
+	"Cancelled":                "was cancelled",
+	"PipelineRunCancelled":     "was cancelled",
+	"TaskRunCancelled":         "was cancelled",
+	"CancelledRunningFinally":  "was cancelled",
+	"StoppedRunningFinally":    "was stopped",
+	"PipelineValidationFailed": "is invalid",
+	"TaskRunValidationFailed":  "is invalid",
+	"ParameterMissing":         "is missing a parameter",
+	"ParameterTypeMismatch":    "has a parameter of the wrong type",
+	"CouldntGetPipeline":       "references a pipeline that could not be found",
+	"CouldntGetTask":           "references a task that could not be found",
+	"TaskRunImagePullFailed":   "could not pull an image",
+}
+
+// IsRun reports whether obj is a Tekton PipelineRun or TaskRun
+func IsRun(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return gvk.Group == Group && (gvk.Kind == PipelineRunKind || gvk.Kind == TaskRunKind)
+}
+
+// ResultPath is the jsonpath of the value of the named result of a run.
+// In tekton.dev/v1beta1 PipelineRuns report their results in
+// status.pipelineResults and TaskRuns in status.taskResults, while both
+// report them in status.results in tekton.dev/v1.
+func ResultPath(obj *unstructured.Unstructured, name string) string {
+	return fmt.Sprintf(`.status.%s[?(@.name=="%s")].value`, resultsField(obj), name)
+}
+
+func resultsField(obj *unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	if gvk.Version == "v1beta1" {
+		switch gvk.Kind {
+		case PipelineRunKind:
+			return "pipelineResults"
+		case TaskRunKind:
+			return "taskResults"
+		}
+	}
+	return "results"
+}
+
+// CancelPatch is a merge patch of spec.status requesting Tekton to cancel
+// the run
+func CancelPatch(obj *unstructured.Unstructured) []byte {
+	status := "Cancelled"
+	if obj.GetKind() == TaskRunKind {
+		status = "TaskRunCancelled"
+	}
+	return []byte(fmt.Sprintf(`{"spec":{"status":%q}}`, status))
+}
+
+// IsCancelled reports whether the run has been requested to be cancelled
+func IsCancelled(obj *unstructured.Unstructured) bool {
+	status, _, _ := unstructured.NestedString(obj.Object, "spec", "status")
+	return cancelledStatuses[status]
+}
+
+// FailureMessage describes why the run failed, given its Succeeded
+// condition
+func FailureMessage(obj *unstructured.Unstructured, succeeded metav1.Condition) string {
+	description, ok := failureDescriptions[succeeded.Reason]
+	if !ok {
+		description = "failed"
+	}
+
+	message := fmt.Sprintf("%s %s", obj.GetKind(), description)
+	if succeeded.Reason != "" {
+		message = fmt.Sprintf("%s [%s]", message, succeeded.Reason)
+	}
+	if succeeded.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, succeeded.Message)
+	}
+	return message
+}
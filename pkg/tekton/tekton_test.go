@@ -0,0 +1,120 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tekton_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/eval"
+	"github.com/vmware-tanzu/cartographer/pkg/tekton"
+)
+
+var _ = Describe("Tekton", func() {
+	makeRun := func(apiVersion string, kind string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetName("my-run")
+		return obj
+	}
+
+	Describe("IsRun", func() {
+		DescribeTable("identifies Tekton runs",
+			func(apiVersion string, kind string, expected bool) {
+				Expect(tekton.IsRun(makeRun(apiVersion, kind))).To(Equal(expected))
+			},
+			Entry("v1beta1 PipelineRun", "tekton.dev/v1beta1", "PipelineRun", true),
+			Entry("v1 PipelineRun", "tekton.dev/v1", "PipelineRun", true),
+			Entry("v1 TaskRun", "tekton.dev/v1", "TaskRun", true),
+			Entry("Pipeline", "tekton.dev/v1", "Pipeline", false),
+			Entry("other group", "example.com/v1", "PipelineRun", false),
+		)
+	})
+
+	Describe("ResultPath", func() {
+		DescribeTable("reads the named result of the run",
+			func(apiVersion string, kind string, field string) {
+				run := makeRun(apiVersion, kind)
+				Expect(unstructured.SetNestedSlice(run.Object, []interface{}{
+					map[string]interface{}{"name": "other", "value": "not-this-one"},
+					map[string]interface{}{"name": "url", "value": "https://example.com/source.tar.gz"},
+				}, "status", field)).To(Succeed())
+
+				value, err := eval.EvaluatorBuilder().EvaluateJsonPath(tekton.ResultPath(run, "url"), run.UnstructuredContent())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(value).To(Equal("https://example.com/source.tar.gz"))
+			},
+			Entry("v1beta1 PipelineRun", "tekton.dev/v1beta1", "PipelineRun", "pipelineResults"),
+			Entry("v1beta1 TaskRun", "tekton.dev/v1beta1", "TaskRun", "taskResults"),
+			Entry("v1 PipelineRun", "tekton.dev/v1", "PipelineRun", "results"),
+			Entry("v1 TaskRun", "tekton.dev/v1", "TaskRun", "results"),
+		)
+
+		It("does not find a result the run has not reported", func() {
+			run := makeRun("tekton.dev/v1beta1", "PipelineRun")
+
+			_, err := eval.EvaluatorBuilder().EvaluateJsonPath(tekton.ResultPath(run, "url"), run.UnstructuredContent())
+			Expect(err).To(BeAssignableToTypeOf(eval.JsonPathDoesNotExistError{}))
+		})
+	})
+
+	Describe("CancelPatch", func() {
+		It("cancels PipelineRuns", func() {
+			Expect(string(tekton.CancelPatch(makeRun("tekton.dev/v1beta1", "PipelineRun")))).To(Equal(`{"spec":{"status":"Cancelled"}}`))
+		})
+
+		It("cancels TaskRuns", func() {
+			Expect(string(tekton.CancelPatch(makeRun("tekton.dev/v1beta1", "TaskRun")))).To(Equal(`{"spec":{"status":"TaskRunCancelled"}}`))
+		})
+	})
+
+	Describe("IsCancelled", func() {
+		It("is false for a run that has not been cancelled", func() {
+			Expect(tekton.IsCancelled(makeRun("tekton.dev/v1", "PipelineRun"))).To(BeFalse())
+		})
+
+		It("is true for a run whose spec.status cancels it", func() {
+			run := makeRun("tekton.dev/v1", "PipelineRun")
+			Expect(unstructured.SetNestedField(run.Object, "Cancelled", "spec", "status")).To(Succeed())
+			Expect(tekton.IsCancelled(run)).To(BeTrue())
+		})
+	})
+
+	Describe("FailureMessage", func() {
+		It("describes known failure reasons", func() {
+			message := tekton.FailureMessage(makeRun("tekton.dev/v1", "PipelineRun"), metav1.Condition{
+				Type:    "Succeeded",
+				Status:  metav1.ConditionFalse,
+				Reason:  "PipelineRunTimeout",
+				Message: `PipelineRun "my-run" failed to finish within "1h0m0s"`,
+			})
+			Expect(message).To(Equal(`PipelineRun timed out [PipelineRunTimeout]: PipelineRun "my-run" failed to finish within "1h0m0s"`))
+		})
+
+		It("treats unknown reasons as failures", func() {
+			message := tekton.FailureMessage(makeRun("tekton.dev/v1", "TaskRun"), metav1.Condition{
+				Type:    "Succeeded",
+				Status:  metav1.ConditionFalse,
+				Reason:  "SomethingNew",
+				Message: "it broke",
+			})
+			Expect(message).To(Equal("TaskRun failed [SomethingNew]: it broke"))
+		})
+	})
+})
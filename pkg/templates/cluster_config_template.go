@@ -22,6 +22,14 @@ type clusterConfigTemplate struct {
 	template *v1alpha1.ClusterConfigTemplate
 }
 
+func (t *clusterConfigTemplate) GetRevision() int64 {
+	return t.template.Generation
+}
+
+func (t *clusterConfigTemplate) GetBaseTemplates() []v1alpha1.TemplateReference {
+	return getBaseTemplates(t.template)
+}
+
 func (t *clusterConfigTemplate) GetLifecycle() *Lifecycle {
 	lifecycle := convertLifecycle(t.template.Spec.Lifecycle)
 	return &lifecycle
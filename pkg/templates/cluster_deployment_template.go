@@ -22,6 +22,14 @@ type clusterDeploymentTemplate struct {
 	template *v1alpha1.ClusterDeploymentTemplate
 }
 
+func (t *clusterDeploymentTemplate) GetRevision() int64 {
+	return t.template.Generation
+}
+
+func (t *clusterDeploymentTemplate) GetBaseTemplates() []v1alpha1.TemplateReference {
+	return getBaseTemplates(t.template)
+}
+
 func (t *clusterDeploymentTemplate) GetLifecycle() *Lifecycle {
 	lifecycle := convertLifecycle(t.template.Spec.Lifecycle)
 	return &lifecycle
@@ -24,6 +24,14 @@ type clusterImageTemplate struct {
 	template *v1alpha1.ClusterImageTemplate
 }
 
+func (t *clusterImageTemplate) GetRevision() int64 {
+	return t.template.Generation
+}
+
+func (t *clusterImageTemplate) GetBaseTemplates() []v1alpha1.TemplateReference {
+	return getBaseTemplates(t.template)
+}
+
 func (t *clusterImageTemplate) GetLifecycle() *Lifecycle {
 	lifecycle := convertLifecycle(t.template.Spec.Lifecycle)
 	return &lifecycle
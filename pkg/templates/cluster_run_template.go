@@ -38,7 +38,9 @@ type Outputs map[string]apiextensionsv1.JSON
 type ClusterRunTemplate interface {
 	GetName() string
 	GetResourceTemplate() v1alpha1.TemplateSpec
-	GetLatestSuccessfulOutput(stampedObjects []*unstructured.Unstructured) (Outputs, *unstructured.Unstructured, error)
+	GetHealthRule() *v1alpha1.HealthRule
+	GetLatestSuccessfulOutput(stampedObjects []*unstructured.Unstructured, succeeded func(*unstructured.Unstructured) bool) (Outputs, *unstructured.Unstructured, error)
+	GetOutputs(stampedObject *unstructured.Unstructured) (Outputs, error)
 }
 
 type runTemplate struct {
@@ -46,15 +48,27 @@ type runTemplate struct {
 	evaluator eval.Evaluator
 }
 
-const SuccessStatusPath = `status.conditions[?(@.type=="Succeeded")].status`
+// DefaultRunTemplateHealthRule is used to determine the success of objects
+// stamped by run templates that do not specify a health rule.
+var DefaultRunTemplateHealthRule = v1alpha1.HealthRule{SingleConditionType: "Succeeded"}
 
-// GetLatestSuccessfulOutput returns the most recent condition:Succeeded=True stamped object.
+// GetHealthRule returns the health rule of the template, or the default rule
+// requiring a Succeeded condition if none is specified.
+func (t *runTemplate) GetHealthRule() *v1alpha1.HealthRule {
+	if t.template.Spec.HealthRule != nil {
+		return t.template.Spec.HealthRule
+	}
+	defaultRule := DefaultRunTemplateHealthRule
+	return &defaultRule
+}
+
+// GetLatestSuccessfulOutput returns the most recent stamped object for which succeeded is true.
 // If no output paths are specified, then you only receive the object and empty outputs.
 // If the output path is specified but doesn't match anything in the latest "suceeded" object, then an error is returned
 // along with the matched object.
 // if the output paths are all satisfied, then the outputs from the latest object, and the object itself, are returned.
-func (t *runTemplate) GetLatestSuccessfulOutput(stampedObjects []*unstructured.Unstructured) (Outputs, *unstructured.Unstructured, error) {
-	latestMatchingObject := t.getLatestSuccessfulObject(stampedObjects)
+func (t *runTemplate) GetLatestSuccessfulOutput(stampedObjects []*unstructured.Unstructured, succeeded func(*unstructured.Unstructured) bool) (Outputs, *unstructured.Unstructured, error) {
+	latestMatchingObject := t.getLatestSuccessfulObject(stampedObjects, succeeded)
 
 	if latestMatchingObject == nil {
 		return Outputs{}, nil, nil
@@ -65,15 +79,20 @@ func (t *runTemplate) GetLatestSuccessfulOutput(stampedObjects []*unstructured.U
 	return outputs, latestMatchingObject, outputError
 }
 
-func (t *runTemplate) getLatestSuccessfulObject(stampedObjects []*unstructured.Unstructured) *unstructured.Unstructured {
+// GetOutputs returns the outputs read from a single stamped object, regardless of its success.
+func (t *runTemplate) GetOutputs(stampedObject *unstructured.Unstructured) (Outputs, error) {
+	outputErr, outputs := t.getOutputsOfSingleObject(t.evaluator, *stampedObject)
+	return outputs, outputErr
+}
+
+func (t *runTemplate) getLatestSuccessfulObject(stampedObjects []*unstructured.Unstructured, succeeded func(*unstructured.Unstructured) bool) *unstructured.Unstructured {
 	var (
 		latestTime           time.Time // zero value is used for comparison
 		latestMatchingObject *unstructured.Unstructured
 	)
 
 	for _, stampedObject := range stampedObjects {
-		status, err := t.evaluator.EvaluateJsonPath(SuccessStatusPath, stampedObject.UnstructuredContent())
-		if !(err == nil && status == "True") {
+		if !succeeded(stampedObject) {
 			continue
 		}
 
@@ -116,6 +135,7 @@ func (t *runTemplate) GetName() string {
 
 func (t *runTemplate) GetResourceTemplate() v1alpha1.TemplateSpec {
 	return v1alpha1.TemplateSpec{
-		Template: &t.template.Spec.Template,
+		Template:                 &t.template.Spec.Template,
+		IgnoreObservedGeneration: t.template.Spec.IgnoreObservedGeneration,
 	}
 }
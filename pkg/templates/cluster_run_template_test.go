@@ -18,6 +18,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
@@ -34,7 +35,26 @@ func makeTemplate(outputs map[string]string) templates.ClusterRunTemplate {
 	return templates.NewRunTemplateModel(apiTemplate)
 }
 
+func succeeded(obj *unstructured.Unstructured) bool {
+	condition := utils.ExtractConditions(obj).ConditionWithType("Succeeded")
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
+
 var _ = Describe("ClusterRunTemplate", func() {
+	Describe("GetHealthRule", func() {
+		It("defaults to the Succeeded condition", func() {
+			Expect(makeTemplate(nil).GetHealthRule()).To(Equal(&v1alpha1.HealthRule{SingleConditionType: "Succeeded"}))
+		})
+
+		It("returns the health rule of the template", func() {
+			healthRule := &v1alpha1.HealthRule{SingleConditionType: "Complete"}
+			template := templates.NewRunTemplateModel(&v1alpha1.ClusterRunTemplate{
+				Spec: v1alpha1.RunTemplateSpec{HealthRule: healthRule},
+			})
+			Expect(template.GetHealthRule()).To(Equal(healthRule))
+		})
+	})
+
 	Describe("GetLatestSuccessfulOutput", func() {
 		var (
 			serializer     runtime.Serializer
@@ -54,7 +74,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 			})
 
 			It("returns no output", func() {
-				outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+				outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(outputs).To(BeEmpty())
 				Expect(outputSourceObject).To(BeNil())
@@ -89,7 +109,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 				})
 
 				It("returns no output", func() {
-					outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+					outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(outputs).To(BeEmpty())
 					Expect(outputSourceObject).To(BeNil())
@@ -118,7 +138,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 				})
 
 				It("returns no output", func() {
-					outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+					outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(outputs).To(BeEmpty())
 					Expect(outputSourceObject).To(BeNil())
@@ -155,7 +175,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 					})
 
 					It("returns no output, the matching object and an error", func() {
-						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 						Expect(err).To(MatchError("failed to evaluate path [status.nonexistant]: jsonpath returned empty list: status.nonexistant"))
 						Expect(outputs).To(BeEmpty())
 						Expect(outputSourceObject).To(Equal(stampedObjects[0]))
@@ -167,7 +187,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 						template = makeTemplate(map[string]string{})
 					})
 					It("returns an empty output and the matched object", func() {
-						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 						Expect(err).NotTo(HaveOccurred())
 						Expect(outputs).To(BeEmpty())
 						Expect(outputSourceObject).To(Equal(stampedObjects[0]))
@@ -176,7 +196,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 
 				Context("that matches the outputs", func() {
 					It("returns the outputs and the matched object", func() {
-						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 						Expect(err).NotTo(HaveOccurred())
 						Expect(outputs["an-output"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`"a thing"`)}))
 						Expect(outputSourceObject).To(Equal(stampedObjects[0]))
@@ -221,7 +241,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 				})
 
 				It("returns no output", func() {
-					outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+					outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(outputs).To(BeEmpty())
 					Expect(outputSourceObject).To(BeNil())
@@ -269,7 +289,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 				})
 
 				It("returns no output", func() {
-					outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+					outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(outputs).To(BeEmpty())
 					Expect(outputSourceObject).To(BeNil())
@@ -323,7 +343,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 					})
 
 					It("returns the empty outputs and the matched object", func() {
-						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 						Expect(err).NotTo(HaveOccurred())
 						Expect(outputs).To(BeEmpty())
 						Expect(outputSourceObject).To(Equal(firstObject))
@@ -338,7 +358,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 					})
 
 					It("returns no output, the matching object and an error", func() {
-						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 						Expect(err).To(MatchError("failed to evaluate path [status.nonexistant]: jsonpath returned empty list: status.nonexistant"))
 						Expect(outputs).To(BeEmpty())
 						Expect(outputSourceObject).To(Equal(firstObject))
@@ -354,7 +374,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 					})
 
 					It("returns the earliest matched outputs and the earliest matched object", func() {
-						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 						Expect(err).NotTo(HaveOccurred())
 						Expect(outputs["an-output"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`"first result"`)}))
 						Expect(outputSourceObject).To(Equal(firstObject))
@@ -413,7 +433,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 					})
 
 					It("returns an empty output and the latest matched object", func() {
-						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 						Expect(err).NotTo(HaveOccurred())
 						Expect(outputs).To(BeEmpty())
 						Expect(outputSourceObject).To(Equal(secondObject))
@@ -428,7 +448,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 					})
 
 					It("returns an error and the latest object", func() {
-						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 						Expect(err).To(MatchError("failed to evaluate path [status.nonexistant]: jsonpath returned empty list: status.nonexistant"))
 						Expect(outputs).To(BeEmpty())
 						Expect(outputSourceObject).To(Equal(secondObject))
@@ -442,7 +462,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 						})
 					})
 					It("returns an error and the latest object", func() {
-						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 						Expect(err).To(MatchError("failed to evaluate path [status.first-only]: jsonpath returned empty list: status.first-only"))
 						Expect(outputs).To(BeEmpty())
 						Expect(outputSourceObject).To(Equal(secondObject))
@@ -457,7 +477,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 					})
 
 					It("returns the latest", func() {
-						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 						Expect(err).NotTo(HaveOccurred())
 						Expect(outputs["an-output"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`"second only result"`)}))
 						Expect(outputSourceObject).To(Equal(secondObject))
@@ -472,7 +492,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 					})
 
 					It("returns the latest matched output and the latest matched object", func() {
-						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects)
+						outputs, outputSourceObject, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 						Expect(err).NotTo(HaveOccurred())
 						Expect(outputs["an-output"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`"second result"`)}))
 						Expect(outputSourceObject).To(Equal(secondObject))
@@ -516,7 +536,7 @@ var _ = Describe("ClusterRunTemplate", func() {
 			})
 
 			It("returns the output", func() {
-				outputs, _, err := template.GetLatestSuccessfulOutput(stampedObjects)
+				outputs, _, err := template.GetLatestSuccessfulOutput(stampedObjects, succeeded)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(outputs["my-complex-output"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`[{"name":"item1","value":{"field1":"one","field2":"two"}},{"name":"item2","value":"a string"}]`)}))
@@ -524,4 +544,25 @@ var _ = Describe("ClusterRunTemplate", func() {
 
 		})
 	})
+
+	Describe("GetOutputs", func() {
+		var stampedObject *unstructured.Unstructured
+
+		BeforeEach(func() {
+			stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{"simple-result": "a result"},
+			}}
+		})
+
+		It("returns the outputs of the object", func() {
+			outputs, err := makeTemplate(map[string]string{"an-output": "status.simple-result"}).GetOutputs(stampedObject)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputs).To(Equal(templates.Outputs{"an-output": apiextensionsv1.JSON{Raw: []byte(`"a result"`)}}))
+		})
+
+		It("returns an error when an output path is not satisfied", func() {
+			_, err := makeTemplate(map[string]string{"an-output": "status.nonexistant"}).GetOutputs(stampedObject)
+			Expect(err).To(MatchError(ContainSubstring("failed to evaluate path [status.nonexistant]")))
+		})
+	})
 })
@@ -22,6 +22,14 @@ type clusterSourceTemplate struct {
 	template *v1alpha1.ClusterSourceTemplate
 }
 
+func (t *clusterSourceTemplate) GetRevision() int64 {
+	return t.template.Generation
+}
+
+func (t *clusterSourceTemplate) GetBaseTemplates() []v1alpha1.TemplateReference {
+	return getBaseTemplates(t.template)
+}
+
 func (t *clusterSourceTemplate) GetLifecycle() *Lifecycle {
 	lifecycle := convertLifecycle(t.template.Spec.Lifecycle)
 	return &lifecycle
@@ -22,6 +22,14 @@ type clusterTemplate struct {
 	template *v1alpha1.ClusterTemplate
 }
 
+func (t *clusterTemplate) GetRevision() int64 {
+	return t.template.Generation
+}
+
+func (t *clusterTemplate) GetBaseTemplates() []v1alpha1.TemplateReference {
+	return getBaseTemplates(t.template)
+}
+
 func (t *clusterTemplate) GetLifecycle() *Lifecycle {
 	lifecycle := convertLifecycle(t.template.Spec.Lifecycle)
 	return &lifecycle
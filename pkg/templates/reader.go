@@ -33,6 +33,12 @@ type Reader interface {
 	IsYTTTemplate() bool
 	GetLifecycle() *Lifecycle
 	GetRetentionPolicy() v1alpha1.RetentionPolicy
+
+	// GetRevision returns the generation of the template, which identifies its ClusterTemplateRevision
+	GetRevision() int64
+
+	// GetBaseTemplates returns the templates extended, directly or transitively, by the template
+	GetBaseTemplates() []v1alpha1.TemplateReference
 }
 
 type Lifecycle string
@@ -50,6 +56,15 @@ func (l *Lifecycle) IsImmutable() bool {
 	return false
 }
 
+// getBaseTemplates reads the bases recorded on a template resolved by the repository
+func getBaseTemplates(template client.Object) []v1alpha1.TemplateReference {
+	bases, err := v1alpha1.GetTemplateBases(template)
+	if err != nil {
+		return nil
+	}
+	return bases
+}
+
 func convertLifecycle(lifecycleString string) Lifecycle {
 	switch lifecycleString {
 	case "immutable":
@@ -0,0 +1,161 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// DefaultStampCacheSize is the number of stamped objects held by a StampCache
+const DefaultStampCacheSize = 1000
+
+var (
+	stampCacheRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cartographer_stamp_cache_requests_total",
+			Help: "Number of lookups of stamped objects in the stamp cache, by result (hit or miss)",
+		},
+		[]string{"cache", "result"},
+	)
+	stampCacheEvictions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cartographer_stamp_cache_evictions_total",
+			Help: "Number of stamped objects evicted from the stamp cache",
+		},
+		[]string{"cache"},
+	)
+	stampCacheEntries = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cartographer_stamp_cache_entries",
+			Help: "Number of stamped objects held in the stamp cache",
+		},
+		[]string{"cache"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(stampCacheRequests, stampCacheEvictions, stampCacheEntries)
+}
+
+// StampCache holds the results of stamping, so that a template is only
+// stamped again when the template, the templating context or the labels change.
+type StampCache interface {
+	Get(key string) (*unstructured.Unstructured, bool)
+	Set(key string, stampedObject *unstructured.Unstructured)
+}
+
+// NewStampCache returns a StampCache holding at most size stamped objects,
+// evicting the least recently used. name identifies the cache in metrics.
+func NewStampCache(name string, size int) StampCache {
+	return &stampCache{
+		name:    name,
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+type stampCache struct {
+	mu      sync.Mutex
+	name    string
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type stampCacheEntry struct {
+	key           string
+	stampedObject *unstructured.Unstructured
+}
+
+func (c *stampCache) Get(key string) (*unstructured.Unstructured, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		stampCacheRequests.WithLabelValues(c.name, "miss").Inc()
+		return nil, false
+	}
+
+	stampCacheRequests.WithLabelValues(c.name, "hit").Inc()
+	c.order.MoveToFront(element)
+	return element.Value.(*stampCacheEntry).stampedObject.DeepCopy(), true
+}
+
+func (c *stampCache) Set(key string, stampedObject *unstructured.Unstructured) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*stampCacheEntry).stampedObject = stampedObject.DeepCopy()
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&stampCacheEntry{key: key, stampedObject: stampedObject.DeepCopy()})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*stampCacheEntry).key)
+		stampCacheEvictions.WithLabelValues(c.name).Inc()
+	}
+
+	stampCacheEntries.WithLabelValues(c.name).Set(float64(c.order.Len()))
+}
+
+// stampCacheKey hashes everything that contributes to a stamped object: the
+// template, identified by its UID and generation as well as its content (templates
+// read from revisions have no UID, and the generation of an extending template does
+// not change with the template it extends), the templating context, the labels and
+// metadata, and the owner.
+func stampCacheKey(template client.Object, resourceTemplate v1alpha1.TemplateSpec, templatingContext JsonPathContext, labels Labels, metadata Metadata, owner client.Object) (string, error) {
+	hash := sha256.New()
+	encoder := json.NewEncoder(hash)
+
+	apiVersion, kind := owner.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+
+	parts := []interface{}{
+		template.GetUID(),
+		template.GetGeneration(),
+		resourceTemplate.Template,
+		resourceTemplate.Ytt,
+		templatingContext,
+		labels,
+		metadata,
+		[]string{apiVersion, kind, owner.GetNamespace(), owner.GetName(), string(owner.GetUID())},
+	}
+
+	for _, part := range parts {
+		if err := encoder.Encode(part); err != nil {
+			return "", fmt.Errorf("failed to encode stamp cache key: %w", err)
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
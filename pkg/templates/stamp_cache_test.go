@@ -0,0 +1,207 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+)
+
+type countingStampCache struct {
+	templates.StampCache
+	gets, sets int
+}
+
+func (c *countingStampCache) Get(key string) (*unstructured.Unstructured, bool) {
+	c.gets++
+	return c.StampCache.Get(key)
+}
+
+func (c *countingStampCache) Set(key string, stampedObject *unstructured.Unstructured) {
+	c.sets++
+	c.StampCache.Set(key, stampedObject)
+}
+
+func stampCacheMetric(name, cache string, labels map[string]string) float64 {
+	families, err := metrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			metricLabels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				metricLabels[label.GetName()] = label.GetValue()
+			}
+			if metricLabels["cache"] != cache {
+				continue
+			}
+			for key, value := range labels {
+				if metricLabels[key] != value {
+					continue metrics
+				}
+			}
+			if metric.Counter != nil {
+				return metric.Counter.GetValue()
+			}
+			return metric.Gauge.GetValue()
+		}
+	}
+	return 0
+}
+
+var _ = Describe("StampCache", func() {
+	var (
+		cache   *countingStampCache
+		owner   *v1.ConfigMap
+		tmpl    *v1alpha1.ClusterTemplate
+		stamper templates.Stamper
+	)
+
+	BeforeEach(func() {
+		owner = &v1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "some-namespace", UID: "owner-uid"},
+		}
+		tmpl = &v1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "some-template", UID: "template-uid", Generation: 1},
+			Spec: v1alpha1.TemplateSpec{
+				Template: &runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "data": {"value": "$(value)$"}}`)},
+			},
+		}
+		stamper = templates.StamperBuilder(owner, map[string]interface{}{"value": "a"}, templates.Labels{"some": "label"})
+	})
+
+	Context("stamping through the cache", func() {
+		BeforeEach(func() {
+			cache = &countingStampCache{StampCache: templates.NewStampCache("stamp-cached-test", 10)}
+		})
+
+		It("stamps once and serves unchanged stamps from the cache", func() {
+			first, err := stamper.StampCached(context.Background(), cache, tmpl, tmpl.Spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cache.sets).To(Equal(1))
+
+			second, err := stamper.StampCached(context.Background(), cache, tmpl, tmpl.Spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cache.sets).To(Equal(1))
+			Expect(cache.gets).To(Equal(2))
+
+			Expect(second).To(Equal(first))
+		})
+
+		It("returns copies, so callers cannot modify cached stamps", func() {
+			first, err := stamper.StampCached(context.Background(), cache, tmpl, tmpl.Spec)
+			Expect(err).NotTo(HaveOccurred())
+			first.SetName("modified")
+
+			second, err := stamper.StampCached(context.Background(), cache, tmpl, tmpl.Spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.GetName()).To(BeEmpty())
+		})
+
+		It("stamps again when the template generation changes", func() {
+			_, err := stamper.StampCached(context.Background(), cache, tmpl, tmpl.Spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			tmpl.Generation = 2
+			_, err = stamper.StampCached(context.Background(), cache, tmpl, tmpl.Spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cache.sets).To(Equal(2))
+		})
+
+		It("stamps again when the templating context changes", func() {
+			_, err := stamper.StampCached(context.Background(), cache, tmpl, tmpl.Spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			stamper.TemplatingContext = map[string]interface{}{"value": "b"}
+			stamped, err := stamper.StampCached(context.Background(), cache, tmpl, tmpl.Spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cache.sets).To(Equal(2))
+			Expect(stamped.Object["data"]).To(Equal(map[string]interface{}{"value": "b"}))
+		})
+
+		It("stamps again when the labels change", func() {
+			_, err := stamper.StampCached(context.Background(), cache, tmpl, tmpl.Spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			stamper.Labels = templates.Labels{"some": "other-label"}
+			stamped, err := stamper.StampCached(context.Background(), cache, tmpl, tmpl.Spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cache.sets).To(Equal(2))
+			Expect(stamped.GetLabels()).To(HaveKeyWithValue("some", "other-label"))
+		})
+
+		It("does not cache stamping errors", func() {
+			tmpl.Spec.Template = &runtime.RawExtension{Raw: []byte(`{"data": "$(missing)$"}`)}
+			_, err := stamper.StampCached(context.Background(), cache, tmpl, tmpl.Spec)
+			Expect(err).To(HaveOccurred())
+			Expect(cache.sets).To(Equal(0))
+		})
+	})
+
+	It("stamps without a cache", func() {
+		stamped, err := stamper.StampCached(context.Background(), nil, tmpl, tmpl.Spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stamped.Object["data"]).To(Equal(map[string]interface{}{"value": "a"}))
+	})
+
+	Describe("NewStampCache", func() {
+		It("evicts the least recently used stamps beyond its size", func() {
+			cache := templates.NewStampCache("eviction-test", 2)
+			cache.Set("a", &unstructured.Unstructured{Object: map[string]interface{}{"kind": "A"}})
+			cache.Set("b", &unstructured.Unstructured{Object: map[string]interface{}{"kind": "B"}})
+
+			_, ok := cache.Get("a")
+			Expect(ok).To(BeTrue())
+
+			cache.Set("c", &unstructured.Unstructured{Object: map[string]interface{}{"kind": "C"}})
+
+			_, ok = cache.Get("b")
+			Expect(ok).To(BeFalse())
+			_, ok = cache.Get("a")
+			Expect(ok).To(BeTrue())
+			_, ok = cache.Get("c")
+			Expect(ok).To(BeTrue())
+		})
+
+		It("reports hits, misses, evictions and entries", func() {
+			cache := templates.NewStampCache("metrics-test", 1)
+			cache.Set("a", &unstructured.Unstructured{Object: map[string]interface{}{"kind": "A"}})
+			cache.Get("a")
+			cache.Get("a")
+			cache.Get("b")
+			cache.Set("b", &unstructured.Unstructured{Object: map[string]interface{}{"kind": "B"}})
+
+			Expect(stampCacheMetric("cartographer_stamp_cache_requests_total", "metrics-test", map[string]string{"result": "hit"})).To(Equal(2.0))
+			Expect(stampCacheMetric("cartographer_stamp_cache_requests_total", "metrics-test", map[string]string{"result": "miss"})).To(Equal(1.0))
+			Expect(stampCacheMetric("cartographer_stamp_cache_evictions_total", "metrics-test", nil)).To(Equal(1.0))
+			Expect(stampCacheMetric("cartographer_stamp_cache_entries", "metrics-test", nil)).To(Equal(1.0))
+		})
+	})
+})
@@ -41,6 +41,13 @@ import (
 
 type Labels map[string]string
 
+// Metadata holds labels and annotations set on stamped objects, beneath the
+// labels and annotations present in the template and Cartographer's own labels
+type Metadata struct {
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
 type pathStack []interface{}
 
 func (s *pathStack) pushString(str string) {
@@ -83,6 +90,7 @@ type Stamper struct {
 	TemplatingContext JsonPathContext
 	Owner             client.Object
 	Labels            Labels
+	Metadata          Metadata
 }
 
 func StamperBuilder(owner client.Object, templatingContext JsonPathContext, labels Labels) Stamper {
@@ -172,8 +180,38 @@ func (s *Stamper) Stamp(ctx context.Context, resourceTemplate v1alpha1.TemplateS
 		},
 	})
 
-	s.mergeLabels(stampedObject)
+	s.mergeMetadata(stampedObject)
+
+	return stampedObject, nil
+}
+
+// StampCached stamps resourceTemplate of template, returning the previously stamped
+// object from cache when neither the template, the templating context nor the
+// labels have changed since. A nil cache always stamps.
+func (s *Stamper) StampCached(ctx context.Context, cache StampCache, template client.Object, resourceTemplate v1alpha1.TemplateSpec) (*unstructured.Unstructured, error) {
+	if cache == nil {
+		return s.Stamp(ctx, resourceTemplate)
+	}
+
+	log := logr.FromContextOrDiscard(ctx)
+
+	key, err := stampCacheKey(template, resourceTemplate, s.TemplatingContext, s.Labels, s.Metadata, s.Owner)
+	if err != nil {
+		log.V(logger.DEBUG).Info("unable to compute stamp cache key", "error", err.Error())
+		return s.Stamp(ctx, resourceTemplate)
+	}
+
+	if stampedObject, ok := cache.Get(key); ok {
+		log.V(logger.DEBUG).Info("stamp cache hit")
+		return stampedObject, nil
+	}
+
+	stampedObject, err := s.Stamp(ctx, resourceTemplate)
+	if err != nil {
+		return nil, err
+	}
 
+	cache.Set(key, stampedObject)
 	return stampedObject, nil
 }
 
@@ -257,15 +295,29 @@ func (s *Stamper) applyYtt(ctx context.Context, template string) (*unstructured.
 	return stampedObject, nil
 }
 
-func (s *Stamper) mergeLabels(obj *unstructured.Unstructured) {
-	labels := obj.GetLabels()
-	if labels == nil {
-		labels = map[string]string{}
+func (s *Stamper) mergeMetadata(obj *unstructured.Unstructured) {
+	labels := map[string]string{}
+	for key, value := range s.Metadata.Labels {
+		labels[key] = value
+	}
+	for key, value := range obj.GetLabels() {
+		labels[key] = value
 	}
-
 	for key, value := range s.Labels {
 		labels[key] = value
 	}
-
 	obj.SetLabels(labels)
+
+	if len(s.Metadata.Annotations) == 0 {
+		return
+	}
+
+	annotations := map[string]string{}
+	for key, value := range s.Metadata.Annotations {
+		annotations[key] = value
+	}
+	for key, value := range obj.GetAnnotations() {
+		annotations[key] = value
+	}
+	obj.SetAnnotations(annotations)
 }
@@ -145,6 +145,67 @@ var _ = Describe("Stamper", func() {
 			})
 		})
 
+		Describe("labels and annotations", func() {
+			var (
+				stamper  templates.Stamper
+				template v1alpha1.TemplateSpec
+			)
+
+			BeforeEach(func() {
+				owner := &v1.ConfigMap{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "ConfigMap",
+						APIVersion: "v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-config-map",
+						Namespace: "owner-ns",
+					},
+				}
+
+				stamper = templates.StamperBuilder(owner, struct{}{}, templates.Labels{"carto.run/workload-name": "my-workload"})
+				stamper.Metadata = templates.Metadata{
+					Labels: map[string]string{
+						"team":                    "from-metadata",
+						"tier":                    "from-metadata",
+						"carto.run/workload-name": "from-metadata",
+					},
+					Annotations: map[string]string{
+						"example.com/on-call":     "from-metadata",
+						"example.com/cost-center": "from-metadata",
+					},
+				}
+
+				template = v1alpha1.TemplateSpec{
+					Template: &runtime.RawExtension{
+						Raw: []byte(`{
+							"kind": "Silly",
+							"apiVersion": "silly.io/v1",
+							"metadata": {
+								"labels": { "tier": "from-template" },
+								"annotations": { "example.com/cost-center": "from-template" }
+							}
+						}`),
+					},
+				}
+			})
+
+			It("sets the metadata beneath the labels and annotations of the template", func() {
+				stamped, err := stamper.Stamp(context.TODO(), template)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(stamped.GetLabels()).To(Equal(map[string]string{
+					"team":                    "from-metadata",
+					"tier":                    "from-template",
+					"carto.run/workload-name": "my-workload",
+				}))
+				Expect(stamped.GetAnnotations()).To(Equal(map[string]string{
+					"example.com/on-call":     "from-metadata",
+					"example.com/cost-center": "from-template",
+				}))
+			})
+		})
+
 		DescribeTable("tag evaluation of template",
 			func(tmpl string, subJSON string, expected interface{}, expectedErr string) {
 				template := v1alpha1.TemplateSpec{
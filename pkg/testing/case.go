@@ -22,6 +22,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/vmware-tanzu/cartographer/pkg/realizer"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
 )
 
@@ -40,10 +41,12 @@ type Test struct {
 
 // Given must specify a Template and a Workload.
 // SupplyChain is optional
+// ParamSources is optional, and required only when params use valueFrom
 type Given struct {
-	Template    Template
-	Workload    Workload
-	SupplyChain SupplyChain
+	Template     Template
+	Workload     Workload
+	SupplyChain  SupplyChain
+	ParamSources ParamSources
 }
 
 func (c *Test) Run() error {
@@ -109,5 +112,14 @@ func (i *Given) getActualObject() (*unstructured.Unstructured, error) {
 		i.SupplyChain = &MockSupplyChain{}
 	}
 
-	return i.SupplyChain.stamp(ctx, workload, *apiTemplate, template)
+	var paramSources []*unstructured.Unstructured
+	if i.ParamSources != nil {
+		paramSources, err = i.ParamSources.GetParamSources()
+		if err != nil {
+			return nil, fmt.Errorf("get param sources failed: %w", err)
+		}
+	}
+	resolver := realizer.NewParamValueResolver(paramSourceGetter(paramSources), workload.Namespace)
+
+	return i.SupplyChain.stamp(ctx, workload, *apiTemplate, template, resolver)
 }
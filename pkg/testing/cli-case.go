@@ -52,6 +52,7 @@ type testInfoGiven struct {
 	Workload        *string             `yaml:"workload"`
 	MockSupplyChain testInfoMockSC      `yaml:"mockSupplyChain"`
 	SupplyChain     testInfoSupplyChain `yaml:"supplyChain"`
+	ParamSources    []string            `yaml:"paramSources"`
 }
 
 type testInfoTemplate struct {
@@ -60,8 +61,9 @@ type testInfoTemplate struct {
 }
 
 type testInfoMockSC struct {
-	BlueprintInputs *Inputs                   `yaml:"blueprintInputs"`
-	BlueprintParams []v1alpha1.BlueprintParam `yaml:"blueprintParams"`
+	BlueprintInputs         *Inputs                   `yaml:"blueprintInputs"`
+	BlueprintParams         []v1alpha1.BlueprintParam `yaml:"blueprintParams"`
+	BlueprintMetadataPolicy *v1alpha1.MetadataPolicy  `yaml:"blueprintMetadataPolicy"`
 }
 
 type testInfoSupplyChain struct {
@@ -95,6 +97,8 @@ func populateTestCase(testCase *Test, directory string) (*Test, error) {
 		return nil, fmt.Errorf("populate testCase workload: %w", err)
 	}
 
+	testCase = populateTestCaseParamSources(testCase, directory, info)
+
 	newExpectedFilePath, err := getLocallySpecifiedPath(directory, expectedDefaultFilename, info.Expected)
 	if err != nil {
 		return nil, fmt.Errorf("get expected file specified in directory %s: %w", directory, err)
@@ -176,6 +180,20 @@ func populateTestCaseWorkload(testCase *Test, directory string, info *testInfo)
 	return testCase, nil
 }
 
+func populateTestCaseParamSources(testCase *Test, directory string, info *testInfo) *Test {
+	if len(info.Given.ParamSources) == 0 {
+		return testCase
+	}
+
+	var paths []string
+	for _, path := range info.Given.ParamSources {
+		paths = append(paths, filepath.Join(directory, path))
+	}
+	testCase.Given.ParamSources = &ParamSourcesFile{Paths: paths}
+
+	return testCase
+}
+
 func populateTestCaseTemplate(testCase *Test, directory string, info *testInfo) (*Test, error) {
 	newTemplateFile := TemplateFile{}
 
@@ -218,6 +236,11 @@ func populateTestCaseMockSupplyChain(testCase *Test, info *testInfo) (*Test, boo
 		mockSupplyChainSpecified = true
 	}
 
+	if info.Given.MockSupplyChain.BlueprintMetadataPolicy != nil {
+		mockSupplyChain.MetadataPolicy = info.Given.MockSupplyChain.BlueprintMetadataPolicy
+		mockSupplyChainSpecified = true
+	}
+
 	testCase.Given.SupplyChain = &mockSupplyChain
 
 	return testCase, mockSupplyChainSpecified
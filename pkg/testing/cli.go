@@ -128,4 +128,10 @@ func init() {
 
 	_ = templateCmd.MarkFlagRequired("directory")
 	_ = templateCmd.MarkFlagDirname("directory")
+
+	rootCmd.AddCommand(explainCmd)
+	explainCmd.Flags().StringVar(&explainWorkload, "workload", "", "workload file to explain supply chain selection for")
+	explainCmd.Flags().StringSliceVar(&explainSupplyChains, "supply-chain", nil, "supply chain file or directory of supply chain files")
+	explainCmd.Flags().StringVar(&explainDeliverable, "deliverable", "", "deliverable file to explain delivery selection for")
+	explainCmd.Flags().StringSliceVar(&explainDeliveries, "delivery", nil, "delivery file or directory of delivery files")
 }
@@ -0,0 +1,201 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testing
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"github.com/vmware-tanzu/cartographer/pkg/selector"
+)
+
+var (
+	explainWorkload     string
+	explainSupplyChains []string
+	explainDeliverable  string
+	explainDeliveries   []string
+)
+
+var explainCmd = &cobra.Command{
+	Use:     "explain",
+	Version: version,
+	Short:   "explain why supply chains or deliveries do or do not select an owner",
+	Long: `the explain command evaluates the selectors of local supply chain (or delivery) files against a
+local workload (or deliverable) file, reporting which requirements are satisfied and the
+specificity score of each match. Supply chains (or deliveries) whose namespaceSelector does not
+allow the namespace are listed without being evaluated.
+Read more at cartographer.sh`,
+	Example: `cartotest explain --workload workload.yaml --supply-chain ./supply-chains/
+cartotest explain --deliverable deliverable.yaml --delivery delivery.yaml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		switch {
+		case explainWorkload != "" && explainDeliverable == "":
+			return ExplainSupplyChains(cmd.OutOrStdout(), explainWorkload, explainSupplyChains)
+		case explainDeliverable != "" && explainWorkload == "":
+			return ExplainDeliveries(cmd.OutOrStdout(), explainDeliverable, explainDeliveries)
+		default:
+			return fmt.Errorf("exactly one of --workload or --deliverable must be specified")
+		}
+	},
+}
+
+// ExplainSupplyChains writes an explanation of how each supply chain in the paths
+// is evaluated against the workload in workloadPath.
+func ExplainSupplyChains(out io.Writer, workloadPath string, supplyChainPaths []string) error {
+	workload := &v1alpha1.Workload{}
+	if err := readYamlFile(workloadPath, workload); err != nil {
+		return fmt.Errorf("read workload: %w", err)
+	}
+
+	var selectingObjects []repository.SelectingObject
+	err := readYamlPaths(supplyChainPaths, func(path string) error {
+		supplyChain := &v1alpha1.ClusterSupplyChain{}
+		if err := readYamlFile(path, supplyChain); err != nil {
+			return err
+		}
+		selectingObjects = append(selectingObjects, supplyChain)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("read supply chains: %w", err)
+	}
+
+	return explainInNamespace(out, "supply chain", workload, workload.Namespace, selectingObjects)
+}
+
+// ExplainDeliveries writes an explanation of how each delivery in the paths is
+// evaluated against the deliverable in deliverablePath.
+func ExplainDeliveries(out io.Writer, deliverablePath string, deliveryPaths []string) error {
+	deliverable := &v1alpha1.Deliverable{}
+	if err := readYamlFile(deliverablePath, deliverable); err != nil {
+		return fmt.Errorf("read deliverable: %w", err)
+	}
+
+	var selectingObjects []repository.SelectingObject
+	err := readYamlPaths(deliveryPaths, func(path string) error {
+		delivery := &v1alpha1.ClusterDelivery{}
+		if err := readYamlFile(path, delivery); err != nil {
+			return err
+		}
+		selectingObjects = append(selectingObjects, delivery)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("read deliveries: %w", err)
+	}
+
+	return explainInNamespace(out, "delivery", deliverable, deliverable.Namespace, selectingObjects)
+}
+
+// explainInNamespace explains the selecting objects whose namespaceSelector
+// allows the namespace, as the controller only considers those, and lists
+// the others.
+func explainInNamespace(out io.Writer, kind string, selectable selector.Selectable, namespace string, selectingObjects []repository.SelectingObject) error {
+	allowed, disallowed, err := repository.FilterByNamespace(selectingObjects, namespaceLabels(namespace))
+	if err != nil {
+		return err
+	}
+
+	return writeExplanations(out, kind, namespace, repository.ExplainSelectorMatch(selectable, allowed), disallowed)
+}
+
+func writeExplanations(out io.Writer, kind string, namespace string, explanations []repository.SelectionExplanation, disallowed []repository.SelectingObject) error {
+	var report strings.Builder
+	for _, explanation := range explanations {
+		status := explanation.Summary()
+		if explanation.Selected {
+			status = "selected, " + status
+		}
+		report.WriteString(fmt.Sprintf("%s [%s] (priority %d): %s\n", kind, explanation.Name, explanation.Priority, status))
+		for _, requirement := range explanation.Requirements {
+			report.WriteString(fmt.Sprintf("  %s: %s\n", requirement.Requirement, satisfiedString(requirement)))
+		}
+	}
+
+	for _, selectingObject := range disallowed {
+		report.WriteString(fmt.Sprintf("%s [%s] (priority %d): namespaceSelector does not allow namespace [%s]\n", kind, selectingObject.GetName(), selectingObject.GetPriority(), namespace))
+	}
+
+	if len(repository.SelectedExplanations(explanations)) == 0 {
+		report.WriteString(fmt.Sprintf("no %s selected\n", kind))
+	}
+
+	_, err := io.WriteString(out, report.String())
+	return err
+}
+
+func satisfiedString(requirement selector.RequirementResult) string {
+	if requirement.Satisfied {
+		return "satisfied"
+	}
+	return "not satisfied"
+}
+
+// readYamlPaths calls read for each path, or for each file of a path that is a
+// directory. Nested directories are not walked.
+func readYamlPaths(paths []string, read func(path string) error) error {
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("could not get fileinfo for path: %w", err)
+		}
+
+		if !info.IsDir() {
+			if err := read(path); err != nil {
+				return fmt.Errorf("read file %s: %w", path, err)
+			}
+			continue
+		}
+
+		files, err := os.ReadDir(path)
+		if err != nil {
+			return fmt.Errorf("os read directory: %w", err)
+		}
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			fullPath := filepath.Join(path, file.Name())
+			if err := read(fullPath); err != nil {
+				return fmt.Errorf("read file %s: %w", fullPath, err)
+			}
+		}
+	}
+	return nil
+}
+
+func readYamlFile(path string, obj interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, obj); err != nil {
+		return fmt.Errorf("unmarshall: %w", err)
+	}
+
+	return nil
+}
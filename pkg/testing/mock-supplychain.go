@@ -31,12 +31,14 @@ import (
 // MockSupplyChain implements SupplyChain
 // SupplyChainInputs simulate expected inputs that are the outputs from earlier resources in the supply chain
 // SupplyChainParams supplies params as if defined in the supply chain
+// MetadataPolicy selects the workload labels and annotations propagated as if defined in the supply chain
 type MockSupplyChain struct {
-	Params SupplyChainParams
-	Inputs SupplyChainInputs
+	Params         SupplyChainParams
+	Inputs         SupplyChainInputs
+	MetadataPolicy *v1alpha1.MetadataPolicy
 }
 
-func (i *MockSupplyChain) stamp(ctx context.Context, workload *v1alpha1.Workload, apiTemplate ValidatableTemplate, template templates.Reader) (*unstructured.Unstructured, error) {
+func (i *MockSupplyChain) stamp(ctx context.Context, workload *v1alpha1.Workload, apiTemplate ValidatableTemplate, template templates.Reader, resolver realizer.ParamValueResolver) (*unstructured.Unstructured, error) {
 	labels := completeLabels(*workload, apiTemplate.GetName(), apiTemplate.GetObjectKind().GroupVersionKind().Kind)
 
 	var (
@@ -52,8 +54,16 @@ func (i *MockSupplyChain) stamp(ctx context.Context, workload *v1alpha1.Workload
 		}
 	}
 
-	paramMerger := realizer.NewParamMerger([]v1alpha1.BlueprintParam{}, blueprintParams, workload.Spec.Params)
-	params := paramMerger.Merge(template)
+	ownerParams, blueprintParams, err := realizer.ResolveParams(ctx, resolver, workload.Spec.Params, blueprintParams)
+	if err != nil {
+		return nil, fmt.Errorf("resolve params: %w", err)
+	}
+
+	paramMerger := realizer.NewParamMerger([]v1alpha1.BlueprintParam{}, blueprintParams, ownerParams)
+	params, err := paramMerger.Merge(template)
+	if err != nil {
+		return nil, fmt.Errorf("merge params: %w", err)
+	}
 
 	templatingContext, err := i.createTemplatingContext(*workload, params)
 	if err != nil {
@@ -61,6 +71,7 @@ func (i *MockSupplyChain) stamp(ctx context.Context, workload *v1alpha1.Workload
 	}
 
 	stampContext := templates.StamperBuilder(workload, templatingContext, labels)
+	stampContext.Metadata = realizer.StampMetadata(i.MetadataPolicy, workload, template.GetResourceTemplate())
 	actualStampedObject, err := stampContext.Stamp(ctx, template.GetResourceTemplate())
 	if err != nil {
 		return nil, fmt.Errorf("could not stamp: %w", err)
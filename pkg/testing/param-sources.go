@@ -0,0 +1,76 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// ParamSources are the ConfigMaps and Secrets that params with valueFrom
+// are read from, as if they existed in the workload's namespace.
+type ParamSources interface {
+	GetParamSources() ([]*unstructured.Unstructured, error)
+}
+
+type ParamSourcesObject struct {
+	Objects []*unstructured.Unstructured
+}
+
+func (p *ParamSourcesObject) GetParamSources() ([]*unstructured.Unstructured, error) {
+	return p.Objects, nil
+}
+
+// ParamSourcesFile reads a ConfigMap or Secret from each of the Paths
+type ParamSourcesFile struct {
+	Paths []string
+}
+
+func (p *ParamSourcesFile) GetParamSources() ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+
+	for _, path := range p.Paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read param source file: %w", err)
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err = yaml.Unmarshal(data, &obj.Object); err != nil {
+			return nil, fmt.Errorf("unmarshall param source: %w", err)
+		}
+
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// paramSourceGetter looks up param sources by kind and name, ignoring
+// their namespace
+type paramSourceGetter []*unstructured.Unstructured
+
+func (g paramSourceGetter) GetUnstructured(_ context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	for _, source := range g {
+		if source.GetKind() == obj.GetKind() && source.GetName() == obj.GetName() {
+			return source.DeepCopy(), nil
+		}
+	}
+	return nil, nil
+}
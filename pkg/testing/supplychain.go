@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/yaml"
 
@@ -32,7 +33,7 @@ import (
 )
 
 type SupplyChain interface {
-	stamp(ctx context.Context, workload *v1alpha1.Workload, apiTemplate ValidatableTemplate, template templates.Reader) (*unstructured.Unstructured, error)
+	stamp(ctx context.Context, workload *v1alpha1.Workload, apiTemplate ValidatableTemplate, template templates.Reader, resolver realizer.ParamValueResolver) (*unstructured.Unstructured, error)
 }
 
 // SupplyChainFileSet is a set of one or more supply chains
@@ -57,7 +58,7 @@ func (s *SupplyChainFileSet) getSupplyChain(workload *v1alpha1.Workload) (*v1alp
 		return nil, fmt.Errorf("read all paths, %w", err)
 	}
 
-	selectedSupplyChains, err := repository.GetSelectedSupplyChain(allSupplyChains, workload, logr.New(noLog))
+	selectedSupplyChains, _, err := repository.GetSelectedSupplyChain(allSupplyChains, workload, namespaceLabels(workload.Namespace), logr.New(noLog))
 	if err != nil {
 		return nil, fmt.Errorf("get selected supply chain, %w", err)
 	}
@@ -186,7 +187,7 @@ func (n *NoLog) Error(_ error, _ string, _ ...interface{}) {}
 func (n *NoLog) WithValues(_ ...interface{}) logr.LogSink  { return n }
 func (n *NoLog) WithName(name string) logr.LogSink         { return n }
 
-func (s *SupplyChainFileSet) stamp(ctx context.Context, workload *v1alpha1.Workload, templateObject ValidatableTemplate, template templates.Reader) (*unstructured.Unstructured, error) {
+func (s *SupplyChainFileSet) stamp(ctx context.Context, workload *v1alpha1.Workload, templateObject ValidatableTemplate, template templates.Reader, resolver realizer.ParamValueResolver) (*unstructured.Unstructured, error) {
 	supplyChain, err := s.getSupplyChain(workload)
 	if err != nil {
 		return nil, fmt.Errorf("get supplychain: %w", err)
@@ -201,7 +202,7 @@ func (s *SupplyChainFileSet) stamp(ctx context.Context, workload *v1alpha1.Workl
 		return nil, fmt.Errorf("template '%s' is not selected by resource/stage '%s' in supply chain '%s'", templateObject.GetName(), resource.Name, supplyChain.Name)
 	}
 
-	templatingContext := realizer.NewContextGenerator(workload, workload.Spec.Params, supplyChain.Spec.Params)
+	contextGenerator := realizer.NewContextGenerator(workload, workload.Spec.Params, supplyChain.Spec.Params)
 
 	resourceLabeler := controllers.BuildWorkloadResourceLabeler(workload, supplyChain)
 	labels := resourceLabeler(*resource, template)
@@ -214,7 +215,13 @@ func (s *SupplyChainFileSet) stamp(ctx context.Context, workload *v1alpha1.Workl
 		outputs = realizer.NewOutputs()
 	}
 
-	stamper := templates.StamperBuilder(workload, templatingContext.Generate(template, *resource, outputs, labels), labels)
+	templatingContext, err := contextGenerator.Generate(ctx, resolver, template, *resource, outputs, labels)
+	if err != nil {
+		return nil, fmt.Errorf("generate templating context: %w", err)
+	}
+
+	stamper := templates.StamperBuilder(workload, templatingContext, labels)
+	stamper.Metadata = realizer.StampMetadata(resource.MetadataPolicy, workload, template.GetResourceTemplate())
 	actualStampedObject, err := stamper.Stamp(ctx, template.GetResourceTemplate())
 	if err != nil {
 		return nil, fmt.Errorf("could not stamp: %w", err)
@@ -236,3 +243,9 @@ func getTargetResource(resources []realizer.OwnerResource, targetResourceName st
 
 	return nil, fmt.Errorf("did not find a supply chain resource with target name: %s", targetResourceName)
 }
+
+// namespaceLabels returns the labels the api server sets on every namespace, as
+// the namespace itself is not available when testing supply chains from files.
+func namespaceLabels(namespace string) map[string]string {
+	return map[string]string{corev1.LabelMetadataName: namespace}
+}
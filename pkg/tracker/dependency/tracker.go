@@ -34,6 +34,7 @@ package dependency
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -68,6 +69,38 @@ func NewKey(gvk schema.GroupVersionKind, namespacedName types.NamespacedName) Ke
 	}
 }
 
+// NewLabelSelectorKey returns the key tracked by an object which selects
+// objects of a kind in a namespace by their labels. Any object matching the
+// labels carries the first of them, so it is looked up by one of its
+// LabelKeys.
+func NewLabelSelectorKey(gk schema.GroupKind, namespace string, matchingLabels map[string]string) Key {
+	var keys []string
+	for key := range matchingLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var label string
+	if len(keys) > 0 {
+		label = fmt.Sprintf("%s=%s", keys[0], matchingLabels[keys[0]])
+	}
+
+	return Key{
+		GroupKind:      gk,
+		NamespacedName: types.NamespacedName{Namespace: namespace, Name: label},
+	}
+}
+
+// LabelKeys returns the keys under which objects selecting an object of a
+// kind in a namespace with the given labels are tracked.
+func LabelKeys(gk schema.GroupKind, namespace string, objectLabels map[string]string) []Key {
+	keys := []Key{NewLabelSelectorKey(gk, namespace, nil)}
+	for key, value := range objectLabels {
+		keys = append(keys, NewLabelSelectorKey(gk, namespace, map[string]string{key: value}))
+	}
+	return keys
+}
+
 type Key struct {
 	GroupKind      schema.GroupKind
 	NamespacedName types.NamespacedName
@@ -32,6 +32,17 @@ func (c ConditionList) ConditionWithType(conditionType string) *metav1.Condition
 	return nil
 }
 
+// StaleObservedGeneration reports whether the status of obj was written for an
+// earlier generation, returning the generation observed. Objects which do not
+// publish status.observedGeneration are never considered stale.
+func StaleObservedGeneration(obj *unstructured.Unstructured) (int64, bool) {
+	observedGeneration, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil || !found {
+		return 0, false
+	}
+	return observedGeneration, observedGeneration < obj.GetGeneration()
+}
+
 func ExtractConditions(stampedObject *unstructured.Unstructured) ConditionList {
 	var conditionList ConditionList
 	maybeStatus := stampedObject.UnstructuredContent()["status"]
@@ -0,0 +1,109 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// SelectorOverlapWarner warns when a supply chain or delivery has the same
+// selectors, namespaceSelector and priority as another of its kind. Owners selected by both can
+// not be reconciled, so this is surfaced at admission rather than when the
+// first owner fails to reconcile.
+type SelectorOverlapWarner struct {
+	Client client.Reader
+}
+
+var _ admission.Handler = &SelectorOverlapWarner{}
+
+type blueprint struct {
+	name              string
+	selector          v1alpha1.LegacySelector
+	namespaceSelector *metav1.LabelSelector
+	priority          int32
+}
+
+func (w *SelectorOverlapWarner) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var (
+		candidate   blueprint
+		others      []blueprint
+		kind, owner string
+	)
+
+	switch req.Kind.Kind {
+	case "ClusterSupplyChain":
+		supplyChain := &v1alpha1.ClusterSupplyChain{}
+		if err := json.Unmarshal(req.Object.Raw, supplyChain); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		candidate = blueprint{name: supplyChain.Name, selector: supplyChain.GetSelectors(), namespaceSelector: supplyChain.GetNamespaceSelector(), priority: supplyChain.GetPriority()}
+
+		list := &v1alpha1.ClusterSupplyChainList{}
+		if err := w.Client.List(ctx, list); err != nil {
+			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("unable to list supply chains: %w", err))
+		}
+		for i := range list.Items {
+			others = append(others, blueprint{name: list.Items[i].Name, selector: list.Items[i].GetSelectors(), namespaceSelector: list.Items[i].GetNamespaceSelector(), priority: list.Items[i].GetPriority()})
+		}
+		kind, owner = "supply chain", "workloads"
+	case "ClusterDelivery":
+		delivery := &v1alpha1.ClusterDelivery{}
+		if err := json.Unmarshal(req.Object.Raw, delivery); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		candidate = blueprint{name: delivery.Name, selector: delivery.GetSelectors(), namespaceSelector: delivery.GetNamespaceSelector(), priority: delivery.GetPriority()}
+
+		list := &v1alpha1.ClusterDeliveryList{}
+		if err := w.Client.List(ctx, list); err != nil {
+			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("unable to list deliveries: %w", err))
+		}
+		for i := range list.Items {
+			others = append(others, blueprint{name: list.Items[i].Name, selector: list.Items[i].GetSelectors(), namespaceSelector: list.Items[i].GetNamespaceSelector(), priority: list.Items[i].GetPriority()})
+		}
+		kind, owner = "delivery", "deliverables"
+	default:
+		return admission.Allowed("")
+	}
+
+	return admission.Allowed("").WithWarnings(overlapWarnings(kind, owner, candidate, others)...)
+}
+
+func overlapWarnings(kind, owner string, candidate blueprint, others []blueprint) []string {
+	var warnings []string
+	for _, other := range others {
+		if other.name == candidate.name {
+			continue
+		}
+		if other.priority == candidate.priority &&
+			equality.Semantic.DeepEqual(other.selector, candidate.selector) &&
+			equality.Semantic.DeepEqual(other.namespaceSelector, candidate.namespaceSelector) {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s [%s] has the same selectors and priority [%d] as %s [%s]: %s selected by both will not be reconciled",
+				kind, candidate.name, candidate.priority, kind, other.name, owner,
+			))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/webhooks"
+)
+
+var _ = Describe("SelectorOverlapWarner", func() {
+	var (
+		ctx      context.Context
+		existing []client.Object
+	)
+
+	supplyChain := func(name string, selector map[string]string, priority int32) *v1alpha1.ClusterSupplyChain {
+		return &v1alpha1.ClusterSupplyChain{
+			TypeMeta:   metav1.TypeMeta{Kind: "ClusterSupplyChain", APIVersion: "carto.run/v1alpha1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.SupplyChainSpec{
+				LegacySelector: v1alpha1.LegacySelector{Selector: selector},
+				Priority:       priority,
+			},
+		}
+	}
+
+	delivery := func(name string, selector map[string]string, priority int32) *v1alpha1.ClusterDelivery {
+		return &v1alpha1.ClusterDelivery{
+			TypeMeta:   metav1.TypeMeta{Kind: "ClusterDelivery", APIVersion: "carto.run/v1alpha1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.DeliverySpec{
+				LegacySelector: v1alpha1.LegacySelector{Selector: selector},
+				Priority:       priority,
+			},
+		}
+	}
+
+	handle := func(obj client.Object) admission.Response {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		raw, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+
+		warner := &webhooks.SelectorOverlapWarner{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing...).Build(),
+		}
+		return warner.Handle(ctx, admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Kind:   metav1.GroupVersionKind{Group: "carto.run", Version: "v1alpha1", Kind: obj.GetObjectKind().GroupVersionKind().Kind},
+				Object: runtime.RawExtension{Raw: raw},
+			},
+		})
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		existing = []client.Object{
+			supplyChain("web", map[string]string{"type": "web"}, 0),
+			supplyChain("web-priority", map[string]string{"type": "web"}, 5),
+			delivery("deliver-web", map[string]string{"type": "web"}, 0),
+		}
+	})
+
+	Context("when a supply chain has the same selectors and priority as another", func() {
+		It("allows it with a warning", func() {
+			response := handle(supplyChain("new-web", map[string]string{"type": "web"}, 0))
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(ConsistOf(
+				"supply chain [new-web] has the same selectors and priority [0] as supply chain [web]: workloads selected by both will not be reconciled",
+			))
+		})
+	})
+
+	Context("when a supply chain has the same selectors but a different priority", func() {
+		It("allows it without a warning", func() {
+			response := handle(supplyChain("new-web", map[string]string{"type": "web"}, 1))
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(BeEmpty())
+		})
+	})
+
+	Context("when a supply chain has different selectors", func() {
+		It("allows it without a warning", func() {
+			response := handle(supplyChain("new-web", map[string]string{"type": "web", "tier": "frontend"}, 0))
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(BeEmpty())
+		})
+	})
+
+	Context("when a supply chain has the same selectors and priority but a different namespace selector", func() {
+		It("allows it without a warning", func() {
+			restricted := supplyChain("new-web", map[string]string{"type": "web"}, 0)
+			restricted.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+			response := handle(restricted)
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(BeEmpty())
+		})
+	})
+
+	Context("when a supply chain is updated", func() {
+		It("does not compare it with itself", func() {
+			response := handle(supplyChain("web-priority", map[string]string{"type": "web"}, 5))
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(BeEmpty())
+		})
+	})
+
+	Context("when a delivery has the same selectors and priority as another", func() {
+		It("allows it with a warning", func() {
+			response := handle(delivery("new-deliver-web", map[string]string{"type": "web"}, 0))
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(ConsistOf(
+				"delivery [new-deliver-web] has the same selectors and priority [0] as delivery [deliver-web]: deliverables selected by both will not be reconciled",
+			))
+		})
+	})
+})
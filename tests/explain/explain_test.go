@@ -0,0 +1,58 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package explain
+
+import (
+	"bytes"
+	"testing"
+
+	cartotesting "github.com/vmware-tanzu/cartographer/pkg/testing"
+)
+
+func TestExplainSupplyChains(t *testing.T) {
+	var out bytes.Buffer
+	err := cartotesting.ExplainSupplyChains(&out, "workload.yaml", []string{"supply-chains"})
+	if err != nil {
+		t.Fatal("explain failed, err:", err)
+	}
+
+	expected := `supply chain [api] (priority 0): did not match: unsatisfied label [apps.tanzu.vmware.com/workload-type=api]
+  label [apps.tanzu.vmware.com/workload-type=api]: not satisfied
+supply chain [web-app] (priority 0): selected, matched with score [2]
+  label [app.kubernetes.io/part-of=my-app]: satisfied
+  label [apps.tanzu.vmware.com/workload-type=web]: satisfied
+supply chain [web] (priority 0): matched with score [1]
+  label [apps.tanzu.vmware.com/workload-type=web]: satisfied
+supply chain [restricted] (priority 0): namespaceSelector does not allow namespace [my-namespace]
+`
+	if out.String() != expected {
+		t.Fatalf("unexpected explanation:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestExplainDeliveries(t *testing.T) {
+	var out bytes.Buffer
+	err := cartotesting.ExplainDeliveries(&out, "deliverable.yaml", []string{"delivery.yaml"})
+	if err != nil {
+		t.Fatal("explain failed, err:", err)
+	}
+
+	expected := `delivery [delivery] (priority 0): namespaceSelector does not allow namespace [my-namespace]
+no delivery selected
+`
+	if out.String() != expected {
+		t.Fatalf("unexpected explanation:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
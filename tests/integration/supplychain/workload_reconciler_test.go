@@ -27,6 +27,7 @@ import (
 	eventsv1 "k8s.io/api/events/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -984,6 +985,142 @@ var _ = Describe("WorkloadReconciler", func() {
 				})
 			})
 		})
+
+		Context("tekton template stamping a Tekton PipelineRun", func() {
+			var listRuns func() ([]unstructured.Unstructured, error)
+			var setRunStatus func(run *unstructured.Unstructured, status map[string]interface{})
+
+			BeforeEach(func() {
+				templateYaml := utils.HereYaml(`
+					---
+					apiVersion: carto.run/v1alpha1
+					kind: ClusterConfigTemplate
+					metadata:
+					  name: my-config-template
+					spec:
+					  lifecycle: tekton
+					  template:
+						apiVersion: tekton.dev/v1beta1
+						kind: PipelineRun
+						metadata:
+						  generateName: my-run-
+						spec:
+						  pipelineRef:
+							name: my-pipeline
+						  params:
+							- name: image
+							  value: $(workload.spec.source.image)$
+				`)
+				template := utils.CreateObjectOnClusterFromYamlDefinition(ctx, c, templateYaml)
+				cleanups = append(cleanups, template)
+
+				listRuns = func() ([]unstructured.Unstructured, error) {
+					runs := &unstructured.UnstructuredList{}
+					runs.SetAPIVersion("tekton.dev/v1beta1")
+					runs.SetKind("PipelineRunList")
+					err := c.List(ctx, runs, client.InNamespace(testNS))
+					return runs.Items, err
+				}
+
+				setRunStatus = func(run *unstructured.Unstructured, status map[string]interface{}) {
+					run.Object["status"] = status
+					Expect(c.Status().Update(ctx, run)).To(Succeed())
+				}
+			})
+
+			It("reads the outputs from the results of the run", func() {
+				var runs []unstructured.Unstructured
+				Eventually(func() ([]unstructured.Unstructured, error) {
+					var err error
+					runs, err = listRuns()
+					return runs, err
+				}).Should(HaveLen(1))
+
+				setRunStatus(&runs[0], map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{
+							"type":               "Succeeded",
+							"status":             "True",
+							"reason":             "Succeeded",
+							"lastTransitionTime": metav1.Now().Format(time.RFC3339),
+						},
+					},
+					"pipelineResults": []interface{}{
+						map[string]interface{}{"name": "config", "value": "some-config"},
+					},
+				})
+
+				itResultsInAHealthyWorkload()
+
+				workload := &v1alpha1.Workload{}
+				err := c.Get(ctx, client.ObjectKey{Name: "workload-joe", Namespace: testNS}, workload)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(workload.Status.Resources[0].Outputs).To(HaveLen(1))
+				Expect(workload.Status.Resources[0].Outputs[0]).To(MatchFields(IgnoreExtras, Fields{
+					"Name":    Equal("config"),
+					"Preview": Equal("some-config\n"),
+				}))
+			})
+
+			It("explains why a failed run failed", func() {
+				var runs []unstructured.Unstructured
+				Eventually(func() ([]unstructured.Unstructured, error) {
+					var err error
+					runs, err = listRuns()
+					return runs, err
+				}).Should(HaveLen(1))
+
+				setRunStatus(&runs[0], map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{
+							"type":               "Succeeded",
+							"status":             "False",
+							"reason":             "PipelineRunTimeout",
+							"message":            "PipelineRun \"my-run\" failed to finish within \"1h0m0s\"",
+							"lastTransitionTime": metav1.Now().Format(time.RFC3339),
+						},
+					},
+				})
+
+				Eventually(func() []metav1.Condition {
+					workload := &v1alpha1.Workload{}
+					err := c.Get(ctx, client.ObjectKey{Name: "workload-joe", Namespace: testNS}, workload)
+					Expect(err).NotTo(HaveOccurred())
+
+					if len(workload.Status.Resources) == 0 {
+						return nil
+					}
+					return workload.Status.Resources[0].Conditions
+				}).Should(ContainElement(MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal("Healthy"),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Message": ContainSubstring("PipelineRun timed out [PipelineRunTimeout]"),
+				})))
+			})
+
+			Context("and the workload is updated while the run is in progress", func() {
+				BeforeEach(func() {
+					Eventually(listRuns).Should(HaveLen(1))
+
+					image := "a-different-image"
+					workload.Spec.Source.Image = &image
+					utils.UpdateObjectOnCluster(ctx, c, &workload, &v1alpha1.Workload{})
+				})
+
+				It("cancels the superseded run", func() {
+					Eventually(func() ([]string, error) {
+						runs, err := listRuns()
+						var statuses []string
+						for _, run := range runs {
+							status, _, _ := unstructured.NestedString(run.Object, "spec", "status")
+							statuses = append(statuses, status)
+						}
+						return statuses, err
+					}).Should(ConsistOf("Cancelled", ""))
+				})
+			})
+		})
 	})
 
 	Context("mutable template", func() {
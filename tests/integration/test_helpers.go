@@ -31,6 +31,7 @@ func CreateTestEnv(workingDir string, out io.Writer) *envtest.Environment {
 		CRDDirectoryPaths: []string{
 			filepath.Join(workingDir, "..", "..", "..", "config", "crd", "bases"),
 			filepath.Join(workingDir, "..", "..", "resources", "crds"),
+			filepath.Join(workingDir, "..", "..", "resources", "tekton"),
 		},
 		AttachControlPlaneOutput: DebugControlPlane, // Set to true for great debug logging
 	}